@@ -0,0 +1,321 @@
+package banmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// key types
+	KeyPeerID    = "peerid"    // libp2p peer ID
+	KeyMiningKey = "miningkey" // base58 BLS mining public key
+
+	// reasons
+	ReasonManual              = "manual"              // banned by banpeer RPC
+	ReasonInvalidBlock        = "invalidblock"        // sent a block failing validation
+	ReasonInvalidConsensusMsg = "invalidconsensusmsg" // sent a propose/vote message failing validation
+	ReasonEquivocation        = "equivocation"        // signed conflicting proposals/votes in a timeslot
+	ReasonDuplicateFlood      = "duplicateflood"      // kept sending duplicated messages
+
+	version  = 1
+	DataFile = "banlist.json"
+
+	// MaxBanDuration - an escalated ban never lasts longer than this
+	MaxBanDuration = 30 * 24 * time.Hour
+	// ForgetAfter - an expired ban is kept this long, so a new offence escalates from it
+	ForgetAfter = 7 * 24 * time.Hour
+)
+
+// ReasonDuration - duration of the first ban for a reason, doubled for every repeated offence
+var ReasonDuration = map[string]time.Duration{
+	ReasonManual:              24 * time.Hour,
+	ReasonInvalidBlock:        time.Hour,
+	ReasonInvalidConsensusMsg: time.Hour,
+	ReasonEquivocation:        7 * 24 * time.Hour,
+	ReasonDuplicateFlood:      10 * time.Minute,
+}
+
+// Ban - a peer ID or mining key banned for Reason. Count is the number of offences, Expiry is 0 for a permanent ban
+type Ban struct {
+	Key      string `json:"Key"`
+	KeyType  string `json:"KeyType"`
+	Reason   string `json:"Reason"`
+	Message  string `json:"Message"`
+	Count    int    `json:"Count"`
+	BannedAt int64  `json:"BannedAt"` // unix time
+	Expiry   int64  `json:"Expiry"`   // unix time
+}
+
+func (b *Ban) activeAt(now time.Time) bool {
+	return b.Expiry == 0 || now.Unix() < b.Expiry
+}
+
+// data structure of ban list which need to be saving in file
+type serializedBanList struct {
+	Version int             `json:"Version"`
+	Bans    map[string]*Ban `json:"Bans"` // banKey -> ban
+}
+
+// BanManager - bans of peers and validators shared by syncker, peerv2 and consensus.
+// Bans are kept in a json file so they survive restart
+type BanManager struct {
+	mtx       sync.RWMutex
+	filePath  string
+	bans      map[string]*Ban
+	protected func(keyType string, key string) bool
+	onBan     []func(Ban)
+	now       func() time.Time
+}
+
+// NewBanManager - load the ban list stored at filePath, or create an empty one if the file does not exist.
+// An empty filePath gives an in-memory ban list
+func NewBanManager(filePath string) (*BanManager, error) {
+	m := &BanManager{
+		filePath: filePath,
+		bans:     make(map[string]*Ban),
+		now:      time.Now,
+	}
+	if filePath == "" {
+		return m, nil
+	}
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, NewBanManagerError(LoadBanListError, err)
+	}
+	defer f.Close()
+	bans, err := decode(f)
+	if err != nil {
+		return nil, err
+	}
+	m.bans = bans
+	return m, nil
+}
+
+func banKey(keyType string, key string) string {
+	return keyType + "-" + key
+}
+
+func checkKeyType(keyType string) error {
+	if keyType != KeyPeerID && keyType != KeyMiningKey {
+		return NewBanManagerError(InvalidKeyTypeError, fmt.Errorf("unknown key type %v", keyType))
+	}
+	return nil
+}
+
+// SetProtected - keys for which protected returns true are never banned, e.g. the connected highway
+func (m *BanManager) SetProtected(protected func(keyType string, key string) bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.protected = protected
+}
+
+// AddOnBan - f is called after a key is banned, e.g. to close the connection to the peer
+func (m *BanManager) AddOnBan(f func(Ban)) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.onBan = append(m.onBan, f)
+}
+
+// Ban - ban key for reason. The ban lasts ReasonDuration[reason], doubled for every offence
+// remembered for this key, up to MaxBanDuration. A key which is already banned is not banned again,
+// so messages still in flight when the ban starts do not escalate it
+func (m *BanManager) Ban(keyType string, key string, reason string, message string) (*Ban, error) {
+	duration, ok := ReasonDuration[reason]
+	if !ok {
+		return nil, NewBanManagerError(InvalidReasonError, fmt.Errorf("unknown reason %v", reason))
+	}
+	return m.ban(keyType, key, reason, message, func(count int) time.Duration {
+		for i := 1; i < count && duration < MaxBanDuration; i++ {
+			duration *= 2
+		}
+		if duration > MaxBanDuration {
+			duration = MaxBanDuration
+		}
+		return duration
+	}, false)
+}
+
+// BanFor - ban key for duration, 0 bans it permanently. Replace the current ban of key if any
+func (m *BanManager) BanFor(keyType string, key string, reason string, message string, duration time.Duration) (*Ban, error) {
+	if _, ok := ReasonDuration[reason]; !ok {
+		return nil, NewBanManagerError(InvalidReasonError, fmt.Errorf("unknown reason %v", reason))
+	}
+	return m.ban(keyType, key, reason, message, func(int) time.Duration { return duration }, true)
+}
+
+func (m *BanManager) ban(keyType string, key string, reason string, message string, duration func(count int) time.Duration, replace bool) (*Ban, error) {
+	if err := checkKeyType(keyType); err != nil {
+		return nil, err
+	}
+	m.mtx.Lock()
+	if m.protected != nil && m.protected(keyType, key) {
+		m.mtx.Unlock()
+		return nil, NewBanManagerError(ProtectedKeyError, fmt.Errorf("%v %v", keyType, key))
+	}
+	m.prune()
+	now := m.now()
+	k := banKey(keyType, key)
+	last := m.bans[k]
+	if last != nil && last.activeAt(now) && !replace {
+		ban := *last
+		m.mtx.Unlock()
+		return &ban, nil
+	}
+	ban := Ban{
+		Key:      key,
+		KeyType:  keyType,
+		Reason:   reason,
+		Message:  message,
+		Count:    1,
+		BannedAt: now.Unix(),
+	}
+	if last != nil {
+		ban.Count = last.Count + 1
+	}
+	if d := duration(ban.Count); d > 0 {
+		ban.Expiry = now.Add(d).Unix()
+	}
+	m.bans[k] = &ban
+	if err := m.save(); err != nil {
+		if last == nil {
+			delete(m.bans, k)
+		} else {
+			m.bans[k] = last
+		}
+		m.mtx.Unlock()
+		return nil, err
+	}
+	onBan := m.onBan
+	m.mtx.Unlock()
+	for _, f := range onBan {
+		f(ban)
+	}
+	return &ban, nil
+}
+
+// Unban - lift the ban of key, the offence count is forgotten
+func (m *BanManager) Unban(keyType string, key string) error {
+	if err := checkKeyType(keyType); err != nil {
+		return err
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	k := banKey(keyType, key)
+	last, ok := m.bans[k]
+	if !ok || !last.activeAt(m.now()) {
+		return NewBanManagerError(NotBannedError, fmt.Errorf("%v %v", keyType, key))
+	}
+	delete(m.bans, k)
+	m.prune()
+	if err := m.save(); err != nil {
+		m.bans[k] = last
+		return err
+	}
+	return nil
+}
+
+// IsBanned - true if key is banned now
+func (m *BanManager) IsBanned(keyType string, key string) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	ban, ok := m.bans[banKey(keyType, key)]
+	return ok && ban.activeAt(m.now())
+}
+
+// List - bans active now, oldest first
+func (m *BanManager) List() []Ban {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	now := m.now()
+	res := []Ban{}
+	for _, ban := range m.bans {
+		if ban.activeAt(now) {
+			res = append(res, *ban)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].BannedAt != res[j].BannedAt {
+			return res[i].BannedAt < res[j].BannedAt
+		}
+		return banKey(res[i].KeyType, res[i].Key) < banKey(res[j].KeyType, res[j].Key)
+	})
+	return res
+}
+
+// prune - forget bans expired for more than ForgetAfter
+func (m *BanManager) prune() {
+	now := m.now()
+	for k, ban := range m.bans {
+		if ban.Expiry != 0 && now.Unix() > ban.Expiry+int64(ForgetAfter/time.Second) {
+			delete(m.bans, k)
+		}
+	}
+}
+
+// save - write ban list to a temp file, sync and rename, so the ban list on disk is never partially written
+func (m *BanManager) save() error {
+	if m.filePath == "" {
+		return nil
+	}
+	f, err := ioutil.TempFile(filepath.Dir(m.filePath), filepath.Base(m.filePath)+".tmp")
+	if err != nil {
+		return NewBanManagerError(SaveBanListError, err)
+	}
+	tmpPath := f.Name()
+	if err := encode(f, m.bans); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return NewBanManagerError(SaveBanListError, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return NewBanManagerError(SaveBanListError, err)
+	}
+	if err := os.Rename(tmpPath, m.filePath); err != nil {
+		os.Remove(tmpPath)
+		return NewBanManagerError(SaveBanListError, err)
+	}
+	return nil
+}
+
+func encode(w io.Writer, bans map[string]*Ban) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(serializedBanList{Version: version, Bans: bans}); err != nil {
+		return NewBanManagerError(SaveBanListError, err)
+	}
+	return nil
+}
+
+func decode(r io.Reader) (map[string]*Ban, error) {
+	var data serializedBanList
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, NewBanManagerError(LoadBanListError, err)
+	}
+	if data.Version != version {
+		return nil, NewBanManagerError(LoadBanListError, fmt.Errorf("unknown ban list version %v", data.Version))
+	}
+	bans := make(map[string]*Ban)
+	for _, ban := range data.Bans {
+		if ban == nil || checkKeyType(ban.KeyType) != nil {
+			continue
+		}
+		bans[banKey(ban.KeyType, ban.Key)] = ban
+	}
+	return bans, nil
+}
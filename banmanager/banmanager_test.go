@@ -0,0 +1,128 @@
+package banmanager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBanManager_Escalation(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	m, err := NewBanManager("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = func() time.Time { return now }
+
+	ban, err := m.Ban(KeyPeerID, "peer1", ReasonInvalidBlock, "bad block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ban.Count != 1 || ban.Expiry != now.Add(time.Hour).Unix() {
+		t.Errorf("unexpected first ban %+v", ban)
+	}
+	//offence while banned does not escalate
+	if ban, _ = m.Ban(KeyPeerID, "peer1", ReasonInvalidBlock, "bad block"); ban.Count != 1 {
+		t.Errorf("expect count 1 while banned, got %+v", ban)
+	}
+	if !m.IsBanned(KeyPeerID, "peer1") || m.IsBanned(KeyMiningKey, "peer1") {
+		t.Error("ban must be keyed by key type and key")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if m.IsBanned(KeyPeerID, "peer1") {
+		t.Error("expect ban expired")
+	}
+	if ban, _ = m.Ban(KeyPeerID, "peer1", ReasonInvalidBlock, "bad block"); ban.Count != 2 || ban.Expiry != now.Add(2*time.Hour).Unix() {
+		t.Errorf("expect doubled ban, got %+v", ban)
+	}
+
+	//escalation is capped
+	for i := 0; i < 20; i++ {
+		now = time.Unix(ban.Expiry, 0)
+		ban, _ = m.Ban(KeyPeerID, "peer1", ReasonInvalidBlock, "bad block")
+	}
+	if ban.Expiry != now.Add(MaxBanDuration).Unix() {
+		t.Errorf("expect ban capped at %v, got %+v", MaxBanDuration, ban)
+	}
+
+	//offences are forgotten long after expiry
+	now = now.Add(MaxBanDuration + ForgetAfter + time.Second)
+	if ban, _ = m.Ban(KeyPeerID, "peer1", ReasonDuplicateFlood, "flood"); ban.Count != 1 {
+		t.Errorf("expect offences forgotten, got %+v", ban)
+	}
+
+	if _, err := m.Ban("ip", "1.2.3.4", ReasonManual, ""); err == nil {
+		t.Error("expect error for unknown key type")
+	}
+	if _, err := m.Ban(KeyPeerID, "peer2", "unknown", ""); err == nil {
+		t.Error("expect error for unknown reason")
+	}
+}
+
+func TestBanManager_ProtectedAndOnBan(t *testing.T) {
+	m, err := NewBanManager("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetProtected(func(keyType string, key string) bool { return keyType == KeyPeerID && key == "highway" })
+	banned := []string{}
+	m.AddOnBan(func(ban Ban) { banned = append(banned, ban.Key) })
+
+	if _, err := m.Ban(KeyPeerID, "highway", ReasonDuplicateFlood, ""); err == nil {
+		t.Error("expect protected key not banned")
+	}
+	if _, err := m.BanFor(KeyMiningKey, "validator1", ReasonManual, "by operator", 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(banned) != 1 || banned[0] != "validator1" {
+		t.Errorf("unexpected onBan calls %v", banned)
+	}
+	if err := m.Unban(KeyMiningKey, "validator1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Unban(KeyMiningKey, "validator1"); err == nil {
+		t.Error("expect error unbanning a key which is not banned")
+	}
+}
+
+func TestBanManager_Persist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "banmanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, DataFile)
+
+	m, err := NewBanManager(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.BanFor(KeyPeerID, "peer1", ReasonManual, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Ban(KeyMiningKey, "validator1", ReasonEquivocation, "double vote"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Ban(KeyPeerID, "peer2", ReasonInvalidBlock, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Unban(KeyPeerID, "peer2"); err != nil {
+		t.Fatal(err)
+	}
+
+	//reload from disk as after a restart
+	reloaded, err := NewBanManager(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := reloaded.List()
+	if len(list) != 2 {
+		t.Fatalf("expect 2 bans after restart, got %+v", list)
+	}
+	if !reloaded.IsBanned(KeyPeerID, "peer1") || !reloaded.IsBanned(KeyMiningKey, "validator1") || reloaded.IsBanned(KeyPeerID, "peer2") {
+		t.Errorf("unexpected bans after restart %+v", list)
+	}
+}
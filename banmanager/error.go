@@ -0,0 +1,48 @@
+package banmanager
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnexpectedError = iota
+	LoadBanListError
+	SaveBanListError
+	InvalidKeyTypeError
+	InvalidReasonError
+	ProtectedKeyError
+	NotBannedError
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	message string
+}{
+	UnexpectedError:     {-1000, "Unexpected error"},
+	LoadBanListError:    {-1001, "Load ban list error"},
+	SaveBanListError:    {-1002, "Save ban list error"},
+	InvalidKeyTypeError: {-1003, "Invalid key type, must be peerid or miningkey"},
+	InvalidReasonError:  {-1004, "Invalid ban reason"},
+	ProtectedKeyError:   {-1005, "Key is protected from ban"},
+	NotBannedError:      {-1006, "Key is not banned"},
+}
+
+type BanManagerError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e BanManagerError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewBanManagerError(key int, err error) error {
+	return &BanManagerError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].message),
+	}
+}
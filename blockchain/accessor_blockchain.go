@@ -64,6 +64,11 @@ func (blockchain *BlockChain) GetBeaconBlockHashByView(view multiview.View, heig
 	return rawdbv2.GetFinalizedBeaconBlockHashByIndex(blockchain.GetBeaconChainDatabase(), height)
 }
 
+// GetBeaconFinalHeight - height of the final beacon view, blocks up to it can not be reverted
+func (blockchain *BlockChain) GetBeaconFinalHeight() uint64 {
+	return blockchain.BeaconChain.GetFinalViewHeight()
+}
+
 func (blockchain *BlockChain) GetBeaconBlockByHeightV1(height uint64) (*BeaconBlock, error) {
 	beaconBlocks, err := blockchain.GetBeaconBlockByHeight(height)
 	if err != nil {
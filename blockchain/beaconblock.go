@@ -114,6 +114,9 @@ func (beaconBlock *BeaconBlock) UnmarshalJSON(data []byte) error {
 	beaconBlock.ValidationData = tempBeaconBlock.ValidationData
 	beaconBlock.Header = tempBeaconBlock.Header
 	beaconBlock.Body = tempBeaconBlock.Body
+	if err := beaconBlock.checkSizeLimit(); err != nil {
+		return NewBlockChainError(UnmashallJsonBeaconBlockError, err)
+	}
 	return nil
 }
 
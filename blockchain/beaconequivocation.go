@@ -0,0 +1,214 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// equivocationMessage - header info of one signed message in an equivocation evidence
+type equivocationMessage struct {
+	hash      common.Hash
+	height    uint64
+	timeSlot  int64
+	committee []incognitokey.CommitteePublicKey
+}
+
+// getEquivocationMessage decode the header of a signed message and find the committee who could sign it
+func (blockchain *BlockChain) getEquivocationMessage(
+	beaconBestState *BeaconBestState,
+	chainID int,
+	msg metadata.SignedHeader,
+) (*equivocationMessage, error) {
+	var committeeHeight uint64
+	res := &equivocationMessage{}
+	if chainID == metadata.EquivocationBeaconChainID {
+		header := BeaconHeader{}
+		if err := json.Unmarshal(msg.Header, &header); err != nil {
+			return nil, err
+		}
+		if header.Height < 2 {
+			return nil, fmt.Errorf("invalid beacon header height %v", header.Height)
+		}
+		res.hash = header.Hash()
+		res.height = header.Height
+		res.timeSlot = common.CalculateTimeSlot(header.ProposeTime)
+		committeeHeight = header.Height - 1
+	} else {
+		header := ShardHeader{}
+		if err := json.Unmarshal(msg.Header, &header); err != nil {
+			return nil, err
+		}
+		if int(header.ShardID) != chainID {
+			return nil, fmt.Errorf("expect header of shard %v but get shard %v", chainID, header.ShardID)
+		}
+		if header.BeaconHeight < 1 {
+			return nil, fmt.Errorf("invalid shard header beacon height %v", header.BeaconHeight)
+		}
+		res.hash = header.Hash()
+		res.height = header.Height
+		res.timeSlot = common.CalculateTimeSlot(header.ProposeTime)
+		committeeHeight = header.BeaconHeight
+	}
+	if committeeHeight > beaconBestState.BeaconHeight {
+		return nil, fmt.Errorf("committee height %v is higher than beacon height %v", committeeHeight, beaconBestState.BeaconHeight)
+	}
+	consensusRootHash, err := blockchain.GetBeaconConsensusRootHash(beaconBestState, committeeHeight)
+	if err != nil {
+		return nil, err
+	}
+	consensusStateDB, err := statedb.NewWithPrefixTrie(consensusRootHash, statedb.NewDatabaseAccessWarper(blockchain.GetBeaconChainDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	if chainID == metadata.EquivocationBeaconChainID {
+		res.committee = statedb.GetBeaconCommittee(consensusStateDB)
+	} else {
+		res.committee = statedb.GetOneShardCommittee(consensusStateDB, byte(chainID))
+	}
+	return res, nil
+}
+
+// verifyEquivocationEvidence - check that both messages are signed by the same committee member
+// for different blocks in the same timeslot, return the message info and the committee public key of the validator
+func (blockchain *BlockChain) verifyEquivocationEvidence(
+	beaconBestState *BeaconBestState,
+	evidence metadata.EquivocationEvidence,
+) ([]*equivocationMessage, string, error) {
+	if err := evidence.ValidateSanity(); err != nil {
+		return nil, "", err
+	}
+	msgs := []*equivocationMessage{}
+	committeePublicKey := ""
+	for _, signedMsg := range evidence.Messages {
+		msg, err := blockchain.getEquivocationMessage(beaconBestState, evidence.ChainID, signedMsg)
+		if err != nil {
+			return nil, "", err
+		}
+		var validatorKey *incognitokey.CommitteePublicKey
+		for i, key := range msg.committee {
+			if key.GetMiningKeyBase58(common.BlsConsensus) == evidence.Validator {
+				validatorKey = &msg.committee[i]
+				break
+			}
+		}
+		if validatorKey == nil {
+			return nil, "", fmt.Errorf("validator %v is not in committee of chain %v at height %v", evidence.Validator, evidence.ChainID, msg.height)
+		}
+		if err := evidence.VerifySignature(signedMsg, msg.hash, validatorKey.MiningPubKey[common.BridgeConsensus]); err != nil {
+			return nil, "", err
+		}
+		keyStr, err := validatorKey.ToBase58()
+		if err != nil {
+			return nil, "", err
+		}
+		if committeePublicKey != "" && committeePublicKey != keyStr {
+			return nil, "", errors.New("evidence messages are signed by different committee keys")
+		}
+		committeePublicKey = keyStr
+		msgs = append(msgs, msg)
+	}
+	if msgs[0].timeSlot != msgs[1].timeSlot {
+		return nil, "", fmt.Errorf("evidence messages are in different timeslots %v and %v", msgs[0].timeSlot, msgs[1].timeSlot)
+	}
+	if msgs[0].hash == msgs[1].hash {
+		return nil, "", errors.New("evidence messages are for the same block")
+	}
+	return msgs, committeePublicKey, nil
+}
+
+func (blockchain *BlockChain) buildInstructionsForReportEquivocation(
+	beaconBestState *BeaconBestState,
+	slashStateDB *statedb.StateDB,
+	contentStr string,
+	shardID byte,
+	metaType int,
+	beaconHeight uint64,
+	ac *metadata.AccumulatedValues,
+) ([][]string, error) {
+	instructions := [][]string{}
+	action, err := metadata.ParseReportEquivocationAction(contentStr)
+	if err != nil {
+		Logger.log.Info("WARNING: an issue occured while parsing report equivocation action content: ", err)
+		return nil, nil
+	}
+	rejectedInst := buildInstruction(metaType, shardID, "rejected", action.TxReqID.String())
+	if beaconHeight < blockchain.GetBCHeightBreakPointEquivocation() {
+		Logger.log.Warnf("WARNING: equivocation report in tx %v before beacon height %v", action.TxReqID.String(), blockchain.GetBCHeightBreakPointEquivocation())
+		return append(instructions, rejectedInst), nil
+	}
+	evidence := action.Meta.Evidence
+	msgs, committeePublicKey, err := blockchain.verifyEquivocationEvidence(beaconBestState, evidence)
+	if err != nil {
+		Logger.log.Warnf("WARNING: invalid equivocation evidence in tx %v: %v", action.TxReqID.String(), err)
+		return append(instructions, rejectedInst), nil
+	}
+	height := msgs[0].height
+	if msgs[1].height < height {
+		height = msgs[1].height
+	}
+	timeSlot := msgs[0].timeSlot
+	slashKey := statedb.GenerateEquivocationSlashObjectKey(committeePublicKey, evidence.Type, evidence.ChainID, height, timeSlot)
+	if !ac.CanProcessEquivocationSlash(slashKey) {
+		Logger.log.Warnf("WARNING: equivocation of %v in timeslot %v was already reported in the current block", committeePublicKey, timeSlot)
+		return append(instructions, rejectedInst), nil
+	}
+	slashed, err := statedb.HasEquivocationSlash(slashStateDB, committeePublicKey, evidence.Type, evidence.ChainID, height, timeSlot)
+	if err != nil {
+		Logger.log.Warn("WARNING: an issue occured while checking equivocation slash: ", err)
+		return append(instructions, rejectedInst), nil
+	}
+	if slashed {
+		Logger.log.Warnf("WARNING: equivocation of %v in timeslot %v was already slashed", committeePublicKey, timeSlot)
+		return append(instructions, rejectedInst), nil
+	}
+
+	punishment := blockchain.config.ChainParams.EquivocationPunishment
+	content := metadata.ReportEquivocationContent{
+		Validator:          evidence.Validator,
+		CommitteePublicKey: committeePublicKey,
+		Type:               evidence.Type,
+		ChainID:            evidence.ChainID,
+		Height:             height,
+		TimeSlot:           timeSlot,
+		PunishedEpoches:    punishment.PunishedEpoches,
+		ForceUnstake:       punishment.ForceUnstake,
+		TxReqID:            action.TxReqID,
+	}
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		Logger.log.Info("WARNING: an error occured while marshaling report equivocation instruction: ", err)
+		return append(instructions, rejectedInst), nil
+	}
+	ac.UniqEquivocationSlash = append(ac.UniqEquivocationSlash, slashKey)
+	instructions = append(instructions, buildInstruction(metaType, shardID, "accepted", base64.StdEncoding.EncodeToString(contentBytes)))
+	if punishment.ForceUnstake {
+		// turn off auto staking so that the validator is swapped out at the end of its term
+		instructions = append(instructions, []string{StopAutoStake, committeePublicKey})
+	}
+	Logger.log.Infof("Accept equivocation evidence of %v, chain %v, height %v, timeslot %v", committeePublicKey, evidence.ChainID, height, timeSlot)
+	return instructions, nil
+}
+
+// getEquivocationSlashes - equivocation slashes accepted in the beacon block
+func getEquivocationSlashes(beaconBlock *BeaconBlock) ([]*metadata.ReportEquivocationContent, error) {
+	slashes := []*metadata.ReportEquivocationContent{}
+	for _, inst := range beaconBlock.GetInstructions() {
+		if len(inst) != 4 || inst[0] != strconv.Itoa(metadata.ReportEquivocationMeta) || inst[2] != "accepted" {
+			continue
+		}
+		content, err := metadata.ParseReportEquivocationContent(inst[3])
+		if err != nil {
+			return nil, NewBlockChainError(ProcessEquivocationSlashError, err)
+		}
+		slashes = append(slashes, content)
+	}
+	return slashes, nil
+}
@@ -0,0 +1,38 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// Equivocation reports are rejected before BCHeightBreakPointEquivocation, by mempool (beacon height 0) and by beacon
+func TestReportEquivocationBreakPoint(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	bc := &BlockChain{config: Config{ChainParams: &Params{BCHeightBreakPointEquivocation: 100}}}
+	meta, err := metadata.NewReportEquivocationMetadata(metadata.EquivocationEvidence{Type: metadata.EquivocationPropose}, metadata.ReportEquivocationMeta)
+	assert.Nil(t, err)
+	tx := &mocks.Transaction{}
+	txHash := common.HashH([]byte("report"))
+	tx.On("IsPrivacy").Return(true)
+	tx.On("Hash").Return(&txHash)
+
+	_, _, err = meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 99}, nil, 0, tx)
+	mtErr, ok := err.(*metadata.MetadataTxError)
+	if assert.True(t, ok, "%v", err) {
+		assert.Equal(t, metadata.ErrCodeMessage[metadata.ReportEquivocationNotActivatedError].Code, mtErr.Code)
+	}
+	// past the break point, the next check rejects the privacy tx
+	_, _, err = meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 100}, nil, 0, tx)
+	_, ok = err.(*metadata.MetadataTxError)
+	assert.False(t, ok)
+
+	actions, err := meta.BuildReqActions(tx, nil, nil, nil, 1, 10)
+	assert.Nil(t, err)
+	insts, err := bc.buildInstructionsForReportEquivocation(nil, nil, actions[0][1], 1, metadata.ReportEquivocationMeta, 99, &metadata.AccumulatedValues{})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{buildInstruction(metadata.ReportEquivocationMeta, 1, "rejected", txHash.String())}, insts)
+}
@@ -32,26 +32,7 @@ func (blockchain *BlockChain) processPDEInstructions(pdexStateDB *statedb.StateD
 		return err
 	}
 	for _, inst := range beaconBlock.Body.Instructions {
-		if len(inst) < 2 {
-			continue // Not error, just not PDE instruction
-		}
-		var err error
-		switch inst[0] {
-		case strconv.Itoa(metadata.PDEContributionMeta):
-			err = blockchain.processPDEContributionV2(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDEPRVRequiredContributionRequestMeta):
-			err = blockchain.processPDEContributionV2(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDETradeRequestMeta):
-			err = blockchain.processPDETrade(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta):
-			err = blockchain.processPDECrossPoolTrade(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDEWithdrawalRequestMeta):
-			err = blockchain.processPDEWithdrawal(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDEFeeWithdrawalRequestMeta):
-			err = blockchain.processPDEFeeWithdrawal(pdexStateDB, beaconHeight, inst, currentPDEState)
-		case strconv.Itoa(metadata.PDETradingFeesDistributionMeta):
-			err = blockchain.processPDETradingFeesDistribution(pdexStateDB, beaconHeight, inst, currentPDEState)
-		}
+		err := blockchain.processPDEInstruction(pdexStateDB, beaconHeight, inst, currentPDEState)
 		if err != nil {
 			Logger.log.Error(err)
 			return nil
@@ -68,6 +49,30 @@ func (blockchain *BlockChain) processPDEInstructions(pdexStateDB *statedb.StateD
 	return nil
 }
 
+// processPDEInstruction - apply a pde instruction of the beacon block after beaconHeight to currentPDEState
+func (blockchain *BlockChain) processPDEInstruction(pdexStateDB *statedb.StateDB, beaconHeight uint64, inst []string, currentPDEState *CurrentPDEState) error {
+	if len(inst) < 2 {
+		return nil // Not error, just not PDE instruction
+	}
+	switch inst[0] {
+	case strconv.Itoa(metadata.PDEContributionMeta):
+		return blockchain.processPDEContributionV2(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDEPRVRequiredContributionRequestMeta):
+		return blockchain.processPDEContributionV2(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDETradeRequestMeta):
+		return blockchain.processPDETrade(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta), strconv.Itoa(metadata.PDERoutedTradeRequestMeta):
+		return blockchain.processPDECrossPoolTrade(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDEWithdrawalRequestMeta):
+		return blockchain.processPDEWithdrawal(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDEFeeWithdrawalRequestMeta):
+		return blockchain.processPDEFeeWithdrawal(pdexStateDB, beaconHeight, inst, currentPDEState)
+	case strconv.Itoa(metadata.PDETradingFeesDistributionMeta):
+		return blockchain.processPDETradingFeesDistribution(pdexStateDB, beaconHeight, inst, currentPDEState)
+	}
+	return nil
+}
+
 func hasPDEInstruction(instructions [][]string) bool {
 	hasPDEXInstruction := false
 	for _, inst := range instructions {
@@ -90,7 +95,7 @@ func hasPDEInstruction(instructions [][]string) bool {
 		case strconv.Itoa(metadata.PDEPRVRequiredContributionRequestMeta):
 			hasPDEXInstruction = true
 			break
-		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta):
+		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta), strconv.Itoa(metadata.PDERoutedTradeRequestMeta):
 			hasPDEXInstruction = true
 			break
 		case strconv.Itoa(metadata.PDETradingFeesDistributionMeta):
@@ -309,7 +314,11 @@ func (blockchain *BlockChain) processPDETrade(pdexStateDB *statedb.StateDB, beac
 	if len(instruction) != 4 {
 		return nil // skip the instruction
 	}
-	if instruction[2] == common.PDETradeRefundChainStatus {
+	if instruction[2] == common.PDETradeRefundChainStatus || instruction[2] == common.PDETradeExpiredRefundChainStatus {
+		refundStatus := common.PDETradeRefundStatus
+		if instruction[2] == common.PDETradeExpiredRefundChainStatus {
+			refundStatus = common.PDETradeExpiredRefundStatus
+		}
 		contentBytes, err := base64.StdEncoding.DecodeString(instruction[3])
 		if err != nil {
 			Logger.log.Errorf("ERROR: an error occured while decoding content string of pde trade instruction: %+v", err)
@@ -325,7 +334,7 @@ func (blockchain *BlockChain) processPDETrade(pdexStateDB *statedb.StateDB, beac
 			pdexStateDB,
 			rawdbv2.PDETradeStatusPrefix,
 			pdeTradeReqAction.TxReqID[:],
-			byte(common.PDETradeRefundStatus),
+			byte(refundStatus),
 		)
 		if err != nil {
 			Logger.log.Errorf("ERROR: an error occured while tracking pde refund trade status: %+v", err)
@@ -369,8 +378,16 @@ func (blockchain *BlockChain) processPDECrossPoolTrade(pdexStateDB *statedb.Stat
 	if len(instruction) != 4 {
 		return nil // skip the instruction
 	}
-	if instruction[2] == common.PDECrossPoolTradeFeeRefundChainStatus ||
-		instruction[2] == common.PDECrossPoolTradeSellingTokenRefundChainStatus {
+	if instruction[2] != common.PDECrossPoolTradeAcceptedChainStatus {
+		refundStatus := common.PDECrossPoolTradeRefundStatus
+		switch instruction[2] {
+		case common.PDECrossPoolTradeFeeRefundChainStatus, common.PDECrossPoolTradeSellingTokenRefundChainStatus:
+		case common.PDECrossPoolTradeExpiredFeeRefundChainStatus, common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus:
+			refundStatus = common.PDECrossPoolTradeExpiredRefundStatus
+		default:
+			Logger.log.Errorf("ERROR: unknown pde cross pool trade status: %v", instruction[2])
+			return nil // skip the instruction
+		}
 		contentBytes := []byte(instruction[3])
 		var pdeRefundCrossPoolTrade metadata.PDERefundCrossPoolTrade
 		err := json.Unmarshal(contentBytes, &pdeRefundCrossPoolTrade)
@@ -382,7 +399,7 @@ func (blockchain *BlockChain) processPDECrossPoolTrade(pdexStateDB *statedb.Stat
 			pdexStateDB,
 			rawdbv2.PDETradeStatusPrefix,
 			pdeRefundCrossPoolTrade.TxReqID[:],
-			byte(common.PDECrossPoolTradeRefundStatus),
+			byte(refundStatus),
 		)
 		if err != nil {
 			Logger.log.Errorf("ERROR: an error occured while tracking pde refund trade status: %+v", err)
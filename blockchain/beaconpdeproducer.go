@@ -419,6 +419,7 @@ type tradeInfo struct {
 func (blockchain *BlockChain) buildInstsForSortedTradableActions(
 	currentPDEState *CurrentPDEState,
 	beaconHeight uint64,
+	beaconTimestamp int64,
 	sortedTradableActions []metadata.PDECrossPoolTradeRequestAction,
 ) ([][]string, map[string]uint64) {
 	prvIDStr := common.PRVCoinID.String()
@@ -460,6 +461,8 @@ func (blockchain *BlockChain) buildInstsForSortedTradableActions(
 			tradeAction.Meta.TraderAddressStr,
 			tradeAction.TxReqID,
 			tradingFeeByPair,
+			tradeAction.Deadline,
+			beaconTimestamp,
 		)
 		if err != nil {
 			Logger.log.Error(err)
@@ -501,6 +504,12 @@ func (blockchain *BlockChain) buildInstsForUntradableActions(
 	return untradableInsts
 }
 
+// isPDETradeExpired - whether a trade with deadline can not be executed in the beacon block after beaconHeight,
+// beaconTimestamp being the timestamp of the block at beaconHeight
+func isPDETradeExpired(deadline *metadata.PDETradeDeadline, beaconHeight uint64, beaconTimestamp int64) bool {
+	return deadline != nil && deadline.IsExpired(beaconHeight+1, beaconTimestamp)
+}
+
 func buildCrossPoolTradeRefundInst(
 	traderAddressStr string,
 	tokenIDStr string,
@@ -537,7 +546,30 @@ func (blockchain *BlockChain) buildInstructionsForPDECrossPoolTrade(
 	traderAddressStr string,
 	txReqID common.Hash,
 	tradingFeeByPair map[string]uint64,
+	deadline *metadata.PDETradeDeadline,
+	beaconTimestamp int64,
 ) ([][]string, error) {
+	if isPDETradeExpired(deadline, beaconHeight, beaconTimestamp) {
+		refundTradingFeeInst := buildCrossPoolTradeRefundInst(
+			traderAddressStr,
+			common.PRVCoinID.String(),
+			tradingFee,
+			metaType,
+			common.PDECrossPoolTradeExpiredFeeRefundChainStatus,
+			shardID,
+			txReqID,
+		)
+		refundSellingTokenInst := buildCrossPoolTradeRefundInst(
+			traderAddressStr,
+			sequentialTrades[0].tokenIDToSellStr,
+			sequentialTrades[0].sellAmount,
+			metaType,
+			common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus,
+			shardID,
+			txReqID,
+		)
+		return [][]string{refundTradingFeeInst, refundSellingTokenInst}, nil
+	}
 	if currentPDEState == nil ||
 		(currentPDEState.PDEPoolPairs == nil || len(currentPDEState.PDEPoolPairs) == 0) {
 		refundTradingFeeInst := buildCrossPoolTradeRefundInst(
@@ -668,7 +700,22 @@ func (blockchain *BlockChain) buildInstructionsForPDETrade(
 	metaType int,
 	currentPDEState *CurrentPDEState,
 	beaconHeight uint64,
+	beaconTimestamp int64,
 ) ([][]string, error) {
+	var pdeTradeReqAction metadata.PDETradeRequestAction
+	contentBytes, err := base64.StdEncoding.DecodeString(contentStr)
+	if err == nil {
+		err = json.Unmarshal(contentBytes, &pdeTradeReqAction)
+	}
+	if err == nil && isPDETradeExpired(pdeTradeReqAction.Deadline, beaconHeight, beaconTimestamp) {
+		inst := []string{
+			strconv.Itoa(metaType),
+			strconv.Itoa(int(shardID)),
+			common.PDETradeExpiredRefundChainStatus,
+			contentStr,
+		}
+		return [][]string{inst}, nil
+	}
 	if currentPDEState == nil ||
 		(currentPDEState.PDEPoolPairs == nil || len(currentPDEState.PDEPoolPairs) == 0) {
 		inst := []string{
@@ -679,15 +726,8 @@ func (blockchain *BlockChain) buildInstructionsForPDETrade(
 		}
 		return [][]string{inst}, nil
 	}
-	contentBytes, err := base64.StdEncoding.DecodeString(contentStr)
-	if err != nil {
-		Logger.log.Errorf("ERROR: an error occured while decoding content string of pde trade instruction: %+v", err)
-		return [][]string{}, nil
-	}
-	var pdeTradeReqAction metadata.PDETradeRequestAction
-	err = json.Unmarshal(contentBytes, &pdeTradeReqAction)
 	if err != nil {
-		Logger.log.Errorf("ERROR: an error occured while unmarshaling pde trade instruction: %+v", err)
+		Logger.log.Errorf("ERROR: an error occured while parsing content string of pde trade instruction: %+v", err)
 		return [][]string{}, nil
 	}
 	pairKey := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, pdeTradeReqAction.Meta.TokenIDToBuyStr, pdeTradeReqAction.Meta.TokenIDToSellStr))
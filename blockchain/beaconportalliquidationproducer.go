@@ -651,6 +651,10 @@ func (blockchain *BlockChain) buildInstForLiquidationByExchangeRatesV3(
 		Logger.log.Errorf("[LIQUIDATIONBYRATES] Final exchange rate is empty")
 		return [][]string{}, nil
 	}
+	if IsExchangeRateOracleStale(currentPortalState, beaconHeight, portalParams) {
+		Logger.log.Warnf("[LIQUIDATIONBYRATES] Exchange rates lost feeder quorum, liquidation by rates is paused at beacon height %v", beaconHeight)
+		return [][]string{}, nil
+	}
 
 	insts := [][]string{}
 	custodianPoolState := currentPortalState.CustodianPoolState
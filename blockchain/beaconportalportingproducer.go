@@ -349,7 +349,7 @@ func (p *portalRequestPTokenProcessor) buildNewInsts(
 		return [][]string{rejectInst}, nil
 	}
 
-	portalTokenProcessor := bc.config.ChainParams.PortalTokens[meta.TokenID]
+	portalTokenProcessor := bc.GetPortalToken(beaconHeight+1, meta.TokenID)
 	if portalTokenProcessor == nil {
 		Logger.log.Errorf("TokenID is not supported currently on Portal")
 		return [][]string{rejectInst}, nil
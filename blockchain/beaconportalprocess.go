@@ -134,7 +134,7 @@ func (blockchain *BlockChain) processPortalInstructions(portalStateDB *statedb.S
 	}
 
 	//save final exchangeRates
-	blockchain.pickExchangesRatesFinal(currentPortalState)
+	blockchain.pickExchangesRatesFinal(currentPortalState, beaconHeight, portalParams)
 
 	// update info of bridge portal token
 	for _, updatingInfo := range updatingInfoByTokenID {
@@ -240,7 +240,28 @@ func (blockchain *BlockChain) processPortalExchangeRates(
 	return nil
 }
 
-func (blockchain *BlockChain) pickExchangesRatesFinal(currentPortalState *CurrentPortalState) {
+func (blockchain *BlockChain) pickExchangesRatesFinal(currentPortalState *CurrentPortalState, beaconHeight uint64, portalParams PortalParams) {
+	updateFinalExchangeRates := currentPortalState.FinalExchangeRatesState.Rates()
+	if updateFinalExchangeRates == nil {
+		updateFinalExchangeRates = map[string]statedb.FinalExchangeRatesDetail{}
+	}
+
+	if isExchangeRateOracleEnabled(portalParams) {
+		if currentPortalState.ExchangeRateOracle == nil {
+			currentPortalState.ExchangeRateOracle = metadata.NewExchangeRateOracleState()
+		}
+		addExchangeRateSubmissions(currentPortalState.ExchangeRateOracle, currentPortalState.ExchangeRatesRequests, beaconHeight+1)
+		pickExchangeRatesFromOracle(
+			currentPortalState.ExchangeRateOracle,
+			updateFinalExchangeRates,
+			blockchain.GetPortalFeederAddresses(beaconHeight+1),
+			beaconHeight+1,
+			portalParams,
+		)
+		currentPortalState.FinalExchangeRatesState = statedb.NewFinalExchangeRatesStateWithValue(updateFinalExchangeRates)
+		return
+	}
+
 	// sort exchange rate requests by rate
 	sumRates := map[string][]uint64{}
 
@@ -250,10 +271,6 @@ func (blockchain *BlockChain) pickExchangesRatesFinal(currentPortalState *Curren
 		}
 	}
 
-	updateFinalExchangeRates := currentPortalState.FinalExchangeRatesState.Rates()
-	if updateFinalExchangeRates == nil {
-		updateFinalExchangeRates = map[string]statedb.FinalExchangeRatesDetail{}
-	}
 	for tokenID, rates := range sumRates {
 		// sort rates
 		sort.SliceStable(rates, func(i, j int) bool {
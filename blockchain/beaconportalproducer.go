@@ -58,30 +58,31 @@ func (p *portalExchangeRateProcessor) buildNewInsts(
 	}
 	metaType := actionData.Meta.Type
 
+	// the feeder set could be changed after the request was included in shard block
+	isFeeder, _ := common.SliceExists(bc.GetPortalFeederAddresses(beaconHeight+1), actionData.Meta.SenderAddress)
+
 	//check key from db
-	if currentPortalState.ExchangeRatesRequests != nil {
-		_, ok := currentPortalState.ExchangeRatesRequests[actionData.TxReqID.String()]
-		if ok {
-			Logger.log.Errorf("ERROR: exchange rates key is duplicated")
-
-			portalExchangeRatesContent := metadata.PortalExchangeRatesContent{
-				SenderAddress: actionData.Meta.SenderAddress,
-				Rates:         actionData.Meta.Rates,
-				TxReqID:       actionData.TxReqID,
-				LockTime:      actionData.LockTime,
-			}
-
-			portalExchangeRatesContentBytes, _ := json.Marshal(portalExchangeRatesContent)
-
-			inst := []string{
-				strconv.Itoa(metaType),
-				strconv.Itoa(int(shardID)),
-				common.PortalExchangeRatesRejectedChainStatus,
-				string(portalExchangeRatesContentBytes),
-			}
-
-			return [][]string{inst}, nil
+	_, isDuplicated := currentPortalState.ExchangeRatesRequests[actionData.TxReqID.String()]
+	if isDuplicated || !isFeeder {
+		Logger.log.Errorf("ERROR: exchange rates key is duplicated or sender %v is not a feeder", actionData.Meta.SenderAddress)
+
+		portalExchangeRatesContent := metadata.PortalExchangeRatesContent{
+			SenderAddress: actionData.Meta.SenderAddress,
+			Rates:         actionData.Meta.Rates,
+			TxReqID:       actionData.TxReqID,
+			LockTime:      actionData.LockTime,
+		}
+
+		portalExchangeRatesContentBytes, _ := json.Marshal(portalExchangeRatesContent)
+
+		inst := []string{
+			strconv.Itoa(metaType),
+			strconv.Itoa(int(shardID)),
+			common.PortalExchangeRatesRejectedChainStatus,
+			string(portalExchangeRatesContentBytes),
 		}
+
+		return [][]string{inst}, nil
 	}
 
 	//success
@@ -613,7 +613,7 @@ func (p *portalRequestUnlockCollateralProcessor) buildNewInsts(
 		return [][]string{rejectInst}, nil
 	}
 
-	portalTokenProcessor := bc.config.ChainParams.PortalTokens[meta.TokenID]
+	portalTokenProcessor := bc.GetPortalToken(beaconHeight+1, meta.TokenID)
 	if portalTokenProcessor == nil {
 		Logger.log.Errorf("TokenID %v is not supported currently on Portal", meta.TokenID)
 		return [][]string{rejectInst}, nil
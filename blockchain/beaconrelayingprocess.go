@@ -25,12 +25,15 @@ func (blockchain *BlockChain) processRelayingInstructions(block *BeaconBlock) er
 		if len(inst) < 4 {
 			continue // Not error, just not relaying instruction
 		}
-		var err error
-		switch inst[0] {
-		//case strconv.Itoa(metadata.RelayingBNBHeaderMeta):
+		metaType, err := strconv.Atoi(inst[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		//case metaType == metadata.RelayingBNBHeaderMeta:
 		//	err = blockchain.processRelayingBNBHeaderInst(inst, relayingState)
-		case strconv.Itoa(metadata.RelayingBTCHeaderMeta):
-			err = blockchain.processRelayingBTCHeaderInst(inst, relayingState)
+		case metaType == metadata.RelayingBTCHeaderMeta || metadata.IsRelayingUTXOHeaderMeta(metaType):
+			err = blockchain.processRelayingUTXOHeaderInst(inst, relayingState.UTXOHeaderChains[metaType])
 		}
 		if err != nil {
 			Logger.log.Error(err)
@@ -45,14 +48,13 @@ func (blockchain *BlockChain) processRelayingInstructions(block *BeaconBlock) er
 	return nil
 }
 
-func (blockchain *BlockChain) processRelayingBTCHeaderInst(
+func (blockchain *BlockChain) processRelayingUTXOHeaderInst(
 	instruction []string,
-	relayingState *RelayingHeaderChainState,
+	btcHeaderChain *btcrelaying.BlockChain,
 ) error {
-	Logger.log.Info("[BTC Relaying] - Processing processRelayingBTCHeaderInst...")
-	btcHeaderChain := relayingState.BTCHeaderChain
+	Logger.log.Info("[UTXO Relaying] - Processing processRelayingUTXOHeaderInst...")
 	if btcHeaderChain == nil {
-		return errors.New("[processRelayingBTCHeaderInst] BTC Header chain instance should not be nil")
+		return errors.New("[processRelayingUTXOHeaderInst] UTXO Header chain instance should not be nil")
 	}
 
 	if len(instruction) != 4 {
@@ -41,6 +41,9 @@ func (blockchain *BlockChain) collectStatefulActions(
 			metadata.PDEFeeWithdrawalRequestMeta,
 			metadata.PDEPRVRequiredContributionRequestMeta,
 			metadata.PDECrossPoolTradeRequestMeta,
+			metadata.PDERoutedTradeRequestMeta,
+			metadata.PDETradeRequestMetaV2,
+			metadata.PDECrossPoolTradeRequestMetaV2,
 			metadata.PortalCustodianDepositMeta,
 			metadata.PortalRequestPortingMeta,
 			metadata.PortalUserRequestPTokenMeta,
@@ -66,11 +69,14 @@ func (blockchain *BlockChain) collectStatefulActions(
 			metadata.PortalCustodianTopupMetaV3,
 			metadata.PortalTopUpWaitingPortingRequestMetaV3,
 			metadata.PortalRequestPortingMetaV3,
-			metadata.PortalRedeemRequestMetaV3:
+			metadata.PortalRedeemRequestMetaV3,
+			metadata.ReportEquivocationMeta:
 			statefulInsts = append(statefulInsts, inst)
 
 		default:
-			continue
+			if metadata.IsRelayingUTXOHeaderMeta(metaType) {
+				statefulInsts = append(statefulInsts, inst)
+			}
 		}
 	}
 	return statefulInsts
@@ -107,7 +113,7 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 		Logger.log.Error(err)
 	}
 
-	pm := NewPortalManager()
+	pm := NewPortalManager(blockchain.config.ChainParams.PortalTokens)
 	relayingHeaderState, err := blockchain.InitRelayingHeaderChainStateFromDB()
 	if err != nil {
 		Logger.log.Error(err)
@@ -125,6 +131,7 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 	pdePRVRequiredContributionActionsByShardID := map[byte][][]string{}
 	pdeTradeActionsByShardID := map[byte][][]string{}
 	pdeCrossPoolTradeActionsByShardID := map[byte][][]string{}
+	pdeRoutedTradeActionsByShardID := map[byte][][]string{}
 	pdeWithdrawalActionsByShardID := map[byte][][]string{}
 	pdeFeeWithdrawalActionsByShardID := map[byte][][]string{}
 
@@ -150,6 +157,9 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 			case metadata.IssuingETHRequestMeta:
 				newInst, err = blockchain.buildInstructionsForIssuingETHReq(beaconBestState, featureStateDB, contentStr, shardID, metaType, accumulatedValues)
 
+			case metadata.ReportEquivocationMeta:
+				newInst, err = blockchain.buildInstructionsForReportEquivocation(beaconBestState, beaconBestState.slashStateDB, contentStr, shardID, metaType, beaconHeight, accumulatedValues)
+
 			case metadata.PDEContributionMeta:
 				pdeContributionActionsByShardID = groupPDEActionsByShardID(
 					pdeContributionActionsByShardID,
@@ -162,18 +172,33 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 					action,
 					shardID,
 				)
-			case metadata.PDETradeRequestMeta:
-				pdeTradeActionsByShardID = groupPDEActionsByShardID(
-					pdeTradeActionsByShardID,
-					action,
-					shardID,
-				)
-			case metadata.PDECrossPoolTradeRequestMeta:
-				pdeCrossPoolTradeActionsByShardID = groupPDEActionsByShardID(
-					pdeCrossPoolTradeActionsByShardID,
-					action,
-					shardID,
-				)
+			case metadata.PDETradeRequestMeta, metadata.PDETradeRequestMetaV2:
+				// v2 is ignored before the break point, like nodes which don't know trade deadlines
+				if metaType == metadata.PDETradeRequestMeta || beaconHeight >= blockchain.GetBCHeightBreakPointPDETradeV2() {
+					pdeTradeActionsByShardID = groupPDEActionsByShardID(
+						pdeTradeActionsByShardID,
+						action,
+						shardID,
+					)
+				}
+			case metadata.PDECrossPoolTradeRequestMeta, metadata.PDECrossPoolTradeRequestMetaV2:
+				// v2 is ignored before the break point, like nodes which don't know trade deadlines
+				if metaType == metadata.PDECrossPoolTradeRequestMeta || beaconHeight >= blockchain.GetBCHeightBreakPointPDETradeV2() {
+					pdeCrossPoolTradeActionsByShardID = groupPDEActionsByShardID(
+						pdeCrossPoolTradeActionsByShardID,
+						action,
+						shardID,
+					)
+				}
+			case metadata.PDERoutedTradeRequestMeta:
+				// ignored before the break point, like nodes which don't know routed trades
+				if beaconHeight >= blockchain.GetBCHeightBreakPointPDERoutedTrade() {
+					pdeRoutedTradeActionsByShardID = groupPDEActionsByShardID(
+						pdeRoutedTradeActionsByShardID,
+						action,
+						shardID,
+					)
+				}
 			case metadata.PDEWithdrawalRequestMeta:
 				pdeWithdrawalActionsByShardID = groupPDEActionsByShardID(
 					pdeWithdrawalActionsByShardID,
@@ -223,12 +248,13 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 			case metadata.PortalCustodianWithdrawRequestMetaV3:
 				pm.portalInstructions[metadata.PortalCustodianWithdrawRequestMetaV3].putAction(action, shardID)
 
-			case metadata.RelayingBNBHeaderMeta:
-				pm.relayingChains[metadata.RelayingBNBHeaderMeta].putAction(action)
-			case metadata.RelayingBTCHeaderMeta:
-				pm.relayingChains[metadata.RelayingBTCHeaderMeta].putAction(action)
 			default:
-				continue
+				// header relaying of portal tokens in the registry
+				rc, ok := pm.relayingChains[metaType]
+				if !ok {
+					continue
+				}
+				rc.putAction(action)
 			}
 			if err != nil {
 				Logger.log.Error(err)
@@ -241,11 +267,12 @@ func (blockchain *BlockChain) buildStatefulInstructions(
 	}
 
 	pdeInsts, err := blockchain.handlePDEInsts(
-		beaconHeight-1, currentPDEState,
+		beaconHeight-1, beaconBestState.BestBlock.Header.Timestamp, currentPDEState,
 		pdeContributionActionsByShardID,
 		pdePRVRequiredContributionActionsByShardID,
 		pdeTradeActionsByShardID,
 		pdeCrossPoolTradeActionsByShardID,
+		pdeRoutedTradeActionsByShardID,
 		pdeWithdrawalActionsByShardID,
 		pdeFeeWithdrawalActionsByShardID,
 	)
@@ -499,11 +526,13 @@ func sortPDETradeInstsByFee(
 
 func (blockchain *BlockChain) handlePDEInsts(
 	beaconHeight uint64,
+	beaconTimestamp int64,
 	currentPDEState *CurrentPDEState,
 	pdeContributionActionsByShardID map[byte][][]string,
 	pdePRVRequiredContributionActionsByShardID map[byte][][]string,
 	pdeTradeActionsByShardID map[byte][][]string,
 	pdeCrossPoolTradeActionsByShardID map[byte][][]string,
+	pdeRoutedTradeActionsByShardID map[byte][][]string,
 	pdeWithdrawalActionsByShardID map[byte][][]string,
 	pdeFeeWithdrawalActionsByShardID map[byte][][]string,
 ) ([][]string, error) {
@@ -540,7 +569,7 @@ func (blockchain *BlockChain) handlePDEInsts(
 	for _, tradeAction := range sortedTradesActions {
 		actionContentBytes, _ := json.Marshal(tradeAction)
 		actionContentBase64Str := base64.StdEncoding.EncodeToString(actionContentBytes)
-		newInst, err := blockchain.buildInstructionsForPDETrade(actionContentBase64Str, tradeAction.ShardID, metadata.PDETradeRequestMeta, currentPDEState, beaconHeight)
+		newInst, err := blockchain.buildInstructionsForPDETrade(actionContentBase64Str, tradeAction.ShardID, metadata.PDETradeRequestMeta, currentPDEState, beaconHeight, beaconTimestamp)
 		if err != nil {
 			Logger.log.Error(err)
 			continue
@@ -556,11 +585,20 @@ func (blockchain *BlockChain) handlePDEInsts(
 		currentPDEState,
 		pdeCrossPoolTradeActionsByShardID,
 	)
-	tradableInsts, tradingFeeByPair := blockchain.buildInstsForSortedTradableActions(currentPDEState, beaconHeight, sortedTradableActions)
+	tradableInsts, tradingFeeByPair := blockchain.buildInstsForSortedTradableActions(currentPDEState, beaconHeight, beaconTimestamp, sortedTradableActions)
 	untradableInsts := blockchain.buildInstsForUntradableActions(untradableActions)
 	instructions = append(instructions, tradableInsts...)
 	instructions = append(instructions, untradableInsts...)
 
+	// handle routed trade, fees go to the same distribution as cross pool trades
+	sortedRoutedActions, untradableRoutedActions := categorizeNSortPDERoutedTradeActions(
+		beaconHeight,
+		currentPDEState,
+		pdeRoutedTradeActionsByShardID,
+	)
+	instructions = append(instructions, blockchain.buildInstsForSortedRoutedTradeActions(currentPDEState, beaconHeight, sortedRoutedActions, tradingFeeByPair)...)
+	instructions = append(instructions, blockchain.buildInstsForUntradableRoutedTradeActions(untradableRoutedActions)...)
+
 	// calculate and build instruction for trading fees distribution
 	tradingFeesDistInst := blockchain.buildInstForTradingFeesDist(currentPDEState, beaconHeight, tradingFeeByPair)
 	if len(tradingFeesDistInst) > 0 {
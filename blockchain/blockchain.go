@@ -41,6 +41,7 @@ type BlockChain struct {
 // Config is a descriptor which specifies the blockchain instance configuration.
 type Config struct {
 	BTCChain      *btcrelaying.BlockChain
+	UTXOChains    map[int]*btcrelaying.BlockChain // header chains of UTXO portal tokens other than BTC, by relaying header meta type
 	BNBChainState *bnbrelaying.BNBChainState
 	DataBase      map[int]incdb.Database
 	MemCache      *memcache.MemoryCache
@@ -667,6 +668,19 @@ func (blockchain *BlockChain) GetSupportedCollateralTokenIDs(beaconHeight uint64
 	return tokenIDs
 }
 
+// GetPortalFeederAddresses returns feeders allowed to submit exchange rates at beaconHeight,
+// mempool validates with beaconHeight 0, the best beacon height is used then
+func (blockchain *BlockChain) GetPortalFeederAddresses(beaconHeight uint64) []string {
+	if beaconHeight == 0 {
+		beaconHeight = blockchain.GetBeaconBestState().GetHeight()
+	}
+	portalParams := blockchain.GetPortalParams(beaconHeight)
+	if len(portalParams.PortalFeeders) > 0 {
+		return portalParams.PortalFeeders
+	}
+	return []string{blockchain.GetConfig().ChainParams.PortalFeederAddress}
+}
+
 func (blockchain *BlockChain) GetSupportedCollateralInfo(beaconHeight uint64) []PortalCollateral {
 	portalParams := blockchain.GetPortalParams(beaconHeight)
 	return portalParams.SupportedCollateralTokens
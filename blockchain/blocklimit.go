@@ -0,0 +1,75 @@
+package blockchain
+
+import "fmt"
+
+func checkSizeLimit(field string, size int, limit int) error {
+	if size > limit {
+		return fmt.Errorf("%v has %v items, exceeds limit %v", field, size, limit)
+	}
+	return nil
+}
+
+func checkInstructionsLimit(instructions [][]string) error {
+	if err := checkSizeLimit("Instructions", len(instructions), MaxBlockInstructions); err != nil {
+		return err
+	}
+	for i, inst := range instructions {
+		if err := checkSizeLimit(fmt.Sprintf("Instruction %v", i), len(inst), MaxInstructionElements); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (shardHeader *ShardHeader) checkSizeLimit() error {
+	if err := checkSizeLimit("TotalTxsFee", len(shardHeader.TotalTxsFee), MaxTotalTxsFeeTokens); err != nil {
+		return err
+	}
+	return checkSizeLimit("CrossShardBitMap", len(shardHeader.CrossShardBitMap), MaxCrossShardBitMapSize)
+}
+
+func (shardBlock *ShardBlock) checkSizeLimit() error {
+	if err := checkSizeLimit("ValidationData", len(shardBlock.ValidationData), MaxValidationDataSize); err != nil {
+		return err
+	}
+	if err := shardBlock.Header.checkSizeLimit(); err != nil {
+		return err
+	}
+	if err := checkInstructionsLimit(shardBlock.Body.Instructions); err != nil {
+		return err
+	}
+	for shardID, crossTxs := range shardBlock.Body.CrossTransactions {
+		if err := checkSizeLimit(fmt.Sprintf("CrossTransactions of shard %v", shardID), len(crossTxs), MaxCrossTransactionsOfShard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (beaconBlock *BeaconBlock) checkSizeLimit() error {
+	if err := checkSizeLimit("ValidationData", len(beaconBlock.ValidationData), MaxValidationDataSize); err != nil {
+		return err
+	}
+	if err := checkInstructionsLimit(beaconBlock.Body.Instructions); err != nil {
+		return err
+	}
+	for shardID, states := range beaconBlock.Body.ShardState {
+		if err := checkSizeLimit(fmt.Sprintf("ShardState of shard %v", shardID), len(states), MaxBeaconShardStatesOfShard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (crossShardBlock *CrossShardBlock) checkSizeLimit() error {
+	if err := checkSizeLimit("ValidationData", len(crossShardBlock.ValidationData), MaxValidationDataSize); err != nil {
+		return err
+	}
+	if err := crossShardBlock.Header.checkSizeLimit(); err != nil {
+		return err
+	}
+	if err := checkSizeLimit("MerklePathShard", len(crossShardBlock.MerklePathShard), MaxMerklePathLength); err != nil {
+		return err
+	}
+	return checkSizeLimit("CrossOutputCoin", len(crossShardBlock.CrossOutputCoin), MaxCrossOutputCoins)
+}
@@ -0,0 +1,40 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Blocks with a field exceeding its limit are rejected when unmarshalled
+func TestBlockSizeLimit(t *testing.T) {
+	insts := make([][]string, MaxBlockInstructions+1)
+	for i := range insts {
+		insts[i] = []string{}
+	}
+	beaconBlock := &BeaconBlock{Body: BeaconBody{Instructions: insts}}
+	data, err := json.Marshal(beaconBlock)
+	assert.Nil(t, err)
+	err = json.Unmarshal(data, &BeaconBlock{})
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "exceeds limit"))
+
+	beaconBlock.Body.Instructions = insts[:MaxBlockInstructions]
+	data, err = json.Marshal(beaconBlock)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, &BeaconBlock{}))
+
+	txs := strings.Repeat(`{"Type":"n"},`, MaxShardBlockTxs+1)
+	data = []byte(`{"Body":{"Transactions":[` + strings.TrimSuffix(txs, ",") + `]}}`)
+	err = json.Unmarshal(data, &ShardBlock{})
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "exceeds limit"))
+
+	crossShardBlock := &CrossShardBlock{MerklePathShard: make([]common.Hash, MaxMerklePathLength+1)}
+	data, err = json.Marshal(crossShardBlock)
+	assert.Nil(t, err)
+	assert.NotNil(t, json.Unmarshal(data, &CrossShardBlock{}))
+}
@@ -29,12 +29,30 @@ const (
 	DefaultMaxBlockSyncTime       = 30 * time.Second // in second
 )
 
+// Limits of block fields decoded from network, a block exceeding them is rejected when it is unmarshalled.
+// Payload size of a message is limited by wire, these limits bound the number of items of each field
+const (
+	MaxValidationDataSize       = 128 * 1024
+	MaxBlockInstructions        = 10000
+	MaxInstructionElements      = 1000
+	MaxShardBlockTxs            = 10000
+	MaxTotalTxsFeeTokens        = 1000
+	MaxCrossShardBitMapSize     = 256
+	MaxCrossTransactionsOfShard = 1000
+	MaxBeaconShardStatesOfShard = 1000
+	MaxMerklePathLength         = 64
+	MaxCrossOutputCoins         = 10000
+)
+
 // burning addresses
 const (
 	burningAddress  = "15pABFiJVeh9D5uiQEhQX4SVibGGbdAVipQxBdxkmDqAJaoG1EdFKHBrNfs"
 	burningAddress2 = "12RxahVABnAVCGP3LGwCn8jkQxgw7z1x14wztHzn455TTVpi1wBq9YGwkRMQg3J4e657AbAnCvYCJSdA9czBUNuCKwGSRQt55Xwz8WA"
 )
 
+// BNBRelayingDataFolderName - folder of the relayed bnb header chain in node data directory
+const BNBRelayingDataFolderName = "bnbrelayingv3"
+
 // CONSTANT for network MAINNET
 const (
 	// ------------- Mainnet ---------------------------------------------
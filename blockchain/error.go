@@ -178,6 +178,14 @@ const (
 	GetShardBlockHeightByHashError
 	GetShardBlockByHashError
 	ResponsedTransactionFromBeaconInstructionsError
+	ProcessEquivocationSlashError
+	PortalProofFormatError
+	PortalProofHeaderNotRelayedError
+	PortalProofConfirmationsError
+	PortalProofTxNotInBlockError
+	PortalProofMemoError
+	PortalProofReceiverError
+	PortalProofAmountError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -346,6 +354,14 @@ var ErrCodeMessage = map[int]struct {
 	GetListOutputCoinsByKeysetError:                   {-2000, "Get List Output Coins By Keyset Error"},
 	GetTotalLockedCollateralError:                     {-3000, "Get Total Locked Collateral Error"},
 	ResponsedTransactionFromBeaconInstructionsError:   {-3100, "Build Transaction Response From Beacon Instructions Error"},
+	ProcessEquivocationSlashError:                     {-3200, "Process Equivocation Slash Error"},
+	PortalProofFormatError:                            {-3300, "Portal proof can not be parsed"},
+	PortalProofHeaderNotRelayedError:                  {-3301, "Header of the external block is not relayed yet"},
+	PortalProofConfirmationsError:                     {-3302, "External block does not have enough confirmations"},
+	PortalProofTxNotInBlockError:                      {-3303, "External tx is not in the relayed block"},
+	PortalProofMemoError:                              {-3304, "External tx memo does not match the request"},
+	PortalProofReceiverError:                          {-3305, "External tx does not pay the expected remote address"},
+	PortalProofAmountError:                            {-3306, "External tx amount is less than the expected amount"},
 }
 
 type BlockChainError struct {
@@ -358,6 +374,10 @@ func (e BlockChainError) Error() string {
 	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
 }
 
+func (e BlockChainError) GetError() error {
+	return e.err
+}
+
 func NewBlockChainError(key int, err error) *BlockChainError {
 	return &BlockChainError{
 		Code:    ErrCodeMessage[key].Code,
@@ -365,3 +385,26 @@ func NewBlockChainError(key int, err error) *BlockChainError {
 		err:     errors.Wrap(err, ErrCodeMessage[key].message),
 	}
 }
+
+// invalidBlockErrors - errors of a block content which cannot become valid later,
+// unlike a block linking to an unknown view or waiting for beacon blocks
+var invalidBlockErrors = map[int]bool{
+	ErrCodeMessage[WrongShardIDError].Code:                  true,
+	ErrCodeMessage[TransactionFromNewBlockError].Code:       true,
+	ErrCodeMessage[TransactionRootHashError].Code:           true,
+	ErrCodeMessage[ShardTransactionRootHashError].Code:      true,
+	ErrCodeMessage[CrossShardTransactionRootHashError].Code: true,
+	ErrCodeMessage[WrongBlockTotalFeeError].Code:            true,
+	ErrCodeMessage[InstructionsHashError].Code:              true,
+	ErrCodeMessage[InstructionMerkleRootError].Code:         true,
+	ErrCodeMessage[CrossTransactionHashError].Code:          true,
+}
+
+// IsInvalidBlockError - true if err means the block itself is invalid, so the peer which sent it can be banned
+func IsInvalidBlockError(err error) bool {
+	var bcErr *BlockChainError
+	if !errors.As(err, &bcErr) {
+		return false
+	}
+	return invalidBlockErrors[bcErr.Code]
+}
@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+var _ = func() (_ struct{}) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	transaction.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	metadata.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	return
+}()
+
+// addMainnetSeeds - add blocks dumped from mainnet into testdata/mainnet, see README there
+func addMainnetSeeds(f *testing.F, pattern string) {
+	files, err := filepath.Glob(filepath.Join("testdata", "mainnet", pattern))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+}
+
+func addJSONSeed(f *testing.F, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+}
+
+func FuzzShardBlockUnmarshalJSON(f *testing.F) {
+	addMainnetSeeds(f, "shardblock-*.json")
+	addJSONSeed(f, &ShardBlock{
+		ValidationData: `{"ProducerBLSSig":"c2ln","ValidatiorsIdx":[0,1]}`,
+		Header: ShardHeader{
+			ShardID:      1,
+			Version:      2,
+			Height:       100,
+			Round:        1,
+			TxRoot:       common.HashH([]byte("txroot")),
+			TotalTxsFee:  map[common.Hash]uint64{common.PRVCoinID: 10},
+			BeaconHeight: 50,
+		},
+		Body: ShardBody{
+			Instructions:      [][]string{{"113", "1", "accepted"}},
+			CrossTransactions: map[byte][]CrossTransaction{2: {{BlockHeight: 3}}},
+			Transactions: []metadata.Transaction{
+				&transaction.Tx{Version: 1, Type: common.TxNormalType, Fee: 10, Info: []byte("info")},
+				&transaction.TxCustomTokenPrivacy{Tx: transaction.Tx{Version: 1, Type: common.TxCustomTokenPrivacyType}},
+			},
+		},
+	})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		block := &ShardBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return
+		}
+		block.Hash()
+	})
+}
+
+func FuzzBeaconBlockUnmarshalJSON(f *testing.F) {
+	addMainnetSeeds(f, "beaconblock-*.json")
+	addJSONSeed(f, &BeaconBlock{
+		ValidationData: `{"ProducerBLSSig":"c2ln","ValidatiorsIdx":[0,1]}`,
+		Header:         BeaconHeader{Version: 2, Height: 100, Epoch: 1, Round: 1},
+		Body:           BeaconBody{Instructions: [][]string{{"stake", "a", "shard"}}, ShardState: map[byte][]ShardState{0: {{Height: 3}}}},
+	})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		block := &BeaconBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return
+		}
+		block.Hash()
+	})
+}
+
+func FuzzCrossShardBlockUnmarshalJSON(f *testing.F) {
+	addMainnetSeeds(f, "crossshardblock-*.json")
+	addJSONSeed(f, &CrossShardBlock{
+		ValidationData:  `{"ProducerBLSSig":"c2ln"}`,
+		Header:          ShardHeader{ShardID: 1, Height: 100},
+		ToShardID:       2,
+		MerklePathShard: []common.Hash{common.HashH([]byte("merkle"))},
+	})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		block := &CrossShardBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return
+		}
+		block.Hash()
+	})
+}
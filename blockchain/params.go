@@ -1,15 +1,23 @@
 package blockchain
 
 import (
+	"math"
 	"time"
 
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
 )
 
 type SlashLevel struct {
 	MinRange        uint8
 	PunishedEpoches uint8
 }
+
+// EquivocationPunishment - punishment for a validator caught signing conflicting messages
+type EquivocationPunishment struct {
+	PunishedEpoches uint8
+	ForceUnstake    bool
+}
 type PortalCollateral struct {
 	ExternalTokenID string
 	Decimal         uint8
@@ -30,6 +38,9 @@ type PortalParams struct {
 	SupportedCollateralTokens            []PortalCollateral
 	MinPortalFee                         uint64 // nano PRV
 	MinUnlockOverRateCollaterals         uint64
+	PortalFeeders                        []string // incognito addresses allowed to feed exchange rates, empty means PortalFeederAddress only
+	ExchangeRateWindow                   uint64   // number of beacon blocks feeder submissions are kept in, 0 picks rates from a single block
+	MinExchangeRateFeeders               uint64   // distinct feeders required within the window to update a final rate
 }
 
 /*
@@ -59,6 +70,7 @@ type Params struct {
 	Epoch                            uint64
 	RandomTime                       uint64
 	SlashLevels                      []SlashLevel
+	EquivocationPunishment           EquivocationPunishment
 	EthContractAddressStr            string // smart contract of ETH for bridge
 	Offset                           int    // default offset for swap policy, is used for cases that good producers length is less than max committee size
 	SwapOffset                       int    // is used for case that good producers length is equal to max committee size
@@ -77,7 +89,7 @@ type Params struct {
 	BNBFullNodeHost                  string
 	BNBFullNodePort                  string
 	PortalParams                     map[uint64]PortalParams
-	PortalTokens                     map[string]PortalTokenProcessor
+	PortalTokens                     map[string]PortalTokenProcessor // registry of portal tokens by incognito token ID
 	PortalFeederAddress              string
 	EpochBreakPointSwapNewKey        []uint64
 	IsBackup                         bool
@@ -87,6 +99,9 @@ type Params struct {
 	BCHeightBreakPointNewZKP         uint64
 	PortalETHContractAddressStr      string // smart contract of ETH for portal
 	BCHeightBreakPointPortalV3       uint64
+	BCHeightBreakPointEquivocation   uint64 // equivocation reports are accepted and slashed from this beacon height
+	BCHeightBreakPointPDERoutedTrade uint64 // pde routed trade requests are accepted from this beacon height
+	BCHeightBreakPointPDETradeV2     uint64 // pde trade requests with deadline (v2) are accepted from this beacon height
 }
 
 type GenesisParams struct {
@@ -114,16 +129,23 @@ var genesisParamsTestnet2New *GenesisParams
 var genesisParamsMainnetNew *GenesisParams
 var GenesisParam *GenesisParams
 
+// portal tokens enabled from genesis, a new token is added with ActivatedBeaconHeight
+// and a UTXO token other than BTC also sets its header chain and a relaying header meta type in the reserved range
 func initPortalTokensForTestNet() map[string]PortalTokenProcessor {
 	return map[string]PortalTokenProcessor{
-		common.PortalBTCIDStr: &PortalBTCTokenProcessor{
-			&PortalToken{
-				ChainID: TestnetBTCChainID,
+		common.PortalBTCIDStr: &PortalUTXOTokenProcessor{
+			PortalToken: &PortalToken{
+				ChainID:          TestnetBTCChainID,
+				MinTokenAmount:   10,
+				ExternalDecimals: 8,
 			},
+			RelayingHeaderMetaType: metadata.RelayingBTCHeaderMeta,
 		},
 		common.PortalBNBIDStr: &PortalBNBTokenProcessor{
 			&PortalToken{
-				ChainID: TestnetBNBChainID,
+				ChainID:          TestnetBNBChainID,
+				MinTokenAmount:   10,
+				ExternalDecimals: 8,
 			},
 		},
 	}
@@ -131,14 +153,19 @@ func initPortalTokensForTestNet() map[string]PortalTokenProcessor {
 
 func initPortalTokensForMainNet() map[string]PortalTokenProcessor {
 	return map[string]PortalTokenProcessor{
-		common.PortalBTCIDStr: &PortalBTCTokenProcessor{
-			&PortalToken{
-				ChainID: MainnetBTCChainID,
+		common.PortalBTCIDStr: &PortalUTXOTokenProcessor{
+			PortalToken: &PortalToken{
+				ChainID:          MainnetBTCChainID,
+				MinTokenAmount:   10,
+				ExternalDecimals: 8,
 			},
+			RelayingHeaderMetaType: metadata.RelayingBTCHeaderMeta,
 		},
 		common.PortalBNBIDStr: &PortalBNBTokenProcessor{
 			&PortalToken{
-				ChainID: MainnetBNBChainID,
+				ChainID:          MainnetBNBChainID,
+				MinTokenAmount:   10,
+				ExternalDecimals: 8,
 			},
 		},
 	}
@@ -223,6 +250,10 @@ func SetupParam() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
+		EquivocationPunishment: EquivocationPunishment{
+			PunishedEpoches: 10,
+			ForceUnstake:    true,
+		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-test.json",
 		ConsensusV2Epoch:               16930,
@@ -254,16 +285,19 @@ func SetupParam() {
 				MinUnlockOverRateCollaterals:         25,
 			},
 		},
-		PortalTokens:                initPortalTokensForTestNet(),
-		EpochBreakPointSwapNewKey:   TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      1,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    2300000, //TODO: change this value when deployed testnet
-		ETHRemoveBridgeSigEpoch:     21920,
+		PortalTokens:              initPortalTokensForTestNet(),
+		EpochBreakPointSwapNewKey: TestnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:    1,
+		IsBackup:                  false,
+		PreloadAddress:            "",
+		BCHeightBreakPointNewZKP:  2300000, //TODO: change this value when deployed testnet
+		ETHRemoveBridgeSigEpoch:   21920,
 
-		PortalETHContractAddressStr: "0x6D53de7aFa363F779B5e125876319695dC97171E", // todo: update sc address
-		BCHeightBreakPointPortalV3:  30158,
+		PortalETHContractAddressStr:      "0x6D53de7aFa363F779B5e125876319695dC97171E", // todo: update sc address
+		BCHeightBreakPointPortalV3:       30158,
+		BCHeightBreakPointEquivocation:   2350000, // todo: should update before deploying
+		BCHeightBreakPointPDERoutedTrade: 2350000, // todo: should update before deploying
+		BCHeightBreakPointPDETradeV2:     2350000, // todo: should update before deploying
 	}
 	// END TESTNET
 
@@ -315,6 +349,10 @@ func SetupParam() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
+		EquivocationPunishment: EquivocationPunishment{
+			PunishedEpoches: 10,
+			ForceUnstake:    true,
+		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-test-2.json",
 		ConsensusV2Epoch:               15290,
@@ -345,15 +383,18 @@ func SetupParam() {
 				MinPortalFee:                         100,
 			},
 		},
-		PortalTokens:                initPortalTokensForTestNet(),
-		EpochBreakPointSwapNewKey:   TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      1,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    1148608, //TODO: change this value when deployed testnet2
-		ETHRemoveBridgeSigEpoch:     2085,
-		PortalETHContractAddressStr: "0xF7befD2806afD96D3aF76471cbCa1cD874AA1F46",   // todo: update sc address
-		BCHeightBreakPointPortalV3:  1328816,
+		PortalTokens:                     initPortalTokensForTestNet(),
+		EpochBreakPointSwapNewKey:        TestnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:           1,
+		IsBackup:                         false,
+		PreloadAddress:                   "",
+		BCHeightBreakPointNewZKP:         1148608, //TODO: change this value when deployed testnet2
+		ETHRemoveBridgeSigEpoch:          2085,
+		PortalETHContractAddressStr:      "0xF7befD2806afD96D3aF76471cbCa1cD874AA1F46", // todo: update sc address
+		BCHeightBreakPointPortalV3:       1328816,
+		BCHeightBreakPointEquivocation:   1400000, // todo: should update before deploying
+		BCHeightBreakPointPDERoutedTrade: 1400000, // todo: should update before deploying
+		BCHeightBreakPointPDETradeV2:     1400000, // todo: should update before deploying
 	}
 	// END TESTNET-2
 
@@ -403,6 +444,10 @@ func SetupParam() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
+		EquivocationPunishment: EquivocationPunishment{
+			PunishedEpoches: 10,
+			ForceUnstake:    true,
+		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-main.json",
 		ConsensusV2Epoch:               3071,
@@ -433,15 +478,18 @@ func SetupParam() {
 				MinPortalFee:                         100,
 			},
 		},
-		PortalTokens:                initPortalTokensForMainNet(),
-		EpochBreakPointSwapNewKey:   MainnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      559380,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    934858,
-		ETHRemoveBridgeSigEpoch:     1973,
-		PortalETHContractAddressStr: "", // todo: update sc address
-		BCHeightBreakPointPortalV3:  40, // todo: should update before deploying
+		PortalTokens:                     initPortalTokensForMainNet(),
+		EpochBreakPointSwapNewKey:        MainnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:           559380,
+		IsBackup:                         false,
+		PreloadAddress:                   "",
+		BCHeightBreakPointNewZKP:         934858,
+		ETHRemoveBridgeSigEpoch:          1973,
+		PortalETHContractAddressStr:      "",             // todo: update sc address
+		BCHeightBreakPointPortalV3:       40,             // todo: should update before deploying
+		BCHeightBreakPointEquivocation:   math.MaxUint64, // todo: should update before deploying, disabled until then
+		BCHeightBreakPointPDERoutedTrade: math.MaxUint64, // todo: should update before deploying, disabled until then
+		BCHeightBreakPointPDETradeV2:     math.MaxUint64, // todo: should update before deploying, disabled until then
 	}
 	if IsTestNet {
 		if !IsTestNet2 {
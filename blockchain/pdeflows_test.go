@@ -154,7 +154,7 @@ func (suite *PDEFlowsSuite) TestSimulatedBeaconBlock1001() {
 		case metadata.PDEContributionMeta:
 			newInst, err = bc.buildInstructionsForPDEContribution(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1, false)
 		case metadata.PDETradeRequestMeta:
-			newInst, err = bc.buildInstructionsForPDETrade(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1)
+			newInst, err = bc.buildInstructionsForPDETrade(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1, 0)
 		case metadata.PDEWithdrawalRequestMeta:
 			newInst, err = bc.buildInstructionsForPDEWithdrawal(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1)
 		default:
@@ -357,7 +357,7 @@ func (suite *PDEFlowsSuite) TestSimulatedBeaconBlock1002() {
 		case metadata.PDEContributionMeta:
 			newInst, err = bc.buildInstructionsForPDEContribution(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1, false)
 		case metadata.PDETradeRequestMeta:
-			newInst, err = bc.buildInstructionsForPDETrade(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1)
+			newInst, err = bc.buildInstructionsForPDETrade(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1, 0)
 		case metadata.PDEWithdrawalRequestMeta:
 			newInst, err = bc.buildInstructionsForPDEWithdrawal(contentStr, shardID, metaType, &suite.currentPDEStateForProducer, beaconHeight-1)
 		default:
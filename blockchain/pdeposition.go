@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+)
+
+// PDELiquidityPosition - shares and withdrawable trading fees of a contributor in the pool of a pair
+type PDELiquidityPosition struct {
+	Token1IDStr     string
+	Token2IDStr     string
+	Share           uint64
+	TotalShares     uint64
+	SharePercent    float64
+	Token1PoolValue uint64
+	Token2PoolValue uint64
+	Token1Amount    uint64 // amount of Token1 the shares would withdraw
+	Token2Amount    uint64
+	TradingFees     uint64 // PRV trading fees the contributor can withdraw
+}
+
+// splitPDEContributorKey - tokens and contributor address of a share or trading fee key of the state at beaconHeight
+func splitPDEContributorKey(key string, prefix []byte, beaconHeight uint64) (string, string, string, bool) {
+	keyPrefix := fmt.Sprintf("%s%d-", prefix, beaconHeight)
+	if !strings.HasPrefix(key, keyPrefix) {
+		return "", "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, keyPrefix), "-", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// GetPDELiquidityPositions - positions of contributorAddressStr in the pde state at beaconHeight, for every pool
+// it has shares or withdrawable trading fees in, sorted by pair
+func GetPDELiquidityPositions(
+	currentPDEState *CurrentPDEState,
+	beaconHeight uint64,
+	contributorAddressStr string,
+) []*PDELiquidityPosition {
+	positions := map[string]*PDELiquidityPosition{}
+	positionOf := func(token1IDStr string, token2IDStr string) *PDELiquidityPosition {
+		pairKey := token1IDStr + "-" + token2IDStr
+		position, ok := positions[pairKey]
+		if !ok {
+			position = &PDELiquidityPosition{Token1IDStr: token1IDStr, Token2IDStr: token2IDStr}
+			positions[pairKey] = position
+		}
+		return position
+	}
+	totalSharesByPair := map[string]*big.Int{}
+	for shareKey, shareAmt := range currentPDEState.PDEShares {
+		token1IDStr, token2IDStr, addressStr, ok := splitPDEContributorKey(shareKey, rawdbv2.PDESharePrefix, beaconHeight)
+		if !ok {
+			continue
+		}
+		pairKey := token1IDStr + "-" + token2IDStr
+		if _, found := totalSharesByPair[pairKey]; !found {
+			totalSharesByPair[pairKey] = big.NewInt(0)
+		}
+		totalSharesByPair[pairKey].Add(totalSharesByPair[pairKey], new(big.Int).SetUint64(shareAmt))
+		if addressStr == contributorAddressStr && shareAmt > 0 {
+			positionOf(token1IDStr, token2IDStr).Share = shareAmt
+		}
+	}
+	for feeKey, feeAmt := range currentPDEState.PDETradingFees {
+		token1IDStr, token2IDStr, addressStr, ok := splitPDEContributorKey(feeKey, rawdbv2.PDETradingFeePrefix, beaconHeight)
+		if !ok || addressStr != contributorAddressStr || feeAmt == 0 {
+			continue
+		}
+		positionOf(token1IDStr, token2IDStr).TradingFees = feeAmt
+	}
+
+	result := []*PDELiquidityPosition{}
+	for pairKey, position := range positions {
+		result = append(result, position)
+		totalShares, found := totalSharesByPair[pairKey]
+		if !found || totalShares.Sign() == 0 {
+			continue
+		}
+		position.TotalShares = totalShares.Uint64()
+		position.SharePercent, _ = new(big.Float).Quo(
+			new(big.Float).Mul(new(big.Float).SetUint64(position.Share), big.NewFloat(100)),
+			new(big.Float).SetInt(totalShares),
+		).Float64()
+		poolPair, found := currentPDEState.PDEPoolPairs[string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, position.Token1IDStr, position.Token2IDStr))]
+		if !found || poolPair == nil {
+			continue
+		}
+		position.Token1PoolValue, position.Token2PoolValue = poolPair.Token1PoolValue, poolPair.Token2PoolValue
+		if poolPair.Token1IDStr != position.Token1IDStr {
+			position.Token1PoolValue, position.Token2PoolValue = poolPair.Token2PoolValue, poolPair.Token1PoolValue
+		}
+		token1Amount := new(big.Int).Mul(new(big.Int).SetUint64(position.Token1PoolValue), new(big.Int).SetUint64(position.Share))
+		position.Token1Amount = token1Amount.Div(token1Amount, totalShares).Uint64()
+		token2Amount := new(big.Int).Mul(new(big.Int).SetUint64(position.Token2PoolValue), new(big.Int).SetUint64(position.Share))
+		position.Token2Amount = token2Amount.Div(token2Amount, totalShares).Uint64()
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Token1IDStr != result[j].Token1IDStr {
+			return result[i].Token1IDStr < result[j].Token1IDStr
+		}
+		return result[i].Token2IDStr < result[j].Token2IDStr
+	})
+	return result
+}
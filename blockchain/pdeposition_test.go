@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPDELiquidityPositions(t *testing.T) {
+	beaconHeight := uint64(10)
+	prvIDStr := common.PRVCoinID.String()
+	otherContributor := "12S5Lrs1XeQLbqN4ySyKtjAjd2d7sBP2tjFijzmp6avrrkQCNFMpkXm3FPzj2Wcu2ZNqJEmh9JriVuRErVwhuQnLmWSaggobEWsBEci"
+	state := newRouteTestPDEState(beaconHeight)
+	state.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, prvIDStr, routeTestTokenA, routeTestTrader))] = 250
+	state.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, prvIDStr, routeTestTokenA, otherContributor))] = 750
+	state.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, routeTestTokenA, routeTestTokenB, otherContributor))] = 1000
+	state.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(beaconHeight-1, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 1000
+	state.PDETradingFees[string(rawdbv2.BuildPDETradingFeeKey(beaconHeight, prvIDStr, routeTestTokenA, routeTestTrader))] = 40
+	state.PDETradingFees[string(rawdbv2.BuildPDETradingFeeKey(beaconHeight, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 7
+
+	positions := GetPDELiquidityPositions(state, beaconHeight, routeTestTrader)
+	assert.Len(t, positions, 2)
+	// shares in PRV-A, and only withdrawable fees left in A-B
+	position := positions[0]
+	assert.Equal(t, prvIDStr, position.Token1IDStr)
+	assert.Equal(t, uint64(250), position.Share)
+	assert.Equal(t, uint64(1000), position.TotalShares)
+	assert.Equal(t, 25.0, position.SharePercent)
+	assert.Equal(t, uint64(250000000), position.Token1Amount)
+	assert.Equal(t, uint64(500000000), position.Token2Amount)
+	assert.Equal(t, uint64(40), position.TradingFees)
+	position = positions[1]
+	assert.Equal(t, routeTestTokenA, position.Token1IDStr)
+	assert.Equal(t, uint64(0), position.Share)
+	assert.Equal(t, uint64(0), position.Token1Amount)
+	assert.Equal(t, uint64(7), position.TradingFees)
+
+	assert.Len(t, GetPDELiquidityPositions(state, beaconHeight, "unknown"), 0)
+}
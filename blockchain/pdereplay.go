@@ -0,0 +1,375 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// Invariants checked by the pde replay
+const (
+	PDEConstantProductInvariant = "constant-product" // trades never decrease the product of pool values
+	PDESharesInvariant          = "shares"           // shares of a pair belong to its pool and withdrawals deduct what they report
+	PDEPoolBalanceInvariant     = "pool-balance"     // pool values never go below zero
+	PDECommittedStateInvariant  = "committed-state"  // recomputed state matches the committed pdex state
+)
+
+// maxPDEStateDiffs - differences listed in a committed state violation
+const maxPDEStateDiffs = 10
+
+// PDEReplayChain - beacon blocks and committed beacon feature states the replay reads
+type PDEReplayChain interface {
+	GetBeaconBlockByHeightV1(height uint64) (*BeaconBlock, error)
+	GetBeaconFeatureStateDBByHeight(beaconHeight uint64) (*statedb.StateDB, error)
+}
+
+// PDEInvariantViolation - invariant broken by an instruction, or by the state recomputed for a beacon block
+type PDEInvariantViolation struct {
+	BeaconHeight     uint64
+	InstructionIndex int // -1 for the committed state check of the block
+	Instruction      []string
+	Invariant        string
+	Detail           string
+}
+
+func (v PDEInvariantViolation) String() string {
+	if v.InstructionIndex < 0 {
+		return fmt.Sprintf("beacon height %v: %v: %v", v.BeaconHeight, v.Invariant, v.Detail)
+	}
+	return fmt.Sprintf("beacon height %v, instruction %v (meta %v, %v): %v: %v", v.BeaconHeight, v.InstructionIndex, v.Instruction[0], v.Instruction[2], v.Invariant, v.Detail)
+}
+
+// PDEReplayResult - pde instructions replayed from FromHeight to ToHeight and the invariants they broke
+type PDEReplayResult struct {
+	FromHeight   uint64
+	ToHeight     uint64
+	Instructions int
+	Violations   []PDEInvariantViolation
+}
+
+// pdeStateSnapshot - pools and shares before an instruction
+type pdeStateSnapshot struct {
+	pools  map[string]rawdbv2.PDEPoolForPair
+	shares map[string]uint64
+}
+
+func newPDEStateSnapshot(state *CurrentPDEState) *pdeStateSnapshot {
+	snapshot := &pdeStateSnapshot{
+		pools:  make(map[string]rawdbv2.PDEPoolForPair, len(state.PDEPoolPairs)),
+		shares: make(map[string]uint64, len(state.PDEShares)),
+	}
+	for key, pool := range state.PDEPoolPairs {
+		if pool != nil {
+			snapshot.pools[key] = *pool
+		}
+	}
+	for key, share := range state.PDEShares {
+		snapshot.shares[key] = share
+	}
+	return snapshot
+}
+
+// ReplayPDEInstructions - replay the pde instructions of beacon blocks fromHeight to toHeight against the pde state
+// committed at fromHeight-1, checking invariants after every instruction and the recomputed state against
+// the committed one after every block. After a block whose recomputed state differs, the replay goes on from
+// the committed state so that every mismatch is reported once
+func ReplayPDEInstructions(chain PDEReplayChain, fromHeight uint64, toHeight uint64) (*PDEReplayResult, error) {
+	if fromHeight < 2 || toHeight < fromHeight {
+		return nil, NewBlockChainError(ProcessPDEInstructionError, fmt.Errorf("invalid replay range %v - %v", fromHeight, toHeight))
+	}
+	committedPDEState, err := loadCommittedPDEState(chain, fromHeight-1, fromHeight-1)
+	if err != nil {
+		return nil, err
+	}
+	bc := &BlockChain{}
+	result := &PDEReplayResult{FromHeight: fromHeight, ToHeight: toHeight}
+	currentPDEState := committedPDEState
+	for height := fromHeight; height <= toHeight; height++ {
+		beaconHeight := height - 1
+		block, err := chain.GetBeaconBlockByHeightV1(height)
+		if err != nil {
+			return nil, NewBlockChainError(ProcessPDEInstructionError, err)
+		}
+		if hasPDEInstruction(block.Body.Instructions) {
+			// statuses are tracked on a copy of the committed state which is never written back
+			pdexStateDB, err := chain.GetBeaconFeatureStateDBByHeight(beaconHeight)
+			if err != nil {
+				return nil, NewBlockChainError(ProcessPDEInstructionError, err)
+			}
+			for i, inst := range block.Body.Instructions {
+				if len(inst) < 4 || !isPDEInstruction(inst[0]) {
+					continue
+				}
+				result.Instructions++
+				before := newPDEStateSnapshot(currentPDEState)
+				if err := bc.processPDEInstruction(pdexStateDB, beaconHeight, inst, currentPDEState); err != nil {
+					return nil, NewBlockChainError(ProcessPDEInstructionError, err)
+				}
+				for _, violation := range checkPDEInstructionInvariants(beaconHeight, inst, before, currentPDEState) {
+					violation.BeaconHeight = height
+					violation.InstructionIndex = i
+					violation.Instruction = inst
+					result.Violations = append(result.Violations, violation)
+				}
+			}
+		}
+		committedPDEState, err = loadCommittedPDEState(chain, height, beaconHeight)
+		if err != nil {
+			return nil, err
+		}
+		if diffs := diffPDEStates(currentPDEState, committedPDEState); len(diffs) > 0 {
+			detail := strings.Join(diffs, "; ")
+			if len(diffs) > maxPDEStateDiffs {
+				detail = fmt.Sprintf("%v; and %v more", strings.Join(diffs[:maxPDEStateDiffs], "; "), len(diffs)-maxPDEStateDiffs)
+			}
+			result.Violations = append(result.Violations, PDEInvariantViolation{
+				BeaconHeight:     height,
+				InstructionIndex: -1,
+				Invariant:        PDECommittedStateInvariant,
+				Detail:           detail,
+			})
+			currentPDEState = committedPDEState
+		}
+		currentPDEState = rekeyPDEState(currentPDEState, height)
+	}
+	return result, nil
+}
+
+// loadCommittedPDEState - pde state committed by the beacon block at height, with keys built with beaconHeight
+func loadCommittedPDEState(chain PDEReplayChain, height uint64, beaconHeight uint64) (*CurrentPDEState, error) {
+	stateDB, err := chain.GetBeaconFeatureStateDBByHeight(height)
+	if err != nil {
+		return nil, NewBlockChainError(ProcessPDEInstructionError, err)
+	}
+	state, err := InitCurrentPDEStateFromDB(stateDB, beaconHeight)
+	if err != nil {
+		return nil, NewBlockChainError(ProcessPDEInstructionError, err)
+	}
+	return state, nil
+}
+
+// rekeyPDEState - copy of state with its keys built with beaconHeight
+func rekeyPDEState(state *CurrentPDEState, beaconHeight uint64) *CurrentPDEState {
+	rekeyed := &CurrentPDEState{
+		WaitingPDEContributions:        make(map[string]*rawdbv2.PDEContribution, len(state.WaitingPDEContributions)),
+		DeletedWaitingPDEContributions: make(map[string]*rawdbv2.PDEContribution),
+		PDEPoolPairs:                   make(map[string]*rawdbv2.PDEPoolForPair, len(state.PDEPoolPairs)),
+		PDEShares:                      make(map[string]uint64, len(state.PDEShares)),
+		PDETradingFees:                 make(map[string]uint64, len(state.PDETradingFees)),
+	}
+	for key, contribution := range state.WaitingPDEContributions {
+		rekeyed.WaitingPDEContributions[replaceNewBCHeightInKeyStr(key, beaconHeight)] = contribution
+	}
+	for key, pool := range state.PDEPoolPairs {
+		rekeyed.PDEPoolPairs[replaceNewBCHeightInKeyStr(key, beaconHeight)] = pool
+	}
+	for key, share := range state.PDEShares {
+		rekeyed.PDEShares[replaceNewBCHeightInKeyStr(key, beaconHeight)] = share
+	}
+	for key, fee := range state.PDETradingFees {
+		rekeyed.PDETradingFees[replaceNewBCHeightInKeyStr(key, beaconHeight)] = fee
+	}
+	return rekeyed
+}
+
+func isPDEInstruction(metaType string) bool {
+	return hasPDEInstruction([][]string{{metaType, ""}})
+}
+
+// checkPDEInstructionInvariants - invariants broken by inst, applied to the state before it with keys built with beaconHeight
+func checkPDEInstructionInvariants(beaconHeight uint64, inst []string, before *pdeStateSnapshot, after *CurrentPDEState) []PDEInvariantViolation {
+	violations := []PDEInvariantViolation{}
+	addViolation := func(invariant string, format string, args ...interface{}) {
+		violations = append(violations, PDEInvariantViolation{Invariant: invariant, Detail: fmt.Sprintf(format, args...)})
+	}
+	// pool value operations asked by the instruction, checked against the pools before it
+	checkDeduction := func(token1IDStr string, token2IDStr string, tokenIDStr string, amount uint64) {
+		pool, found := before.pools[string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, token1IDStr, token2IDStr))]
+		if !found {
+			addViolation(PDEPoolBalanceInvariant, "no pool for pair %v - %v", token1IDStr, token2IDStr)
+			return
+		}
+		poolValue := pool.Token1PoolValue
+		if pool.Token2IDStr == tokenIDStr {
+			poolValue = pool.Token2PoolValue
+		}
+		if amount > poolValue {
+			addViolation(PDEPoolBalanceInvariant, "deducting %v of %v from pool %v - %v holding %v", amount, tokenIDStr, token1IDStr, token2IDStr, poolValue)
+		}
+	}
+	checkTradeOperations := func(token1IDStr string, token2IDStr string, operation1 metadata.TokenPoolValueOperation, operation2 metadata.TokenPoolValueOperation) {
+		if operation1.Operator == "-" {
+			checkDeduction(token1IDStr, token2IDStr, token1IDStr, operation1.Value)
+		}
+		if operation2.Operator == "-" {
+			checkDeduction(token1IDStr, token2IDStr, token2IDStr, operation2.Value)
+		}
+	}
+
+	metaType, _ := strconv.Atoi(inst[0])
+	isTrade := false
+	switch metaType {
+	case metadata.PDETradeRequestMeta:
+		if inst[2] != common.PDETradeAcceptedChainStatus {
+			break
+		}
+		isTrade = true
+		var content metadata.PDETradeAcceptedContent
+		if err := json.Unmarshal([]byte(inst[3]), &content); err == nil {
+			checkTradeOperations(content.Token1IDStr, content.Token2IDStr, content.Token1PoolValueOperation, content.Token2PoolValueOperation)
+		}
+	case metadata.PDECrossPoolTradeRequestMeta, metadata.PDERoutedTradeRequestMeta:
+		if inst[2] != common.PDECrossPoolTradeAcceptedChainStatus {
+			break
+		}
+		isTrade = true
+		var contents []metadata.PDECrossPoolTradeAcceptedContent
+		if err := json.Unmarshal([]byte(inst[3]), &contents); err == nil {
+			for _, content := range contents {
+				checkTradeOperations(content.Token1IDStr, content.Token2IDStr, content.Token1PoolValueOperation, content.Token2PoolValueOperation)
+			}
+		}
+	case metadata.PDEWithdrawalRequestMeta:
+		if inst[2] != common.PDEWithdrawalAcceptedChainStatus {
+			break
+		}
+		var content metadata.PDEWithdrawalAcceptedContent
+		if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+			break
+		}
+		checkDeduction(content.PairToken1IDStr, content.PairToken2IDStr, content.WithdrawalTokenIDStr, content.DeductingPoolValue)
+		shareKey := string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, content.PairToken1IDStr, content.PairToken2IDStr, content.WithdrawerAddressStr))
+		if before.shares[shareKey] < content.DeductingShares {
+			addViolation(PDESharesInvariant, "withdrawing %v shares of %v holding %v", content.DeductingShares, content.WithdrawerAddressStr, before.shares[shareKey])
+		} else if after.PDEShares[shareKey] != before.shares[shareKey]-content.DeductingShares {
+			addViolation(PDESharesInvariant, "shares of %v went from %v to %v, withdrawal deducts %v", content.WithdrawerAddressStr, before.shares[shareKey], after.PDEShares[shareKey], content.DeductingShares)
+		}
+	}
+
+	for key, pool := range after.PDEPoolPairs {
+		if pool == nil {
+			continue
+		}
+		// deductions larger than the pool wrap around, checked above from the instruction
+		beforePool, found := before.pools[key]
+		if !isTrade || !found || (beforePool.Token1PoolValue == pool.Token1PoolValue && beforePool.Token2PoolValue == pool.Token2PoolValue) {
+			continue
+		}
+		beforeProduct := new(big.Int).Mul(new(big.Int).SetUint64(beforePool.Token1PoolValue), new(big.Int).SetUint64(beforePool.Token2PoolValue))
+		afterProduct := new(big.Int).Mul(new(big.Int).SetUint64(pool.Token1PoolValue), new(big.Int).SetUint64(pool.Token2PoolValue))
+		if afterProduct.Cmp(beforeProduct) < 0 {
+			addViolation(PDEConstantProductInvariant, "pool %v - %v product went from %v to %v", pool.Token1IDStr, pool.Token2IDStr, beforeProduct, afterProduct)
+		}
+	}
+
+	// pairs whose shares changed must have a pool, and their total shares must fit a uint64
+	changedPairs := map[string]bool{}
+	for key, share := range after.PDEShares {
+		if before.shares[key] != share {
+			if token1IDStr, token2IDStr, _, ok := splitPDEContributorKey(key, rawdbv2.PDESharePrefix, beaconHeight); ok {
+				changedPairs[token1IDStr+"-"+token2IDStr] = true
+			}
+		}
+	}
+	for pair := range changedPairs {
+		tokenIDStrs := strings.SplitN(pair, "-", 2)
+		totalShares := big.NewInt(0)
+		prefix := string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, tokenIDStrs[0], tokenIDStrs[1], ""))
+		for key, share := range after.PDEShares {
+			if strings.HasPrefix(key, prefix) {
+				totalShares.Add(totalShares, new(big.Int).SetUint64(share))
+			}
+		}
+		if !totalShares.IsUint64() {
+			addViolation(PDESharesInvariant, "total shares %v of pair %v overflow", totalShares, pair)
+		}
+		if totalShares.Sign() > 0 && after.PDEPoolPairs[string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, tokenIDStrs[0], tokenIDStrs[1]))] == nil {
+			addViolation(PDESharesInvariant, "pair %v has %v shares but no pool", pair, totalShares)
+		}
+	}
+	return violations
+}
+
+// diffPDEStates - differences between the recomputed and the committed pde states, missing shares and fees count as 0
+func diffPDEStates(recomputed *CurrentPDEState, committed *CurrentPDEState) []string {
+	diffs := []string{}
+	for _, key := range unionKeysOfPools(recomputed.PDEPoolPairs, committed.PDEPoolPairs) {
+		pool, committedPool := recomputed.PDEPoolPairs[key], committed.PDEPoolPairs[key]
+		if pool == nil || committedPool == nil {
+			diffs = append(diffs, fmt.Sprintf("pool %v recomputed %+v, committed %+v", key, pool, committedPool))
+			continue
+		}
+		if *pool != *committedPool {
+			diffs = append(diffs, fmt.Sprintf("pool %v recomputed %v/%v, committed %v/%v", key, pool.Token1PoolValue, pool.Token2PoolValue, committedPool.Token1PoolValue, committedPool.Token2PoolValue))
+		}
+	}
+	for _, maps := range []struct {
+		name       string
+		recomputed map[string]uint64
+		committed  map[string]uint64
+	}{
+		{"share", recomputed.PDEShares, committed.PDEShares},
+		{"trading fee", recomputed.PDETradingFees, committed.PDETradingFees},
+	} {
+		keys := map[string]bool{}
+		for key := range maps.recomputed {
+			keys[key] = true
+		}
+		for key := range maps.committed {
+			keys[key] = true
+		}
+		sortedKeys := []string{}
+		for key := range keys {
+			if maps.recomputed[key] != maps.committed[key] {
+				sortedKeys = append(sortedKeys, key)
+			}
+		}
+		sort.Strings(sortedKeys)
+		for _, key := range sortedKeys {
+			diffs = append(diffs, fmt.Sprintf("%v %v recomputed %v, committed %v", maps.name, key, maps.recomputed[key], maps.committed[key]))
+		}
+	}
+	keys := map[string]bool{}
+	for key := range recomputed.WaitingPDEContributions {
+		keys[key] = true
+	}
+	for key := range committed.WaitingPDEContributions {
+		keys[key] = true
+	}
+	sortedKeys := []string{}
+	for key := range keys {
+		contribution, committedContribution := recomputed.WaitingPDEContributions[key], committed.WaitingPDEContributions[key]
+		if contribution == nil || committedContribution == nil || *contribution != *committedContribution {
+			sortedKeys = append(sortedKeys, key)
+		}
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		diffs = append(diffs, fmt.Sprintf("waiting contribution %v recomputed %+v, committed %+v", key, recomputed.WaitingPDEContributions[key], committed.WaitingPDEContributions[key]))
+	}
+	return diffs
+}
+
+func unionKeysOfPools(pools1 map[string]*rawdbv2.PDEPoolForPair, pools2 map[string]*rawdbv2.PDEPoolForPair) []string {
+	keys := map[string]bool{}
+	for key := range pools1 {
+		keys[key] = true
+	}
+	for key := range pools2 {
+		keys[key] = true
+	}
+	sortedKeys := []string{}
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	return sortedKeys
+}
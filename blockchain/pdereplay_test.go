@@ -0,0 +1,162 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayTestChain - beacon blocks and the feature state roots they commit
+type replayTestChain struct {
+	db     statedb.DatabaseAccessWarper
+	blocks map[uint64]*BeaconBlock
+	roots  map[uint64]common.Hash
+}
+
+func (c *replayTestChain) GetBeaconBlockByHeightV1(height uint64) (*BeaconBlock, error) {
+	return c.blocks[height], nil
+}
+
+func (c *replayTestChain) GetBeaconFeatureStateDBByHeight(beaconHeight uint64) (*statedb.StateDB, error) {
+	return statedb.NewWithPrefixTrie(c.roots[beaconHeight], c.db)
+}
+
+// commit - process the pde instructions of block on the state committed before it as the beacon does,
+// tamper the pde state and commit it as the state of the block
+func (c *replayTestChain) commit(t *testing.T, block *BeaconBlock, tamper func(state *CurrentPDEState)) {
+	stateDB, err := c.GetBeaconFeatureStateDBByHeight(block.Header.Height - 1)
+	assert.Nil(t, err)
+	assert.Nil(t, (&BlockChain{}).processPDEInstructions(stateDB, block))
+	if tamper != nil {
+		state, err := InitCurrentPDEStateFromDB(stateDB, block.Header.Height)
+		assert.Nil(t, err)
+		tamper(state)
+		assert.Nil(t, storePDEStateToDB(stateDB, block.Header.Height, state))
+	}
+	root, err := stateDB.Commit(true)
+	assert.Nil(t, err)
+	assert.Nil(t, stateDB.Database().TrieDB().Commit(root, false))
+	c.blocks[block.Header.Height] = block
+	c.roots[block.Header.Height] = root
+}
+
+func newReplayTestWithdrawalInst(tokenIDStr string, deductingPoolValue uint64, deductingShares uint64) []string {
+	contentBytes, _ := json.Marshal(metadata.PDEWithdrawalAcceptedContent{
+		WithdrawalTokenIDStr: tokenIDStr,
+		WithdrawerAddressStr: routeTestTrader,
+		DeductingPoolValue:   deductingPoolValue,
+		DeductingShares:      deductingShares,
+		PairToken1IDStr:      routeTestTokenA,
+		PairToken2IDStr:      routeTestTokenB,
+		TxReqID:              common.HashH([]byte("withdraw")),
+	})
+	return []string{strconv.Itoa(metadata.PDEWithdrawalRequestMeta), "0", common.PDEWithdrawalAcceptedChainStatus, string(contentBytes)}
+}
+
+// checkPDEReplay - replays the pde instructions of beacon blocks fromHeight to toHeight,
+// failing t for every invariant they break
+func checkPDEReplay(t testing.TB, chain PDEReplayChain, fromHeight uint64, toHeight uint64) *PDEReplayResult {
+	t.Helper()
+	result, err := ReplayPDEInstructions(chain, fromHeight, toHeight)
+	if err != nil {
+		t.Fatalf("could not replay pde instructions: %v", err)
+	}
+	for _, violation := range result.Violations {
+		t.Error(violation.String())
+	}
+	return result
+}
+
+func TestReplayPDEInstructions(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_pdereplay_")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("leveldb", dbPath)
+	assert.Nil(t, err)
+	chain := &replayTestChain{
+		db:     statedb.NewDatabaseAccessWarper(db),
+		blocks: map[uint64]*BeaconBlock{},
+		roots:  map[uint64]common.Hash{0: common.EmptyRoot},
+	}
+
+	// block 1 seeds an A-B pool held by one contributor
+	chain.commit(t, &BeaconBlock{Header: BeaconHeader{Height: 1}}, func(state *CurrentPDEState) {
+		state.PDEPoolPairs[string(rawdbv2.BuildPDEPoolForPairKey(1, routeTestTokenA, routeTestTokenB))] = rawdbv2.NewPDEPoolForPair(routeTestTokenA, 10000, routeTestTokenB, 10000)
+		state.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(1, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 10000
+	})
+	// block 2 trades A for B
+	tradeContent, _ := json.Marshal(metadata.PDETradeAcceptedContent{
+		TokenIDToBuyStr:          routeTestTokenB,
+		ReceiveAmount:            909,
+		Token1IDStr:              routeTestTokenA,
+		Token2IDStr:              routeTestTokenB,
+		Token1PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "+", Value: 1000},
+		Token2PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "-", Value: 909},
+		RequestedTxID:            common.HashH([]byte("trade")),
+	})
+	chain.commit(t, &BeaconBlock{
+		Header: BeaconHeader{Height: 2},
+		Body:   BeaconBody{Instructions: [][]string{{strconv.Itoa(metadata.PDETradeRequestMeta), "0", common.PDETradeAcceptedChainStatus, string(tradeContent)}}},
+	}, nil)
+	result := checkPDEReplay(t, chain, 2, 2)
+	assert.Equal(t, 1, result.Instructions)
+
+	// block 3 withdraws more B than the pool holds and reports shares it does not deduct,
+	// and its committed state has an extra trading fee
+	chain.commit(t, &BeaconBlock{
+		Header: BeaconHeader{Height: 3},
+		Body: BeaconBody{Instructions: [][]string{
+			newReplayTestWithdrawalInst(routeTestTokenA, 5500, 5000),
+			newReplayTestWithdrawalInst(routeTestTokenB, 9200, 0),
+		}},
+	}, func(state *CurrentPDEState) {
+		state.PDETradingFees[string(rawdbv2.BuildPDETradingFeeKey(3, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 1
+	})
+	// block 4 has no pde instruction
+	chain.commit(t, &BeaconBlock{Header: BeaconHeader{Height: 4}}, nil)
+
+	result, err = ReplayPDEInstructions(chain, 2, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Instructions)
+	invariants := []string{}
+	for _, violation := range result.Violations {
+		assert.Equal(t, uint64(3), violation.BeaconHeight)
+		invariants = append(invariants, violation.Invariant)
+	}
+	assert.Equal(t, []string{PDEPoolBalanceInvariant, PDECommittedStateInvariant}, invariants)
+	assert.Equal(t, 1, result.Violations[0].InstructionIndex)
+	assert.Contains(t, result.Violations[1].Detail, "trading fee")
+
+	// a trade decreasing the product of the pool
+	before := newPDEStateSnapshot(newRouteTestPDEState(1))
+	after := newRouteTestPDEState(1)
+	pool := after.PDEPoolPairs[string(rawdbv2.BuildPDEPoolForPairKey(1, routeTestTokenA, routeTestTokenB))]
+	pool.Token1PoolValue += 1000
+	pool.Token2PoolValue -= 2000
+	violations := checkPDEInstructionInvariants(1, []string{strconv.Itoa(metadata.PDETradeRequestMeta), "0", common.PDETradeAcceptedChainStatus, string(tradeContent)}, before, after)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, PDEConstantProductInvariant, violations[0].Invariant)
+	}
+	// a withdrawal reporting shares it does not deduct
+	before = newPDEStateSnapshot(after)
+	after.PDEShares[string(rawdbv2.BuildPDESharesKeyV2(1, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 100
+	before.shares[string(rawdbv2.BuildPDESharesKeyV2(1, routeTestTokenA, routeTestTokenB, routeTestTrader))] = 300
+	violations = checkPDEInstructionInvariants(1, newReplayTestWithdrawalInst(routeTestTokenA, 10, 100), before, after)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, PDESharesInvariant, violations[0].Invariant)
+	}
+
+	_, err = ReplayPDEInstructions(chain, 1, 4)
+	assert.NotNil(t, err)
+}
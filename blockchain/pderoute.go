@@ -0,0 +1,312 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// PDETradeHop - trade in one pool of a route
+type PDETradeHop struct {
+	TokenIDToSellStr string
+	TokenIDToBuyStr  string
+	SellAmount       uint64
+	ReceiveAmount    uint64
+	AddingFee        uint64 // part of the trading fee added to the pool, split like beacon does
+}
+
+// PDETradeRoute - trade from Path[0] to Path[len(Path)-1] through the pools of consecutive tokens of Path
+type PDETradeRoute struct {
+	Path          []string
+	Hops          []PDETradeHop
+	SellAmount    uint64
+	ReceiveAmount uint64
+	TradingFee    uint64
+	// PriceImpact - percent of the amount lost compared to trading at current pool prices
+	PriceImpact float64
+}
+
+type pdeRouteEdge struct {
+	tokenIDStr string
+	poolPair   *rawdbv2.PDEPoolForPair
+}
+
+// FindPDEBestRoute - route through at most maxHops pools of currentPDEState receiving the most tokenIDToBuyStr
+// for sellAmount, with calcTradeValue like beacon does. Among routes receiving the same amount the shortest one is chosen.
+// Returns nil if no route receives anything
+func FindPDEBestRoute(
+	currentPDEState *CurrentPDEState,
+	tokenIDToSellStr string,
+	tokenIDToBuyStr string,
+	sellAmount uint64,
+	tradingFee uint64,
+	maxHops int,
+) *PDETradeRoute {
+	if currentPDEState == nil || sellAmount == 0 || tokenIDToSellStr == tokenIDToBuyStr {
+		return nil
+	}
+	edges := map[string][]pdeRouteEdge{}
+	for _, poolPair := range currentPDEState.PDEPoolPairs {
+		if poolPair == nil || poolPair.Token1PoolValue == 0 || poolPair.Token2PoolValue == 0 {
+			continue
+		}
+		edges[poolPair.Token1IDStr] = append(edges[poolPair.Token1IDStr], pdeRouteEdge{tokenIDStr: poolPair.Token2IDStr, poolPair: poolPair})
+		edges[poolPair.Token2IDStr] = append(edges[poolPair.Token2IDStr], pdeRouteEdge{tokenIDStr: poolPair.Token1IDStr, poolPair: poolPair})
+	}
+	for _, tokenEdges := range edges {
+		sort.Slice(tokenEdges, func(i, j int) bool {
+			return tokenEdges[i].tokenIDStr < tokenEdges[j].tokenIDStr
+		})
+	}
+
+	var bestPath []string
+	bestAmount := uint64(0)
+	visited := map[string]bool{tokenIDToSellStr: true}
+	path := []string{tokenIDToSellStr}
+	var walk func(amount uint64)
+	walk = func(amount uint64) {
+		current := path[len(path)-1]
+		for _, edge := range edges[current] {
+			if visited[edge.tokenIDStr] {
+				continue
+			}
+			receiveAmount, _, _ := calcTradeValue(edge.poolPair, current, amount)
+			if receiveAmount == 0 {
+				continue
+			}
+			path = append(path, edge.tokenIDStr)
+			if edge.tokenIDStr == tokenIDToBuyStr {
+				if receiveAmount > bestAmount || (receiveAmount == bestAmount && len(path) < len(bestPath)) {
+					bestAmount = receiveAmount
+					bestPath = append([]string{}, path...)
+				}
+			} else if len(path) <= maxHops {
+				visited[edge.tokenIDStr] = true
+				walk(receiveAmount)
+				visited[edge.tokenIDStr] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	walk(sellAmount)
+	if bestPath == nil {
+		return nil
+	}
+	return NewPDETradeRoute(currentPDEState, bestPath, sellAmount, tradingFee)
+}
+
+// NewPDETradeRoute - expected result of trading sellAmount along path, pools of the path must exist
+func NewPDETradeRoute(
+	currentPDEState *CurrentPDEState,
+	path []string,
+	sellAmount uint64,
+	tradingFee uint64,
+) *PDETradeRoute {
+	route := &PDETradeRoute{
+		Path:       path,
+		Hops:       []PDETradeHop{},
+		SellAmount: sellAmount,
+		TradingFee: tradingFee,
+	}
+	// amount received at current pool prices, without slippage
+	spotAmount := new(big.Float).SetUint64(sellAmount)
+	amount := sellAmount
+	hops := len(path) - 1
+	proportionalFee := tradingFee / uint64(hops)
+	for i := 0; i < hops; i++ {
+		poolPair := findPDEPoolPair(currentPDEState, path[i], path[i+1])
+		if poolPair == nil {
+			return nil
+		}
+		tokenPoolValueToSell, tokenPoolValueToBuy := poolPair.Token2PoolValue, poolPair.Token1PoolValue
+		if poolPair.Token1IDStr == path[i] {
+			tokenPoolValueToSell, tokenPoolValueToBuy = poolPair.Token1PoolValue, poolPair.Token2PoolValue
+		}
+		spotAmount.Mul(spotAmount, new(big.Float).SetUint64(tokenPoolValueToBuy))
+		spotAmount.Quo(spotAmount, new(big.Float).SetUint64(tokenPoolValueToSell))
+
+		receiveAmount, _, _ := calcTradeValue(poolPair, path[i], amount)
+		addingFee := proportionalFee
+		if i == hops-1 {
+			addingFee = tradingFee - uint64(hops-1)*proportionalFee
+		}
+		route.Hops = append(route.Hops, PDETradeHop{
+			TokenIDToSellStr: path[i],
+			TokenIDToBuyStr:  path[i+1],
+			SellAmount:       amount,
+			ReceiveAmount:    receiveAmount,
+			AddingFee:        addingFee,
+		})
+		amount = receiveAmount
+	}
+	route.ReceiveAmount = amount
+	if spotAmount.Sign() > 0 {
+		lost := new(big.Float).Sub(spotAmount, new(big.Float).SetUint64(amount))
+		route.PriceImpact, _ = new(big.Float).Quo(new(big.Float).Mul(lost, big.NewFloat(100)), spotAmount).Float64()
+	}
+	return route
+}
+
+// findPDEPoolPair - pool of the pair of tokens whatever the height in its key, nil if empty or not found
+func findPDEPoolPair(currentPDEState *CurrentPDEState, token1IDStr string, token2IDStr string) *rawdbv2.PDEPoolForPair {
+	for _, poolPair := range currentPDEState.PDEPoolPairs {
+		if poolPair == nil || poolPair.Token1PoolValue == 0 || poolPair.Token2PoolValue == 0 {
+			continue
+		}
+		if (poolPair.Token1IDStr == token1IDStr && poolPair.Token2IDStr == token2IDStr) ||
+			(poolPair.Token1IDStr == token2IDStr && poolPair.Token2IDStr == token1IDStr) {
+			return poolPair
+		}
+	}
+	return nil
+}
+
+// prepareInfoForSortingRoutedTrade - trading fee and sell amount valued in PRV like prepareInfoForSorting,
+// raw sell amount if the token sold has no pool with PRV
+func prepareInfoForSortingRoutedTrade(
+	currentPDEState *CurrentPDEState,
+	beaconHeight uint64,
+	tradeAction metadata.PDERoutedTradeRequestAction,
+) (uint64, uint64) {
+	prvIDStr := common.PRVCoinID.String()
+	tradeMeta := tradeAction.Meta
+	if tradeMeta.TokenIDToSellStr == prvIDStr || !isPoolPairExisting(beaconHeight, currentPDEState, prvIDStr, tradeMeta.TokenIDToSellStr) {
+		return tradeMeta.TradingFee, tradeMeta.SellAmount
+	}
+	poolPairKey := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, prvIDStr, tradeMeta.TokenIDToSellStr))
+	sellAmount, _, _ := calcTradeValue(currentPDEState.PDEPoolPairs[poolPairKey], tradeMeta.TokenIDToSellStr, tradeMeta.SellAmount)
+	return tradeMeta.TradingFee, sellAmount
+}
+
+// categorizeNSortPDERoutedTradeActions - routed trades whose pools all exist, sorted by trading fee over sell amount,
+// and the others to refund
+func categorizeNSortPDERoutedTradeActions(
+	beaconHeight uint64,
+	currentPDEState *CurrentPDEState,
+	pdeRoutedTradeActionsByShardID map[byte][][]string,
+) ([]metadata.PDERoutedTradeRequestAction, []metadata.PDERoutedTradeRequestAction) {
+	tradableActions := []metadata.PDERoutedTradeRequestAction{}
+	untradableActions := []metadata.PDERoutedTradeRequestAction{}
+	var keys []int
+	for k := range pdeRoutedTradeActionsByShardID {
+		keys = append(keys, int(k))
+	}
+	sort.Ints(keys)
+	for _, value := range keys {
+		shardID := byte(value)
+		actions := pdeRoutedTradeActionsByShardID[shardID]
+		for _, action := range actions {
+			contentBytes, err := base64.StdEncoding.DecodeString(action[1])
+			if err != nil {
+				Logger.log.Errorf("ERROR: an error occured while decoding content string of pde routed trade action: %+v", err)
+				continue
+			}
+			var routedTradeRequestAction metadata.PDERoutedTradeRequestAction
+			err = json.Unmarshal(contentBytes, &routedTradeRequestAction)
+			if err != nil {
+				Logger.log.Errorf("ERROR: an error occured while unmarshaling pde routed trade request action: %+v", err)
+				continue
+			}
+			tradeMeta := routedTradeRequestAction.Meta
+			tradable := metadata.ValidatePDETradePath(tradeMeta.Path, tradeMeta.TokenIDToSellStr, tradeMeta.TokenIDToBuyStr) == nil
+			for i := 0; tradable && i < len(tradeMeta.Path)-1; i++ {
+				tradable = isPoolPairExisting(beaconHeight, currentPDEState, tradeMeta.Path[i], tradeMeta.Path[i+1])
+			}
+			if !tradable {
+				untradableActions = append(untradableActions, routedTradeRequestAction)
+				continue
+			}
+			tradableActions = append(tradableActions, routedTradeRequestAction)
+		}
+	}
+
+	// sort tradable actions by trading fee
+	sort.SliceStable(tradableActions, func(i, j int) bool {
+		firstTradingFee, firstSellAmount := prepareInfoForSortingRoutedTrade(currentPDEState, beaconHeight, tradableActions[i])
+		secondTradingFee, secondSellAmount := prepareInfoForSortingRoutedTrade(currentPDEState, beaconHeight, tradableActions[j])
+		// comparing a/b to c/d is equivalent with comparing a*d to c*b
+		firstItemProportion := new(big.Int).Mul(new(big.Int).SetUint64(firstTradingFee), new(big.Int).SetUint64(secondSellAmount))
+		secondItemProportion := new(big.Int).Mul(new(big.Int).SetUint64(secondTradingFee), new(big.Int).SetUint64(firstSellAmount))
+		return firstItemProportion.Cmp(secondItemProportion) == 1
+	})
+	return tradableActions, untradableActions
+}
+
+// buildInstsForSortedRoutedTradeActions - trade along the path of each action like cross pool trades,
+// refunded if less than MinAcceptableAmount would be received. Trading fees are added to tradingFeeByPair
+func (blockchain *BlockChain) buildInstsForSortedRoutedTradeActions(
+	currentPDEState *CurrentPDEState,
+	beaconHeight uint64,
+	sortedTradableActions []metadata.PDERoutedTradeRequestAction,
+	tradingFeeByPair map[string]uint64,
+) [][]string {
+	tradableInsts := [][]string{}
+	for _, tradeAction := range sortedTradableActions {
+		tradeMeta := tradeAction.Meta
+		sequentialTrades := []*tradeInfo{}
+		for i := 0; i < len(tradeMeta.Path)-1; i++ {
+			sequentialTrades = append(sequentialTrades, &tradeInfo{
+				tokenIDToBuyStr:  tradeMeta.Path[i+1],
+				tokenIDToSellStr: tradeMeta.Path[i],
+				sellAmount:       uint64(0),
+			})
+		}
+		sequentialTrades[0].sellAmount = tradeMeta.SellAmount
+		newInsts, err := blockchain.buildInstructionsForPDECrossPoolTrade(
+			sequentialTrades,
+			tradeMeta.MinAcceptableAmount,
+			tradeMeta.TradingFee,
+			tradeAction.ShardID,
+			metadata.PDERoutedTradeRequestMeta,
+			currentPDEState,
+			beaconHeight,
+			tradeMeta.TraderAddressStr,
+			tradeAction.TxReqID,
+			tradingFeeByPair,
+			nil,
+			0,
+		)
+		if err != nil {
+			Logger.log.Error(err)
+			continue
+		}
+		if len(newInsts) > 0 {
+			tradableInsts = append(tradableInsts, newInsts...)
+		}
+	}
+	return tradableInsts
+}
+
+func (blockchain *BlockChain) buildInstsForUntradableRoutedTradeActions(
+	untradableActions []metadata.PDERoutedTradeRequestAction,
+) [][]string {
+	untradableInsts := [][]string{}
+	for _, tradeAction := range untradableActions {
+		refundTradingFeeInst := buildCrossPoolTradeRefundInst(
+			tradeAction.Meta.TraderAddressStr,
+			common.PRVCoinID.String(),
+			tradeAction.Meta.TradingFee,
+			metadata.PDERoutedTradeRequestMeta,
+			common.PDECrossPoolTradeFeeRefundChainStatus,
+			tradeAction.ShardID,
+			tradeAction.TxReqID,
+		)
+		untradableInsts = append(untradableInsts, refundTradingFeeInst)
+		refundSellingTokenInst := buildCrossPoolTradeRefundInst(
+			tradeAction.Meta.TraderAddressStr,
+			tradeAction.Meta.TokenIDToSellStr,
+			tradeAction.Meta.SellAmount,
+			metadata.PDERoutedTradeRequestMeta,
+			common.PDECrossPoolTradeSellingTokenRefundChainStatus,
+			tradeAction.ShardID,
+			tradeAction.TxReqID,
+		)
+		untradableInsts = append(untradableInsts, refundSellingTokenInst)
+	}
+	return untradableInsts
+}
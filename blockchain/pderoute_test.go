@@ -0,0 +1,174 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	routeTestTokenA = "0000000000000000000000000000000000000000000000000000000000000061"
+	routeTestTokenB = "0000000000000000000000000000000000000000000000000000000000000062"
+	routeTestTokenC = "0000000000000000000000000000000000000000000000000000000000000063"
+	routeTestTrader = "12RuEdPjq4yxivzm8xPxRVHmkL74t4eAdUKPdKKhMEnpxPH3k8GEyULbwq4hjwHWmHQr7MmGBJsMpdCHsYAqNE18jipWQwciBf9yqvQ"
+)
+
+func newRouteTestPDEState(beaconHeight uint64) *CurrentPDEState {
+	prvIDStr := common.PRVCoinID.String()
+	state := &CurrentPDEState{
+		WaitingPDEContributions:        make(map[string]*rawdbv2.PDEContribution),
+		DeletedWaitingPDEContributions: make(map[string]*rawdbv2.PDEContribution),
+		PDEPoolPairs:                   make(map[string]*rawdbv2.PDEPoolForPair),
+		PDEShares:                      make(map[string]uint64),
+		PDETradingFees:                 make(map[string]uint64),
+	}
+	addPool := func(token1IDStr string, token1PoolValue uint64, token2IDStr string, token2PoolValue uint64) {
+		key := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, token1IDStr, token2IDStr))
+		state.PDEPoolPairs[key] = rawdbv2.NewPDEPoolForPair(token1IDStr, token1PoolValue, token2IDStr, token2PoolValue)
+	}
+	// deep PRV-A and A-B pools, shallow PRV-B pool
+	addPool(prvIDStr, 1000000000, routeTestTokenA, 2000000000)
+	addPool(routeTestTokenA, 1000000000, routeTestTokenB, 1000000000)
+	addPool(prvIDStr, 1000000, routeTestTokenB, 2000000)
+	return state
+}
+
+func buildPDERoutedTradeReqAction(path []string, sellAmount uint64, minAcceptableAmount uint64, tradingFee uint64) []string {
+	meta, _ := metadata.NewPDERoutedTradeRequest(
+		path[len(path)-1],
+		path[0],
+		path,
+		sellAmount,
+		minAcceptableAmount,
+		tradingFee,
+		routeTestTrader,
+		metadata.PDERoutedTradeRequestMeta,
+	)
+	actionContentBytes, _ := json.Marshal(metadata.PDERoutedTradeRequestAction{Meta: *meta, ShardID: 1})
+	return []string{strconv.Itoa(metadata.PDERoutedTradeRequestMeta), base64.StdEncoding.EncodeToString(actionContentBytes)}
+}
+
+func TestFindPDEBestRoute(t *testing.T) {
+	prvIDStr := common.PRVCoinID.String()
+	state := newRouteTestPDEState(10)
+
+	// going through A beats the shallow direct pool
+	route := FindPDEBestRoute(state, prvIDStr, routeTestTokenB, 1000000, 300, metadata.PDEMaxTradeHops)
+	if assert.NotNil(t, route) {
+		assert.Equal(t, []string{prvIDStr, routeTestTokenA, routeTestTokenB}, route.Path)
+		assert.Equal(t, route.Hops[1].ReceiveAmount, route.ReceiveAmount)
+		assert.Equal(t, uint64(150), route.Hops[0].AddingFee)
+		assert.Equal(t, uint64(150), route.Hops[1].AddingFee)
+		assert.True(t, route.PriceImpact >= 0 && route.PriceImpact < 1)
+	}
+
+	// direct pool only
+	route = FindPDEBestRoute(state, prvIDStr, routeTestTokenB, 1000000, 0, 1)
+	if assert.NotNil(t, route) {
+		assert.Equal(t, []string{prvIDStr, routeTestTokenB}, route.Path)
+		assert.Equal(t, uint64(1000000), route.ReceiveAmount)
+		assert.InDelta(t, 50, route.PriceImpact, 0.01)
+	}
+
+	assert.Nil(t, FindPDEBestRoute(state, prvIDStr, "0000000000000000000000000000000000000000000000000000000000000063", 1000, 0, metadata.PDEMaxTradeHops))
+}
+
+func TestBuildInstsForRoutedTradeActions(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	prvIDStr := common.PRVCoinID.String()
+	beaconHeight := uint64(10)
+	state := newRouteTestPDEState(beaconHeight)
+	bc := &BlockChain{}
+	expected := FindPDEBestRoute(state, routeTestTokenB, routeTestTokenA, 1000000, 100, metadata.PDEMaxTradeHops)
+	assert.Equal(t, []string{routeTestTokenB, routeTestTokenA}, expected.Path)
+
+	actions := map[byte][][]string{
+		1: {
+			buildPDERoutedTradeReqAction([]string{routeTestTokenB, routeTestTokenA}, 1000000, expected.ReceiveAmount, 100),
+			// less than the minimum acceptable amount
+			buildPDERoutedTradeReqAction([]string{routeTestTokenB, prvIDStr, routeTestTokenA}, 1000000, 1000000000, 100),
+			// no pool between B and the unknown token
+			buildPDERoutedTradeReqAction([]string{routeTestTokenB, "0000000000000000000000000000000000000000000000000000000000000063", routeTestTokenA}, 1000000, 0, 100),
+		},
+	}
+	tradableActions, untradableActions := categorizeNSortPDERoutedTradeActions(beaconHeight, state, actions)
+	assert.Len(t, tradableActions, 2)
+	assert.Len(t, untradableActions, 1)
+
+	tradingFeeByPair := map[string]uint64{}
+	insts := bc.buildInstsForSortedRoutedTradeActions(state, beaconHeight, tradableActions, tradingFeeByPair)
+	insts = append(insts, bc.buildInstsForUntradableRoutedTradeActions(untradableActions)...)
+	statuses := []string{}
+	for _, inst := range insts {
+		assert.Equal(t, strconv.Itoa(metadata.PDERoutedTradeRequestMeta), inst[0])
+		statuses = append(statuses, inst[2])
+	}
+	assert.Equal(t, []string{
+		common.PDECrossPoolTradeAcceptedChainStatus,
+		common.PDECrossPoolTradeFeeRefundChainStatus,
+		common.PDECrossPoolTradeSellingTokenRefundChainStatus,
+		common.PDECrossPoolTradeFeeRefundChainStatus,
+		common.PDECrossPoolTradeSellingTokenRefundChainStatus,
+	}, statuses)
+
+	var accepted []metadata.PDECrossPoolTradeAcceptedContent
+	assert.Nil(t, json.Unmarshal([]byte(insts[0][3]), &accepted))
+	if assert.Len(t, accepted, 1) {
+		assert.Equal(t, expected.ReceiveAmount, accepted[0].ReceiveAmount)
+	}
+	assert.Equal(t, uint64(100), tradingFeeByPair[string(rawdbv2.BuildPDESharesKeyV2(beaconHeight, routeTestTokenA, routeTestTokenB, ""))])
+}
+
+// Routed trades are rejected before BCHeightBreakPointPDERoutedTrade and need every pool of their path in the beacon view
+func TestPDERoutedTradeRequestValidation(t *testing.T) {
+	prvIDStr := common.PRVCoinID.String()
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_statedb_")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dbPath)
+	diskDB, _ := incdb.Open("leveldb", dbPath)
+	stateDB, _ := statedb.NewWithPrefixTrie(common.HexToHash(common.HexEmptyRoot), statedb.NewDatabaseAccessWarper(diskDB))
+	assert.Nil(t, storePDEStateToDB(stateDB, 10, newRouteTestPDEState(10)))
+	_, err = stateDB.Commit(true)
+	assert.Nil(t, err)
+	bc := &BlockChain{config: Config{ChainParams: &Params{BCHeightBreakPointPDERoutedTrade: 10}}}
+	beaconView := &BeaconBestState{featureStateDB: stateDB}
+
+	checkErrCode := func(expectedKey int, err error) {
+		mtErr, ok := err.(*metadata.MetadataTxError)
+		if assert.True(t, ok, "%v", err) {
+			assert.Equal(t, metadata.ErrCodeMessage[expectedKey].Code, mtErr.Code)
+		}
+	}
+	newTx := func(path []string) (*metadata.PDERoutedTradeRequest, *mocks.Transaction) {
+		meta, _ := metadata.NewPDERoutedTradeRequest(path[len(path)-1], path[0], path, 1000, 1, 0, routeTestTrader, metadata.PDERoutedTradeRequestMeta)
+		tx := &mocks.Transaction{}
+		tx.On("GetMetadata").Return(meta)
+		return meta, tx
+	}
+
+	meta, tx := newTx([]string{prvIDStr, routeTestTokenA, routeTestTokenB})
+	_, _, err = meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 9}, beaconView, 0, tx)
+	checkErrCode(metadata.PDERoutedTradeRequestNotActivatedError, err)
+	isValid, err := meta.ValidateTxWithBlockChain(tx, bc, &ShardBestState{BeaconHeight: 10}, beaconView, 0, nil)
+	assert.Nil(t, err)
+	assert.True(t, isValid)
+
+	meta, tx = newTx([]string{prvIDStr, routeTestTokenA, routeTestTokenA})
+	_, _, err = meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 10}, beaconView, 0, tx)
+	checkErrCode(metadata.PDERoutedTradeRequestInvalidPathError, err)
+
+	meta, tx = newTx([]string{routeTestTokenA, routeTestTokenB, routeTestTokenC})
+	_, err = meta.ValidateTxWithBlockChain(tx, bc, &ShardBestState{BeaconHeight: 10}, beaconView, 0, nil)
+	checkErrCode(metadata.PDERoutedTradeRequestPoolNotFoundError, err)
+}
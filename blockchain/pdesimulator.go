@@ -0,0 +1,154 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+const (
+	PDESimulatedTradeNoPoolRefund       = "pool not found"
+	PDESimulatedTradeMinAcceptedRefund  = "receive amount less than MinAcceptableAmount"
+	PDESimulatedTradeInvalidTradeRefund = "invalid trade"
+)
+
+// PDESimulatedTrade - cross pool trade to simulate, routed trade if Path is set
+type PDESimulatedTrade struct {
+	TokenIDToBuyStr     string
+	TokenIDToSellStr    string
+	Path                []string
+	SellAmount          uint64
+	MinAcceptableAmount uint64
+	TradingFee          uint64
+}
+
+type PDESimulatedTradeResult struct {
+	Order         int // position of the trade in the order applied by beacon
+	Refunded      bool
+	RefundReason  string
+	ReceiveAmount uint64
+	Hops          []PDETradeHop
+}
+
+// PDETradesSimulation - results in the order of the simulated trades, and pools touched by them after all trades
+type PDETradesSimulation struct {
+	Trades    []*PDESimulatedTradeResult
+	PoolPairs map[string]*rawdbv2.PDEPoolForPair
+}
+
+// SimulatePDETrades - instructions beacon would build for trades all in the next block, in its trading fee order,
+// on a copy of currentPDEState
+func (blockchain *BlockChain) SimulatePDETrades(
+	currentPDEState *CurrentPDEState,
+	beaconHeight uint64,
+	trades []PDESimulatedTrade,
+) (*PDETradesSimulation, error) {
+	pdeState := currentPDEState.Copy()
+	if pdeState == nil {
+		return nil, NewBlockChainError(ProcessPDEInstructionError, errors.New("can not copy pde state"))
+	}
+	crossPoolTradeActions := map[byte][][]string{}
+	routedTradeActions := map[byte][][]string{}
+	simulation := &PDETradesSimulation{
+		Trades:    make([]*PDESimulatedTradeResult, len(trades)),
+		PoolPairs: map[string]*rawdbv2.PDEPoolForPair{},
+	}
+	indexByTxReqID := map[common.Hash]int{}
+	for i, trade := range trades {
+		simulation.Trades[i] = &PDESimulatedTradeResult{Order: -1, Hops: []PDETradeHop{}}
+		txReqID := common.HashH([]byte(strconv.Itoa(i)))
+		indexByTxReqID[txReqID] = i
+		if trade.SellAmount == 0 || trade.TokenIDToBuyStr == trade.TokenIDToSellStr {
+			simulation.Trades[i].Refunded = true
+			simulation.Trades[i].RefundReason = PDESimulatedTradeInvalidTradeRefund
+			continue
+		}
+		var actionContent interface{}
+		metaType := metadata.PDECrossPoolTradeRequestMeta
+		if len(trade.Path) > 0 {
+			metaType = metadata.PDERoutedTradeRequestMeta
+			meta, _ := metadata.NewPDERoutedTradeRequest(trade.TokenIDToBuyStr, trade.TokenIDToSellStr, trade.Path, trade.SellAmount, trade.MinAcceptableAmount, trade.TradingFee, "", metaType)
+			actionContent = metadata.PDERoutedTradeRequestAction{Meta: *meta, TxReqID: txReqID}
+		} else {
+			meta, _ := metadata.NewPDECrossPoolTradeRequest(trade.TokenIDToBuyStr, trade.TokenIDToSellStr, trade.SellAmount, trade.MinAcceptableAmount, trade.TradingFee, "", metaType)
+			actionContent = metadata.PDECrossPoolTradeRequestAction{Meta: *meta, TxReqID: txReqID}
+		}
+		actionContentBytes, err := json.Marshal(actionContent)
+		if err != nil {
+			return nil, err
+		}
+		action := []string{strconv.Itoa(metaType), base64.StdEncoding.EncodeToString(actionContentBytes)}
+		if metaType == metadata.PDERoutedTradeRequestMeta {
+			routedTradeActions = groupPDEActionsByShardID(routedTradeActions, action, 0)
+		} else {
+			crossPoolTradeActions = groupPDEActionsByShardID(crossPoolTradeActions, action, 0)
+		}
+	}
+
+	// same steps as handlePDEInsts
+	sortedTradableActions, untradableActions := categorizeNSortPDECrossPoolTradeInstsByFee(beaconHeight, pdeState, crossPoolTradeActions)
+	insts, tradingFeeByPair := blockchain.buildInstsForSortedTradableActions(pdeState, beaconHeight, 0, sortedTradableActions) // simulated trades have no deadline
+	insts = append(insts, blockchain.buildInstsForUntradableActions(untradableActions)...)
+	sortedRoutedActions, untradableRoutedActions := categorizeNSortPDERoutedTradeActions(beaconHeight, pdeState, routedTradeActions)
+	insts = append(insts, blockchain.buildInstsForSortedRoutedTradeActions(pdeState, beaconHeight, sortedRoutedActions, tradingFeeByPair)...)
+	insts = append(insts, blockchain.buildInstsForUntradableRoutedTradeActions(untradableRoutedActions)...)
+
+	noPoolTxReqIDs := map[common.Hash]bool{}
+	for _, action := range untradableActions {
+		noPoolTxReqIDs[action.TxReqID] = true
+	}
+	for _, action := range untradableRoutedActions {
+		noPoolTxReqIDs[action.TxReqID] = true
+	}
+	order := 0
+	for _, inst := range insts {
+		if inst[2] == common.PDECrossPoolTradeAcceptedChainStatus {
+			var acceptedContents []metadata.PDECrossPoolTradeAcceptedContent
+			if err := json.Unmarshal([]byte(inst[3]), &acceptedContents); err != nil || len(acceptedContents) == 0 {
+				continue
+			}
+			result := simulation.Trades[indexByTxReqID[acceptedContents[0].RequestedTxID]]
+			for _, content := range acceptedContents {
+				hop := PDETradeHop{
+					TokenIDToSellStr: content.Token1IDStr,
+					TokenIDToBuyStr:  content.TokenIDToBuyStr,
+					SellAmount:       content.Token1PoolValueOperation.Value,
+					ReceiveAmount:    content.ReceiveAmount,
+					AddingFee:        content.AddingFee,
+				}
+				if content.Token1IDStr == content.TokenIDToBuyStr {
+					hop.TokenIDToSellStr = content.Token2IDStr
+					hop.SellAmount = content.Token2PoolValueOperation.Value
+				}
+				result.Hops = append(result.Hops, hop)
+				poolPairKey := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, content.Token1IDStr, content.Token2IDStr))
+				simulation.PoolPairs[poolPairKey] = pdeState.PDEPoolPairs[poolPairKey]
+			}
+			result.ReceiveAmount = acceptedContents[len(acceptedContents)-1].ReceiveAmount
+			result.Order = order
+			order++
+			continue
+		}
+		var refundContent metadata.PDERefundCrossPoolTrade
+		if err := json.Unmarshal([]byte(inst[3]), &refundContent); err != nil {
+			continue
+		}
+		result := simulation.Trades[indexByTxReqID[refundContent.TxReqID]]
+		if result.Refunded {
+			continue // selling token refund after trading fee refund
+		}
+		result.Refunded = true
+		result.RefundReason = PDESimulatedTradeMinAcceptedRefund
+		if noPoolTxReqIDs[refundContent.TxReqID] {
+			result.RefundReason = PDESimulatedTradeNoPoolRefund
+		}
+		result.Order = order
+		order++
+	}
+	return simulation, nil
+}
@@ -0,0 +1,53 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulatePDETrades(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	prvIDStr := common.PRVCoinID.String()
+	beaconHeight := uint64(10)
+	state := newRouteTestPDEState(beaconHeight)
+	prvAPoolKey := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, prvIDStr, routeTestTokenA))
+	prvAPool := *state.PDEPoolPairs[prvAPoolKey]
+	bc := &BlockChain{}
+
+	simulation, err := bc.SimulatePDETrades(state, beaconHeight, []PDESimulatedTrade{
+		{TokenIDToSellStr: prvIDStr, TokenIDToBuyStr: routeTestTokenA, SellAmount: 1000000, TradingFee: 10},
+		// higher fee, traded first
+		{TokenIDToSellStr: prvIDStr, TokenIDToBuyStr: routeTestTokenA, SellAmount: 1000000, TradingFee: 1000},
+		{TokenIDToSellStr: routeTestTokenA, TokenIDToBuyStr: routeTestTokenB, SellAmount: 1000000, MinAcceptableAmount: 1000000000},
+		{TokenIDToSellStr: prvIDStr, TokenIDToBuyStr: "0000000000000000000000000000000000000000000000000000000000000063", SellAmount: 1000},
+		{TokenIDToSellStr: routeTestTokenB, TokenIDToBuyStr: routeTestTokenA, Path: []string{routeTestTokenB, routeTestTokenA}, SellAmount: 1000},
+		{TokenIDToSellStr: prvIDStr, TokenIDToBuyStr: prvIDStr, SellAmount: 1000},
+	})
+	assert.Nil(t, err)
+	trades := simulation.Trades
+	assert.Equal(t, 1, trades[0].Order)
+	assert.Equal(t, 0, trades[1].Order)
+	assert.True(t, trades[1].ReceiveAmount > trades[0].ReceiveAmount)
+	assert.Len(t, trades[0].Hops, 1)
+	assert.Equal(t, uint64(1000000), trades[0].Hops[0].SellAmount)
+
+	assert.True(t, trades[2].Refunded)
+	assert.Equal(t, PDESimulatedTradeMinAcceptedRefund, trades[2].RefundReason)
+	assert.True(t, trades[3].Refunded)
+	assert.Equal(t, PDESimulatedTradeNoPoolRefund, trades[3].RefundReason)
+	assert.False(t, trades[4].Refunded)
+	assert.Equal(t, 4, trades[4].Order)
+	assert.True(t, trades[5].Refunded)
+	assert.Equal(t, PDESimulatedTradeInvalidTradeRefund, trades[5].RefundReason)
+	assert.Equal(t, -1, trades[5].Order)
+
+	// post trade pools, current state untouched
+	if assert.Len(t, simulation.PoolPairs, 2) {
+		assert.Equal(t, prvAPool.Token1PoolValue+2000000, simulation.PoolPairs[prvAPoolKey].Token1PoolValue)
+		assert.Equal(t, prvAPool.Token2PoolValue-trades[0].ReceiveAmount-trades[1].ReceiveAmount, simulation.PoolPairs[prvAPoolKey].Token2PoolValue)
+	}
+	assert.Equal(t, prvAPool, *state.PDEPoolPairs[prvAPoolKey])
+}
@@ -0,0 +1,161 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildPDETradeReqActionWithDeadline(txReqID common.Hash, deadline *metadata.PDETradeDeadline) []string {
+	metaType := metadata.PDETradeRequestMeta
+	if deadline != nil {
+		metaType = metadata.PDETradeRequestMetaV2
+	}
+	meta, _ := metadata.NewPDETradeRequest(routeTestTokenA, common.PRVCoinID.String(), 1000000, 0, 100, routeTestTrader, metaType)
+	actionContentBytes, _ := json.Marshal(metadata.PDETradeRequestAction{Meta: *meta, TxReqID: txReqID, ShardID: 1, Deadline: deadline})
+	return []string{strconv.Itoa(metaType), base64.StdEncoding.EncodeToString(actionContentBytes)}
+}
+
+func buildPDECrossPoolTradeReqActionWithDeadline(txReqID common.Hash, deadline *metadata.PDETradeDeadline) []string {
+	metaType := metadata.PDECrossPoolTradeRequestMeta
+	if deadline != nil {
+		metaType = metadata.PDECrossPoolTradeRequestMetaV2
+	}
+	meta, _ := metadata.NewPDECrossPoolTradeRequest(routeTestTokenB, routeTestTokenA, 1000000, 0, 100, routeTestTrader, metaType)
+	actionContentBytes, _ := json.Marshal(metadata.PDECrossPoolTradeRequestAction{Meta: *meta, TxReqID: txReqID, ShardID: 1, Deadline: deadline})
+	return []string{strconv.Itoa(metaType), base64.StdEncoding.EncodeToString(actionContentBytes)}
+}
+
+func TestPDETradeDeadline(t *testing.T) {
+	deadline := metadata.PDETradeDeadline{DeadlineBeaconHeight: 10}
+	assert.False(t, deadline.IsExpired(10, 0))
+	assert.True(t, deadline.IsExpired(11, 0))
+	deadline = metadata.PDETradeDeadline{DeadlineTimestamp: 1000}
+	assert.False(t, deadline.IsExpired(100, 1000))
+	assert.True(t, deadline.IsExpired(1, 1001))
+	assert.False(t, metadata.PDETradeDeadline{}.IsExpired(100, 1000))
+}
+
+func TestHandlePDEInstsWithTradeDeadlines(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	beaconHeight := uint64(10)
+	beaconTimestamp := int64(1000)
+	state := newRouteTestPDEState(beaconHeight)
+	bc := &BlockChain{}
+	txReqIDs := []common.Hash{}
+	for i := 0; i < 6; i++ {
+		txReqIDs = append(txReqIDs, common.HashH([]byte(strconv.Itoa(i))))
+	}
+	// the instructions are built for beacon block 11, after block 10 built at beaconTimestamp
+	tradeActions := map[byte][][]string{
+		1: {
+			buildPDETradeReqActionWithDeadline(txReqIDs[0], nil),
+			buildPDETradeReqActionWithDeadline(txReqIDs[1], &metadata.PDETradeDeadline{DeadlineBeaconHeight: 10}),
+			buildPDETradeReqActionWithDeadline(txReqIDs[2], &metadata.PDETradeDeadline{DeadlineBeaconHeight: 11, DeadlineTimestamp: 1000}),
+		},
+	}
+	crossPoolTradeActions := map[byte][][]string{
+		1: {
+			buildPDECrossPoolTradeReqActionWithDeadline(txReqIDs[3], &metadata.PDETradeDeadline{DeadlineTimestamp: 999}),
+			buildPDECrossPoolTradeReqActionWithDeadline(txReqIDs[4], &metadata.PDETradeDeadline{DeadlineTimestamp: 1000}),
+			buildPDECrossPoolTradeReqActionWithDeadline(txReqIDs[5], nil),
+		},
+	}
+	poolPairKey := string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, common.PRVCoinID.String(), routeTestTokenA))
+	poolPairBefore := *state.PDEPoolPairs[poolPairKey]
+	insts, err := bc.handlePDEInsts(
+		beaconHeight, beaconTimestamp, state,
+		map[byte][][]string{}, map[byte][][]string{},
+		tradeActions, crossPoolTradeActions,
+		map[byte][][]string{}, map[byte][][]string{}, map[byte][][]string{},
+	)
+	assert.Nil(t, err)
+
+	statusesByTxReqID := map[common.Hash][]string{}
+	for _, inst := range insts {
+		switch inst[0] {
+		case strconv.Itoa(metadata.PDETradeRequestMeta):
+			if inst[2] == common.PDETradeAcceptedChainStatus {
+				var accepted metadata.PDETradeAcceptedContent
+				assert.Nil(t, json.Unmarshal([]byte(inst[3]), &accepted))
+				statusesByTxReqID[accepted.RequestedTxID] = append(statusesByTxReqID[accepted.RequestedTxID], inst[2])
+				continue
+			}
+			refund, err := parseTradeRefundContent(inst[3])
+			if assert.Nil(t, err) {
+				statusesByTxReqID[refund.TxReqID] = append(statusesByTxReqID[refund.TxReqID], inst[2])
+			}
+		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta):
+			if inst[2] == common.PDECrossPoolTradeAcceptedChainStatus {
+				var accepted []metadata.PDECrossPoolTradeAcceptedContent
+				assert.Nil(t, json.Unmarshal([]byte(inst[3]), &accepted))
+				statusesByTxReqID[accepted[0].RequestedTxID] = append(statusesByTxReqID[accepted[0].RequestedTxID], inst[2])
+				continue
+			}
+			refund, err := parseCrossPoolTradeRefundContent(inst[3])
+			if assert.Nil(t, err) {
+				statusesByTxReqID[refund.TxReqID] = append(statusesByTxReqID[refund.TxReqID], inst[2])
+			}
+		}
+	}
+	assert.Equal(t, []string{common.PDETradeAcceptedChainStatus}, statusesByTxReqID[txReqIDs[0]])
+	assert.Equal(t, []string{common.PDETradeExpiredRefundChainStatus}, statusesByTxReqID[txReqIDs[1]])
+	assert.Equal(t, []string{common.PDETradeAcceptedChainStatus}, statusesByTxReqID[txReqIDs[2]])
+	assert.Equal(t, []string{
+		common.PDECrossPoolTradeExpiredFeeRefundChainStatus,
+		common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus,
+	}, statusesByTxReqID[txReqIDs[3]])
+	assert.Equal(t, []string{common.PDECrossPoolTradeAcceptedChainStatus}, statusesByTxReqID[txReqIDs[4]])
+	assert.Equal(t, []string{common.PDECrossPoolTradeAcceptedChainStatus}, statusesByTxReqID[txReqIDs[5]])
+
+	// only executed trades move the pools
+	poolPairAfter := state.PDEPoolPairs[poolPairKey]
+	assert.NotEqual(t, poolPairBefore.Token1PoolValue, poolPairAfter.Token1PoolValue)
+
+	// the content of trades without deadline is unchanged
+	for _, inst := range insts {
+		if inst[2] == common.PDETradeExpiredRefundChainStatus {
+			contentBytes, _ := base64.StdEncoding.DecodeString(inst[3])
+			assert.True(t, strings.Contains(string(contentBytes), "DeadlineBeaconHeight"))
+		}
+	}
+	v1ContentBytes, _ := base64.StdEncoding.DecodeString(buildPDETradeReqActionWithDeadline(txReqIDs[0], nil)[1])
+	assert.False(t, strings.Contains(string(v1ContentBytes), "Deadline"))
+}
+
+// Trades with deadline are rejected before BCHeightBreakPointPDETradeV2, by mempool (beacon height 0) and by beacon
+func TestPDETradeRequestV2BreakPoint(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	bc := &BlockChain{config: Config{ChainParams: &Params{BCHeightBreakPointPDETradeV2: 100}}}
+	assertErrCode := func(code int, err error) {
+		mtErr, ok := err.(*metadata.MetadataTxError)
+		if assert.True(t, ok, "%v", err) {
+			assert.Equal(t, metadata.ErrCodeMessage[code].Code, mtErr.Code)
+		}
+	}
+	tradeV2, _ := metadata.NewPDETradeRequestV2(routeTestTokenA, common.PRVCoinID.String(), 1000000, 0, 100, routeTestTrader, metadata.PDETradeDeadline{}, metadata.PDETradeRequestMetaV2)
+	crossPoolTradeV2, _ := metadata.NewPDECrossPoolTradeRequestV2(routeTestTokenB, routeTestTokenA, 1000000, 0, 100, routeTestTrader, metadata.PDETradeDeadline{}, metadata.PDECrossPoolTradeRequestMetaV2)
+	for _, meta := range []metadata.Metadata{tradeV2, crossPoolTradeV2} {
+		_, _, err := meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 99}, nil, 0, nil)
+		assertErrCode(metadata.PDETradeRequestV2NotActivatedError, err)
+		// past the break point, the deadline is checked
+		_, _, err = meta.ValidateSanityData(bc, &ShardBestState{BeaconHeight: 100}, nil, 0, nil)
+		assertErrCode(metadata.PDETradeRequestV2InvalidDeadlineError, err)
+	}
+}
+
+// Cross pool trade instructions with an unknown status are neither refunded nor accepted
+func TestPDECrossPoolTradeUnknownStatus(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	refund, _ := json.Marshal(metadata.PDERefundCrossPoolTrade{TraderAddressStr: routeTestTrader, TokenIDStr: routeTestTokenA, Amount: 100, ShardID: 1})
+	tx, err := (&BlockGenerator{}).buildPDECrossPoolTradeIssuanceTx("unknown", string(refund), nil, 1, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tx)
+}
@@ -313,7 +313,7 @@ func (blockGenerator *BlockGenerator) buildPDETradeIssuanceTx(
 	beaconView *BeaconBestState,
 ) (metadata.Transaction, error) {
 	Logger.log.Info("[PDE Trade] Starting...")
-	if instStatus == common.PDETradeRefundChainStatus {
+	if instStatus == common.PDETradeRefundChainStatus || instStatus == common.PDETradeExpiredRefundChainStatus {
 		return blockGenerator.buildPDETradeRefundTx(
 			instStatus,
 			contentStr,
@@ -342,7 +342,11 @@ func (blockGenerator *BlockGenerator) buildPDECrossPoolTradeIssuanceTx(
 	beaconView *BeaconBestState,
 ) (metadata.Transaction, error) {
 	Logger.log.Info("[PDE Cross Pool Trade] Starting...")
-	if instStatus == common.PDECrossPoolTradeFeeRefundChainStatus || instStatus == common.PDECrossPoolTradeSellingTokenRefundChainStatus {
+	switch instStatus {
+	case common.PDECrossPoolTradeFeeRefundChainStatus,
+		common.PDECrossPoolTradeSellingTokenRefundChainStatus,
+		common.PDECrossPoolTradeExpiredFeeRefundChainStatus,
+		common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus:
 		return blockGenerator.buildPDECrossPoolTradeRefundTx(
 			instStatus,
 			contentStr,
@@ -351,15 +355,18 @@ func (blockGenerator *BlockGenerator) buildPDECrossPoolTradeIssuanceTx(
 			shardView,
 			beaconView,
 		)
+	case common.PDECrossPoolTradeAcceptedChainStatus:
+		return blockGenerator.buildPDECrossPoolTradeAcceptedTx(
+			instStatus,
+			contentStr,
+			producerPrivateKey,
+			shardID,
+			shardView,
+			beaconView,
+		)
 	}
-	return blockGenerator.buildPDECrossPoolTradeAcceptedTx(
-		instStatus,
-		contentStr,
-		producerPrivateKey,
-		shardID,
-		shardView,
-		beaconView,
-	)
+	Logger.log.Errorf("ERROR: unknown pde cross pool trade status: %v", instStatus)
+	return nil, nil
 }
 
 func (blockGenerator *BlockGenerator) buildPDEWithdrawalTx(
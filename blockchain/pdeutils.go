@@ -81,6 +81,24 @@ func InitCurrentPDEStateFromDB(
 	}, nil
 }
 
+// GetPDEStateByHeight - pde state after the beacon block at beaconHeight, its keys are built with beaconHeight
+func (blockchain *BlockChain) GetPDEStateByHeight(beaconHeight uint64) (*CurrentPDEState, error) {
+	beaconFeatureStateDB, err := blockchain.GetBeaconFeatureStateDBByHeight(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	return InitCurrentPDEStateFromDB(beaconFeatureStateDB, beaconHeight)
+}
+
+// GetBeaconFeatureStateDBByHeight - beacon feature state committed by the beacon block at beaconHeight
+func (blockchain *BlockChain) GetBeaconFeatureStateDBByHeight(beaconHeight uint64) (*statedb.StateDB, error) {
+	beaconFeatureStateRootHash, err := blockchain.GetBeaconFeatureRootHash(blockchain.GetBeaconBestState(), beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.NewWithPrefixTrie(beaconFeatureStateRootHash, statedb.NewDatabaseAccessWarper(blockchain.GetBeaconChainDatabase()))
+}
+
 func storePDEStateToDB(
 	stateDB *statedb.StateDB,
 	beaconHeight uint64,
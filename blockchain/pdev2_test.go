@@ -602,7 +602,7 @@ func (s *PDETestSuiteV2) TestSimulatedBeaconBlock1002() {
 	s.Equal(getTradeMetaFromAction(tradeInst1[1]), sortedTradableActions[3].Meta)
 	s.Equal(getTradeMetaFromAction(tradeInst7[1]), sortedTradableActions[4].Meta)
 
-	tradableInsts, tradingFeePair := bc.buildInstsForSortedTradableActions(&s.currentPDEStateForProducer, beaconHeight-1, sortedTradableActions)
+	tradableInsts, tradingFeePair := bc.buildInstsForSortedTradableActions(&s.currentPDEStateForProducer, beaconHeight-1, 0, sortedTradableActions)
 	untradableInsts := bc.buildInstsForUntradableActions(untradableActions)
 	tradingFeesDistInst := bc.buildInstForTradingFeesDist(&s.currentPDEStateForProducer, beaconHeight-1, tradingFeePair)
 	newTradeInsts := append(tradableInsts, untradableInsts...)
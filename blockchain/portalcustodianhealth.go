@@ -0,0 +1,125 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// Health levels of a custodian for a portal token, by its collateral ratio
+const (
+	CustodianHealthLevelHealthy = "healthy"
+	CustodianHealthLevelTP130   = "tp130" // ratio is at most TP130
+	CustodianHealthLevelTP120   = "tp120" // ratio is at most TP120, the custodian is liquidated by exchange rates
+)
+
+// CustodianTokenHealth - collateral ratio of a custodian for a portal token with the same accounting as liquidation by exchange rates
+type CustodianTokenHealth struct {
+	TokenID                string
+	HoldPubTokenAmount     uint64 // in custodian state, waiting and matched redeem requests
+	HoldPubTokenInUSDT     uint64
+	LockedCollateralInUSDT uint64 // excluding collaterals locked for waiting porting requests
+	Ratio                  uint64 // LockedCollateralInUSDT * 100 / HoldPubTokenInUSDT
+	Level                  string
+	// moves of exchange rates, in percent, that bring the ratio down to TP120 with the other rates unchanged
+	PubTokenRateRiseToLiquidation   float64
+	CollateralRateDropToLiquidation float64
+}
+
+// CustodianHealth - collateral health of a custodian after the beacon block at BeaconHeight
+type CustodianHealth struct {
+	IncognitoAddress string
+	BeaconHeight     uint64
+	Level            string // worst level of its tokens
+	Tokens           map[string]CustodianTokenHealth
+}
+
+func custodianHealthLevelOf(ratio uint64, portalParams PortalParams) string {
+	tp120, ok := checkTPRatio(ratio, portalParams)
+	if !ok {
+		return CustodianHealthLevelHealthy
+	}
+	if tp120 {
+		return CustodianHealthLevelTP120
+	}
+	return CustodianHealthLevelTP130
+}
+
+// CustodianHealthLevelSeverity - higher is closer to liquidation
+func CustodianHealthLevelSeverity(level string) int {
+	switch level {
+	case CustodianHealthLevelTP120:
+		return 2
+	case CustodianHealthLevelTP130:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CalCustodianHealth - collateral ratios of custodianState for each portal token it holds at final exchange rates of portalState
+func CalCustodianHealth(
+	portalState *CurrentPortalState,
+	custodianState *statedb.CustodianState,
+	beaconHeight uint64,
+	portalParams PortalParams,
+) (*CustodianHealth, error) {
+	if portalState.FinalExchangeRatesState == nil {
+		return nil, fmt.Errorf("Final exchange rates are empty")
+	}
+	exchangeTool := NewPortalExchangeRateTool(portalState.FinalExchangeRatesState, portalParams.SupportedCollateralTokens)
+	lockedAmount, err := convertLockCollateralsToUSDTExcludeWPorting(exchangeTool, custodianState, portalState)
+	if err != nil {
+		return nil, err
+	}
+	totalHoldPubToken, _, _, _ := GetHoldPubTokensByCustodian(portalState, custodianState)
+
+	health := &CustodianHealth{
+		IncognitoAddress: custodianState.GetIncognitoAddress(),
+		BeaconHeight:     beaconHeight,
+		Level:            CustodianHealthLevelHealthy,
+		Tokens:           map[string]CustodianTokenHealth{},
+	}
+	tokenIDs := make([]string, 0, len(totalHoldPubToken))
+	for tokenID := range totalHoldPubToken {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Strings(tokenIDs)
+	for _, tokenID := range tokenIDs {
+		holdAmount := totalHoldPubToken[tokenID]
+		if holdAmount == 0 {
+			continue
+		}
+		holdAmountInUSDT, err := exchangeTool.ConvertToUSD(tokenID, holdAmount)
+		if err != nil {
+			return nil, err
+		}
+		if holdAmountInUSDT == 0 {
+			continue
+		}
+		ratioBN := new(big.Int).Mul(new(big.Int).SetUint64(lockedAmount[tokenID]), big.NewInt(100))
+		ratioBN = ratioBN.Div(ratioBN, new(big.Int).SetUint64(holdAmountInUSDT))
+		ratio := ratioBN.Uint64()
+
+		tokenHealth := CustodianTokenHealth{
+			TokenID:                tokenID,
+			HoldPubTokenAmount:     holdAmount,
+			HoldPubTokenInUSDT:     holdAmountInUSDT,
+			LockedCollateralInUSDT: lockedAmount[tokenID],
+			Ratio:                  ratio,
+			Level:                  custodianHealthLevelOf(ratio, portalParams),
+		}
+		// the ratio is proportional to collateral rates and inversely proportional to the public token rate
+		if ratio > portalParams.TP120 && portalParams.TP120 > 0 {
+			tokenHealth.PubTokenRateRiseToLiquidation = (float64(ratio)/float64(portalParams.TP120) - 1) * 100
+			tokenHealth.CollateralRateDropToLiquidation = (1 - float64(portalParams.TP120)/float64(ratio)) * 100
+		}
+		if CustodianHealthLevelSeverity(tokenHealth.Level) > CustodianHealthLevelSeverity(health.Level) {
+			health.Level = tokenHealth.Level
+		}
+		health.Tokens[tokenID] = tokenHealth
+	}
+	return health, nil
+}
@@ -0,0 +1,137 @@
+package blockchain
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// isExchangeRateOracleEnabled - the oracle keeps feeder submissions across beacon blocks when the window is set,
+// otherwise final rates are the median of the requests of a single beacon block
+func isExchangeRateOracleEnabled(portalParams PortalParams) bool {
+	return portalParams.ExchangeRateWindow > 0
+}
+
+func getMinExchangeRateFeeders(portalParams PortalParams) int {
+	if portalParams.MinExchangeRateFeeders == 0 {
+		return 1
+	}
+	return int(portalParams.MinExchangeRateFeeders)
+}
+
+// addExchangeRateSubmissions appends accepted exchange rates requests of the beacon block at newBeaconHeight to the oracle window
+func addExchangeRateSubmissions(
+	oracle *metadata.ExchangeRateOracleState,
+	exchangeRatesRequests map[string]*metadata.ExchangeRatesRequestStatus,
+	newBeaconHeight uint64,
+) {
+	// sort by tx id to make the window consistent for every node
+	txReqIDs := make([]string, 0, len(exchangeRatesRequests))
+	for txReqID := range exchangeRatesRequests {
+		txReqIDs = append(txReqIDs, txReqID)
+	}
+	sort.Strings(txReqIDs)
+
+	for _, txReqID := range txReqIDs {
+		req := exchangeRatesRequests[txReqID]
+		if req.Status != common.PortalExchangeRatesAcceptedStatus {
+			continue
+		}
+		for _, rate := range req.Rates {
+			oracle.Submissions[rate.PTokenID] = append(oracle.Submissions[rate.PTokenID], metadata.ExchangeRateFeederSubmission{
+				FeederAddress: req.SenderAddress,
+				Rate:          rate.Rate,
+				BeaconHeight:  newBeaconHeight,
+				TxReqID:       txReqID,
+			})
+		}
+	}
+}
+
+// pickExchangeRatesFromOracle drops submissions out of the window ending at newBeaconHeight
+// and updates the final rate of every token whose window has submissions from enough distinct feeders.
+// The final rate is the median of the latest rate of each feeder
+func pickExchangeRatesFromOracle(
+	oracle *metadata.ExchangeRateOracleState,
+	finalExchangeRates map[string]statedb.FinalExchangeRatesDetail,
+	feederAddresses []string,
+	newBeaconHeight uint64,
+	portalParams PortalParams,
+) {
+	minBeaconHeight := uint64(0)
+	if newBeaconHeight >= portalParams.ExchangeRateWindow {
+		minBeaconHeight = newBeaconHeight - portalParams.ExchangeRateWindow + 1
+	}
+	minFeeders := getMinExchangeRateFeeders(portalParams)
+
+	for tokenID, submissions := range oracle.Submissions {
+		inWindowSubmissions := []metadata.ExchangeRateFeederSubmission{}
+		latestRateByFeeder := map[string]uint64{}
+		for _, submission := range submissions {
+			if submission.BeaconHeight < minBeaconHeight {
+				continue
+			}
+			inWindowSubmissions = append(inWindowSubmissions, submission)
+			// submissions of removed feeders stay in the window but are not counted
+			if isFeeder, _ := common.SliceExists(feederAddresses, submission.FeederAddress); isFeeder {
+				latestRateByFeeder[submission.FeederAddress] = submission.Rate
+			}
+		}
+		if len(inWindowSubmissions) == 0 {
+			delete(oracle.Submissions, tokenID)
+			continue
+		}
+		oracle.Submissions[tokenID] = inWindowSubmissions
+
+		if len(latestRateByFeeder) < minFeeders {
+			Logger.log.Warnf("Portal exchange rate oracle: token %v has %v distinct feeders, less than %v", tokenID, len(latestRateByFeeder), minFeeders)
+			continue
+		}
+		rates := make([]uint64, 0, len(latestRateByFeeder))
+		for _, rate := range latestRateByFeeder {
+			rates = append(rates, rate)
+		}
+		sort.Slice(rates, func(i, j int) bool {
+			return rates[i] < rates[j]
+		})
+		medianRate := calcMedian(rates)
+		if medianRate > 0 {
+			finalExchangeRates[tokenID] = statedb.FinalExchangeRatesDetail{Amount: medianRate}
+			oracle.LastQuorumHeights[tokenID] = newBeaconHeight
+		}
+	}
+}
+
+// IsExchangeRateOracleStale - whether some final rate used by portal did not reach feeder quorum in the beacon block at beaconHeight,
+// liquidation by exchange rates is paused until the quorum is back
+func IsExchangeRateOracleStale(currentPortalState *CurrentPortalState, beaconHeight uint64, portalParams PortalParams) bool {
+	if !isExchangeRateOracleEnabled(portalParams) {
+		return false
+	}
+	if currentPortalState.ExchangeRateOracle == nil || currentPortalState.FinalExchangeRatesState == nil {
+		return true
+	}
+	for tokenID := range currentPortalState.FinalExchangeRatesState.Rates() {
+		if !isPortalExchangeRateTokenByParams(tokenID, portalParams) {
+			continue
+		}
+		if currentPortalState.ExchangeRateOracle.LastQuorumHeights[tokenID] < beaconHeight {
+			return true
+		}
+	}
+	return false
+}
+
+func isPortalExchangeRateTokenByParams(tokenID string, portalParams PortalParams) bool {
+	if isIncognitoTokenID(tokenID) {
+		return true
+	}
+	for _, collateral := range portalParams.SupportedCollateralTokens {
+		if collateral.ExternalTokenID == tokenID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oracleTestFeeder1 = "feeder1"
+	oracleTestFeeder2 = "feeder2"
+	oracleTestFeeder3 = "feeder3"
+)
+
+func newOracleTestBlockChain(portalParams PortalParams) *BlockChain {
+	return &BlockChain{
+		config: Config{
+			ChainParams: &Params{
+				PortalFeederAddress: oracleTestFeeder1,
+				PortalParams:        map[uint64]PortalParams{0: portalParams},
+			},
+		},
+	}
+}
+
+func newOracleTestPortalState(rates map[string]statedb.FinalExchangeRatesDetail) *CurrentPortalState {
+	return &CurrentPortalState{
+		FinalExchangeRatesState: statedb.NewFinalExchangeRatesStateWithValue(rates),
+		ExchangeRatesRequests:   map[string]*metadata.ExchangeRatesRequestStatus{},
+	}
+}
+
+func submitOracleTestRate(state *CurrentPortalState, txReqID string, feeder string, tokenID string, rate uint64) {
+	state.ExchangeRatesRequests[txReqID] = metadata.NewExchangeRatesRequestStatus(
+		common.PortalExchangeRatesAcceptedStatus,
+		feeder,
+		[]*metadata.ExchangeRateInfo{{PTokenID: tokenID, Rate: rate}},
+	)
+}
+
+func TestGetPortalFeederAddresses(t *testing.T) {
+	bc := newOracleTestBlockChain(PortalParams{})
+	assert.Equal(t, []string{oracleTestFeeder1}, bc.GetPortalFeederAddresses(10))
+
+	bc.config.ChainParams.PortalParams[100] = PortalParams{PortalFeeders: []string{oracleTestFeeder2, oracleTestFeeder3}}
+	assert.Equal(t, []string{oracleTestFeeder1}, bc.GetPortalFeederAddresses(99))
+	assert.Equal(t, []string{oracleTestFeeder2, oracleTestFeeder3}, bc.GetPortalFeederAddresses(100))
+}
+
+// mempool validates exchange rates with beacon height 0
+func TestPortalFeederAddressesInMempool(t *testing.T) {
+	bc := newOracleTestBlockChain(PortalParams{})
+	bc.config.ChainParams.PortalParams[100] = PortalParams{PortalFeeders: []string{oracleTestFeeder2}}
+	bc.BeaconChain = &BeaconChain{multiView: multiview.NewMultiView()}
+	beaconState := NewBeaconBestState()
+	beaconState.BestBlock = BeaconBlock{Header: BeaconHeader{Height: 120}}
+	bc.BeaconChain.multiView.AddView(beaconState)
+
+	meta, _ := metadata.NewPortalExchangeRates(metadata.PortalExchangeRatesMeta, oracleTestFeeder1, nil)
+	_, _, err := meta.ValidateSanityData(bc, nil, nil, 0, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{oracleTestFeeder2}, bc.GetPortalFeederAddresses(0))
+}
+
+func TestPickExchangesRatesFinalWithOracle(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	portalParams := PortalParams{
+		PortalFeeders:          []string{oracleTestFeeder1, oracleTestFeeder2, oracleTestFeeder3},
+		ExchangeRateWindow:     3,
+		MinExchangeRateFeeders: 2,
+	}
+	bc := newOracleTestBlockChain(portalParams)
+	state := newOracleTestPortalState(map[string]statedb.FinalExchangeRatesDetail{
+		common.PRVIDStr: {Amount: 1000},
+	})
+
+	// one feeder is not enough to move the rate
+	submitOracleTestRate(state, "tx1", oracleTestFeeder1, common.PRVIDStr, 2000)
+	bc.pickExchangesRatesFinal(state, 9, portalParams)
+	assert.Equal(t, uint64(1000), state.FinalExchangeRatesState.Rates()[common.PRVIDStr].Amount)
+	assert.True(t, IsExchangeRateOracleStale(state, 10, portalParams))
+
+	// the second feeder reaches quorum, the latest rate of each feeder is used
+	state.ExchangeRatesRequests = map[string]*metadata.ExchangeRatesRequestStatus{}
+	submitOracleTestRate(state, "tx2", oracleTestFeeder1, common.PRVIDStr, 3000)
+	submitOracleTestRate(state, "tx3", oracleTestFeeder2, common.PRVIDStr, 4000)
+	bc.pickExchangesRatesFinal(state, 10, portalParams)
+	assert.Equal(t, uint64(3500), state.FinalExchangeRatesState.Rates()[common.PRVIDStr].Amount)
+	assert.Len(t, state.ExchangeRateOracle.Submissions[common.PRVIDStr], 3)
+	assert.False(t, IsExchangeRateOracleStale(state, 11, portalParams))
+
+	// the submission at 10 dropped out of the window
+	state.ExchangeRatesRequests = map[string]*metadata.ExchangeRatesRequestStatus{}
+	submitOracleTestRate(state, "tx4", oracleTestFeeder3, common.PRVIDStr, 5000)
+	bc.pickExchangesRatesFinal(state, 12, portalParams)
+	assert.Equal(t, uint64(4000), state.FinalExchangeRatesState.Rates()[common.PRVIDStr].Amount)
+	assert.Len(t, state.ExchangeRateOracle.Submissions[common.PRVIDStr], 3)
+
+	// submissions at 11 dropped out of the window, only feeder 3 is left
+	state.ExchangeRatesRequests = map[string]*metadata.ExchangeRatesRequestStatus{}
+	bc.pickExchangesRatesFinal(state, 14, portalParams)
+	assert.Equal(t, uint64(4000), state.FinalExchangeRatesState.Rates()[common.PRVIDStr].Amount)
+	assert.Len(t, state.ExchangeRateOracle.Submissions[common.PRVIDStr], 1)
+	assert.True(t, IsExchangeRateOracleStale(state, 15, portalParams))
+}
+
+func TestPickExchangesRatesFinalWithoutOracle(t *testing.T) {
+	portalParams := PortalParams{}
+	bc := newOracleTestBlockChain(portalParams)
+	state := newOracleTestPortalState(map[string]statedb.FinalExchangeRatesDetail{})
+	submitOracleTestRate(state, "tx1", oracleTestFeeder1, common.PRVIDStr, 2000)
+	bc.pickExchangesRatesFinal(state, 9, portalParams)
+	assert.Equal(t, uint64(2000), state.FinalExchangeRatesState.Rates()[common.PRVIDStr].Amount)
+	assert.Nil(t, state.ExchangeRateOracle)
+	assert.False(t, IsExchangeRateOracleStale(state, 10, portalParams))
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
-	"github.com/incognitochain/incognito-chain/metadata"
 	"math"
 	"math/big"
 	"sort"
@@ -18,10 +17,16 @@ type PortalExchangeRateTool struct {
 	Rates map[string]RateInfo
 }
 
+// isIncognitoTokenID returns true for PRV and portal tokens, collateral tokens are identified by their external token IDs.
+// Final exchange rates only keep tokens accepted by metadata.IsPortalExchangeRateToken
+func isIncognitoTokenID(tokenID string) bool {
+	return len(tokenID) == common.HashSize*2
+}
+
 // getDecimal returns decimal for portal token or collateral tokens
 func getDecimal(supportPortalCollateral []PortalCollateral, tokenID string) uint8 {
-	if metadata.IsPortalToken(tokenID) || tokenID == common.PRVIDStr {
-		return 9
+	if isIncognitoTokenID(tokenID) {
+		return portalTokenIncDecimals
 	}
 	for _, col := range supportPortalCollateral {
 		if tokenID == col.ExternalTokenID {
@@ -49,21 +49,15 @@ type portalManager struct {
 	portalInstructions map[int]portalInstructionProcessor
 }
 
-func NewPortalManager() *portalManager {
-	rbnbChain := &relayingBNBChain{
-		relayingChain: &relayingChain{
-			actions: [][]string{},
-		},
-	}
-	rbtcChain := &relayingBTCChain{
-		relayingChain: &relayingChain{
-			actions: [][]string{},
-		},
-	}
-
-	relayingChainProcessor := map[int]relayingProcessor{
-		metadata.RelayingBNBHeaderMeta: rbnbChain,
-		metadata.RelayingBTCHeaderMeta: rbtcChain,
+// NewPortalManager - relaying processors come from the registry, every portal token with a relayed header chain registers one
+func NewPortalManager(portalTokens map[string]PortalTokenProcessor) *portalManager {
+	relayingChainProcessor := map[int]relayingProcessor{}
+	for _, portalToken := range portalTokens {
+		relayingToken, ok := portalToken.(portalRelayingTokenProcessor)
+		if !ok {
+			continue
+		}
+		relayingChainProcessor[relayingToken.GetRelayingHeaderMetaType()] = relayingToken.newRelayingProcessor()
 	}
 
 	portalInstProcessor := map[int]portalInstructionProcessor{
@@ -0,0 +1,311 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/binance-chain/go-sdk/types/msg"
+	bnbtx "github.com/binance-chain/go-sdk/types/tx"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/relaying/bnb"
+	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
+)
+
+// portalProofPayment - remote address the proven external tx must pay, amount is in incognito decimals
+type portalProofPayment struct {
+	remoteAddress string
+	amount        uint64
+}
+
+func portingProofPayments(portingReq *statedb.WaitingPortingRequest) []portalProofPayment {
+	payments := []portalProofPayment{}
+	for _, cusDetail := range portingReq.Custodians() {
+		payments = append(payments, portalProofPayment{remoteAddress: cusDetail.RemoteAddress, amount: cusDetail.Amount})
+	}
+	return payments
+}
+
+func redeemProofPayments(redeemReq *statedb.RedeemRequest, matchedCustodian *statedb.MatchingRedeemCustodianDetail) []portalProofPayment {
+	return []portalProofPayment{{remoteAddress: redeemReq.GetRedeemerRemoteAddress(), amount: matchedCustodian.GetAmount()}}
+}
+
+// VerifyUTXOProof checks the tx of proof is in a block of headerChain with enough confirmations
+func VerifyUTXOProof(proof string, headerChain *btcrelaying.BlockChain) (*btcrelaying.BTCProof, error) {
+	btcTxProof, err := btcrelaying.ParseBTCProofFromB64EncodeStr(proof)
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofFormatError, err)
+	}
+	if btcTxProof.BTCTx == nil || btcTxProof.BlockHash == nil {
+		return nil, NewBlockChainError(PortalProofFormatError, errors.New("tx and block hash of btc proof should not be null"))
+	}
+
+	blockHeight, err := headerChain.BlockHeightByHash(btcTxProof.BlockHash)
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofHeaderNotRelayedError, err)
+	}
+	bestHeight := headerChain.BestSnapshot().Height
+	if bestHeight < blockHeight+btcrelaying.BTCBlockConfirmations {
+		return nil, NewBlockChainError(PortalProofConfirmationsError,
+			fmt.Errorf("need %v confirmations of block %v at height %v, relayed best height is %v",
+				btcrelaying.BTCBlockConfirmations, btcTxProof.BlockHash, blockHeight, bestHeight))
+	}
+
+	isValid, err := headerChain.VerifyTxWithMerkleProofs(btcTxProof)
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofTxNotInBlockError, err)
+	}
+	if !isValid {
+		return nil, NewBlockChainError(PortalProofTxNotInBlockError,
+			fmt.Errorf("merkle proofs of tx %v do not match block %v", btcTxProof.BTCTx.TxHash(), btcTxProof.BlockHash))
+	}
+	return btcTxProof, nil
+}
+
+func checkUTXOMemo(btcTx *wire.MsgTx, rawMsg string) error {
+	btcAttachedMsg, err := btcrelaying.ExtractAttachedMsgFromTx(btcTx)
+	if err != nil {
+		return NewBlockChainError(PortalProofMemoError, err)
+	}
+	encodedMsg := btcrelaying.HashAndEncodeBase58(rawMsg)
+	if btcAttachedMsg != encodedMsg {
+		return NewBlockChainError(PortalProofMemoError,
+			fmt.Errorf("attached message %v is not matched with %v, the encoded hash of %v", btcAttachedMsg, encodedMsg, rawMsg))
+	}
+	return nil
+}
+
+func (p *PortalUTXOTokenProcessor) checkPayments(btcTx *wire.MsgTx, payments []portalProofPayment, headerChain *btcrelaying.BlockChain) error {
+	for _, payment := range payments {
+		amountInExternal := p.ConvertIncToExternalAmount(int64(payment.amount))
+		isChecked := false
+		for _, out := range btcTx.TxOut {
+			addrStr, err := headerChain.ExtractPaymentAddrStrFromPkScript(out.PkScript)
+			if err != nil {
+				Logger.log.Warnf("[portal] ExtractPaymentAddrStrFromPkScript: could not extract payment address string from pkscript with err: %v\n", err)
+				continue
+			}
+			if addrStr != payment.remoteAddress {
+				continue
+			}
+			if out.Value < amountInExternal {
+				return NewBlockChainError(PortalProofAmountError,
+					fmt.Errorf("the transferred amount to %s must be equal to or greater than %d, but got %d", addrStr, amountInExternal, out.Value))
+			}
+			isChecked = true
+			break
+		}
+		if !isChecked {
+			return NewBlockChainError(PortalProofReceiverError, fmt.Errorf("no output pays %v", payment.remoteAddress))
+		}
+	}
+	return nil
+}
+
+// VerifyPortingProof checks proof of the tx paying the custodians of portingReq on the relayed headerChain
+func (p *PortalUTXOTokenProcessor) VerifyPortingProof(proof string, portingReq *statedb.WaitingPortingRequest, headerChain *btcrelaying.BlockChain) error {
+	btcTxProof, err := VerifyUTXOProof(proof, headerChain)
+	if err != nil {
+		return err
+	}
+	err = checkUTXOMemo(btcTxProof.BTCTx, portingReq.UniquePortingID())
+	if err != nil {
+		return err
+	}
+	return p.checkPayments(btcTxProof.BTCTx, portingProofPayments(portingReq), headerChain)
+}
+
+// VerifyRedeemProof checks proof of the tx paying matchedCustodian's part of redeemReq on the relayed headerChain
+func (p *PortalUTXOTokenProcessor) VerifyRedeemProof(
+	proof string,
+	redeemReq *statedb.RedeemRequest,
+	matchedCustodian *statedb.MatchingRedeemCustodianDetail,
+	headerChain *btcrelaying.BlockChain) error {
+	btcTxProof, err := VerifyUTXOProof(proof, headerChain)
+	if err != nil {
+		return err
+	}
+	err = checkUTXOMemo(btcTxProof.BTCTx, fmt.Sprintf("%s%s", redeemReq.GetUniqueRedeemID(), matchedCustodian.GetIncognitoAddress()))
+	if err != nil {
+		return err
+	}
+	return p.checkPayments(btcTxProof.BTCTx, redeemProofPayments(redeemReq, matchedCustodian), headerChain)
+}
+
+// BNBHeaderStore - bnb headers a bnb proof is verified on.
+// Beacon reads them from the bnb fullnode, offline tools from the relayed header chain with RelayedBNBHeaderStore
+type BNBHeaderStore interface {
+	GetLatestBNBBlkHeight() (int64, error)
+	GetBNBDataHash(blockHeight int64) ([]byte, error)
+}
+
+// RelayedBNBHeaderStore - BNBHeaderStore of the bnb header chain relayed to beacon
+type RelayedBNBHeaderStore struct {
+	*bnb.BNBChainState
+}
+
+func (s RelayedBNBHeaderStore) GetLatestBNBBlkHeight() (int64, error) {
+	if s.LatestBlock == nil {
+		return 0, errors.New("Latest bnb block is nil")
+	}
+	return s.LatestBlock.Height, nil
+}
+
+func (s RelayedBNBHeaderStore) GetBNBDataHash(blockHeight int64) ([]byte, error) {
+	return s.GetDataHashBNBBlockByHeight(blockHeight)
+}
+
+// VerifyBNBProof checks the tx of proof is in a bnb block of headers with enough confirmations
+func VerifyBNBProof(proof string, headers BNBHeaderStore) (*bnbtx.StdTx, error) {
+	txProofBNB, bnbErr := bnb.ParseBNBProofFromB64EncodeStr(proof)
+	if bnbErr != nil {
+		return nil, NewBlockChainError(PortalProofFormatError, bnbErr)
+	}
+	if txProofBNB.Proof == nil {
+		return nil, NewBlockChainError(PortalProofFormatError, errors.New("tx proof of bnb proof should not be null"))
+	}
+
+	latestBNBBlockHeight, err := headers.GetLatestBNBBlkHeight()
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofHeaderNotRelayedError, fmt.Errorf("can not get latest bnb block height %v", err))
+	}
+	if latestBNBBlockHeight < txProofBNB.BlockHeight {
+		return nil, NewBlockChainError(PortalProofHeaderNotRelayedError,
+			fmt.Errorf("bnb block %v is above latest bnb block height %v", txProofBNB.BlockHeight, latestBNBBlockHeight))
+	}
+	if latestBNBBlockHeight < txProofBNB.BlockHeight+bnb.MinConfirmationsBlock {
+		return nil, NewBlockChainError(PortalProofConfirmationsError,
+			fmt.Errorf("need %v confirmations of bnb block %v, latest bnb block height is %v",
+				bnb.MinConfirmationsBlock, txProofBNB.BlockHeight, latestBNBBlockHeight))
+	}
+	dataHash, err := headers.GetBNBDataHash(txProofBNB.BlockHeight)
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofHeaderNotRelayedError,
+			fmt.Errorf("can not get data hash of bnb block %v %v", txProofBNB.BlockHeight, err))
+	}
+
+	isValid, bnbErr := txProofBNB.Verify(dataHash)
+	if !isValid || bnbErr != nil {
+		return nil, NewBlockChainError(PortalProofTxNotInBlockError, fmt.Errorf("verify bnb tx proof failed %v", bnbErr))
+	}
+
+	txBNB, bnbErr := bnb.ParseTxFromData(txProofBNB.Proof.Data)
+	if bnbErr != nil {
+		return nil, NewBlockChainError(PortalProofFormatError, bnbErr)
+	}
+	if len(txBNB.Msgs) == 0 {
+		return nil, NewBlockChainError(PortalProofFormatError, errors.New("bnb tx has no message"))
+	}
+	if _, ok := txBNB.Msgs[0].(msg.SendMsg); !ok {
+		return nil, NewBlockChainError(PortalProofFormatError, errors.New("bnb tx is not a send tx"))
+	}
+	return txBNB, nil
+}
+
+func decodeBNBMemo(txBNB *bnbtx.StdTx) ([]byte, error) {
+	memoBytes, err := base64.StdEncoding.DecodeString(txBNB.Memo)
+	if err != nil {
+		return nil, NewBlockChainError(PortalProofMemoError, fmt.Errorf("can not decode memo %v %v", txBNB.Memo, err))
+	}
+	return memoBytes, nil
+}
+
+func checkBNBPortingMemo(txBNB *bnbtx.StdTx, portingID string) error {
+	memoBytes, err := decodeBNBMemo(txBNB)
+	if err != nil {
+		return err
+	}
+	var portingMemo PortingMemoBNB
+	err = json.Unmarshal(memoBytes, &portingMemo)
+	if err != nil {
+		return NewBlockChainError(PortalProofMemoError, fmt.Errorf("can not unmarshal memo %v", err))
+	}
+	if portingMemo.PortingID != portingID {
+		return NewBlockChainError(PortalProofMemoError,
+			fmt.Errorf("PortingId in memoTx %v is not matched with portingID in metadata %v", portingMemo.PortingID, portingID))
+	}
+	return nil
+}
+
+func checkBNBRedeemMemo(txBNB *bnbtx.StdTx, redeemID string, custodianIncAddress string) error {
+	memoHashBytes, err := decodeBNBMemo(txBNB)
+	if err != nil {
+		return err
+	}
+	expectedRedeemMemo := RedeemMemoBNB{
+		RedeemID:                  redeemID,
+		CustodianIncognitoAddress: custodianIncAddress}
+	expectedRedeemMemoBytes, _ := json.Marshal(expectedRedeemMemo)
+	if !bytes.Equal(memoHashBytes, common.HashB(expectedRedeemMemoBytes)) {
+		return NewBlockChainError(PortalProofMemoError,
+			fmt.Errorf("memo is not the hash of RedeemID %v and CustodianIncognitoAddress %v", redeemID, custodianIncAddress))
+	}
+	return nil
+}
+
+func (p *PortalBNBTokenProcessor) checkPayments(txBNB *bnbtx.StdTx, payments []portalProofPayment, bnbChainID string) error {
+	outputs := txBNB.Msgs[0].(msg.SendMsg).Outputs
+	for _, payment := range payments {
+		amountInExternal := p.ConvertIncToExternalAmount(int64(payment.amount))
+		isChecked := false
+		for _, out := range outputs {
+			addr, _ := bnb.GetAccAddressString(&out.Address, bnbChainID)
+			if addr != payment.remoteAddress {
+				continue
+			}
+
+			// calculate amount that was transferred to the remote address
+			amountTransfer := int64(0)
+			for _, coin := range out.Coins {
+				if coin.Denom == bnb.DenomBNB {
+					amountTransfer += coin.Amount
+				}
+			}
+			if amountTransfer < amountInExternal {
+				return NewBlockChainError(PortalProofAmountError,
+					fmt.Errorf("amount transfer to %s must be equal to or greater than %d, but got %d", addr, amountInExternal, amountTransfer))
+			}
+			isChecked = true
+			break
+		}
+		if !isChecked {
+			return NewBlockChainError(PortalProofReceiverError, fmt.Errorf("no output pays %v", payment.remoteAddress))
+		}
+	}
+	return nil
+}
+
+// VerifyPortingProof checks proof of the tx paying the custodians of portingReq on headers of bnb chain bnbChainID
+func (p *PortalBNBTokenProcessor) VerifyPortingProof(proof string, portingReq *statedb.WaitingPortingRequest, headers BNBHeaderStore, bnbChainID string) error {
+	txBNB, err := VerifyBNBProof(proof, headers)
+	if err != nil {
+		return err
+	}
+	err = checkBNBPortingMemo(txBNB, portingReq.UniquePortingID())
+	if err != nil {
+		return err
+	}
+	return p.checkPayments(txBNB, portingProofPayments(portingReq), bnbChainID)
+}
+
+// VerifyRedeemProof checks proof of the tx paying matchedCustodian's part of redeemReq on headers of bnb chain bnbChainID
+func (p *PortalBNBTokenProcessor) VerifyRedeemProof(
+	proof string,
+	redeemReq *statedb.RedeemRequest,
+	matchedCustodian *statedb.MatchingRedeemCustodianDetail,
+	headers BNBHeaderStore,
+	bnbChainID string) error {
+	txBNB, err := VerifyBNBProof(proof, headers)
+	if err != nil {
+		return err
+	}
+	err = checkBNBRedeemMemo(txBNB, redeemReq.GetUniqueRedeemID(), matchedCustodian.GetIncognitoAddress())
+	if err != nil {
+		return err
+	}
+	return p.checkPayments(txBNB, redeemProofPayments(redeemReq, matchedCustodian), bnbChainID)
+}
@@ -0,0 +1,120 @@
+package blockchain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/go-sdk/types/msg"
+	bnbtx "github.com/binance-chain/go-sdk/types/tx"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/relaying/bnb"
+	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
+	"github.com/stretchr/testify/assert"
+	tdmtypes "github.com/tendermint/tendermint/types"
+)
+
+// fakeBNBHeaderStore - data hashes of relayed bnb blocks by height
+type fakeBNBHeaderStore struct {
+	latestHeight int64
+	dataHashes   map[int64][]byte
+}
+
+func (s *fakeBNBHeaderStore) GetLatestBNBBlkHeight() (int64, error) {
+	return s.latestHeight, nil
+}
+
+func (s *fakeBNBHeaderStore) GetBNBDataHash(blockHeight int64) ([]byte, error) {
+	dataHash, ok := s.dataHashes[blockHeight]
+	if !ok {
+		return nil, errors.New("bnb block is not found")
+	}
+	return dataHash, nil
+}
+
+// newTestBNBBlock - bnb block at height 100 with a tx sending amount BNB to receiver with memo
+func newTestBNBBlock(t *testing.T, receiver types.AccAddress, amount int64, memo string) (*tdmtypes.Block, []byte) {
+	sendMsg := msg.SendMsg{
+		Inputs:  []msg.Input{{Address: types.AccAddress(make([]byte, types.AddrLen)), Coins: types.Coins{{Denom: bnb.DenomBNB, Amount: amount}}}},
+		Outputs: []msg.Output{{Address: receiver, Coins: types.Coins{{Denom: bnb.DenomBNB, Amount: amount}}}},
+	}
+	txBytes, err := bnbtx.Cdc.MarshalBinaryLengthPrefixed(bnbtx.NewStdTx([]msg.Msg{sendMsg}, nil, memo, 0, nil))
+	assert.Nil(t, err)
+	block := &tdmtypes.Block{}
+	block.Height = 100
+	block.Txs = tdmtypes.Txs{tdmtypes.Tx("other tx"), tdmtypes.Tx(txBytes)}
+	return block, tdmtypes.Tx(txBytes).Hash()
+}
+
+func TestVerifyBNBPortingProof(t *testing.T) {
+	receiver := types.AccAddress([]byte("custodian-remote-add"))
+	receiverStr, _ := bnb.GetAccAddressString(&receiver, TestnetBNBChainID)
+	memoBytes, _ := json.Marshal(PortingMemoBNB{PortingID: "porting-1"})
+	memo := base64.StdEncoding.EncodeToString(memoBytes)
+
+	// 1 BNB in incognito decimals
+	portingReq := statedb.NewWaitingPortingRequestWithValue("porting-1", common.Hash{}, common.PortalBNBIDStr, "", 1e9,
+		[]*statedb.MatchingPortingCustodianDetail{{RemoteAddress: receiverStr, Amount: 1e9}}, 0, 0, 0, 0)
+	p := &PortalBNBTokenProcessor{PortalToken: &PortalToken{ChainID: TestnetBNBChainID, ExternalDecimals: 8}}
+
+	checkErrCode := func(expectedKey int, err error) {
+		bcErr, ok := err.(*BlockChainError)
+		if assert.True(t, ok, "%v", err) {
+			assert.Equal(t, ErrCodeMessage[expectedKey].Code, bcErr.Code)
+		}
+	}
+
+	block, txHash := newTestBNBBlock(t, receiver, 1e8, memo)
+	proof, err := bnb.BuildProofFromBlock(block, txHash)
+	assert.Nil(t, err)
+	headers := &fakeBNBHeaderStore{latestHeight: 103, dataHashes: map[int64][]byte{100: block.Txs.Hash()}}
+	assert.Nil(t, p.VerifyPortingProof(proof, portingReq, headers, TestnetBNBChainID))
+
+	_, err = VerifyBNBProof("not a proof", headers)
+	checkErrCode(PortalProofFormatError, err)
+
+	err = p.VerifyPortingProof(proof, portingReq, &fakeBNBHeaderStore{latestHeight: 99}, TestnetBNBChainID)
+	checkErrCode(PortalProofHeaderNotRelayedError, err)
+
+	err = p.VerifyPortingProof(proof, portingReq, &fakeBNBHeaderStore{latestHeight: 102, dataHashes: headers.dataHashes}, TestnetBNBChainID)
+	checkErrCode(PortalProofConfirmationsError, err)
+
+	err = p.VerifyPortingProof(proof, portingReq, &fakeBNBHeaderStore{latestHeight: 103, dataHashes: map[int64][]byte{100: common.HashB([]byte("other"))}}, TestnetBNBChainID)
+	checkErrCode(PortalProofTxNotInBlockError, err)
+
+	otherPortingReq := statedb.NewWaitingPortingRequestWithValue("porting-2", common.Hash{}, common.PortalBNBIDStr, "", 1e9,
+		portingReq.Custodians(), 0, 0, 0, 0)
+	err = p.VerifyPortingProof(proof, otherPortingReq, headers, TestnetBNBChainID)
+	checkErrCode(PortalProofMemoError, err)
+
+	block, txHash = newTestBNBBlock(t, receiver, 1e8-1, memo)
+	proof, _ = bnb.BuildProofFromBlock(block, txHash)
+	headers.dataHashes[100] = block.Txs.Hash()
+	err = p.VerifyPortingProof(proof, portingReq, headers, TestnetBNBChainID)
+	checkErrCode(PortalProofAmountError, err)
+
+	block, txHash = newTestBNBBlock(t, types.AccAddress([]byte("another-remote-addre")), 1e8, memo)
+	proof, _ = bnb.BuildProofFromBlock(block, txHash)
+	headers.dataHashes[100] = block.Txs.Hash()
+	err = p.VerifyPortingProof(proof, portingReq, headers, TestnetBNBChainID)
+	checkErrCode(PortalProofReceiverError, err)
+}
+
+func TestCheckUTXOMemo(t *testing.T) {
+	btcTx := wire.NewMsgTx(wire.TxVersion)
+	memo := btcrelaying.HashAndEncodeBase58("redeem-1" + "custodian-address")
+	script, _ := txscript.NullDataScript([]byte(memo))
+	btcTx.AddTxOut(wire.NewTxOut(0, script))
+
+	assert.Nil(t, checkUTXOMemo(btcTx, "redeem-1"+"custodian-address"))
+	err := checkUTXOMemo(btcTx, "redeem-2"+"custodian-address")
+	bcErr, ok := err.(*BlockChainError)
+	if assert.True(t, ok) {
+		assert.Equal(t, ErrCodeMessage[PortalProofMemoError].Code, bcErr.Code)
+	}
+}
@@ -1,409 +1,221 @@
 package blockchain
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"github.com/binance-chain/go-sdk/types/msg"
-	"github.com/incognitochain/incognito-chain/common"
+	"math"
+	"sort"
+
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/relaying/bnb"
 	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
 )
 
+// portal tokens on incognito chain have 9 decimals
+const portalTokenIncDecimals = 9
+
+// PortalTokenProcessor - a portal token is defined by params, ChainParams.PortalTokens is the registry by incognito token ID
 type PortalTokenProcessor interface {
 	ParseAndVerifyProofForPorting(proof string, portingReq *statedb.WaitingPortingRequest, bc *BlockChain) (bool, error)
 	ParseAndVerifyProofForRedeem(proof string, redeemReq *statedb.RedeemRequest, bc *BlockChain, matchedCustodian *statedb.MatchingRedeemCustodianDetail) (bool, error)
-	IsValidRemoteAddress(address string) (bool, error)
-	GetChainID() (string)
+	IsValidRemoteAddress(address string, bc *BlockChain) (bool, error)
+	GetChainID() string
+	GetMinTokenAmount() uint64
+	GetActivatedBeaconHeight() uint64
+	ConvertIncToExternalAmount(incAmount int64) int64
 }
 
-type PortalToken struct {
-	ChainID string
+// portalRelayingTokenProcessor - portal token whose external chain headers are relayed to beacon by txs with RelayingHeader metadata
+type portalRelayingTokenProcessor interface {
+	GetRelayingHeaderMetaType() int
+	newRelayingProcessor() relayingProcessor
 }
 
-type PortalBTCTokenProcessor struct {
-	*PortalToken
+type PortalToken struct {
+	ChainID               string
+	MinTokenAmount        uint64 // to avoid attacking with amount less than the smallest unit of the external coin
+	ExternalDecimals      uint8
+	ActivatedBeaconHeight uint64
 }
 
-func (p *PortalBTCTokenProcessor) ParseAndVerifyProofForPorting(proof string, portingReq *statedb.WaitingPortingRequest, bc *BlockChain) (bool, error){
-	btcChain := bc.config.BTCChain
-	if btcChain == nil {
-		Logger.log.Error("BTC relaying chain should not be null")
-		return false, errors.New("BTC relaying chain should not be null")
-	}
-	// parse PortingProof in meta
-	btcTxProof, err := btcrelaying.ParseBTCProofFromB64EncodeStr(proof)
-	if err != nil {
-		Logger.log.Errorf("PortingProof is invalid %v\n", err)
-		return false, fmt.Errorf("PortingProof is invalid %v\n", err)
-	}
-
-	isValid, err := btcChain.VerifyTxWithMerkleProofs(btcTxProof)
-	if !isValid || err != nil {
-		Logger.log.Errorf("Verify btcTxProof failed %v", err)
-		return false, fmt.Errorf("Verify btcTxProof failed %v", err)
-	}
-
-	// extract attached message from txOut's OP_RETURN
-	btcAttachedMsg, err := btcrelaying.ExtractAttachedMsgFromTx(btcTxProof.BTCTx)
-	if err != nil {
-		Logger.log.Errorf("Could not extract attached message from BTC tx proof with err: %v", err)
-		return false, fmt.Errorf("Could not extract attached message from BTC tx proof with err: %v", err)
-	}
-
-	encodedMsg := btcrelaying.HashAndEncodeBase58(portingReq.UniquePortingID())
-	if btcAttachedMsg != encodedMsg {
-		Logger.log.Errorf("PortingId in the btc attached message is not matched with portingID in metadata")
-		return false, fmt.Errorf("PortingId in the btc attached message %v is not matched with portingID in metadata %v", btcAttachedMsg, encodedMsg)
-	}
-
-	// check whether amount transfer in txBNB is equal porting amount or not
-	// check receiver and amount in tx
-	// get list matching custodians in waitingPortingRequest
-	custodians := portingReq.Custodians()
-	outputs := btcTxProof.BTCTx.TxOut
-	for _, cusDetail := range custodians {
-		remoteAddressNeedToBeTransfer := cusDetail.RemoteAddress
-		amountNeedToBeTransfer := cusDetail.Amount
-		amountNeedToBeTransferInBTC := btcrelaying.ConvertIncPBTCAmountToExternalBTCAmount(int64(amountNeedToBeTransfer))
-
-		isChecked := false
-		for _, out := range outputs {
-			addrStr, err := btcChain.ExtractPaymentAddrStrFromPkScript(out.PkScript)
-			if err != nil {
-				Logger.log.Errorf("[portal] ExtractPaymentAddrStrFromPkScript: could not extract payment address string from pkscript with err: %v\n", err)
-				continue
-			}
-			if addrStr != remoteAddressNeedToBeTransfer {
-				continue
-			}
-			if out.Value < amountNeedToBeTransferInBTC {
-				Logger.log.Errorf("BTC-TxProof is invalid - the transferred amount to %s must be equal to or greater than %d, but got %d", addrStr, amountNeedToBeTransferInBTC, out.Value)
-				return false, fmt.Errorf("BTC-TxProof is invalid - the transferred amount to %s must be equal to or greater than %d, but got %d", addrStr, amountNeedToBeTransferInBTC, out.Value)
-			} else {
-				isChecked = true
-				break
-			}
-		}
-		if !isChecked {
-			Logger.log.Error("BTC-TxProof is invalid")
-			return false, errors.New("BTC-TxProof is invalid")
-		}
-	}
+func (p *PortalToken) GetChainID() string {
+	return p.ChainID
+}
 
-	return true, nil
+func (p *PortalToken) GetMinTokenAmount() uint64 {
+	return p.MinTokenAmount
 }
 
-func (p *PortalBTCTokenProcessor) ParseAndVerifyProofForRedeem(
-	proof string,
-	redeemReq *statedb.RedeemRequest,
-	bc *BlockChain,
-	matchedCustodian *statedb.MatchingRedeemCustodianDetail) (bool, error){
-	btcChain := bc.config.BTCChain
-	if btcChain == nil {
-		Logger.log.Error("BTC relaying chain should not be null")
-		return false, errors.New("BTC relaying chain should not be null")
-	}
-	// parse PortingProof in meta
-	btcTxProof, err := btcrelaying.ParseBTCProofFromB64EncodeStr(proof)
-	if err != nil {
-		Logger.log.Errorf("RedeemProof is invalid %v\n", err)
-		return false, fmt.Errorf("RedeemProof is invalid %v\n", err)
-	}
+func (p *PortalToken) GetActivatedBeaconHeight() uint64 {
+	return p.ActivatedBeaconHeight
+}
 
-	isValid, err := btcChain.VerifyTxWithMerkleProofs(btcTxProof)
-	if !isValid || err != nil {
-		Logger.log.Errorf("Verify btcTxProof failed %v", err)
-		return false, fmt.Errorf("Verify btcTxProof failed %v", err)
+// ConvertIncToExternalAmount converts amount in inc chain (decimal 9) to amount in the external chain
+func (p *PortalToken) ConvertIncToExternalAmount(incAmount int64) int64 {
+	if p.ExternalDecimals >= portalTokenIncDecimals {
+		return incAmount * int64(math.Pow10(int(p.ExternalDecimals-portalTokenIncDecimals)))
 	}
+	return incAmount / int64(math.Pow10(int(portalTokenIncDecimals-p.ExternalDecimals)))
+}
 
-	// extract attached message from txOut's OP_RETURN
-	btcAttachedMsg, err := btcrelaying.ExtractAttachedMsgFromTx(btcTxProof.BTCTx)
-	if err != nil {
-		Logger.log.Errorf("Could not extract message from btc proof with error: %v", err)
-		return false, fmt.Errorf("Could not extract message from btc proof with error: %v", err)
+// GetPortalToken returns the processor of tokenIDStr if it is a portal token enabled at beaconHeight, otherwise nil.
+// beaconHeight 0 (txs validated by mempool) means the height of the best beacon state
+func (blockchain *BlockChain) GetPortalToken(beaconHeight uint64, tokenIDStr string) PortalTokenProcessor {
+	portalToken, ok := blockchain.GetConfig().ChainParams.PortalTokens[tokenIDStr]
+	if ok && beaconHeight == 0 {
+		beaconHeight = blockchain.GetBeaconBestState().GetHeight()
 	}
-
-	rawMsg := fmt.Sprintf("%s%s", redeemReq.GetUniqueRedeemID(), matchedCustodian.GetIncognitoAddress())
-	encodedMsg := btcrelaying.HashAndEncodeBase58(rawMsg)
-	if btcAttachedMsg != encodedMsg {
-		Logger.log.Errorf("The hash of combination of UniqueRedeemID(%s) and CustodianAddressStr(%s) is not matched to tx's attached message",
-			redeemReq.GetUniqueRedeemID(),  matchedCustodian.GetIncognitoAddress())
-		return false, fmt.Errorf("The hash of combination of UniqueRedeemID(%s) and CustodianAddressStr(%s) is not matched to tx's attached message",
-			redeemReq.GetUniqueRedeemID(),  matchedCustodian.GetIncognitoAddress())
+	if !ok || beaconHeight < portalToken.GetActivatedBeaconHeight() {
+		return nil
 	}
+	return portalToken
+}
 
-	// check whether amount transfer in txBNB is equal redeem amount or not
-	// check receiver and amount in tx
-	// get list matching custodians in matchedRedeemRequest
-
-	outputs := btcTxProof.BTCTx.TxOut
-	remoteAddressNeedToBeTransfer := redeemReq.GetRedeemerRemoteAddress()
-	amountNeedToBeTransfer := matchedCustodian.GetAmount()
-	amountNeedToBeTransferInBTC := btcrelaying.ConvertIncPBTCAmountToExternalBTCAmount(int64(amountNeedToBeTransfer))
-
-	isChecked := false
-	for _, out := range outputs {
-		addrStr, err := btcChain.ExtractPaymentAddrStrFromPkScript(out.PkScript)
-		if err != nil {
-			Logger.log.Warnf("[portal] ExtractPaymentAddrStrFromPkScript: could not extract payment address string from pkscript with err: %v\n", err)
-			continue
-		}
-		if addrStr != remoteAddressNeedToBeTransfer {
-			continue
-		}
-		if out.Value < amountNeedToBeTransferInBTC {
-			Logger.log.Errorf("BTC-TxProof is invalid - the transferred amount to %s must be equal to or greater than %d, but got %d", addrStr, amountNeedToBeTransferInBTC, out.Value)
-			return false, fmt.Errorf("BTC-TxProof is invalid - the transferred amount to %s must be equal to or greater than %d, but got %d", addrStr, amountNeedToBeTransferInBTC, out.Value)
-		} else {
-			isChecked = true
-			break
+// GetPortalTokenIDs returns sorted IDs of portal tokens enabled at beaconHeight
+func (blockchain *BlockChain) GetPortalTokenIDs(beaconHeight uint64) []string {
+	tokenIDs := []string{}
+	for tokenID := range blockchain.GetConfig().ChainParams.PortalTokens {
+		if blockchain.GetPortalToken(beaconHeight, tokenID) != nil {
+			tokenIDs = append(tokenIDs, tokenID)
 		}
 	}
+	sort.Strings(tokenIDs)
+	return tokenIDs
+}
 
-	if !isChecked {
-		Logger.log.Error("BTC-TxProof is invalid")
-		return false, errors.New("BTC-TxProof is invalid")
+func (blockchain *BlockChain) IsPortalToken(beaconHeight uint64, tokenIDStr string) bool {
+	return blockchain.GetPortalToken(beaconHeight, tokenIDStr) != nil
+}
+
+func (blockchain *BlockChain) IsValidPortalRemoteAddress(tokenIDStr string, remoteAddress string, beaconHeight uint64) (bool, error) {
+	portalToken := blockchain.GetPortalToken(beaconHeight, tokenIDStr)
+	if portalToken == nil {
+		return false, fmt.Errorf("TokenID %v is not a portal token at beacon height %v", tokenIDStr, beaconHeight)
 	}
-	return true, nil
+	return portalToken.IsValidRemoteAddress(remoteAddress, blockchain)
 }
 
-//todo:
-func (p *PortalBTCTokenProcessor) IsValidRemoteAddress(address string) (bool, error) {
-	return true, nil
+func (blockchain *BlockChain) GetMinAmountPortalToken(tokenIDStr string, beaconHeight uint64) (uint64, error) {
+	portalToken := blockchain.GetPortalToken(beaconHeight, tokenIDStr)
+	if portalToken == nil {
+		return 0, fmt.Errorf("TokenID %v is not a portal token at beacon height %v", tokenIDStr, beaconHeight)
+	}
+	return portalToken.GetMinTokenAmount(), nil
 }
 
-func (p *PortalBTCTokenProcessor) GetChainID() string {
-	return p.ChainID
+// GetUTXOHeaderChain returns the relayed header chain of UTXO portal tokens by their relaying header meta type
+func (blockchain *BlockChain) GetUTXOHeaderChain(relayingHeaderMetaType int) *btcrelaying.BlockChain {
+	if relayingHeaderMetaType == metadata.RelayingBTCHeaderMeta {
+		return blockchain.config.BTCChain
+	}
+	return blockchain.config.UTXOChains[relayingHeaderMetaType]
 }
 
-type PortalBNBTokenProcessor struct {
+// PortalUTXOTokenProcessor - portal token of a btcd-compatible UTXO chain.
+// Headers are relayed by txs of RelayingHeaderMetaType and proofs are verified on the header chain by relaying/btc
+type PortalUTXOTokenProcessor struct {
 	*PortalToken
+	RelayingHeaderMetaType int
+	// header chain the node creates on start, not used for BTC which has its own chain by BTCRelayingHeaderChainID
+	HeaderChainParams             *chaincfg.Params
+	HeaderChainGenesisBlockHeight int32
+	HeaderChainDataFolderName     string
 }
 
-func (p *PortalBNBTokenProcessor) ParseAndVerifyProofForPorting(proof string, portingReq *statedb.WaitingPortingRequest, bc *BlockChain) (bool, error) {
-	// parse PortingProof in meta
-	txProofBNB, err := bnb.ParseBNBProofFromB64EncodeStr(proof)
-	if err != nil {
-		Logger.log.Errorf("PortingProof is invalid %v\n", err)
-		return false, fmt.Errorf("PortingProof is invalid %v\n", err)
-	}
-
-	// check minimum confirmations block of bnb proof
-	latestBNBBlockHeight, err2 := bc.GetLatestBNBBlkHeight()
-	if err2 != nil {
-		Logger.log.Errorf("Can not get latest relaying bnb block height %v\n", err)
-		return false, fmt.Errorf("Can not get latest relaying bnb block height %v\n", err)
-	}
+func (p *PortalUTXOTokenProcessor) GetRelayingHeaderMetaType() int {
+	return p.RelayingHeaderMetaType
+}
 
-	if latestBNBBlockHeight < txProofBNB.BlockHeight+bnb.MinConfirmationsBlock {
-		Logger.log.Errorf("Not enough min bnb confirmations block %v, latestBNBBlockHeight %v - txProofBNB.BlockHeight %v\n",
-			bnb.MinConfirmationsBlock, latestBNBBlockHeight, txProofBNB.BlockHeight)
-		return false, fmt.Errorf("Not enough min bnb confirmations block %v, latestBNBBlockHeight %v - txProofBNB.BlockHeight %v\n",
-			bnb.MinConfirmationsBlock, latestBNBBlockHeight, txProofBNB.BlockHeight)
-	}
-	dataHash, err2 := bc.GetBNBDataHash(txProofBNB.BlockHeight)
-	if err2 != nil {
-		Logger.log.Errorf("Error when get data hash in blockHeight %v - %v\n",
-			txProofBNB.BlockHeight, err2)
-		return false, fmt.Errorf("Error when get data hash in blockHeight %v - %v\n",
-			txProofBNB.BlockHeight, err2)
+func (p *PortalUTXOTokenProcessor) newRelayingProcessor() relayingProcessor {
+	return &relayingUTXOChain{
+		relayingChain: &relayingChain{
+			actions: [][]string{},
+		},
 	}
+}
 
-	isValid, err := txProofBNB.Verify(dataHash)
-	if !isValid || err != nil {
-		Logger.log.Errorf("Verify txProofBNB failed %v", err)
-		return false, fmt.Errorf("Verify txProofBNB failed %v", err)
+func (p *PortalUTXOTokenProcessor) getHeaderChain(bc *BlockChain) (*btcrelaying.BlockChain, error) {
+	headerChain := bc.GetUTXOHeaderChain(p.RelayingHeaderMetaType)
+	if headerChain == nil {
+		Logger.log.Errorf("Relaying header chain of %v should not be null", p.ChainID)
+		return nil, fmt.Errorf("Relaying header chain of %v should not be null", p.ChainID)
 	}
+	return headerChain, nil
+}
 
-	// parse Tx from Data in txProofBNB
-	txBNB, err := bnb.ParseTxFromData(txProofBNB.Proof.Data)
+func (p *PortalUTXOTokenProcessor) ParseAndVerifyProofForPorting(proof string, portingReq *statedb.WaitingPortingRequest, bc *BlockChain) (bool, error) {
+	btcChain, err := p.getHeaderChain(bc)
 	if err != nil {
-		Logger.log.Errorf("Data in PortingProof is invalid %v", err)
-		return false, fmt.Errorf("Data in PortingProof is invalid %v", err)
+		return false, err
 	}
-
-	// check memo attach portingID req:
-	memo := txBNB.Memo
-	memoBytes, err2 := base64.StdEncoding.DecodeString(memo)
-	if err2 != nil {
-		Logger.log.Errorf("Can not decode memo in tx bnb proof", err2)
-		return false, fmt.Errorf("Data in PortingProof is invalid %v", err)
-	}
-
-	var portingMemo PortingMemoBNB
-	err2 = json.Unmarshal(memoBytes, &portingMemo)
-	if err2 != nil {
-		Logger.log.Errorf("Can not unmarshal memo in tx bnb proof", err2)
-		return false, fmt.Errorf("Data in PortingProof is invalid %v", err)
-	}
-
-	if portingMemo.PortingID != portingReq.UniquePortingID() {
-		Logger.log.Errorf("PortingId in memoTx %v is not matched with portingID in metadata %v", portingMemo.PortingID, portingReq.UniquePortingID())
-		return false, fmt.Errorf("PortingId in memoTx %v is not matched with portingID in metadata %v", portingMemo.PortingID, portingReq.UniquePortingID())
-	}
-
-	// check whether amount transfer in txBNB is equal porting amount or not
-	// check receiver and amount in tx
-	// get list matching custodians in waitingPortingRequest
-	custodians := portingReq.Custodians()
-	outputs := txBNB.Msgs[0].(msg.SendMsg).Outputs
-	for _, cusDetail := range custodians {
-		remoteAddressNeedToBeTransfer := cusDetail.RemoteAddress
-		amountNeedToBeTransfer := cusDetail.Amount
-		amountNeedToBeTransferInBNB := convertIncPBNBAmountToExternalBNBAmount(int64(amountNeedToBeTransfer))
-
-		isChecked := false
-		for _, out := range outputs {
-			addr, _ := bnb.GetAccAddressString(&out.Address, bc.config.ChainParams.BNBRelayingHeaderChainID)
-			if addr != remoteAddressNeedToBeTransfer {
-				Logger.log.Warnf("[portal] remoteAddressNeedToBeTransfer: %v - addr: %v\n", remoteAddressNeedToBeTransfer, addr)
-				continue
-			}
-
-			// calculate amount that was transferred to custodian's remote address
-			amountTransfer := int64(0)
-			for _, coin := range out.Coins {
-				if coin.Denom == bnb.DenomBNB {
-					amountTransfer += coin.Amount
-				}
-			}
-			if amountTransfer < amountNeedToBeTransferInBNB {
-				Logger.log.Errorf("TxProof-BNB is invalid - Amount transfer to %s must be equal to or greater than %d, but got %d",
-					addr, amountNeedToBeTransferInBNB, amountTransfer)
-				return false, fmt.Errorf("TxProof-BNB is invalid - Amount transfer to %s must be equal to or greater than %d, but got %d",
-					addr, amountNeedToBeTransferInBNB, amountTransfer)
-			} else {
-				isChecked = true
-				break
-			}
-		}
-		if !isChecked {
-			Logger.log.Errorf("TxProof-BNB is invalid - Receiver address is invalid, expected %v",
-				remoteAddressNeedToBeTransfer)
-			return false, fmt.Errorf("TxProof-BNB is invalid - Receiver address is invalid, expected %v",
-				remoteAddressNeedToBeTransfer)
-		}
+	err = p.VerifyPortingProof(proof, portingReq, btcChain)
+	if err != nil {
+		Logger.log.Errorf("Porting proof of %v is invalid %v", portingReq.UniquePortingID(), err)
+		return false, err
 	}
 	return true, nil
 }
 
-func (p *PortalBNBTokenProcessor) ParseAndVerifyProofForRedeem(proof string, redeemReq *statedb.RedeemRequest, bc *BlockChain, matchedCustodian *statedb.MatchingRedeemCustodianDetail) (bool, error){
-	// parse RedeemProof in meta
-	txProofBNB, err := bnb.ParseBNBProofFromB64EncodeStr(proof)
+func (p *PortalUTXOTokenProcessor) ParseAndVerifyProofForRedeem(
+	proof string,
+	redeemReq *statedb.RedeemRequest,
+	bc *BlockChain,
+	matchedCustodian *statedb.MatchingRedeemCustodianDetail) (bool, error) {
+	btcChain, err := p.getHeaderChain(bc)
 	if err != nil {
-		Logger.log.Errorf("RedeemProof is invalid %v\n", err)
-		return false, fmt.Errorf("RedeemProof is invalid %v\n", err)
+		return false, err
 	}
-
-	// check minimum confirmations block of bnb proof
-	latestBNBBlockHeight, err2 := bc.GetLatestBNBBlkHeight()
-	if err2 != nil {
-		Logger.log.Errorf("Can not get latest relaying bnb block height %v\n", err)
-		return false, fmt.Errorf("Can not get latest relaying bnb block height %v\n", err)
-	}
-
-	if latestBNBBlockHeight < txProofBNB.BlockHeight+bnb.MinConfirmationsBlock {
-		Logger.log.Errorf("Not enough min bnb confirmations block %v, latestBNBBlockHeight %v - txProofBNB.BlockHeight %v\n",
-			bnb.MinConfirmationsBlock, latestBNBBlockHeight, txProofBNB.BlockHeight)
-		return false, fmt.Errorf("Not enough min bnb confirmations block %v, latestBNBBlockHeight %v - txProofBNB.BlockHeight %v\n",
-			bnb.MinConfirmationsBlock, latestBNBBlockHeight, txProofBNB.BlockHeight)
-	}
-
-	dataHash, err2 := bc.GetBNBDataHash(txProofBNB.BlockHeight)
-	if err2 != nil {
-		Logger.log.Errorf("Error when get data hash in blockHeight %v - %v\n",
-			txProofBNB.BlockHeight, err2)
-		return false, fmt.Errorf("Error when get data hash in blockHeight %v - %v\n",
-			txProofBNB.BlockHeight, err2)
-	}
-
-	isValid, err := txProofBNB.Verify(dataHash)
-	if !isValid || err != nil {
-		Logger.log.Errorf("Verify txProofBNB failed %v", err)
-		return false, fmt.Errorf("Verify txProofBNB failed %v", err)
+	err = p.VerifyRedeemProof(proof, redeemReq, matchedCustodian, btcChain)
+	if err != nil {
+		Logger.log.Errorf("Redeem proof of %v is invalid %v", redeemReq.GetUniqueRedeemID(), err)
+		return false, err
 	}
+	return true, nil
+}
 
-	// parse Tx from Data in txProofBNB
-	txBNB, err := bnb.ParseTxFromData(txProofBNB.Proof.Data)
+func (p *PortalUTXOTokenProcessor) IsValidRemoteAddress(address string, bc *BlockChain) (bool, error) {
+	btcChain, err := p.getHeaderChain(bc)
 	if err != nil {
-		Logger.log.Errorf("Data in RedeemProof is invalid %v", err)
-		return false, fmt.Errorf("Data in RedeemProof is invalid %v", err)
+		return false, err
 	}
+	return btcChain.IsBTCAddressValid(address), nil
+}
 
-	// check memo attach redeemID req (compare hash memo)
-	memo := txBNB.Memo
-	memoHashBytes, err2 := base64.StdEncoding.DecodeString(memo)
-	if err2 != nil {
-		Logger.log.Errorf("Can not decode memo in tx bnb proof %v", err2)
-		return false, fmt.Errorf("Can not decode memo in tx bnb proof %v", err2)
-	}
+type PortalBNBTokenProcessor struct {
+	*PortalToken
+}
 
-	expectedRedeemMemo := RedeemMemoBNB{
-		RedeemID:                  redeemReq.GetUniqueRedeemID(),
-		CustodianIncognitoAddress: matchedCustodian.GetIncognitoAddress()}
-	expectedRedeemMemoBytes, _ := json.Marshal(expectedRedeemMemo)
-	expectedRedeemMemoHashBytes := common.HashB(expectedRedeemMemoBytes)
+func (p *PortalBNBTokenProcessor) GetRelayingHeaderMetaType() int {
+	return metadata.RelayingBNBHeaderMeta
+}
 
-	if !bytes.Equal(memoHashBytes, expectedRedeemMemoHashBytes) {
-		Logger.log.Errorf("Memo redeem is invalid")
-		return false, fmt.Errorf("Memo redeem is invalid")
+func (p *PortalBNBTokenProcessor) newRelayingProcessor() relayingProcessor {
+	return &relayingBNBChain{
+		relayingChain: &relayingChain{
+			actions: [][]string{},
+		},
 	}
+}
 
-	// check whether amount transfer in txBNB is equal redeem amount or not
-	// check receiver and amount in tx
-	// get list matching custodians in matchedRedeemRequest
-
-	outputs := txBNB.Msgs[0].(msg.SendMsg).Outputs
-
-	remoteAddressNeedToBeTransfer := redeemReq.GetRedeemerRemoteAddress()
-	amountNeedToBeTransfer := matchedCustodian.GetAmount()
-	amountNeedToBeTransferInBNB := convertIncPBNBAmountToExternalBNBAmount(int64(amountNeedToBeTransfer))
-
-	isChecked := false
-	for _, out := range outputs {
-		addr, _ := bnb.GetAccAddressString(&out.Address, bc.config.ChainParams.BNBRelayingHeaderChainID)
-		if addr != remoteAddressNeedToBeTransfer {
-			continue
-		}
-
-		// calculate amount that was transferred to custodian's remote address
-		amountTransfer := int64(0)
-		for _, coin := range out.Coins {
-			if coin.Denom == bnb.DenomBNB {
-				amountTransfer += coin.Amount
-				// note: log error for debug
-				Logger.log.Infof("TxProof-BNB coin.Amount %d",
-					coin.Amount)
-			}
-		}
-		if amountTransfer < amountNeedToBeTransferInBNB {
-			Logger.log.Errorf("TxProof-BNB is invalid - Amount transfer to %s must be equal to or greater than %d, but got %d",
-				addr, amountNeedToBeTransferInBNB, amountTransfer)
-			return false, fmt.Errorf("TxProof-BNB is invalid - Amount transfer to %s must be equal to or greater than %d, but got %d",
-				addr, amountNeedToBeTransferInBNB, amountTransfer)
-		} else {
-			isChecked = true
-			break
-		}
+func (p *PortalBNBTokenProcessor) ParseAndVerifyProofForPorting(proof string, portingReq *statedb.WaitingPortingRequest, bc *BlockChain) (bool, error) {
+	err := p.VerifyPortingProof(proof, portingReq, bc, bc.config.ChainParams.BNBRelayingHeaderChainID)
+	if err != nil {
+		Logger.log.Errorf("Porting proof of %v is invalid %v", portingReq.UniquePortingID(), err)
+		return false, err
 	}
+	return true, nil
+}
 
-	if !isChecked {
-		Logger.log.Errorf("TxProof-BNB is invalid - Receiver address is invalid, expected %v",
-			remoteAddressNeedToBeTransfer)
-		return false, fmt.Errorf("TxProof-BNB is invalid - Receiver address is invalid, expected %v",
-			remoteAddressNeedToBeTransfer)
+func (p *PortalBNBTokenProcessor) ParseAndVerifyProofForRedeem(proof string, redeemReq *statedb.RedeemRequest, bc *BlockChain, matchedCustodian *statedb.MatchingRedeemCustodianDetail) (bool, error) {
+	err := p.VerifyRedeemProof(proof, redeemReq, matchedCustodian, bc, bc.config.ChainParams.BNBRelayingHeaderChainID)
+	if err != nil {
+		Logger.log.Errorf("Redeem proof of %v is invalid %v", redeemReq.GetUniqueRedeemID(), err)
+		return false, err
 	}
 	return true, nil
 }
 
-func (p *PortalBNBTokenProcessor) IsValidRemoteAddress(address string) (bool, error) {
+func (p *PortalBNBTokenProcessor) IsValidRemoteAddress(address string, bc *BlockChain) (bool, error) {
 	return bnb.IsValidBNBAddress(address, p.ChainID), nil
 }
-
-func (p *PortalBNBTokenProcessor) GetChainID() string {
-	return p.ChainID
-}
@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/incognitochain/incognito-chain/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUTXOTokenID = "0000000000000000000000000000000000000000000000000000000000000909"
+
+func newTestPortalTokensChain() *BlockChain {
+	portalTokens := initPortalTokensForTestNet()
+	portalTokens[testUTXOTokenID] = &PortalUTXOTokenProcessor{
+		PortalToken: &PortalToken{
+			ChainID:               "LTC-Testnet",
+			MinTokenAmount:        10,
+			ExternalDecimals:      8,
+			ActivatedBeaconHeight: 100,
+		},
+		RelayingHeaderMetaType: metadata.RelayingUTXOHeaderMetaMin,
+		HeaderChainParams:      &chaincfg.TestNet3Params,
+	}
+	return &BlockChain{config: Config{ChainParams: &Params{PortalTokens: portalTokens}}}
+}
+
+func TestGetPortalToken(t *testing.T) {
+	bc := newTestPortalTokensChain()
+
+	assert.Nil(t, bc.GetPortalToken(99, testUTXOTokenID))
+	assert.False(t, bc.IsPortalToken(99, testUTXOTokenID))
+	assert.NotNil(t, bc.GetPortalToken(100, testUTXOTokenID))
+	assert.True(t, bc.IsPortalToken(100, common.PortalBNBIDStr))
+	assert.False(t, bc.IsPortalToken(100, common.PRVIDStr))
+
+	_, err := bc.GetMinAmountPortalToken(testUTXOTokenID, 99)
+	assert.NotNil(t, err)
+	minAmount, err := bc.GetMinAmountPortalToken(testUTXOTokenID, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), minAmount)
+
+	_, err = bc.IsValidPortalRemoteAddress(common.PRVIDStr, "", 100)
+	assert.NotNil(t, err)
+
+	assert.NotContains(t, bc.GetPortalTokenIDs(99), testUTXOTokenID)
+	tokenIDs := bc.GetPortalTokenIDs(100)
+	assert.Contains(t, tokenIDs, testUTXOTokenID)
+	assert.True(t, sort.StringsAreSorted(tokenIDs))
+}
+
+func TestConvertIncToExternalAmount(t *testing.T) {
+	tests := []struct {
+		decimals uint8
+		in       int64
+		out      int64
+	}{
+		{8, 1234567890, 123456789},
+		{9, 1234567890, 1234567890},
+		{6, 1234567890, 1234567},
+		{18, 1, 1000000000},
+	}
+	for _, tc := range tests {
+		p := &PortalToken{ExternalDecimals: tc.decimals}
+		assert.Equal(t, tc.out, p.ConvertIncToExternalAmount(tc.in))
+	}
+}
+
+func TestNewPortalManagerRelayingChains(t *testing.T) {
+	bc := newTestPortalTokensChain()
+	pm := NewPortalManager(bc.config.ChainParams.PortalTokens)
+
+	assert.Len(t, pm.relayingChains, 3)
+	assert.IsType(t, &relayingBNBChain{}, pm.relayingChains[metadata.RelayingBNBHeaderMeta])
+	assert.IsType(t, &relayingUTXOChain{}, pm.relayingChains[metadata.RelayingBTCHeaderMeta])
+	assert.IsType(t, &relayingUTXOChain{}, pm.relayingChains[metadata.RelayingUTXOHeaderMetaMin])
+}
+
+// newTestPortalTokensChainAt - newTestPortalTokensChain with best beacon state at beaconHeight
+func newTestPortalTokensChainAt(beaconHeight uint64) *BlockChain {
+	bc := newTestPortalTokensChain()
+	bc.BeaconChain = &BeaconChain{multiView: multiview.NewMultiView()}
+	beaconState := NewBeaconBestState()
+	beaconState.BestBlock = BeaconBlock{Header: BeaconHeader{Height: beaconHeight}}
+	bc.BeaconChain.multiView.AddView(beaconState)
+	return bc
+}
+
+// mempool validates sanity data of new txs with beacon height 0
+func TestPortalTokenActivationInMempool(t *testing.T) {
+	requester, _ := wallet.NewMasterKey([]byte("portal token activation requester"))
+	tx := &mocks.Transaction{}
+	tx.On("GetSigPubKey").Return([]byte(requester.KeySet.PaymentAddress.Pk))
+	tx.On("GetType").Return(common.TxNormalType)
+	meta, _ := metadata.NewPortalRequestPTokens(
+		metadata.PortalUserRequestPTokenMeta, "porting-1", testUTXOTokenID, requester.Base58CheckSerialize(wallet.PaymentAddressType), 1e9, "")
+
+	_, _, err := meta.ValidateSanityData(newTestPortalTokensChainAt(99), nil, nil, 0, tx)
+	assert.NotNil(t, err)
+	isValid, _, err := meta.ValidateSanityData(newTestPortalTokensChainAt(100), nil, nil, 0, tx)
+	assert.Nil(t, err)
+	assert.True(t, isValid)
+}
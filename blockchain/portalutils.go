@@ -26,6 +26,8 @@ type CurrentPortalState struct {
 	LockedCollateralForRewards *statedb.LockedCollateralState
 	//Store temporary exchange rates requests
 	ExchangeRatesRequests map[string]*metadata.ExchangeRatesRequestStatus // key : hash(beaconHeight | TxID)
+	// feeder submissions within the exchange rate window, nil until the oracle is enabled
+	ExchangeRateOracle *metadata.ExchangeRateOracleState
 }
 
 type CustodianStateSlice struct {
@@ -73,6 +75,18 @@ func InitCurrentPortalStateFromDB(
 	if err != nil {
 		return nil, err
 	}
+	var exchangeRateOracle *metadata.ExchangeRateOracleState
+	exchangeRateOracleBytes, has, err := statedb.GetPortalExchangeRateOracleState(stateDB)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		exchangeRateOracle = metadata.NewExchangeRateOracleState()
+		err = json.Unmarshal(exchangeRateOracleBytes, exchangeRateOracle)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &CurrentPortalState{
 		CustodianPoolState:         custodianPoolState,
@@ -83,9 +97,19 @@ func InitCurrentPortalStateFromDB(
 		ExchangeRatesRequests:      make(map[string]*metadata.ExchangeRatesRequestStatus),
 		LiquidationPool:            liquidateExchangeRatesPool,
 		LockedCollateralForRewards: lockedCollateralState,
+		ExchangeRateOracle:         exchangeRateOracle,
 	}, nil
 }
 
+// GetPortalStateByHeight - portal state after the beacon block at beaconHeight
+func (blockchain *BlockChain) GetPortalStateByHeight(beaconHeight uint64) (*CurrentPortalState, error) {
+	beaconFeatureStateDB, err := blockchain.GetBeaconFeatureStateDBByHeight(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	return InitCurrentPortalStateFromDB(beaconFeatureStateDB)
+}
+
 func storePortalStateToDB(
 	stateDB *statedb.StateDB,
 	currentPortalState *CurrentPortalState,
@@ -118,6 +142,16 @@ func storePortalStateToDB(
 	if err != nil {
 		return err
 	}
+	if currentPortalState.ExchangeRateOracle != nil {
+		exchangeRateOracleBytes, err := json.Marshal(currentPortalState.ExchangeRateOracle)
+		if err != nil {
+			return err
+		}
+		err = statedb.StorePortalExchangeRateOracleState(stateDB, exchangeRateOracleBytes)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -540,11 +574,6 @@ func pickupCustodianForRedeem(redeemAmount uint64, tokenID string, portalState *
 	return nil, errors.New("Not enough amount public token to return user")
 }
 
-// convertIncPBNBAmountToExternalBNBAmount converts amount in inc chain (decimal 9) to amount in bnb chain (decimal 8)
-func convertIncPBNBAmountToExternalBNBAmount(incPBNBAmount int64) int64 {
-	return incPBNBAmount / 10 // incPBNBAmount / 1^9 * 1^8
-}
-
 // updateCustodianStateAfterReqUnlockCollateral updates custodian state (amount collaterals) when custodian returns redeemAmount public token to user
 func updateCustodianStateAfterReqUnlockCollateral(custodianState *statedb.CustodianState, unlockedAmount uint64, tokenID string) error {
 	lockedAmount := custodianState.GetLockedAmountCollateral()
@@ -1666,7 +1695,13 @@ func UpdateLockedCollateralForRewardsV3(currentPortalState *CurrentPortalState,
 	if lockedCollateralDetails == nil {
 		lockedCollateralDetails = map[string]uint64{}
 	}
-	portalTokenIDs := common.PortalSupportedIncTokenIDs
+	// portal tokens without final exchange rates add nothing
+	portalTokenIDs := []string{}
+	for tokenID := range exchangeTool.Rates {
+		if isIncognitoTokenID(tokenID) && tokenID != common.PRVIDStr {
+			portalTokenIDs = append(portalTokenIDs, tokenID)
+		}
+	}
 	for _, custodianState := range currentPortalState.CustodianPoolState {
 		for _, tokenID := range portalTokenIDs {
 			holdPubTokenAmount := GetTotalHoldPubTokenAmount(currentPortalState, custodianState, tokenID)
@@ -117,18 +117,7 @@ func (s *PortalTestSuiteV3) SetupTest() {
 				MinBeaconBlockInterval: 40 * time.Second,
 				MinShardBlockInterval:  40 * time.Second,
 				Epoch:                  100,
-				PortalTokens: map[string]PortalTokenProcessor{
-					common.PortalBTCIDStr: &PortalBTCTokenProcessor{
-						&PortalToken{
-							ChainID: "Bitcoin-Testnet",
-						},
-					},
-					common.PortalBNBIDStr: &PortalBNBTokenProcessor{
-						&PortalToken{
-							ChainID: "Binance-Chain-Ganges",
-						},
-					},
-				},
+				PortalTokens:           initPortalTokensForTestNet(),
 				PortalParams: map[uint64]PortalParams{
 					0: {
 						TimeOutCustodianReturnPubToken:       24 * time.Hour,
@@ -406,7 +395,7 @@ func processPortalInstructions(
 	}
 
 	//save final exchangeRates
-	blockchain.pickExchangesRatesFinal(currentPortalState)
+	blockchain.pickExchangesRatesFinal(currentPortalState, beaconHeight, portalParams)
 
 	// update info of bridge portal token
 	for _, updatingInfo := range updatingInfoByTokenID {
@@ -769,7 +758,7 @@ func buildPortalExchangeRateActionsFromTcs(tcs []TestCaseRelayExchangeRate, shar
 //func (s *PortalTestSuiteV3) TestRelayExchangeRate() {
 //	fmt.Println("Running TestRelayExchangeRate - beacon height 999 ...")
 //	bc := s.blockChain
-//	pm := NewPortalManager()
+//	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 //	beaconHeight := uint64(999)
 //	shardID := byte(0)
 //	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -940,7 +929,7 @@ func buildCustodianDepositActionsFromTcs(tcs []TestCaseCustodianDeposit, shardID
 func (s *PortalTestSuiteV3) TestCustodianDepositCollateral() {
 	fmt.Println("Running TestCustodianDepositCollateral - beacon height 1000 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1000)
 	shardID := byte(0)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -1148,7 +1137,7 @@ func buildCustodianDepositActionsV3FromTcs(tcs []TestCaseCustodianDepositV3, sha
 func (s *PortalTestSuiteV3) TestCustodianDepositCollateralV3() {
 	fmt.Println("Running TestCustodianDepositCollateralV3 - beacon height 1000 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1000)
 	shardID := byte(0)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -1367,7 +1356,7 @@ func buildCustodianWithdrawActionsV3FromTcs(tcs []TestCaseCustodianWithdrawV3, s
 func (s *PortalTestSuiteV3) TestCustodianWithdrawCollateralV3() {
 	fmt.Println("Running TestCustodianWithdrawCollateralV3 - beacon height 1000 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1000)
 	shardID := byte(0)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -1670,7 +1659,7 @@ func buildRequestPortingActionsFromTcs(tcs []TestCaseRequestPorting, shardID byt
 func (s *PortalTestSuiteV3) TestPortingRequest() {
 	fmt.Println("Running TestPortingRequest - beacon height 1001 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1001)
 	shardID := byte(0)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -2012,7 +2001,7 @@ func buildRequestPtokensActionsFromTcs(tcs []TestCaseRequestPtokens, shardID byt
 func (s *PortalTestSuiteV3) TestRequestPtokens() {
 	fmt.Println("Running TestRequestPtokens - beacon height 1002 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1002)
 	shardID := byte(0)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
@@ -2415,7 +2404,7 @@ func buildRequestRedeemActionsFromTcs(tcs []TestCaseRequestRedeemV3, shardID byt
 func (s *PortalTestSuiteV3) TestRequestRedeemV3() {
 	fmt.Println("Running TestRequestRedeemV3 - beacon height 1003 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1003)
 	shardHeight := uint64(1003)
 	shardID := byte(0)
@@ -2820,7 +2809,7 @@ func buildRequestMatchingWRedeemV3ActionsFromTcs(tcs []TestCaseRequestMatchingWR
 func (s *PortalTestSuiteV3) TestRequestMatchingWRedeemV3() {
 	fmt.Println("Running TestRequestMatchingWRedeemV3 - beacon height 1004 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1004)
 	shardHeight := uint64(1004)
 	shardID := byte(0)
@@ -3426,7 +3415,7 @@ func buildRequestUnlockCollateralsV3ActionsFromTcs(tcs []TestCaseRequestUnlockCo
 func (s *PortalTestSuiteV3) TestRequestUnlockCollateralsV3() {
 	fmt.Println("Running TestRequestUnlockCollateralsV3 - beacon height 1004 ...")
 	bc := s.blockChain
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	beaconHeight := uint64(1004)
 	shardHeight := uint64(1004)
 	shardID := byte(0)
@@ -4402,7 +4391,7 @@ func (s *PortalTestSuiteV3) TestAutoLiquidationByExchangeRate() {
 //	fmt.Println("Running TestAutoLiquidationCustodian - beacon height 1501 ...")
 //	bc := s.blockChain
 //	beaconHeight := uint64(1501)
-//	pm := NewPortalManager()
+//	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 //	shardID := byte(0)
 //	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
 //
@@ -5061,7 +5050,7 @@ func (s *PortalTestSuiteV3) TestUnlockOverRateCollaterals() {
 	beaconHeight := uint64(1501)
 	shardID := byte(0)
 	//newMatchedRedeemReqIDs := []string{}
-	pm := NewPortalManager()
+	pm := NewPortalManager(s.blockChain.config.ChainParams.PortalTokens)
 	updatingInfoByTokenID := map[common.Hash]UpdatingInfo{}
 
 	s.SetupTestUnlockOverRateCollaterals()
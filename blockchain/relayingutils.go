@@ -3,6 +3,7 @@ package blockchain
 import (
 	"encoding/base64"
 	"encoding/json"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/metadata"
 	bnbrelaying "github.com/incognitochain/incognito-chain/relaying/bnb"
@@ -18,7 +19,7 @@ type relayingChain struct {
 type relayingBNBChain struct {
 	*relayingChain
 }
-type relayingBTCChain struct {
+type relayingUTXOChain struct {
 	*relayingChain
 }
 
@@ -118,13 +119,13 @@ func (rbnbChain *relayingBNBChain) buildRelayingInst(
 	return [][]string{inst}
 }
 
-func (rbtcChain *relayingBTCChain) buildRelayingInst(
+func (rutxoChain *relayingUTXOChain) buildRelayingInst(
 	blockchain *BlockChain,
 	relayingHeaderAction metadata.RelayingHeaderAction,
 	relayingState *RelayingHeaderChainState,
 ) [][]string {
-	Logger.log.Info("[BTC Relaying] - Processing buildRelayingInst...")
-	inst := rbtcChain.buildHeaderRelayingInst(
+	Logger.log.Infof("[UTXO Relaying] - Processing buildRelayingInst for meta type %v...", relayingHeaderAction.Meta.Type)
+	inst := rutxoChain.buildHeaderRelayingInst(
 		relayingHeaderAction.Meta.IncogAddressStr,
 		relayingHeaderAction.Meta.Header,
 		relayingHeaderAction.Meta.BlockHeight,
@@ -139,19 +140,39 @@ func (rbtcChain *relayingBTCChain) buildRelayingInst(
 
 
 type RelayingHeaderChainState struct {
-	BNBHeaderChain *bnbrelaying.BNBChainState
-	BTCHeaderChain *btcrelaying.BlockChain
+	BNBHeaderChain   *bnbrelaying.BNBChainState
+	UTXOHeaderChains map[int]*btcrelaying.BlockChain // by relaying header meta type, including BTC
 }
 
 func (bc *BlockChain) InitRelayingHeaderChainStateFromDB() (*RelayingHeaderChainState, error) {
 	bnbChain := bc.GetBNBChainState()
-	btcChain := bc.config.BTCChain
+	utxoChains := map[int]*btcrelaying.BlockChain{
+		metadata.RelayingBTCHeaderMeta: bc.config.BTCChain,
+	}
+	for metaType, utxoChain := range bc.config.UTXOChains {
+		utxoChains[metaType] = utxoChain
+	}
 	return &RelayingHeaderChainState{
-		BNBHeaderChain: bnbChain,
-		BTCHeaderChain: btcChain,
+		BNBHeaderChain:   bnbChain,
+		UTXOHeaderChains: utxoChains,
 	}, nil
 }
 
+// GetBTCRelayingChainParams returns params and genesis block height of the btc header chain btcRelayingChainID
+func GetBTCRelayingChainParams(btcRelayingChainID string) (*chaincfg.Params, int32) {
+	relayingChainParams := map[string]*chaincfg.Params{
+		TestnetBTCChainID:  btcrelaying.GetTestNet3Params(),
+		Testnet2BTCChainID: btcrelaying.GetTestNet3ParamsForInc2(),
+		MainnetBTCChainID:  btcrelaying.GetMainNetParams(),
+	}
+	relayingChainGenesisBlkHeight := map[string]int32{
+		TestnetBTCChainID:  int32(1896910),
+		Testnet2BTCChainID: int32(1863675),
+		MainnetBTCChainID:  int32(634140),
+	}
+	return relayingChainParams[btcRelayingChainID], relayingChainGenesisBlkHeight[btcRelayingChainID]
+}
+
 // GetBNBChainState gets bnb header chain state
 func (bc *BlockChain) GetBNBChainState() *bnbrelaying.BNBChainState {
 	return bc.config.BNBChainState
@@ -35,6 +35,18 @@ func (blockchain *BlockChain) GetBCHeightBreakPointPortalV3() uint64 {
 	return blockchain.config.ChainParams.BCHeightBreakPointPortalV3
 }
 
+func (blockchain *BlockChain) GetBCHeightBreakPointEquivocation() uint64 {
+	return blockchain.config.ChainParams.BCHeightBreakPointEquivocation
+}
+
+func (blockchain *BlockChain) GetBCHeightBreakPointPDERoutedTrade() uint64 {
+	return blockchain.config.ChainParams.BCHeightBreakPointPDERoutedTrade
+}
+
+func (blockchain *BlockChain) GetBCHeightBreakPointPDETradeV2() uint64 {
+	return blockchain.config.ChainParams.BCHeightBreakPointPDETradeV2
+}
+
 func (blockchain *BlockChain) GetBurningAddress(beaconHeight uint64) string {
 	breakPoint := blockchain.GetBeaconHeightBreakPointBurnAddr()
 	if beaconHeight == 0 {
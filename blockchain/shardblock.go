@@ -65,6 +65,18 @@ type CrossShardBlock struct {
 	CrossTxTokenPrivacyData []ContentCrossShardTokenPrivacyData
 }
 
+func (crossShardBlock *CrossShardBlock) UnmarshalJSON(data []byte) error {
+	type Alias CrossShardBlock
+	temp := (*Alias)(crossShardBlock)
+	if err := json.Unmarshal(data, temp); err != nil {
+		return NewBlockChainError(UnmashallJsonShardBlockError, err)
+	}
+	if err := crossShardBlock.checkSizeLimit(); err != nil {
+		return NewBlockChainError(UnmashallJsonShardBlockError, err)
+	}
+	return nil
+}
+
 func NewShardBlock() *ShardBlock {
 	return &ShardBlock{
 		Header: ShardHeader{},
@@ -267,6 +279,9 @@ func (shardBlock *ShardBlock) UnmarshalJSON(data []byte) error {
 		return NewBlockChainError(UnmashallJsonShardBlockError, err)
 	}
 	shardBlock.ValidationData = tempShardBlock.ValidationData
+	if tempShardBlock.Body == nil {
+		return NewBlockChainError(UnmashallJsonShardBlockError, errors.New("shard block has no body"))
+	}
 
 	blkBody := ShardBody{}
 	err = blkBody.UnmarshalJSON(*tempShardBlock.Body)
@@ -291,6 +306,9 @@ func (shardBlock *ShardBlock) UnmarshalJSON(data []byte) error {
 		return NewBlockChainError(UnmashallJsonShardBlockError, err)
 	}
 	shardBlock.Body = blkBody
+	if err := shardBlock.checkSizeLimit(); err != nil {
+		return NewBlockChainError(UnmashallJsonShardBlockError, err)
+	}
 	return nil
 }
 
@@ -374,6 +392,10 @@ func (shardBody *ShardBody) UnmarshalJSON(data []byte) error {
 		return NewBlockChainError(UnmashallJsonShardBlockError, err)
 	}
 
+	if err := checkSizeLimit("Transactions", len(temp.Transactions), MaxShardBlockTxs); err != nil {
+		return NewBlockChainError(UnmashallJsonShardBlockError, err)
+	}
+
 	// process tx from tx interface of temp
 	for _, txTemp := range temp.Transactions {
 		txTempJson, _ := json.MarshalIndent(txTemp, "", "\t")
@@ -382,6 +404,9 @@ func (shardBody *ShardBody) UnmarshalJSON(data []byte) error {
 		var tx metadata.Transaction
 		var parseErr error
 		txType := ""
+		if txTemp["Type"] == nil {
+			return NewBlockChainError(UnmashallJsonShardBlockError, errors.New("tx has no type"))
+		}
 		err = json.Unmarshal(*txTemp["Type"], &txType)
 		if err != nil {
 			return NewBlockChainError(UnmashallJsonShardBlockError, err)
@@ -335,7 +335,7 @@ func (blockGenerator *BlockGenerator) buildResponseTxsFromBeaconInstructions(cur
 				if len(l) >= 4 {
 					newTx, err = blockGenerator.buildPDETradeIssuanceTx(l[2], l[3], producerPrivateKey, shardID, curView, beaconView)
 				}
-			case metadata.PDECrossPoolTradeRequestMeta:
+			case metadata.PDECrossPoolTradeRequestMeta, metadata.PDERoutedTradeRequestMeta:
 				if len(l) >= 4 {
 					newTx, err = blockGenerator.buildPDECrossPoolTradeIssuanceTx(l[2], l[3], producerPrivateKey, shardID, curView, beaconView)
 				}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"encoding/json"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
 	"sort"
 	"strings"
 )
@@ -155,6 +156,24 @@ func (blockchain *BlockChain) processForSlashing(slashStateDB *statedb.StateDB,
 			}
 		}
 	}
+	equivocationSlashes := []*metadata.ReportEquivocationContent{}
+	if beaconHeight >= blockchain.GetBCHeightBreakPointEquivocation() {
+		equivocationSlashes, err = getEquivocationSlashes(beaconBlock)
+		if err != nil {
+			return err
+		}
+	}
+	for _, slash := range equivocationSlashes {
+		epoches, found := producersBlackList[slash.CommitteePublicKey]
+		if !found || epoches < slash.PunishedEpoches {
+			producersBlackList[slash.CommitteePublicKey] = slash.PunishedEpoches
+		}
+		equivocationSlash := statedb.NewEquivocationSlashStateWithValue(slash.CommitteePublicKey, slash.Type, slash.ChainID, slash.Height, slash.TimeSlot, slash.PunishedEpoches, slash.ForceUnstake, beaconHeight, slash.TxReqID)
+		err = statedb.StoreEquivocationSlash(slashStateDB, equivocationSlash)
+		if err != nil {
+			return NewBlockChainError(ProcessEquivocationSlashError, err)
+		}
+	}
 	for _, punishedProducerFinished := range punishedProducersFinished {
 		flag := false
 		for producerBlaskList, _ := range producersBlackList {
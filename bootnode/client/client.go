@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"net/rpc"
 
-	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/incognitochain/incognito-chain/bootnode/server"
 )
 
 func main() {
@@ -15,8 +15,8 @@ func main() {
 	}
 	if client != nil {
 		defer client.Close()
-		var response []wire.RawPeer
-		err := client.Call("Handler.GetPeers", "", &response)
+		var response server.GetPeersResponse
+		err := client.Call("Handler.GetPeers", &server.GetPeersArgs{}, &response)
 		if err != nil {
 			panic(err)
 		} else {
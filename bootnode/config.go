@@ -8,7 +8,10 @@ import (
 
 // See loadConfig for details on the configuration load process.
 type config struct {
-	RPCPort int `long:"rpcport" short:"p" description:"Linsten port of RPC server"`
+	RPCPort       int    `long:"rpcport" short:"p" description:"Linsten port of RPC server"`
+	DataDir       string `long:"datadir" description:"Directory of database to keep registered peers after restart"`
+	PeerTimeout   int    `long:"peertimeout" description:"Remove a peer not pinging for this many seconds"`
+	PeerBootnodes string `long:"peerbootnodes" description:"Comma separated address (host:port) of other bootnodes to exchange peer lists with"`
 }
 
 // newConfigParser returns a new command line flags parser.
@@ -24,7 +27,9 @@ func newConfigParser(cfg *config, options flags.Options) *flags.Parser {
 func loadConfig() (*config, error) {
 	// create config object from default values
 	cfg := config{
-		RPCPort: defaultRPCServerPort,
+		RPCPort:     defaultRPCServerPort,
+		DataDir:     defaultDataDir,
+		PeerTimeout: defaultPeerTimeout,
 	}
 
 	//preCfg := cfg
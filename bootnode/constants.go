@@ -3,4 +3,6 @@ package main
 const (
 	version              = "1.1.0"
 	defaultRPCServerPort = 9330
+	defaultDataDir       = "data/bootnode"
+	defaultPeerTimeout   = 120
 )
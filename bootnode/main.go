@@ -4,8 +4,12 @@ package main
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	"github.com/incognitochain/incognito-chain/bootnode/server"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
 )
 
 var (
@@ -26,15 +30,33 @@ func main() {
 	}
 	cfg = tcfg
 
+	// open database to keep registered peers
+	db, err := incdb.Open("leveldb", cfg.DataDir)
+	if err != nil {
+		log.Println("Open database error", err.Error())
+		return
+	}
+	defer db.Close()
+
 	// create RPC config for RPC server
 	rpcConfig := server.RpcServerConfig{
-		Port: cfg.RPCPort,
+		Port:        cfg.RPCPort,
+		DB:          db,
+		PeerTimeout: time.Duration(cfg.PeerTimeout) * time.Second,
+	}
+	for _, address := range strings.Split(cfg.PeerBootnodes, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			rpcConfig.PeerBootnodes = append(rpcConfig.PeerBootnodes, address)
+		}
 	}
 
 	// Init RPC Serer in golang
 	rpcServer := &server.RpcServer{}
 	log.Printf("Init rpcServer with config \n")
-	rpcServer.Init(&rpcConfig)
+	if err := rpcServer.Init(&rpcConfig); err != nil {
+		log.Println("Init rpcServer error", err.Error())
+		return
+	}
 
 	log.Printf("Start rpcServer with config \n %+v\n", rpcServer.Config)
 	for {
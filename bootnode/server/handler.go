@@ -10,33 +10,49 @@ type Handler struct {
 	rpcServer *RpcServer
 }
 
-func (s Handler) GetPeers(args string, responseMessagePeers *[]wire.RawPeer) error {
-	fmt.Println(args)
-	// return note list
-	for _, p := range s.rpcServer.peers {
-		*responseMessagePeers = append(*responseMessagePeers, wire.RawPeer{p.rawAddress, p.publickeyType, p.publicKey})
+// GetPeers - return a page of peers matching role and shards of args
+func (s Handler) GetPeers(args *GetPeersArgs, response *GetPeersResponse) error {
+	fmt.Println("Receive ```GetPeers``` method from ```RPC client``` with data", args)
+	if args.Offset < 0 || args.Limit < 0 {
+		return fmt.Errorf("invalid offset %v or limit %v", args.Offset, args.Limit)
 	}
-	fmt.Println("Response", *responseMessagePeers)
+	peers := s.rpcServer.store.Query(PeerFilter{Role: args.Role, ShardIDs: args.ShardIDs})
+	response.Total = len(peers)
+	if args.Offset >= len(peers) {
+		response.Peers = []PeerRecord{}
+		return nil
+	}
+	peers = peers[args.Offset:]
+	if args.Limit > 0 && args.Limit < len(peers) {
+		peers = peers[:args.Limit]
+	}
+	response.Peers = peers
 	return nil
 }
 
 // Ping - handler func which receive data from rpc client,
-// add into list current peers and response all of them to client
+// add into list current peers if it is signed and response all of them to client
 func (s Handler) Ping(args *PingArgs, responseMessagePeers *[]wire.RawPeer) error {
 	fmt.Println("Receive ```Ping``` method from ```RPC client``` with data", args)
 
 	// update peer which have just send information to our rpc server
-	err := s.rpcServer.AddOrUpdatePeer(args.RawAddress, args.PublicKeyType, args.PublicKey, args.SignData)
+	err := s.rpcServer.AddOrUpdatePeer(args)
 	if err != nil {
 		return err
 	}
 
-	s.rpcServer.peersMtx.Lock()
-	defer s.rpcServer.peersMtx.Unlock()
 	// return note list
-	for _, p := range s.rpcServer.peers {
-		*responseMessagePeers = append(*responseMessagePeers, wire.RawPeer{p.rawAddress, p.publickeyType, p.publicKey})
+	for _, p := range s.rpcServer.store.All() {
+		*responseMessagePeers = append(*responseMessagePeers, wire.RawPeer{RawAddress: p.RawAddress, PublicKeyType: p.PublicKeyType, PublicKey: p.PublicKey})
 	}
 	fmt.Println("Response", *responseMessagePeers)
 	return nil
 }
+
+// ExchangePeers - merge peers sent by another bootnode and response all of ours
+func (s Handler) ExchangePeers(args *ExchangePeersArgs, response *ExchangePeersResponse) error {
+	cnt := s.rpcServer.MergePeers(args.Peers)
+	fmt.Printf("Receive ```ExchangePeers``` method with %v peers, merged %v\n", len(args.Peers), cnt)
+	response.Peers = s.rpcServer.store.All()
+	return nil
+}
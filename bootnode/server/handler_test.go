@@ -1,11 +1,11 @@
 package server
 
 import (
+	"fmt"
+	"testing"
+
 	"github.com/incognitochain/incognito-chain/common"
-	"github.com/incognitochain/incognito-chain/common/base58"
-	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/incognitochain/incognito-chain/wire"
-	"testing"
 )
 
 func TestHandler_Ping(t *testing.T) {
@@ -14,29 +14,63 @@ func TestHandler_Ping(t *testing.T) {
 		Port: 9333,
 	})
 	handler := Handler{rpcServer: &rpcServer}
-	keyWallet, err := wallet.Base58CheckDeserialize("112t8rnXCqbbNYBquntyd6EvDT4WiDDQw84ZSRDKmazkqrzi6w8rWyCVt7QEZgAiYAV4vhJiX7V9MCfuj4hGLoDN7wdU1LoWGEFpLs59X7K3")
+	args := newSignedPingArgs(t, "seed", "localhost:9333", "", -1)
+
+	var response = make([]wire.RawPeer, 0)
+	err := handler.Ping(args, &response)
 	if err != nil {
 		t.Error(err)
 	}
-	err = keyWallet.KeySet.InitFromPrivateKey(&keyWallet.KeySet.PrivateKey)
-	if err != nil {
-		t.Error(err)
+	if len(response) != 1 || response[0].PublicKey != args.PublicKey {
+		t.Errorf("unexpected response %+v", response)
 	}
+}
 
-	args := &PingArgs{
-		RawAddress: "localhost:9333",
-		PublicKey:  base58.Base58Check{}.Encode(keyWallet.KeySet.PaymentAddress.Pk, common.ZeroByte),
+func TestHandler_GetPeers(t *testing.T) {
+	rpcServer := RpcServer{}
+	rpcServer.Init(&RpcServerConfig{
+		Port: 9333,
+	})
+	handler := Handler{rpcServer: &rpcServer}
+	for i := 0; i < 5; i++ {
+		args := newSignedPingArgs(t, fmt.Sprintf("seed%v", i), fmt.Sprintf("localhost:900%v", i), common.CommitteeRole, i%2)
+		if err := rpcServer.AddOrUpdatePeer(args); err != nil {
+			t.Fatal(err)
+		}
 	}
-	signDataB58, err := keyWallet.KeySet.SignDataInBase58CheckEncode([]byte(args.RawAddress))
-	if err != nil {
-		t.Error(err)
+	beacon := newSignedPingArgs(t, "beacon", "localhost:9100", common.CommitteeRole, -1)
+	if err := rpcServer.AddOrUpdatePeer(beacon); err != nil {
+		t.Fatal(err)
 	}
-	args.SignData = signDataB58
 
-	var response = make([]wire.RawPeer, 0)
-	err = handler.Ping(args, &response)
-	if err != nil {
-		t.Error(err)
+	tests := []struct {
+		args      GetPeersArgs
+		wantTotal int
+		wantLen   int
+	}{
+		{GetPeersArgs{}, 6, 6},
+		{GetPeersArgs{ShardIDs: []int{0}}, 3, 3},
+		{GetPeersArgs{ShardIDs: []int{1, -1}}, 3, 3},
+		{GetPeersArgs{Role: common.PendingRole}, 0, 0},
+		{GetPeersArgs{Offset: 2, Limit: 3}, 6, 3},
+		{GetPeersArgs{Offset: 5, Limit: 3}, 6, 1},
+		{GetPeersArgs{Offset: 7}, 6, 0},
+	}
+	all := GetPeersResponse{}
+	handler.GetPeers(&GetPeersArgs{}, &all)
+	for _, tt := range tests {
+		response := GetPeersResponse{}
+		if err := handler.GetPeers(&tt.args, &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Total != tt.wantTotal || len(response.Peers) != tt.wantLen {
+			t.Errorf("GetPeers(%+v) = total %v len %v, want %v %v", tt.args, response.Total, len(response.Peers), tt.wantTotal, tt.wantLen)
+		}
+		if tt.args.Role == "" && len(tt.args.ShardIDs) == 0 && len(response.Peers) > 0 && response.Peers[0].PublicKey != all.Peers[tt.args.Offset].PublicKey {
+			t.Errorf("GetPeers(%+v) return wrong page", tt.args)
+		}
+	}
+	if err := handler.GetPeers(&GetPeersArgs{Offset: -1}, &GetPeersResponse{}); err == nil {
+		t.Error("expect error of negative offset")
 	}
-
 }
@@ -1,10 +1,15 @@
 package server
 
+// PingArgs - SignData is the signature of PeerRecordSignData(RawAddress, Role, ShardID, Timestamp) by the mining key,
+// nodes without mining key or external address send an empty SignData or RawAddress, they are not registered
 type PingArgs struct {
 	RawAddress    string
 	PublicKeyType string
 	PublicKey     string
 	SignData      string
+	Role          string
+	ShardID       int
+	Timestamp     int64 // unix time of signing
 }
 
 func (ping *PingArgs) Init(RawAddress string, PublicKeyType string, PublicKey string, SignData string) {
@@ -13,3 +18,24 @@ func (ping *PingArgs) Init(RawAddress string, PublicKeyType string, PublicKey st
 	ping.SignData = SignData
 	ping.RawAddress = RawAddress
 }
+
+// GetPeersArgs - filter peers by role and shard (empty matches all), Limit = 0 returns all peers from Offset
+type GetPeersArgs struct {
+	Role     string
+	ShardIDs []int
+	Offset   int
+	Limit    int
+}
+
+type GetPeersResponse struct {
+	Total int // number of peers matching filter
+	Peers []PeerRecord
+}
+
+type ExchangePeersArgs struct {
+	Peers []PeerRecord
+}
+
+type ExchangePeersResponse struct {
+	Peers []PeerRecord
+}
@@ -7,47 +7,56 @@ import (
 	"log"
 	"net"
 	"net/rpc"
-	"sync"
 	"time"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+	"github.com/incognitochain/incognito-chain/incdb"
 )
 
 const (
 	heartbeatInterval = 10
 	heartbeatTimeout  = 120
+	exchangeInterval  = 30
+	maxClockDrift     = 30 // seconds a peer record can be signed ahead of bootnode time
 )
 
-type peer struct {
-	id            string
-	rawAddress    string
-	publicKey     string
-	publickeyType string
-	firstPing     time.Time
-	lastPing      time.Time
-}
-
 // rpcServer provides a concurrent safe RPC server to a bootnode server.
 type RpcServer struct {
-	peers    map[string]*peer // list peers which are still pinging to bootnode continuously
-	peersMtx sync.Mutex
-	server   *rpc.Server
-	Config   RpcServerConfig // config for RPC server
+	store     *PeerStore // list peers which are still pinging to bootnode continuously
+	startTime time.Time
+	server    *rpc.Server
+	Config    RpcServerConfig // config for RPC server
 }
 
 type RpcServerConfig struct {
-	Port int // rpc port
+	Port          int            // rpc port
+	DB            incdb.Database // persist peers, nil to keep them in memory only
+	PeerTimeout   time.Duration  // remove peers not pinging for this long, default heartbeatTimeout seconds
+	PeerBootnodes []string       // address of other bootnodes to exchange peer lists with
 }
 
-func (rpcServer *RpcServer) Init(config *RpcServerConfig) {
+func (rpcServer *RpcServer) Init(config *RpcServerConfig) error {
 	// get config and init list Peers
 	rpcServer.Config = *config
-	rpcServer.peers = make(map[string]*peer)
+	if rpcServer.Config.PeerTimeout == 0 {
+		rpcServer.Config.PeerTimeout = heartbeatTimeout * time.Second
+	}
+	store, err := NewPeerStore(config.DB)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loaded %v peers\n", store.Len())
+	rpcServer.store = store
+	rpcServer.startTime = time.Now()
 	rpcServer.server = rpc.NewServer()
 	// start go routin hertbeat to check invalid peers
-	go rpcServer.PeerHeartBeat(heartbeatTimeout)
+	go rpcServer.PeerHeartBeat(rpcServer.Config.PeerTimeout)
+	if len(rpcServer.Config.PeerBootnodes) > 0 {
+		go rpcServer.ExchangePeersWithBootnodes(exchangeInterval * time.Second)
+	}
+	return nil
 }
 
 // Start - create handler and add into rpc server
@@ -65,57 +74,113 @@ func (rpcServer *RpcServer) Start() error {
 	return nil
 }
 
-// AddOrUpdatePeer - push a connected peer in to list of mem or update an old peer node
-func (rpcServer *RpcServer) AddOrUpdatePeer(rawAddress string, publicKeyType string, publicKeyMining string, signDataB58 string) error {
-	rpcServer.peersMtx.Lock()
-	defer rpcServer.peersMtx.Unlock()
-	if signDataB58 != "" && publicKeyMining != "" && rawAddress != "" {
+// PeerRecordSignData - data signed by the mining key of a peer registering to bootnode
+func PeerRecordSignData(rawAddress string, role string, shardID int, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("%v|%v|%v|%v", rawAddress, role, shardID, timestamp))
+}
 
-		sigByte, _, err := base58.Base58Check{}.Decode(signDataB58)
-		if err != nil {
-			return err
-		}
-		publicKeyByte := []byte(publicKeyMining)
-		miningKey := map[string][]byte{}
-		if publicKeyType == common.BlsConsensus {
-			err := json.Unmarshal(publicKeyByte, &miningKey)
-			if err != nil {
-				return err
-			}
-			ecdsaKeyByte, ok := miningKey[common.BridgeConsensus]
-			if !ok {
-				return errors.New("ECDSA Public key not found")
-			}
-			valid, err := bridgesig.Verify(ecdsaKeyByte, []byte(rawAddress), sigByte) //, []int{0}, []blsmultisig.PublicKey{publicKeyByte})
-			if valid {
-				// blsKeyByte, ok := miningKey[common.BlsConsensus]
-				// if !ok {
-				// 	return errors.New("BLS Public key not found")
-				// }
-				rpcServer.peers[publicKeyMining] = &peer{
-					id:            rpcServer.CombineID(rawAddress, publicKeyMining),
-					rawAddress:    rawAddress,
-					publicKey:     publicKeyMining,
-					publickeyType: publicKeyType,
-					firstPing:     time.Now().Local(),
-					lastPing:      time.Now().Local(),
-				}
-			} else {
-				log.Println("AddOrUpdatePeer error", err)
-				return err
-			}
-		} else {
-			log.Println("AddOrUpdatePeer error", err)
-			return err
-		}
+// isSignedPeerRecord - false for nodes without mining key or external address, they are not registered
+func isSignedPeerRecord(record *PeerRecord) bool {
+	return record.SignData != "" && record.PublicKey != "" && record.RawAddress != ""
+}
 
+// verifyPeerRecord - check SignData is the signature of the record by the bridge key of mining public key
+func verifyPeerRecord(record *PeerRecord) error {
+	if !isSignedPeerRecord(record) {
+		return errors.New("raw address, mining public key and signature are required")
+	}
+	if record.PublicKeyType != common.BlsConsensus {
+		return fmt.Errorf("unsupported public key type %v", record.PublicKeyType)
+	}
+	sigByte, _, err := base58.Base58Check{}.Decode(record.SignData)
+	if err != nil {
+		return err
+	}
+	miningKey := map[string][]byte{}
+	if err := json.Unmarshal([]byte(record.PublicKey), &miningKey); err != nil {
+		return err
+	}
+	ecdsaKeyByte, ok := miningKey[common.BridgeConsensus]
+	if !ok {
+		return errors.New("ECDSA Public key not found")
+	}
+	valid, err := bridgesig.Verify(ecdsaKeyByte, PeerRecordSignData(record.RawAddress, record.Role, record.ShardID, record.SignedAt), sigByte)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid signature of peer record")
 	}
 	return nil
 }
 
-// RemovePeerByPbk - remove peer from mem of bootnode
+// isFreshPeerRecord - record signed in the last maxAge, a few seconds ahead are allowed for clock drift
+func isFreshPeerRecord(record *PeerRecord, now time.Time, maxAge time.Duration) bool {
+	return record.SignedAt >= now.Add(-maxAge).Unix() && record.SignedAt <= now.Add(maxClockDrift*time.Second).Unix()
+}
+
+// AddOrUpdatePeer - verify the signed record of a connected peer and push it in to the address book or update an old peer node.
+// Records without signature are not stored, the node is not registered
+func (rpcServer *RpcServer) AddOrUpdatePeer(args *PingArgs) error {
+	now := time.Now()
+	record := PeerRecord{
+		RawAddress:    args.RawAddress,
+		PublicKey:     args.PublicKey,
+		PublicKeyType: args.PublicKeyType,
+		SignData:      args.SignData,
+		Role:          args.Role,
+		ShardID:       args.ShardID,
+		SignedAt:      args.Timestamp,
+		FirstSeen:     now.Unix(),
+		LastSeen:      now.Unix(),
+	}
+	if !isSignedPeerRecord(&record) {
+		return nil
+	}
+	if err := verifyPeerRecord(&record); err != nil {
+		log.Println("AddOrUpdatePeer error", err)
+		return err
+	}
+	if !isFreshPeerRecord(&record, now, rpcServer.Config.PeerTimeout) {
+		err := fmt.Errorf("peer record signed at %v, bootnode time is %v", record.SignedAt, now.Unix())
+		log.Println("AddOrUpdatePeer error", err)
+		return err
+	}
+	return rpcServer.store.Put(record)
+}
+
+// MergePeers - add records received from another bootnode which are valid and newer than ours.
+// A record is as recent as its signature, records signed before peer timeout are stale
+func (rpcServer *RpcServer) MergePeers(records []PeerRecord) int {
+	cnt := 0
+	now := time.Now()
+	for i := range records {
+		record := records[i]
+		record.LastSeen = record.SignedAt
+		if old, ok := rpcServer.store.Get(record.PublicKey); ok && old.LastSeen >= record.LastSeen {
+			continue
+		}
+		if !isFreshPeerRecord(&record, now, rpcServer.Config.PeerTimeout) {
+			continue
+		}
+		if err := verifyPeerRecord(&record); err != nil {
+			log.Println("MergePeers ignore invalid record", err)
+			continue
+		}
+		if err := rpcServer.store.Put(record); err != nil {
+			log.Println("MergePeers error", err)
+			continue
+		}
+		cnt++
+	}
+	return cnt
+}
+
+// RemovePeerByPbk - remove peer from address book of bootnode
 func (rpcServer *RpcServer) RemovePeerByPbk(publicKey string) {
-	delete(rpcServer.peers, publicKey)
+	if err := rpcServer.store.Delete(publicKey); err != nil {
+		log.Println("RemovePeerByPbk error", err)
+	}
 }
 
 // CombineID - return string = rawAddress of peer + public key in base58check encode of node(run as committee)
@@ -127,19 +192,42 @@ func (rpcServer *RpcServer) CombineID(rawAddress string, publicKey string) strin
 
 // PeerHeartBeat - loop forever after heartbeatInterval to check peers
 // which are not connected to remove from bootnode
-// use Last Ping time to compare with time.now
-func (rpcServer *RpcServer) PeerHeartBeat(heartbeatTimeout int) {
+// use Last Ping time to compare with time.now.
+// Peers loaded after restart have peerTimeout to ping again
+func (rpcServer *RpcServer) PeerHeartBeat(peerTimeout time.Duration) {
 	for {
-		now := time.Now().Local()
-		if len(rpcServer.peers) > 0 {
-		loop:
-			for publicKey, peer := range rpcServer.peers {
-				if now.Sub(peer.lastPing).Seconds() > float64(heartbeatTimeout) {
-					rpcServer.RemovePeerByPbk(publicKey)
-					goto loop
-				}
-			}
+		if time.Since(rpcServer.startTime) > peerTimeout {
+			rpcServer.store.RemoveExpired(time.Now().Add(-peerTimeout))
 		}
 		time.Sleep(heartbeatInterval * time.Second)
 	}
 }
+
+// ExchangePeersWithBootnodes - loop forever to send our peers to other bootnodes and merge theirs,
+// so a set of bootnodes answers the same peer list
+func (rpcServer *RpcServer) ExchangePeersWithBootnodes(interval time.Duration) {
+	for {
+		for _, address := range rpcServer.Config.PeerBootnodes {
+			if err := rpcServer.exchangePeers(address); err != nil {
+				log.Printf("Exchange peers with bootnode %v error %v\n", address, err)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (rpcServer *RpcServer) exchangePeers(address string) error {
+	client, err := rpc.Dial("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	args := &ExchangePeersArgs{Peers: rpcServer.store.All()}
+	response := &ExchangePeersResponse{}
+	if err := client.Call("Handler.ExchangePeers", args, response); err != nil {
+		return err
+	}
+	cnt := rpcServer.MergePeers(response.Peers)
+	log.Printf("Exchanged peers with bootnode %v: sent %v, received %v, merged %v\n", address, len(args.Peers), len(response.Peers), cnt)
+	return nil
+}
@@ -1,39 +1,71 @@
 package server
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
-	"github.com/incognitochain/incognito-chain/consensus/blsbft"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
 )
 
+// newSignedPingArgs - ping of a node whose mining key is generated from seed, signing its raw address, role and shard now
+func newSignedPingArgs(t *testing.T, seed string, rawAddress string, role string, shardID int) *PingArgs {
+	privKey, pubKey := bridgesig.KeyGen([]byte(seed))
+	publicKey, err := json.Marshal(map[string][]byte{common.BridgeConsensus: bridgesig.PKBytes(&pubKey)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamp := time.Now().Unix()
+	sig, err := bridgesig.Sign(bridgesig.SKBytes(&privKey), PeerRecordSignData(rawAddress, role, shardID, timestamp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &PingArgs{}
+	args.Init(rawAddress, common.BlsConsensus, string(publicKey), base58.Base58Check{}.Encode(sig, common.ZeroByte))
+	args.Role, args.ShardID, args.Timestamp = role, shardID, timestamp
+	return args
+}
+
 func TestRpcServer_AddOrUpdatePeer(t *testing.T) {
 	rpcServer := RpcServer{}
 	rpcServer.Init(&RpcServerConfig{
 		Port: 9333,
 	})
 
-	blsBft := blsbft.BLSBFT{}
-	privateSeed, err := blsBft.LoadUserKeyFromIncPrivateKey("112t8rnXCqbbNYBquntyd6EvDT4WiDDQw84ZSRDKmazkqrzi6w8rWyCVt7QEZgAiYAV4vhJiX7V9MCfuj4hGLoDN7wdU1LoWGEFpLs59X7K3")
-	if err != nil {
+	args := newSignedPingArgs(t, "seed", "/ip4/127.0.0.1/tcp/9433/p2p/QmPeer", "", -1)
+	if err := rpcServer.AddOrUpdatePeer(args); err != nil {
 		t.Error(err)
 	}
-	blsBft.LoadUserKey(privateSeed)
-	blsPublicKeyBytes := blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BridgeConsensus]
-
-	args := &PingArgs{
-		RawAddress: "localhost:9333",
-		PublicKey:  base58.Base58Check{}.Encode(blsPublicKeyBytes, common.ZeroByte),
+	if rpcServer.store.Len() == 0 {
+		t.Error("AddOrUpdatePeer fail")
 	}
-	signDataInByte, err := blsBft.UserKeySet.BriSignData([]byte(args.RawAddress)) //, 0, []blsmultisig.PublicKey{blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BlsConsensus]})
-	if err != nil {
+
+	// node without mining key is answered but not registered
+	unsigned := *newSignedPingArgs(t, "unsigned", "/ip4/127.0.0.3/tcp/9433/p2p/QmPeer", "", -1)
+	unsigned.SignData = ""
+	if err := rpcServer.AddOrUpdatePeer(&unsigned); err != nil {
 		t.Error(err)
 	}
-	args.SignData = base58.Base58Check{}.Encode(signDataInByte, common.ZeroByte)
-	rpcServer.AddOrUpdatePeer(args.RawAddress, common.BlsConsensus, args.PublicKey, args.SignData)
-	if len(rpcServer.peers) == 0 {
-		t.Error("AddOrUpdatePeer fail")
+	if _, ok := rpcServer.store.Get(unsigned.PublicKey); ok {
+		t.Error("AddOrUpdatePeer store unsigned record")
+	}
+	forged := *newSignedPingArgs(t, "seed", "/ip4/127.0.0.1/tcp/9433/p2p/QmPeer", "", -1)
+	forged.RawAddress = "/ip4/127.0.0.2/tcp/9433/p2p/QmPeer"
+	if err := rpcServer.AddOrUpdatePeer(&forged); err == nil {
+		t.Error("AddOrUpdatePeer accept record with wrong signature")
+	}
+	// role and shard are signed
+	forged = *newSignedPingArgs(t, "seed", "/ip4/127.0.0.1/tcp/9433/p2p/QmPeer", "", -1)
+	forged.Role, forged.ShardID = common.CommitteeRole, 0
+	if err := rpcServer.AddOrUpdatePeer(&forged); err == nil {
+		t.Error("AddOrUpdatePeer accept record with forged role")
+	}
+	stale := *newSignedPingArgs(t, "seed", "/ip4/127.0.0.1/tcp/9433/p2p/QmPeer", "", -1)
+	stale.Timestamp -= 2 * heartbeatTimeout
+	if err := rpcServer.AddOrUpdatePeer(&stale); err == nil {
+		t.Error("AddOrUpdatePeer accept stale record")
 	}
 }
 
@@ -43,31 +75,14 @@ func TestRpcServer_RemovePeerByPbk(t *testing.T) {
 		Port: 9333,
 	})
 
-	blsBft := blsbft.BLSBFT{}
-	privateSeed, err := blsBft.LoadUserKeyFromIncPrivateKey("112t8rnXCqbbNYBquntyd6EvDT4WiDDQw84ZSRDKmazkqrzi6w8rWyCVt7QEZgAiYAV4vhJiX7V9MCfuj4hGLoDN7wdU1LoWGEFpLs59X7K3")
-	if err != nil {
-		t.Error(err)
-	}
-	blsBft.LoadUserKey(privateSeed)
-	briPublicKeyBytes := blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BridgeConsensus]
-
-	args := &PingArgs{
-		RawAddress: "localhost:9333",
-		PublicKey:  base58.Base58Check{}.Encode(briPublicKeyBytes, common.ZeroByte),
-	}
-	signDataInByte, err := blsBft.UserKeySet.BriSignData([]byte(args.RawAddress)) //, 0, []blsmultisig.PublicKey{blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BlsConsensus]})
-	if err != nil {
-		t.Error(err)
-	}
-	args.SignData = base58.Base58Check{}.Encode(signDataInByte, common.ZeroByte)
-
-	rpcServer.AddOrUpdatePeer(args.RawAddress, common.BlsConsensus, args.PublicKey, args.SignData)
-	if len(rpcServer.peers) == 0 {
+	args := newSignedPingArgs(t, "seed", "localhost:9333", "", -1)
+	rpcServer.AddOrUpdatePeer(args)
+	if rpcServer.store.Len() == 0 {
 		t.Error("AddOrUpdatePeer fail")
 	}
 
 	rpcServer.RemovePeerByPbk(args.PublicKey)
-	if len(rpcServer.peers) > 0 {
+	if rpcServer.store.Len() > 0 {
 		t.Error("RemovePeerByPbk fail")
 	}
 }
@@ -78,31 +93,15 @@ func TestRpcServer_PeerHeartBeat(t *testing.T) {
 		Port: 9333,
 	})
 
-	blsBft := blsbft.BLSBFT{}
-	privateSeed, err := blsBft.LoadUserKeyFromIncPrivateKey("112t8rnXCqbbNYBquntyd6EvDT4WiDDQw84ZSRDKmazkqrzi6w8rWyCVt7QEZgAiYAV4vhJiX7V9MCfuj4hGLoDN7wdU1LoWGEFpLs59X7K3")
-	if err != nil {
-		t.Error(err)
-	}
-	blsBft.LoadUserKey(privateSeed)
-	briPublicKeyBytes := blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BridgeConsensus]
-
-	args := &PingArgs{
-		RawAddress: "localhost:9333",
-		PublicKey:  base58.Base58Check{}.Encode(briPublicKeyBytes, common.ZeroByte),
-	}
-	signDataInByte, err := blsBft.UserKeySet.BriSignData([]byte(args.RawAddress)) //, 0, []blsmultisig.PublicKey{blsBft.UserKeySet.GetPublicKey().MiningPubKey[common.BlsConsensus]})
-	if err != nil {
-		t.Error(err)
-	}
-	args.SignData = base58.Base58Check{}.Encode(signDataInByte, common.ZeroByte)
-	rpcServer.AddOrUpdatePeer(args.RawAddress, common.BlsConsensus, args.PublicKey, args.SignData)
-	if len(rpcServer.peers) == 0 {
+	args := newSignedPingArgs(t, "seed", "localhost:9333", "", -1)
+	rpcServer.AddOrUpdatePeer(args)
+	if rpcServer.store.Len() == 0 {
 		t.Error("AddOrUpdatePeer fail")
 	}
 
-	go rpcServer.PeerHeartBeat(6)
+	go rpcServer.PeerHeartBeat(6 * time.Second)
 	for {
-		if len(rpcServer.peers) == 0 {
+		if rpcServer.store.Len() == 0 {
 			t.Log("PeerHeartBeat")
 			return
 		}
@@ -110,6 +109,36 @@ func TestRpcServer_PeerHeartBeat(t *testing.T) {
 
 }
 
+func TestRpcServer_MergePeers(t *testing.T) {
+	rpcServer := RpcServer{}
+	rpcServer.Init(&RpcServerConfig{
+		Port: 9333,
+	})
+	other := RpcServer{}
+	other.Init(&RpcServerConfig{
+		Port: 9334,
+	})
+	other.AddOrUpdatePeer(newSignedPingArgs(t, "seed1", "localhost:9001", "", -1))
+	other.AddOrUpdatePeer(newSignedPingArgs(t, "seed2", "localhost:9002", "", -1))
+	records := other.store.All()
+	records[1].RawAddress = "localhost:9003" // signature does not match anymore
+
+	if cnt := rpcServer.MergePeers(records); cnt != 1 {
+		t.Errorf("expect 1 merged peer, got %v", cnt)
+	}
+	// replayed record is not merged again
+	if cnt := rpcServer.MergePeers(records[:1]); cnt != 0 {
+		t.Errorf("expect replayed record is not merged, got %v", cnt)
+	}
+	// record signed before peer timeout is stale
+	stale := newSignedPingArgs(t, "seed3", "localhost:9004", "", -1)
+	stale.Timestamp -= 2 * heartbeatTimeout
+	record := PeerRecord{RawAddress: stale.RawAddress, PublicKey: stale.PublicKey, PublicKeyType: stale.PublicKeyType, SignData: stale.SignData, Role: stale.Role, ShardID: stale.ShardID, SignedAt: stale.Timestamp, LastSeen: time.Now().Unix()}
+	if cnt := rpcServer.MergePeers([]PeerRecord{record}); cnt != 0 {
+		t.Errorf("expect stale record is not merged, got %v", cnt)
+	}
+}
+
 func TestRpcServer_Start(t *testing.T) {
 	rpcServer := RpcServer{}
 	rpcServer.Init(&RpcServerConfig{
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/pkg/errors"
+)
+
+var peerPrefix = []byte("bootnode-peer-")
+
+// PeerRecord - a peer registered to bootnode. SignData is the signature of RawAddress, Role, ShardID and SignedAt
+// by the mining key, it is kept so other bootnodes can verify the record when peer lists are exchanged
+type PeerRecord struct {
+	RawAddress    string
+	PublicKey     string
+	PublicKeyType string
+	SignData      string
+	Role          string
+	ShardID       int
+	SignedAt      int64 // unix time
+	FirstSeen     int64 // unix time
+	LastSeen      int64 // unix time
+}
+
+// PeerStore keeps peer records in memory and in an incdb database, so they are kept after restart
+type PeerStore struct {
+	db    incdb.Database // nil: memory only
+	peers map[string]*PeerRecord
+	mtx   sync.RWMutex
+}
+
+// NewPeerStore - load all records of db, db can be nil
+func NewPeerStore(db incdb.Database) (*PeerStore, error) {
+	s := &PeerStore{
+		db:    db,
+		peers: map[string]*PeerRecord{},
+	}
+	if db == nil {
+		return s, nil
+	}
+	iter := db.NewIteratorWithPrefix(peerPrefix)
+	defer iter.Release()
+	for iter.Next() {
+		record := &PeerRecord{}
+		if err := json.Unmarshal(iter.Value(), record); err != nil {
+			return nil, errors.Wrapf(err, "invalid peer record %s", iter.Key())
+		}
+		s.peers[record.PublicKey] = record
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return s, nil
+}
+
+func peerKey(publicKey string) []byte {
+	return append(append([]byte{}, peerPrefix...), []byte(publicKey)...)
+}
+
+// Put - add or replace the record of its public key, keep FirstSeen of the old record
+func (s *PeerStore) Put(record PeerRecord) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if old, ok := s.peers[record.PublicKey]; ok && old.FirstSeen != 0 && old.FirstSeen < record.FirstSeen {
+		record.FirstSeen = old.FirstSeen
+	}
+	if s.db != nil {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := s.db.Put(peerKey(record.PublicKey), value); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	s.peers[record.PublicKey] = &record
+	return nil
+}
+
+func (s *PeerStore) Get(publicKey string) (PeerRecord, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	record, ok := s.peers[publicKey]
+	if !ok {
+		return PeerRecord{}, false
+	}
+	return *record, true
+}
+
+func (s *PeerStore) Delete(publicKey string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.peers, publicKey)
+	if s.db != nil {
+		return errors.WithStack(s.db.Delete(peerKey(publicKey)))
+	}
+	return nil
+}
+
+func (s *PeerStore) Len() int {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return len(s.peers)
+}
+
+// All - all records ordered by public key
+func (s *PeerStore) All() []PeerRecord {
+	return s.Query(PeerFilter{})
+}
+
+// PeerFilter - empty Role and ShardIDs match all peers
+type PeerFilter struct {
+	Role     string
+	ShardIDs []int
+	SeenFrom int64 // unix time, only peers seen from this time
+}
+
+func (f PeerFilter) match(record *PeerRecord) bool {
+	if f.Role != "" && f.Role != record.Role {
+		return false
+	}
+	if record.LastSeen < f.SeenFrom {
+		return false
+	}
+	if len(f.ShardIDs) == 0 {
+		return true
+	}
+	for _, shardID := range f.ShardIDs {
+		if shardID == record.ShardID {
+			return true
+		}
+	}
+	return false
+}
+
+// Query - records matching filter, ordered by public key so paging is stable
+func (s *PeerStore) Query(filter PeerFilter) []PeerRecord {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	res := []PeerRecord{}
+	for _, record := range s.peers {
+		if filter.match(record) {
+			res = append(res, *record)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PublicKey < res[j].PublicKey
+	})
+	return res
+}
+
+// RemoveExpired - remove peers not seen since `before`, return their public keys
+func (s *PeerStore) RemoveExpired(before time.Time) []string {
+	expired := []string{}
+	s.mtx.RLock()
+	for publicKey, record := range s.peers {
+		if record.LastSeen < before.Unix() {
+			expired = append(expired, publicKey)
+		}
+	}
+	s.mtx.RUnlock()
+	for _, publicKey := range expired {
+		if err := s.Delete(publicKey); err != nil {
+			log.Println("Remove expired peer error", err)
+		}
+	}
+	return expired
+}
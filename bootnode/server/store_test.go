@@ -0,0 +1,57 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+)
+
+// Peers are loaded from database after restart, peers of a restarted bootnode have peer timeout to ping again
+func TestPeerStore_Persist(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "bootnode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rpcServer := RpcServer{}
+	if err := rpcServer.Init(&RpcServerConfig{Port: 9333, DB: db}); err != nil {
+		t.Fatal(err)
+	}
+	args := newSignedPingArgs(t, "seed", "localhost:9333", "committee", 3)
+	if err := rpcServer.AddOrUpdatePeer(args); err != nil {
+		t.Fatal(err)
+	}
+	rpcServer.RemovePeerByPbk(newSignedPingArgs(t, "other", "localhost:9334", "", -1).PublicKey)
+	db.Close()
+
+	db, err = incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err := NewPeerStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, ok := store.Get(args.PublicKey)
+	if !ok || record.RawAddress != args.RawAddress || record.SignData != args.SignData || record.Role != "committee" || record.ShardID != 3 || record.LastSeen == 0 {
+		t.Errorf("unexpected loaded record %+v", record)
+	}
+
+	if expired := store.RemoveExpired(time.Now().Add(time.Minute)); len(expired) != 1 {
+		t.Errorf("expect 1 expired peer, got %v", expired)
+	}
+	store, _ = NewPeerStore(db)
+	if store.Len() != 0 {
+		t.Error("expired peer is not removed from database")
+	}
+}
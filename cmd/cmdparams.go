@@ -48,6 +48,19 @@ type params struct {
 	// pToken
 	PNetwork string `long:"pNetwork" description:"Bridge network"`
 	PToken   string `long:"pToken" description:"Bridge token"`
+
+	// pde
+	FromHeight uint64 `long:"fromheight" description:"First beacon height to replay pde instructions of, default is 2"`
+	ToHeight   uint64 `long:"toheight" description:"Last beacon height to replay pde instructions of, default is the best beacon height"`
+
+	// portal
+	PortalTokenID    string            `long:"portaltokenid" description:"Incognito token ID of the portal token to build a proof for"`
+	BlockFile        string            `long:"blockfile" description:"File of the raw external block, hex of a serialized btc block or json of a bnb block, default is stdin"`
+	TxID             string            `long:"txid" description:"ID of the external tx to build a proof for"`
+	PortingID        string            `long:"portingid" description:"Unique porting ID the external tx pays custodians for"`
+	RedeemID         string            `long:"redeemid" description:"Unique redeem ID the external tx pays the redeemer for"`
+	CustodianAddress string            `long:"custodianaddress" description:"Incognito address of the custodian paying the redeem"`
+	Receivers        map[string]uint64 `long:"receiver" description:"Remote address the external tx must pay and its amount in incognito decimals as address:amount, can be repeated"`
 }
 
 // newConfigParser returns a new command line flags parser.
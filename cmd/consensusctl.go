@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
+)
+
+// exportSignJournal - write the sign journal of node in dataDir to file,
+// so that validator can move to another host without double signing
+func exportSignJournal(dataDir string, fileName string) error {
+	if fileName == "" {
+		return errors.New("No export file")
+	}
+	journal, err := signjournal.NewJournal(filepath.Join(dataDir, signjournal.DataFile))
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return journal.Export(f)
+}
+
+// importSignJournal - merge sign journal exported from another host into the journal of node in dataDir
+func importSignJournal(dataDir string, fileName string) error {
+	if fileName == "" {
+		return errors.New("No import file")
+	}
+	journal, err := signjournal.NewJournal(filepath.Join(dataDir, signjournal.DataFile))
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return journal.Import(f)
+}
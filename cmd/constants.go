@@ -8,6 +8,10 @@ const (
 	getPrivacyTokenID      = "getprivacytokenid"
 	backupChain            = "backupchain"
 	restoreChain           = "restorechain"
+	exportSignJournalCmd   = "exportsignjournal"
+	importSignJournalCmd   = "importsignjournal"
+	checkPDEStateCmd       = "checkpdestate"
+	buildPortalProofCmd    = "buildportalproof"
 )
 
 var CmdList = []string{
@@ -18,4 +22,8 @@ var CmdList = []string{
 	getPrivacyTokenID,
 	backupChain,
 	restoreChain,
+	exportSignJournalCmd,
+	importSignJournalCmd,
+	checkPDEStateCmd,
+	buildPortalProofCmd,
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+)
+
+// checkPDEState - replay the pde instructions of beacon blocks fromHeight to toHeight stored in bc,
+// logging every invariant they break
+func checkPDEState(bc *blockchain.BlockChain, fromHeight uint64, toHeight uint64) error {
+	if fromHeight < 2 {
+		fromHeight = 2
+	}
+	if toHeight == 0 {
+		toHeight = bc.GetBeaconBestState().BeaconHeight
+	}
+	log.Printf("Replay pde instructions of beacon blocks %v - %v", fromHeight, toHeight)
+	result, err := blockchain.ReplayPDEInstructions(bc, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	for _, violation := range result.Violations {
+		log.Println(violation.String())
+	}
+	log.Printf("Replayed %v pde instructions, %v violations", result.Instructions, len(result.Violations))
+	if len(result.Violations) > 0 {
+		return fmt.Errorf("pde state check failed with %v violations", len(result.Violations))
+	}
+	return nil
+}
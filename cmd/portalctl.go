@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	bnbrelaying "github.com/incognitochain/incognito-chain/relaying/bnb"
+	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
+	"github.com/tendermint/tendermint/types"
+)
+
+// portalProofRequest - what the proven external tx pays, checked like beacon checks porting and redeem proofs.
+// Without PortingID and RedeemID only the block of the tx is checked
+type portalProofRequest struct {
+	PortingID        string
+	RedeemID         string
+	CustodianAddress string
+	Receivers        map[string]uint64 // remote address => amount in incognito decimals
+}
+
+// readExternalBlock - raw external block from fileName, or from stdin if fileName is empty
+func readExternalBlock(fileName string) ([]byte, error) {
+	if fileName == "" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(fileName)
+}
+
+// buildUTXOProof - rawBlock is the hex of a serialized block, as returned by getblock of bitcoind with verbosity 0
+func buildUTXOProof(rawBlock []byte, txID string) (string, error) {
+	blockBytes, err := hex.DecodeString(strings.TrimSpace(string(rawBlock)))
+	if err != nil {
+		return "", err
+	}
+	block := new(wire.MsgBlock)
+	err = block.Deserialize(bytes.NewReader(blockBytes))
+	if err != nil {
+		return "", err
+	}
+	txHash, err := chainhash.NewHashFromStr(txID)
+	if err != nil {
+		return "", err
+	}
+	proof, err := btcrelaying.BuildBTCProof(block, txHash)
+	if err != nil {
+		return "", err
+	}
+	return btcrelaying.EncodeBTCProofToB64Str(proof)
+}
+
+// buildBNBProof - rawBlock is the json of a bnb block with its txs, as relayed by RelayingHeader metadata
+func buildBNBProof(rawBlock []byte, txID string) (string, error) {
+	block := new(types.Block)
+	err := json.Unmarshal(rawBlock, block)
+	if err != nil {
+		return "", err
+	}
+	txHash, err := hex.DecodeString(txID)
+	if err != nil {
+		return "", err
+	}
+	return bnbrelaying.BuildProofFromBlock(block, txHash)
+}
+
+// buildPortalProof - base64 proof of tx txID in rawBlock, in the format of proofs of porting and redeem requests of tokenID
+func buildPortalProof(chainParams *blockchain.Params, tokenID string, rawBlock []byte, txID string) (string, error) {
+	switch chainParams.PortalTokens[tokenID].(type) {
+	case *blockchain.PortalUTXOTokenProcessor:
+		return buildUTXOProof(rawBlock, txID)
+	case *blockchain.PortalBNBTokenProcessor:
+		return buildBNBProof(rawBlock, txID)
+	}
+	return "", fmt.Errorf("%v is not a portal token", tokenID)
+}
+
+func newPortingRequest(tokenID string, req portalProofRequest) *statedb.WaitingPortingRequest {
+	custodians := []*statedb.MatchingPortingCustodianDetail{}
+	amount := uint64(0)
+	for remoteAddress, cusAmount := range req.Receivers {
+		custodians = append(custodians, &statedb.MatchingPortingCustodianDetail{RemoteAddress: remoteAddress, Amount: cusAmount})
+		amount += cusAmount
+	}
+	return statedb.NewWaitingPortingRequestWithValue(req.PortingID, common.Hash{}, tokenID, "", amount, custodians, 0, 0, 0, 0)
+}
+
+func newRedeemRequest(tokenID string, req portalProofRequest) (*statedb.RedeemRequest, *statedb.MatchingRedeemCustodianDetail, error) {
+	if len(req.Receivers) != 1 || req.CustodianAddress == "" {
+		return nil, nil, errors.New("A redeem proof needs the custodian address and exactly one receiver")
+	}
+	for remoteAddress, amount := range req.Receivers {
+		matchedCustodian := statedb.NewMatchingRedeemCustodianDetailWithValue(req.CustodianAddress, "", amount)
+		redeemReq := statedb.NewRedeemRequestWithValue(req.RedeemID, tokenID, "", remoteAddress, amount,
+			[]*statedb.MatchingRedeemCustodianDetail{matchedCustodian}, 0, 0, common.Hash{}, 0, 0, "")
+		return redeemReq, matchedCustodian, nil
+	}
+	return nil, nil, nil
+}
+
+// openUTXOHeaderChain - relayed header chain of portalToken stored by the node in dataDir
+func openUTXOHeaderChain(chainParams *blockchain.Params, dataDir string, portalToken *blockchain.PortalUTXOTokenProcessor) (*btcrelaying.BlockChain, error) {
+	if portalToken.RelayingHeaderMetaType == metadata.RelayingBTCHeaderMeta {
+		headerChainParams, genesisBlockHeight := blockchain.GetBTCRelayingChainParams(chainParams.BTCRelayingHeaderChainID)
+		return btcrelaying.GetChainV2(filepath.Join(dataDir, chainParams.BTCDataFolderName), headerChainParams, genesisBlockHeight)
+	}
+	if portalToken.HeaderChainParams == nil {
+		return nil, fmt.Errorf("Portal token of %v has no header chain params", portalToken.ChainID)
+	}
+	err := btcrelaying.RegisterUTXOChainParams(portalToken.HeaderChainParams)
+	if err != nil {
+		return nil, err
+	}
+	return btcrelaying.GetChainV2(
+		filepath.Join(dataDir, portalToken.HeaderChainDataFolderName),
+		portalToken.HeaderChainParams,
+		portalToken.HeaderChainGenesisBlockHeight,
+	)
+}
+
+// verifyPortalProof - check proof against the external headers relayed to the node in dataDir,
+// the returned error is the one beacon would reject the proof with
+func verifyPortalProof(chainParams *blockchain.Params, dataDir string, tokenID string, proof string, req portalProofRequest) error {
+	switch portalToken := chainParams.PortalTokens[tokenID].(type) {
+	case *blockchain.PortalUTXOTokenProcessor:
+		headerChain, err := openUTXOHeaderChain(chainParams, dataDir, portalToken)
+		if err != nil {
+			return err
+		}
+		defer headerChain.GetDB().Close()
+		switch {
+		case req.PortingID != "":
+			return portalToken.VerifyPortingProof(proof, newPortingRequest(tokenID, req), headerChain)
+		case req.RedeemID != "":
+			redeemReq, matchedCustodian, err := newRedeemRequest(tokenID, req)
+			if err != nil {
+				return err
+			}
+			return portalToken.VerifyRedeemProof(proof, redeemReq, matchedCustodian, headerChain)
+		}
+		_, err = blockchain.VerifyUTXOProof(proof, headerChain)
+		return err
+	case *blockchain.PortalBNBTokenProcessor:
+		bnbChainState := new(bnbrelaying.BNBChainState)
+		err := bnbChainState.LoadBNBChainState(filepath.Join(dataDir, blockchain.BNBRelayingDataFolderName), chainParams.BNBRelayingHeaderChainID)
+		if err != nil {
+			return err
+		}
+		defer bnbChainState.ChainDB.Close()
+		headers := blockchain.RelayedBNBHeaderStore{BNBChainState: bnbChainState}
+		switch {
+		case req.PortingID != "":
+			return portalToken.VerifyPortingProof(proof, newPortingRequest(tokenID, req), headers, chainParams.BNBRelayingHeaderChainID)
+		case req.RedeemID != "":
+			redeemReq, matchedCustodian, err := newRedeemRequest(tokenID, req)
+			if err != nil {
+				return err
+			}
+			return portalToken.VerifyRedeemProof(proof, redeemReq, matchedCustodian, headers, chainParams.BNBRelayingHeaderChainID)
+		}
+		_, err = blockchain.VerifyBNBProof(proof, headers)
+		return err
+	}
+	return fmt.Errorf("%v is not a portal token", tokenID)
+}
+
+// portalProofRejection - why beacon would reject a proof, without the stack of the error
+func portalProofRejection(err error) string {
+	if bcErr, ok := err.(*blockchain.BlockChainError); ok {
+		return fmt.Sprintf("%v (%v)", bcErr.GetError().Error(), bcErr.Code)
+	}
+	return err.Error()
+}
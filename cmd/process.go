@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/incognitochain/incognito-chain/privacy"
 	"log"
+	"os"
 	"strconv"
 	"strings"
 
@@ -213,5 +215,76 @@ func processCmd() {
 				}
 			}
 		}
+	case exportSignJournalCmd:
+		{
+			err := exportSignJournal(cfg.DataDir, cfg.FileName)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			log.Printf("Export sign journal to %v", cfg.FileName)
+		}
+	case importSignJournalCmd:
+		{
+			err := importSignJournal(cfg.DataDir, cfg.FileName)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			log.Printf("Import sign journal from %v", cfg.FileName)
+		}
+	case checkPDEStateCmd:
+		{
+			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.TestNet)
+			if err != nil {
+				log.Println("Error create blockchain variable ", err)
+				return
+			}
+			err = checkPDEState(bc, cfg.FromHeight, cfg.ToHeight)
+			if err != nil {
+				log.Println(err)
+				os.Exit(1)
+			}
+		}
+	case buildPortalProofCmd:
+		{
+			if cfg.PortalTokenID == "" || cfg.TxID == "" {
+				log.Println("Wrong param")
+				return
+			}
+			if (cfg.PortingID != "" || cfg.RedeemID != "") && len(cfg.Receivers) == 0 {
+				log.Println("No receiver to check the proof against")
+				return
+			}
+			var bcParams *blockchain.Params
+			if cfg.TestNet {
+				bcParams = &blockchain.ChainTestParam
+			} else {
+				bcParams = &blockchain.ChainMainParam
+			}
+			rawBlock, err := readExternalBlock(cfg.BlockFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			proof, err := buildPortalProof(bcParams, cfg.PortalTokenID, rawBlock, cfg.TxID)
+			if err != nil {
+				log.Println(err)
+				os.Exit(1)
+			}
+			fmt.Println(proof)
+			req := portalProofRequest{
+				PortingID:        cfg.PortingID,
+				RedeemID:         cfg.RedeemID,
+				CustodianAddress: cfg.CustodianAddress,
+				Receivers:        cfg.Receivers,
+			}
+			err = verifyPortalProof(bcParams, cfg.DataDir, cfg.PortalTokenID, proof, req)
+			if err != nil {
+				log.Printf("Proof would be rejected on chain: %v", portalProofRejection(err))
+				os.Exit(1)
+			}
+			log.Println("Proof is valid on the relayed headers")
+		}
 	}
 }
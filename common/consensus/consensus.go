@@ -1,6 +1,9 @@
 package consensus
 
-import "github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+import (
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+)
 
 type MiningState struct {
 	Role    string
@@ -10,6 +13,7 @@ type MiningState struct {
 
 type Validator struct {
 	MiningKey   signatureschemes.MiningKey
+	Signer      signer.Signer
 	PrivateSeed string
 	State       MiningState
 }
@@ -123,11 +123,13 @@ const (
 	PDEContributionRefundStatus           = 3
 	PDEContributionMatchedNReturnedStatus = 4
 
-	PDETradeAcceptedStatus = 1
-	PDETradeRefundStatus   = 2
+	PDETradeAcceptedStatus      = 1
+	PDETradeRefundStatus        = 2
+	PDETradeExpiredRefundStatus = 3
 
-	PDECrossPoolTradeAcceptedStatus = 1
-	PDECrossPoolTradeRefundStatus   = 2
+	PDECrossPoolTradeAcceptedStatus      = 1
+	PDECrossPoolTradeRefundStatus        = 2
+	PDECrossPoolTradeExpiredRefundStatus = 3
 
 	PDEWithdrawalAcceptedStatus = 1
 	PDEWithdrawalRejectedStatus = 2
@@ -212,8 +214,9 @@ const (
 	PDEContributionRefundChainStatus           = "refund"
 	PDEContributionMatchedNReturnedChainStatus = "matchedNReturned"
 
-	PDETradeAcceptedChainStatus = "accepted"
-	PDETradeRefundChainStatus   = "refund"
+	PDETradeAcceptedChainStatus      = "accepted"
+	PDETradeRefundChainStatus        = "refund"
+	PDETradeExpiredRefundChainStatus = "expiredRefund"
 
 	PDEWithdrawalAcceptedChainStatus = "accepted"
 	PDEWithdrawalRejectedChainStatus = "rejected"
@@ -225,9 +228,11 @@ const (
 	PDEWithdrawalOnPoolPairAcceptedChainStatus = "onPoolPairAccepted"
 	PDEWithdrawalWithPRVFeeRejectedChainStatus = "withPRVFeeRejected"
 
-	PDECrossPoolTradeFeeRefundChainStatus          = "xPoolTradeRefundFee"
-	PDECrossPoolTradeSellingTokenRefundChainStatus = "xPoolTradeRefundSellingToken"
-	PDECrossPoolTradeAcceptedChainStatus           = "xPoolTradeAccepted"
+	PDECrossPoolTradeFeeRefundChainStatus                 = "xPoolTradeRefundFee"
+	PDECrossPoolTradeSellingTokenRefundChainStatus        = "xPoolTradeRefundSellingToken"
+	PDECrossPoolTradeExpiredFeeRefundChainStatus          = "xPoolTradeExpiredRefundFee"
+	PDECrossPoolTradeExpiredSellingTokenRefundChainStatus = "xPoolTradeExpiredRefundSellingToken"
+	PDECrossPoolTradeAcceptedChainStatus                  = "xPoolTradeAccepted"
 )
 
 // Portal status for chain
@@ -301,18 +306,6 @@ const PortalBTCIDStr = "ef5947f70ead81a76a53c7c8b7317dd5245510c665d3a13921dc9a58
 const PortalBNBIDStr = "6abd698ea7ddd1f98b1ecaaddab5db0453b8363ff092f0d8d7d4c6b1155fb693"
 const PRVIDStr = "0000000000000000000000000000000000000000000000000000000000000004"
 
-var PortalSupportedIncTokenIDs = []string{
-	PortalBTCIDStr, // pBTC
-	PortalBNBIDStr, // pBNB
-}
-
-// set MinAmountPortalPToken to avoid attacking with amount is less than smallest unit of cryptocurrency
-// such as satoshi in BTC
-var MinAmountPortalPToken = map[string]uint64{
-	PortalBTCIDStr: 10,
-	PortalBNBIDStr: 10,
-}
-
 const ETHChainName = "eth"
 
 const (
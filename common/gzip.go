@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"compress/gzip"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 )
 
@@ -45,3 +47,22 @@ func GZipToBytes(src []byte) ([]byte, error) {
 	}
 	return resultBytes, nil
 }
+
+// GZipToBytesWithLimit - GZipToBytes, return an error instead of decompressing more than limit bytes
+func GZipToBytesWithLimit(src []byte, limit int) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	resultBytes, err := ioutil.ReadAll(io.LimitReader(gz, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(resultBytes) > limit {
+		return nil, fmt.Errorf("decompressed data is larger than %v bytes", limit)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return resultBytes, nil
+}
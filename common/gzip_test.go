@@ -53,3 +53,17 @@ func TestGzipGZipToBytesWithInvalidData(t *testing.T) {
 	assert.NotEqual(t, nil, err)
 	assert.Equal(t, 0, len(decompressedData))
 }
+
+func TestGzipGZipToBytesWithLimit(t *testing.T) {
+	data := make([]byte, 10000)
+	compressedData, _ := GZipFromBytes(data)
+
+	decompressedData, err := GZipToBytesWithLimit(compressedData, len(data))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, data, decompressedData)
+
+	// compressed data is small, decompressed data exceeds limit
+	decompressedData, err = GZipToBytesWithLimit(compressedData, len(data)-1)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 0, len(decompressedData))
+}
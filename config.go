@@ -16,6 +16,8 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/wire"
 	"github.com/jessevdk/go-flags"
 )
 
@@ -43,10 +45,13 @@ const (
 	DefaultDisableRpcTLS               = true
 	DefaultFastStartup                 = true
 	// DefaultNodeMode                    = common.NodeModeRelay
-	DefaultEnableMining = true
-	DefaultTxPoolTTL    = uint(15 * 60) // 15 minutes
-	DefaultTxPoolMaxTx  = uint64(100000)
-	DefaultLimitFee     = uint64(1) // 1 nano PRV = 10^-9 PRV
+	DefaultEnableMining       = true
+	DefaultTxPoolTTL          = uint(15 * 60) // 15 minutes
+	DefaultTxPoolMaxTx        = uint64(100000)
+	DefaultTxPoolPeerRate     = float64(50)
+	DefaultTxPoolPeerBurst    = 200
+	DefaultTxPoolInboundQueue = 2000
+	DefaultLimitFee           = uint64(1) // 1 nano PRV = 10^-9 PRV
 	//DefaultLimitFee = uint64(100000) // 100000 nano PRV = 100000 * 10^-9 PRV
 	// For wallet
 	DefaultWalletName     = "wallet"
@@ -133,12 +138,17 @@ type config struct {
 
 	FastStartup bool `long:"faststartup" description:"Load existed shard/chain dependencies instead of rebuild from block data"`
 
-	TxPoolTTL   uint   `long:"txpoolttl" description:"Set Time To Live (TTL) Value for transaction that enter pool"`
-	TxPoolMaxTx uint64 `long:"txpoolmaxtx" description:"Set Maximum number of transaction in pool"`
-	LimitFee    uint64 `long:"limitfee" description:"Limited fee for tx(per Kb data), default is 0.00 PRV"`
+	TxPoolTTL          uint    `long:"txpoolttl" description:"Set Time To Live (TTL) Value for transaction that enter pool"`
+	TxPoolMaxTx        uint64  `long:"txpoolmaxtx" description:"Set Maximum number of transaction in pool"`
+	TxPoolPeerRate     float64 `long:"txpoolpeerrate" description:"Transactions per second accepted from a peer via gossip, transactions relayed by highway count for the peer which published them"`
+	TxPoolPeerBurst    int     `long:"txpoolpeerburst" description:"Transactions a peer can send at once via gossip"`
+	TxPoolInboundQueue int     `long:"txpoolinboundqueue" description:"Max transactions from gossip waiting for verification, more are dropped"`
+	LimitFee           uint64  `long:"limitfee" description:"Limited fee for tx(per Kb data), default is 0.00 PRV"`
 
 	LoadMempool       bool   `long:"loadmempool" description:"Load transactions from Mempool database"`
 	PersistMempool    bool   `long:"persistmempool" description:"Persistence transaction in memepool database"`
+	PDEIndexer        bool   `long:"pdeindexer" description:"Index candles, trades and liquidity of PDE pairs from finalized beacon blocks, for getpdecandles, getpdepairstats and getpdetradehistory"`
+	CustodianMonitor  bool   `long:"custodianmonitor" description:"Re-evaluate collateral ratios of portal custodians after each beacon block, for getcustodianhealth and subcribecustodianhealth"`
 	MetricUrl         string `long:"metricurl" description:"Metric URL"`
 	BtcClient         uint   `long:"btcclient" description:"Default 0: BlockCypherClient, 1: Self Host Bitcoin Client (Must pass in btcclientip, btcclientport, btcclientusername, btcclientpassword"`
 	BtcClientIP       string `long:"btcclientip" description:"Bitcoin Client IP (Static IP)"`
@@ -148,10 +158,20 @@ type config struct {
 	EnableMining      bool   `long:"mining" description:"enable mining"`
 	MiningKeys        string `long:"miningkeys" description:"keys used for different consensus algorigthm"`
 	PrivateKey        string `long:"privatekey" description:"your wallet privatekey"`
+	RemoteSigner      string `long:"remotesigner" description:"Address of incognito-signer daemon holding the mining key (unix:///path/to/socket or host:port), used instead of miningkeys/privatekey, requires consensus v2"`
+	RemoteSignerCert  string `long:"remotesignercert" description:"Client certificate to connect to remote signer over tcp (mTLS)"`
+	RemoteSignerKey   string `long:"remotesignerkey" description:"Client certificate key to connect to remote signer over tcp (mTLS)"`
+	RemoteSignerCA    string `long:"remotesignerca" description:"CA certificate of remote signer"`
 	Accelerator       bool   `long:"accelerator" description:"Relay Node Configuration For Consensus"`
 
 	// Highway
 	Libp2pPrivateKey string `long:"libp2pprivatekey" description:"Private key used to create node's PeerID, empty to generate random key each run"`
+	P2PCodec         string `long:"p2pcodec" description:"Codec of published p2p messages: json (default), binary, or per message type e.g. bft:binary,peerstate:binary -- binary is only published while all peers advertise it, messages of both codecs are always accepted"`
+	P2PMode          string `long:"p2pmode" description:"highway (default): gossip via highways, direct: gossip directly with static peers and mDNS peers (private devnet), auto: via highways, fall back to direct while no highway is reachable"`
+	P2PStaticPeers   string `long:"p2pstaticpeers" description:"Comma separated libp2p addresses of peers to gossip with in direct mode, e.g. /ip4/1.2.3.4/tcp/9433/p2p/QmPeerID"`
+	P2PMDNS          bool   `long:"p2pmdns" description:"Discover peers on local network with mDNS in direct mode"`
+	P2PCompactBlocks bool   `long:"p2pcompactblocks" description:"Announce produced shard blocks by header and short transaction ids, peers rebuild them from their pool and request missing transactions from the producer, through the highway in highway mode -- compact blocks are always accepted, enable once peers are upgraded"`
+	NAT              string `long:"nat" description:"none (default): external address from --externaladdress only, discover: learn external address from peers with AutoNAT, upnp: map listening port on the gateway with UPnP or NAT-PMP and discover if it fails"`
 
 	//backup
 	PreloadAddress string `long:"preloadaddress" description:"Endpoint of fullnode to download backup database"`
@@ -334,17 +354,20 @@ func loadConfig() (*config, []string, error) {
 		TestNet:                     "true",
 		DiscoverPeersAddress:        "127.0.0.1:9330", //"35.230.8.182:9339",
 		// NodeMode:                    DefaultNodeMode,
-		MiningKeys:     common.EmptyString,
-		PrivateKey:     common.EmptyString,
-		FastStartup:    DefaultFastStartup,
-		TxPoolTTL:      DefaultTxPoolTTL,
-		TxPoolMaxTx:    DefaultTxPoolMaxTx,
-		PersistMempool: DefaultPersistMempool,
-		LimitFee:       DefaultLimitFee,
-		MetricUrl:      DefaultMetricUrl,
-		BtcClient:      DefaultBtcClient,
-		BtcClientPort:  DefaultBtcClientPort,
-		EnableMining:   DefaultEnableMining,
+		MiningKeys:         common.EmptyString,
+		PrivateKey:         common.EmptyString,
+		FastStartup:        DefaultFastStartup,
+		TxPoolTTL:          DefaultTxPoolTTL,
+		TxPoolMaxTx:        DefaultTxPoolMaxTx,
+		TxPoolPeerRate:     DefaultTxPoolPeerRate,
+		TxPoolPeerBurst:    DefaultTxPoolPeerBurst,
+		TxPoolInboundQueue: DefaultTxPoolInboundQueue,
+		PersistMempool:     DefaultPersistMempool,
+		LimitFee:           DefaultLimitFee,
+		MetricUrl:          DefaultMetricUrl,
+		BtcClient:          DefaultBtcClient,
+		BtcClientPort:      DefaultBtcClientPort,
+		EnableMining:       DefaultEnableMining,
 	}
 
 	// Service options which are only added on Windows.
@@ -647,6 +670,24 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	if cfg.RemoteSigner != "" && (cfg.MiningKeys != "" || cfg.PrivateKey != "") {
+		err := errors.New("remote signer can not be used together with miningkeys or privatekey")
+		return nil, nil, err
+	}
+
+	if _, _, err := wire.ParseCodecs(cfg.P2PCodec); err != nil {
+		return nil, nil, err
+	}
+	if _, err := peerv2.ParseP2PMode(cfg.P2PMode); err != nil {
+		return nil, nil, err
+	}
+	if _, err := peerv2.ParseStaticPeers(cfg.P2PStaticPeers); err != nil {
+		return nil, nil, err
+	}
+	if _, err := peerv2.ParseNATMode(cfg.NAT); err != nil {
+		return nil, nil, err
+	}
+
 	// if cfg.MiningKeys == "" && cfg.PrivateKey == "" && cfg.NodeMode != common.NodeModeRelay {
 	// 	return nil, nil, errors.New("MiningKeys can't be empty if nodemode isn't relay")
 	// }
@@ -35,6 +35,9 @@ type ConnManager struct {
 	listeningPeer *peer.Peer
 
 	randShards []byte
+
+	// external address discovered by NAT traversal, used when ExternalAddress is not configured
+	discoveredAddress atomic.Value
 }
 
 type Config struct {
@@ -74,6 +77,20 @@ func (connManager ConnManager) GetConfig() *Config {
 	return &connManager.config
 }
 
+// SetExternalAddress - set the host:port discovered by NAT traversal, reported to bootnode in place of
+// config ExternalAddress if it is empty. An empty address means it is not reachable anymore
+func (connManager *ConnManager) SetExternalAddress(address string) {
+	connManager.discoveredAddress.Store(address)
+}
+
+func (connManager *ConnManager) getExternalAddress() string {
+	if connManager.config.ExternalAddress != common.EmptyString {
+		return connManager.config.ExternalAddress
+	}
+	address, _ := connManager.discoveredAddress.Load().(string)
+	return address
+}
+
 func (connManager ConnManager) GetListeningPeer() *peer.Peer {
 	return connManager.listeningPeer
 }
@@ -389,7 +406,7 @@ func (connManager *ConnManager) processDiscoverPeers() error {
 		listener := connManager.config.ListenerPeer
 		var response []wire.RawPeer
 
-		externalAddress := connManager.config.ExternalAddress
+		externalAddress := connManager.getExternalAddress()
 		Logger.log.Info("Start Process Discover Peers ExternalAddress", externalAddress)
 		monitor.SetGlobalParam("ExternalAddress", externalAddress)
 		// remove later
@@ -426,7 +443,13 @@ func (connManager *ConnManager) processDiscoverPeers() error {
 		// 		Logger.log.Error(err)
 		// 	}
 		// }
-		publicKeyMining, publicKeyType, signDataInBase58CheckEncode, err := listener.GetConfig().ConsensusEngine.SignDataWithCurrentMiningKey([]byte(rawAddress))
+		// role, shard and signing time are signed along with raw address so bootnode can reject forged or replayed records
+		role, shardID := "", 0
+		if connManager.config.ConsensusState != nil {
+			role, shardID = connManager.config.ConsensusState.getRoleAndShard()
+		}
+		timestamp := time.Now().Unix()
+		publicKeyMining, publicKeyType, signDataInBase58CheckEncode, err := listener.GetConfig().ConsensusEngine.SignDataWithCurrentMiningKey(server.PeerRecordSignData(rawAddress, role, shardID, timestamp))
 		// fmt.Printf("CONNLog User Publickey in Ping message RawAddress %v PublicKey %v \n", rawAddress, publicKeyMining)
 		if err != nil {
 			Logger.log.Error(err)
@@ -436,6 +459,7 @@ func (connManager *ConnManager) processDiscoverPeers() error {
 		// packing in a object PingArgs
 		args := &server.PingArgs{}
 		args.Init(rawAddress, publicKeyType, publicKeyMining, signDataInBase58CheckEncode)
+		args.Role, args.ShardID, args.Timestamp = role, shardID, timestamp
 		Logger.log.Debugf("[Exchange Peers] Ping %+v", args)
 
 		err = client.Call("Handler.Ping", args, &response)
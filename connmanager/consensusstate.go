@@ -55,6 +55,17 @@ func (consensusState *ConsensusState) getCommitteeByShard(shard byte) []string {
 	return make([]string, 0)
 }
 
+// getRoleAndShard - return role and current shard of node, shard is -1 if node is not in a shard
+func (consensusState *ConsensusState) getRoleAndShard() (string, int) {
+	consensusState.Lock()
+	defer consensusState.Unlock()
+	shardID := -1
+	if consensusState.currentShard != nil {
+		shardID = int(*consensusState.currentShard)
+	}
+	return consensusState.role, shardID
+}
+
 // getShardByCommittee - return list [commitee public key] = shardID
 func (consensusState *ConsensusState) getShardByCommittee() map[string]byte {
 	consensusState.Lock()
@@ -9,11 +9,13 @@ import (
 	"sort"
 	"time"
 
-	signatureschemes2 "github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/banmanager"
+	"github.com/incognitochain/incognito-chain/consensus_v2/consensusjournal"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/incognitochain/incognito-chain/common"
-	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -26,7 +28,9 @@ type BLSBFT_V2 struct {
 	ChainID  int
 	PeerID   string
 
-	UserKeySet   []signatureschemes2.MiningKey
+	UserKeySet   []signer.Signer
+	SignJournal  *signjournal.Journal   // nil to disable double-sign protection (simulation)
+	Bans         *banmanager.BanManager // ignore banned validators and ban equivocating ones, nil to disable
 	BFTMessageCh chan wire.MessageBFT
 	isStarted    bool
 	destroyCh    chan struct{}
@@ -41,6 +45,11 @@ type BLSBFT_V2 struct {
 	receiveBlockByHeight map[uint64][]*ProposeBlockInfo   //blockHeight -> blockInfo
 	receiveBlockByHash   map[string]*ProposeBlockInfo     //blockHash -> blockInfo
 	voteHistory          map[uint64]common.BlockInterface // bestview height (previsous height )-> block
+	evidences            *evidencePool
+	Journal              *consensusjournal.Journal // what happened in the latest timeslots, for post-mortem
+
+	now   func() time.Time // current time, virtual clock in simulation
+	async func(f func())   // run chain/network side effect, synchronously in simulation
 }
 
 func (e BLSBFT_V2) GetChainKey() string {
@@ -110,218 +119,259 @@ func (e *BLSBFT_V2) run() error {
 				e.Logger.Info("exit bls-bftv2 consensus for chain", e.ChainKey)
 				return
 			case proposeMsg := <-e.ProposeMessageCh:
-				//fmt.Println("debug receive propose message", string(proposeMsg.Block))
-				blockIntf, err := e.Chain.UnmarshalBlock(proposeMsg.Block)
-				if err != nil || blockIntf == nil {
-					e.Logger.Info(err)
-					continue
-				}
-				block := blockIntf.(common.BlockInterface)
-				blkHash := block.Hash().String()
-
-				if _, ok := e.receiveBlockByHash[blkHash]; !ok {
-					e.receiveBlockByHash[blkHash] = &ProposeBlockInfo{
-						block:       block,
-						votes:       make(map[string]*BFTVote),
-						hasNewVote:  false,
-						receiveTime: time.Now(),
-					}
-					e.Logger.Info(e.ChainKey, "Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
-					e.receiveBlockByHeight[block.GetHeight()] = append(e.receiveBlockByHeight[block.GetHeight()], e.receiveBlockByHash[blkHash])
-				} else {
-					e.receiveBlockByHash[blkHash].block = block
-				}
-
-				if block.GetHeight() <= e.Chain.GetBestView().GetHeight() {
-					e.Logger.Infof("%v Receive block create from old view - height %v. Rejected! Expect: %v", e.ChainKey, block.GetHeight(), e.Chain.GetBestView().GetHeight())
-					continue
-				}
-
-				proposeView := e.Chain.GetViewByHash(block.GetPrevHash())
-				if proposeView == nil {
-					e.Logger.Infof("%v Request sync block from node %s from %s to %s", e.ChainKey, proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().String())
-					e.Node.RequestMissingViewViaStream(proposeMsg.PeerID, [][]byte{block.GetPrevHash().Bytes()}, e.Chain.GetShardID(), e.Chain.GetChainName())
-				}
-
+				e.processProposeMsg(proposeMsg)
 			case voteMsg := <-e.VoteMessageCh:
-				voteMsg.IsValid = 0
-				if b, ok := e.receiveBlockByHash[voteMsg.BlockHash]; ok { //if receiveblock is already initiated
-					if _, ok := b.votes[voteMsg.Validator]; !ok { // and not receive validatorA vote
-						b.votes[voteMsg.Validator] = &voteMsg // store it
-						vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
-						if v != nil {
-							vbase58, _ := v.ToBase58()
-							e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
-						} else {
-							e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
-						}
-
-						b.hasNewVote = true
-					}
-				} else {
-					e.receiveBlockByHash[voteMsg.BlockHash] = &ProposeBlockInfo{
-						votes:      make(map[string]*BFTVote),
-						hasNewVote: true,
-					}
-					e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator] = &voteMsg
-					vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
-					if v != nil {
-						vbase58, _ := v.ToBase58()
-						e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
-					} else {
-						e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
-					}
-				}
+				e.processVoteMsg(voteMsg)
 			case <-cleanMemTicker:
-				for h, _ := range e.receiveBlockByHeight {
-					if h <= e.Chain.GetFinalView().GetHeight() {
-						delete(e.receiveBlockByHeight, h)
-					}
-				}
-				for h, _ := range e.voteHistory {
-					if h <= e.Chain.GetFinalView().GetHeight() {
-						delete(e.voteHistory, h)
-					}
-				}
-				for h, proposeBlk := range e.receiveBlockByHash {
-					if time.Now().Sub(proposeBlk.receiveTime) > time.Minute {
-						delete(e.receiveBlockByHash, h)
-					}
-				}
+				e.cleanMem()
 			case <-ticker:
-				if !e.Chain.IsReady() {
-					continue
-				}
-				e.currentTime = time.Now().Unix()
+				e.processTick()
+			}
+		}
+	}()
+	return nil
+}
 
-				newTimeSlot := false
-				if e.currentTimeSlot != common.CalculateTimeSlot(e.currentTime) {
-					newTimeSlot = true
-				}
+func (e *BLSBFT_V2) processProposeMsg(proposeMsg BFTPropose) {
+	//fmt.Println("debug receive propose message", string(proposeMsg.Block))
+	blockIntf, err := e.Chain.UnmarshalBlock(proposeMsg.Block)
+	if err != nil || blockIntf == nil {
+		e.Logger.Info(err)
+		return
+	}
+	block := blockIntf.(common.BlockInterface)
+	blkHash := block.Hash().String()
+	if e.isBannedProposer(block) {
+		e.Logger.Infof("%v Drop block %v proposed by banned validator %v", e.ChainKey, blkHash, block.GetProposer())
+		return
+	}
 
-				e.currentTimeSlot = common.CalculateTimeSlot(e.currentTime)
-				bestView := e.Chain.GetBestView()
-
-				/*
-					Check for whether we should propose block
-				*/
-				proposerPk, _ := bestView.GetProposerByTimeSlot(e.currentTimeSlot, 2)
-				var userProposeKey signatureschemes2.MiningKey
-				shouldPropose := false
-				shouldListen := true
-				for _, userKey := range e.UserKeySet {
-					userPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
-					if proposerPk.GetMiningKeyBase58(common.BlsConsensus) == userPk {
-						shouldListen = false
-						if common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()) != e.currentTimeSlot { // current timeslot is not add to view, and this user is proposer of this timeslot
-							//using block hash as key of best view -> check if this best view we propose or not
-							if _, ok := e.proposeHistory.Get(fmt.Sprintf("%s%d", e.currentTimeSlot)); !ok {
-								shouldPropose = true
-								userProposeKey = userKey
-							}
-						}
-					}
-				}
+	if _, ok := e.receiveBlockByHash[blkHash]; !ok {
+		e.receiveBlockByHash[blkHash] = &ProposeBlockInfo{
+			block:       block,
+			votes:       make(map[string]*BFTVote),
+			hasNewVote:  false,
+			receiveTime: e.now(),
+		}
+		e.Logger.Info(e.ChainKey, "Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
+		e.receiveBlockByHeight[block.GetHeight()] = append(e.receiveBlockByHeight[block.GetHeight()], e.receiveBlockByHash[blkHash])
+	} else {
+		e.receiveBlockByHash[blkHash].block = block
+		for _, vote := range e.receiveBlockByHash[blkHash].votes {
+			if e.observeVote(block, vote) {
+				e.journalVote(vote)
+			}
+		}
+	}
+	if e.observePropose(block) {
+		e.journalProposal(block, proposeMsg.PeerID)
+	}
 
-				if newTimeSlot { //for logging
-					e.Logger.Infof("%v", e.ChainKey)
-					e.Logger.Infof("%v ======================================================", e.ChainKey)
-					e.Logger.Infof("%v", e.ChainKey)
-					if shouldListen {
-						e.Logger.Infof("%v TS: %v, LISTEN BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
-					}
-					if shouldPropose {
-						e.Logger.Infof("%v TS: %v, PROPOSE BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
-					}
+	if block.GetHeight() <= e.Chain.GetBestView().GetHeight() {
+		e.Logger.Infof("%v Receive block create from old view - height %v. Rejected! Expect: %v", e.ChainKey, block.GetHeight(), e.Chain.GetBestView().GetHeight())
+		return
+	}
 
-				}
+	proposeView := e.Chain.GetViewByHash(block.GetPrevHash())
+	if proposeView == nil {
+		e.Logger.Infof("%v Request sync block from node %s from %s to %s", e.ChainKey, proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().String())
+		e.Node.RequestMissingViewViaStream(proposeMsg.PeerID, [][]byte{block.GetPrevHash().Bytes()}, e.Chain.GetShardID(), e.Chain.GetChainName())
+	}
+}
 
-				if shouldPropose {
-					e.proposeHistory.Add(fmt.Sprintf("%s%d", e.currentTimeSlot), 1)
-					//Proposer Rule: check propose block connected to bestview(longest chain rule 1) and re-propose valid block with smallest timestamp (including already propose in the past) (rule 2)
-					sort.Slice(e.receiveBlockByHeight[bestView.GetHeight()+1], func(i, j int) bool {
-						return e.receiveBlockByHeight[bestView.GetHeight()+1][i].block.GetProduceTime() < e.receiveBlockByHeight[bestView.GetHeight()+1][j].block.GetProduceTime()
-					})
-
-					var proposeBlock common.BlockInterface = nil
-					for _, v := range e.receiveBlockByHeight[bestView.GetHeight()+1] {
-						if v.isValid {
-							proposeBlock = v.block
-							break
-						}
-					}
-
-					//proposerPk: which include mining pubkey + incokey
-					//userKey: only have minigkey
-					if createdBlk, err := e.proposeBlock(userProposeKey, proposerPk, proposeBlock); err != nil {
-						e.Logger.Critical(UnExpectedError, errors.New("can't propose block"))
-						e.Logger.Critical(err)
-
-					} else {
-						e.Logger.Infof("%v proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", e.ChainKey, createdBlk.GetHeight(), e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
-					}
-				}
+func (e *BLSBFT_V2) processVoteMsg(voteMsg BFTVote) {
+	voteMsg.IsValid = 0
+	if e.isBannedValidator(voteMsg.Validator) {
+		e.Logger.Infof("%v Drop vote of banned validator %v", e.ChainKey, voteMsg.Validator)
+		return
+	}
+	voteMsg.receiveTime = e.now()
+	if b, ok := e.receiveBlockByHash[voteMsg.BlockHash]; ok { //if receiveblock is already initiated
+		if _, ok := b.votes[voteMsg.Validator]; !ok { // and not receive validatorA vote
+			b.votes[voteMsg.Validator] = &voteMsg // store it
+			if b.block != nil && e.observeVote(b.block, &voteMsg) {
+				e.journalVote(&voteMsg)
+			}
+			vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
+			if v != nil {
+				vbase58, _ := v.ToBase58()
+				e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
+			} else {
+				e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
+			}
 
-				/*
-					Check for valid block to vote
-				*/
-				validProposeBlock := []*ProposeBlockInfo{}
-				//get all block that has height = bestview height  + 1(rule 2 & rule 3) (
-				for h, proposeBlockInfo := range e.receiveBlockByHash {
-					if proposeBlockInfo.block == nil {
-						continue
-					}
-					// e.Logger.Infof("[Monitor] bestview height %v, finalview height %v, block height %v %v", bestViewHeight, e.Chain.GetFinalView().GetHeight(), proposeBlockInfo.block.GetHeight(), proposeBlockInfo.block.GetProduceTime())
-					// check if propose block in current time
-					if e.currentTimeSlot == common.CalculateTimeSlot(proposeBlockInfo.block.GetProposeTime()) {
-						validProposeBlock = append(validProposeBlock, proposeBlockInfo)
-					}
-
-					if proposeBlockInfo.block.GetHeight() < e.Chain.GetFinalView().GetHeight() {
-						delete(e.receiveBlockByHash, h)
-					}
-				}
-				//rule 1: get history of vote for this height, vote if (round is lower than the vote before) or (round is equal but new proposer) or (there is no vote for this height yet)
-				sort.Slice(validProposeBlock, func(i, j int) bool {
-					return validProposeBlock[i].block.GetProduceTime() < validProposeBlock[j].block.GetProduceTime()
-				})
-
-				for _, v := range validProposeBlock {
-					if v.sendVote {
-						continue
-					}
-
-					blkCreateTimeSlot := common.CalculateTimeSlot(v.block.GetProduceTime())
-					bestViewHeight := bestView.GetHeight()
-
-					if lastVotedBlk, ok := e.voteHistory[bestViewHeight+1]; ok {
-						if blkCreateTimeSlot < common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) { //blkCreateTimeSlot is smaller than voted block => vote for this blk
-							e.validateAndVote(v)
-						} else if blkCreateTimeSlot == common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) && common.CalculateTimeSlot(v.block.GetProposeTime()) > common.CalculateTimeSlot(lastVotedBlk.GetProposeTime()) { //blk is old block (same round), but new proposer(larger timeslot) => vote again
-							e.validateAndVote(v)
-						} //blkCreateTimeSlot is larger or equal than voted block => do nothing
-					} else { //there is no vote for this height yet
-						e.validateAndVote(v)
-					}
-				}
+			b.hasNewVote = true
+		}
+	} else {
+		e.receiveBlockByHash[voteMsg.BlockHash] = &ProposeBlockInfo{
+			votes:       make(map[string]*BFTVote),
+			hasNewVote:  true,
+			receiveTime: e.now(),
+		}
+		e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator] = &voteMsg
+		vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
+		if v != nil {
+			vbase58, _ := v.ToBase58()
+			e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
+		} else {
+			e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
+		}
+	}
+}
+
+func (e *BLSBFT_V2) cleanMem() {
+	for h, _ := range e.receiveBlockByHeight {
+		if h <= e.Chain.GetFinalView().GetHeight() {
+			delete(e.receiveBlockByHeight, h)
+		}
+	}
+	for h, _ := range e.voteHistory {
+		if h <= e.Chain.GetFinalView().GetHeight() {
+			delete(e.voteHistory, h)
+		}
+	}
+	e.evidences.clean(e.Chain.GetFinalView().GetHeight())
+	for h, proposeBlk := range e.receiveBlockByHash {
+		if e.now().Sub(proposeBlk.receiveTime) > time.Minute {
+			delete(e.receiveBlockByHash, h)
+		}
+	}
+}
+
+func (e *BLSBFT_V2) processTick() {
+	if !e.Chain.IsReady() {
+		return
+	}
+	e.currentTime = e.now().Unix()
 
-				/*
-					Check for 2/3 vote to commit
-				*/
-				for k, v := range e.receiveBlockByHash {
-					e.processIfBlockGetEnoughVote(k, v)
+	newTimeSlot := false
+	if e.currentTimeSlot != common.CalculateTimeSlot(e.currentTime) {
+		newTimeSlot = true
+	}
+
+	e.currentTimeSlot = common.CalculateTimeSlot(e.currentTime)
+	bestView := e.Chain.GetBestView()
+
+	/*
+		Check for whether we should propose block
+	*/
+	proposerPk, proposerIndex := bestView.GetProposerByTimeSlot(e.currentTimeSlot, 2)
+	if newTimeSlot {
+		e.Journal.StartTimeSlot(e.currentTimeSlot, bestView.GetHeight(), proposerPk.GetMiningKeyBase58(common.BlsConsensus), proposerIndex)
+	}
+	var userProposeKey signer.Signer
+	shouldPropose := false
+	shouldListen := true
+	for _, userKey := range e.UserKeySet {
+		userPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
+		if proposerPk.GetMiningKeyBase58(common.BlsConsensus) == userPk {
+			shouldListen = false
+			if common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()) != e.currentTimeSlot { // current timeslot is not add to view, and this user is proposer of this timeslot
+				//using block hash as key of best view -> check if this best view we propose or not
+				if _, ok := e.proposeHistory.Get(fmt.Sprintf("%s%d", e.currentTimeSlot)); !ok {
+					shouldPropose = true
+					userProposeKey = userKey
 				}
+			}
+		}
+	}
+
+	if newTimeSlot { //for logging
+		e.Logger.Infof("%v", e.ChainKey)
+		e.Logger.Infof("%v ======================================================", e.ChainKey)
+		e.Logger.Infof("%v", e.ChainKey)
+		if shouldListen {
+			e.Logger.Infof("%v TS: %v, LISTEN BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
+		}
+		if shouldPropose {
+			e.Logger.Infof("%v TS: %v, PROPOSE BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
+		}
+
+	}
 
+	if shouldPropose {
+		e.proposeHistory.Add(fmt.Sprintf("%s%d", e.currentTimeSlot), 1)
+		//Proposer Rule: check propose block connected to bestview(longest chain rule 1) and re-propose valid block with smallest timestamp (including already propose in the past) (rule 2)
+		sort.Slice(e.receiveBlockByHeight[bestView.GetHeight()+1], func(i, j int) bool {
+			return e.receiveBlockByHeight[bestView.GetHeight()+1][i].block.GetProduceTime() < e.receiveBlockByHeight[bestView.GetHeight()+1][j].block.GetProduceTime()
+		})
+
+		var proposeBlock common.BlockInterface = nil
+		for _, v := range e.receiveBlockByHeight[bestView.GetHeight()+1] {
+			if v.isValid {
+				proposeBlock = v.block
+				break
 			}
 		}
-	}()
-	return nil
+
+		//proposerPk: which include mining pubkey + incokey
+		//userKey: only have minigkey
+		if createdBlk, err := e.proposeBlock(userProposeKey, proposerPk, proposeBlock); err != nil {
+			e.Logger.Critical(UnExpectedError, errors.New("can't propose block"))
+			e.Logger.Critical(err)
+
+		} else {
+			e.Logger.Infof("%v proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", e.ChainKey, createdBlk.GetHeight(), e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
+		}
+	}
+
+	/*
+		Check for valid block to vote
+	*/
+	validProposeBlock := []*ProposeBlockInfo{}
+	//get all block that has height = bestview height  + 1(rule 2 & rule 3) (
+	for h, proposeBlockInfo := range e.receiveBlockByHash {
+		if proposeBlockInfo.block == nil {
+			continue
+		}
+		// e.Logger.Infof("[Monitor] bestview height %v, finalview height %v, block height %v %v", bestViewHeight, e.Chain.GetFinalView().GetHeight(), proposeBlockInfo.block.GetHeight(), proposeBlockInfo.block.GetProduceTime())
+		// check if propose block in current time
+		if e.currentTimeSlot == common.CalculateTimeSlot(proposeBlockInfo.block.GetProposeTime()) {
+			validProposeBlock = append(validProposeBlock, proposeBlockInfo)
+		}
+
+		if proposeBlockInfo.block.GetHeight() < e.Chain.GetFinalView().GetHeight() {
+			delete(e.receiveBlockByHash, h)
+		}
+	}
+	//rule 1: get history of vote for this height, vote if (round is lower than the vote before) or (round is equal but new proposer) or (there is no vote for this height yet)
+	sort.Slice(validProposeBlock, func(i, j int) bool {
+		return validProposeBlock[i].block.GetProduceTime() < validProposeBlock[j].block.GetProduceTime()
+	})
+
+	for _, v := range validProposeBlock {
+		if v.sendVote {
+			continue
+		}
+
+		blkCreateTimeSlot := common.CalculateTimeSlot(v.block.GetProduceTime())
+		bestViewHeight := bestView.GetHeight()
+
+		if lastVotedBlk, ok := e.voteHistory[bestViewHeight+1]; ok {
+			if blkCreateTimeSlot < common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) { //blkCreateTimeSlot is smaller than voted block => vote for this blk
+				e.validateAndVote(v)
+			} else if blkCreateTimeSlot == common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) && common.CalculateTimeSlot(v.block.GetProposeTime()) > common.CalculateTimeSlot(lastVotedBlk.GetProposeTime()) { //blk is old block (same round), but new proposer(larger timeslot) => vote again
+				e.validateAndVote(v)
+			} //blkCreateTimeSlot is larger or equal than voted block => do nothing
+		} else { //there is no vote for this height yet
+			e.validateAndVote(v)
+		}
+	}
+
+	/*
+		Check for 2/3 vote to commit
+	*/
+	for k, v := range e.receiveBlockByHash {
+		e.processIfBlockGetEnoughVote(k, v)
+	}
 }
 
-func NewInstance(chain ChainInterface, chainKey string, chainID int, node NodeInterface, logger common.Logger) *BLSBFT_V2 {
+func newInstance(chain ChainInterface, chainKey string, chainID int, node NodeInterface, signJournal *signjournal.Journal, bans *banmanager.BanManager, logger common.Logger) *BLSBFT_V2 {
 	var err error
 	var newInstance = new(BLSBFT_V2)
 	newInstance.Chain = chain
+	newInstance.SignJournal = signJournal
+	newInstance.Bans = bans
 	newInstance.ChainKey = chainKey
 	newInstance.ChainID = chainID
 	newInstance.Node = node
@@ -332,14 +382,54 @@ func NewInstance(chain ChainInterface, chainKey string, chainID int, node NodeIn
 	newInstance.receiveBlockByHash = make(map[string]*ProposeBlockInfo)
 	newInstance.receiveBlockByHeight = make(map[uint64][]*ProposeBlockInfo)
 	newInstance.voteHistory = make(map[uint64]common.BlockInterface)
+	newInstance.evidences = newEvidencePool()
+	newInstance.Journal = consensusjournal.NewJournal(chainID, consensusjournal.DefaultCapacity)
 	newInstance.proposeHistory, err = lru.New(1000)
 	if err != nil {
 		panic(err) //must not error
 	}
+	newInstance.now = time.Now
+	newInstance.async = func(f func()) { go f() }
+	return newInstance
+}
+
+func NewInstance(chain ChainInterface, chainKey string, chainID int, node NodeInterface, signJournal *signjournal.Journal, bans *banmanager.BanManager, logger common.Logger) *BLSBFT_V2 {
+	newInstance := newInstance(chain, chainKey, chainID, node, signJournal, bans, logger)
 	newInstance.run()
 	return newInstance
 }
 
+// NewSimulationInstance - create an instance which is not driven by its own goroutine and wall clock.
+// The caller steps it with HandleBFTMsg, HandleTick and HandleCleanMem, and now gives the (virtual) current time.
+// Used by consensus simulation to replay a scenario deterministically
+func NewSimulationInstance(chain ChainInterface, chainKey string, chainID int, node NodeInterface, signJournal *signjournal.Journal, bans *banmanager.BanManager, logger common.Logger, now func() time.Time) *BLSBFT_V2 {
+	newInstance := newInstance(chain, chainKey, chainID, node, signJournal, bans, logger)
+	newInstance.now = now
+	newInstance.async = func(f func()) { f() }
+	return newInstance
+}
+
+// HandleBFTMsg - process a propose/vote message synchronously (simulation instance only)
+func (e *BLSBFT_V2) HandleBFTMsg(msgBFT *wire.MessageBFT) {
+	if !e.isStarted {
+		return
+	}
+	e.handleBFTMsg(msgBFT)
+}
+
+// HandleTick - run one tick of the actor loop synchronously (simulation instance only)
+func (e *BLSBFT_V2) HandleTick() {
+	if !e.isStarted {
+		return
+	}
+	e.processTick()
+}
+
+// HandleCleanMem - clean received blocks and votes synchronously (simulation instance only)
+func (e *BLSBFT_V2) HandleCleanMem() {
+	e.cleanMem()
+}
+
 func GetValidatorIndex(view multiview.View, validator string) (int, *incognitokey.CommitteePublicKey) {
 	for id, c := range view.GetCommittee() {
 		if validator == c.GetMiningKeyBase58(common.BlsConsensus) {
@@ -392,10 +482,9 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 
 			err := vote.validateVoteOwner(dsaKey)
 			if err != nil {
-				e.Logger.Error(dsaKey)
-				e.Logger.Error(err)
-				panic(1)
-				v.votes[id].IsValid = -1
+				// vote is not signed by the validator it claims, drop it so the real vote can still be received
+				e.Logger.Errorf("%v Invalid vote of validator %v for block %v: %v", e.ChainKey, vote.Validator, blockHash, err)
+				delete(v.votes, id)
 				errVote++
 			} else {
 				v.votes[id].IsValid = 1
@@ -409,6 +498,7 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 	v.hasNewVote = false
 	if validVote > 2*len(view.GetCommittee())/3 {
 		e.Logger.Infof("%v Commit block %v , height: %v", e.ChainKey, blockHash, v.block.GetHeight())
+		e.journalThreshold(v.block, validVote, len(view.GetCommittee()))
 		committeeBLSString, err := incognitokey.ExtractPublickeysFromCommitteeKeyList(view.GetCommittee(), common.BlsConsensus)
 		//fmt.Println(committeeBLSString)
 		if err != nil {
@@ -445,7 +535,7 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 			return
 		}
 
-		go e.Chain.InsertAndBroadcastBlock(v.block)
+		e.async(func() { e.Chain.InsertAndBroadcastBlock(v.block) })
 	}
 }
 
@@ -472,7 +562,12 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 	for _, userKey := range e.UserKeySet {
 		pubKey := userKey.GetPublicKey()
 		if common.IndexOfStr(pubKey.GetMiningKeyBase58(e.GetConsensusName()), committeeBLSString) != -1 {
-			Vote, err := CreateVote(&userKey, v.block, view.GetCommittee())
+			//journal the vote before signing, so that a restarted node can not vote for a conflicting block
+			if err := e.recordSign(signjournal.ActionVote, pubKey, v.block); err != nil {
+				e.Logger.Error(err)
+				return err
+			}
+			Vote, err := CreateVote(userKey, e.ChainID, v.block, view.GetCommittee())
 			if err != nil {
 				e.Logger.Error(err)
 				return NewConsensusError(UnExpectedError, err)
@@ -488,17 +583,18 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 			e.voteHistory[v.block.GetHeight()] = v.block
 			e.Logger.Info(e.ChainKey, "sending vote...")
 			v.sendVote = true
-			go e.ProcessBFTMsg(msg.(*wire.MessageBFT))
-			go e.Node.PushMessageToChain(msg, e.Chain)
+			e.Journal.SetVoted(common.CalculateTimeSlot(e.now().Unix()), Vote.BlockHash)
+			e.processVoteMsg(*Vote)
+			e.async(func() { e.Node.PushMessageToChain(msg, e.Chain) })
 		}
 	}
 
 	return nil
 }
 
-func CreateVote(userKey *signatureschemes2.MiningKey, block common.BlockInterface, committees []incognitokey.CommitteePublicKey) (*BFTVote, error) {
+func CreateVote(userKey signer.Signer, chainID int, block common.BlockInterface, committees []incognitokey.CommitteePublicKey) (*BFTVote, error) {
 	var Vote = new(BFTVote)
-	bytelist := []blsmultisig.PublicKey{}
+	bytelist := [][]byte{}
 	selfIdx := 0
 	userBLSPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
 	for i, v := range committees {
@@ -508,34 +604,30 @@ func CreateVote(userKey *signatureschemes2.MiningKey, block common.BlockInterfac
 		bytelist = append(bytelist, v.MiningPubKey[common.BlsConsensus])
 	}
 
-	blsSig, err := userKey.BLSSignData(block.Hash().GetBytes(), selfIdx, bytelist)
+	sig, err := userKey.SignVote(&signer.VoteRequest{
+		Validator: userBLSPk,
+		ChainID:   chainID,
+		Height:    block.GetHeight(),
+		TimeSlot:  common.CalculateTimeSlot(block.GetProposeTime()),
+		BlockHash: *block.Hash(),
+		Committee: bytelist,
+		SelfIdx:   selfIdx,
+		BridgeSig: metadata.HasBridgeInstructions(block.GetInstructions()) || metadata.HasPortalInstructions(block.GetInstructions()),
+	})
 	if err != nil {
-
 		return nil, NewConsensusError(UnExpectedError, err)
 	}
-	bridgeSig := []byte{}
-	if metadata.HasBridgeInstructions(block.GetInstructions()) || metadata.HasPortalInstructions(block.GetInstructions()) {
-		bridgeSig, err = userKey.BriSignData(block.Hash().GetBytes())
-		if err != nil {
-			return nil, NewConsensusError(UnExpectedError, err)
-		}
-	}
-	Vote.BLS = blsSig
-	Vote.BRI = bridgeSig
+	Vote.BLS = sig.BLS
+	Vote.BRI = sig.BRI
+	Vote.Confirmation = sig.Confirmation
 	Vote.BlockHash = block.Hash().String()
-
-	userPk := userKey.GetPublicKey()
-	Vote.Validator = userPk.GetMiningKeyBase58(common.BlsConsensus)
+	Vote.Validator = userBLSPk
 	Vote.PrevBlockHash = block.GetPrevHash().String()
-	err = Vote.signVote(userKey)
-	if err != nil {
-		return nil, NewConsensusError(UnExpectedError, err)
-	}
 	return Vote, nil
 }
 
-func (e *BLSBFT_V2) proposeBlock(userMiningKey signatureschemes2.MiningKey, proposerPk incognitokey.CommitteePublicKey, block common.BlockInterface) (common.BlockInterface, error) {
-	time1 := time.Now()
+func (e *BLSBFT_V2) proposeBlock(userMiningKey signer.Signer, proposerPk incognitokey.CommitteePublicKey, block common.BlockInterface) (common.BlockInterface, error) {
+	time1 := e.now()
 	b58Str, _ := proposerPk.ToBase58()
 	var err error
 	if block == nil {
@@ -558,12 +650,26 @@ func (e *BLSBFT_V2) proposeBlock(userMiningKey signatureschemes2.MiningKey, prop
 	if block != nil {
 		e.Logger.Infof("%v create block %v hash %v, propose time %v, produce time %v", e.ChainKey, block.GetHeight(), block.Hash().String(), block.(common.BlockInterface).GetProposeTime(), block.(common.BlockInterface).GetProduceTime())
 	} else {
-		e.Logger.Infof("%v create block fail, time: %v", e.ChainKey, time.Since(time1).Seconds())
+		e.Logger.Infof("%v create block fail, time: %v", e.ChainKey, e.now().Sub(time1).Seconds())
 		return nil, NewConsensusError(BlockCreationError, errors.New("block is nil"))
 	}
 
+	//journal the proposal before signing, so that a restarted node can not propose a conflicting block
+	if err := e.recordSign(signjournal.ActionPropose, userMiningKey.GetPublicKey(), block); err != nil {
+		return nil, err
+	}
+
 	var validationData ValidationData
-	validationData.ProducerBLSSig, _ = userMiningKey.BriSignData(block.Hash().GetBytes())
+	validationData.ProducerBLSSig, err = userMiningKey.SignPropose(&signer.ProposeRequest{
+		Validator: userMiningKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus),
+		ChainID:   e.ChainID,
+		Height:    block.GetHeight(),
+		TimeSlot:  common.CalculateTimeSlot(block.GetProposeTime()),
+		BlockHash: *block.Hash(),
+	})
+	if err != nil {
+		return nil, NewConsensusError(SignDataError, err)
+	}
 	validationDataString, _ := EncodeValidationData(validationData)
 	block.(blockValidation).AddValidationField(validationDataString)
 	blockData, _ := json.Marshal(block)
@@ -571,12 +677,29 @@ func (e *BLSBFT_V2) proposeBlock(userMiningKey signatureschemes2.MiningKey, prop
 	proposeCtn.Block = blockData
 	proposeCtn.PeerID = e.Node.GetSelfPeerID().String()
 	msg, _ := MakeBFTProposeMsg(proposeCtn, e.ChainKey, e.currentTimeSlot, block.GetHeight())
-	go e.ProcessBFTMsg(msg.(*wire.MessageBFT))
-	go e.Node.PushMessageToChain(msg, e.Chain)
+	e.processProposeMsg(*proposeCtn)
+	e.async(func() { e.Node.PushMessageToChain(msg, e.Chain) })
 
 	return block, nil
 }
 
+//recordSign check the block against the sign journal of this key and persist it, return error if signing would conflict
+func (e *BLSBFT_V2) recordSign(action string, pubKey *incognitokey.CommitteePublicKey, block common.BlockInterface) error {
+	if e.SignJournal == nil {
+		return nil
+	}
+	record := signjournal.SignRecord{
+		ChainID:   e.ChainID,
+		Height:    block.GetHeight(),
+		TimeSlot:  common.CalculateTimeSlot(block.GetProposeTime()),
+		BlockHash: block.Hash().String(),
+	}
+	if err := e.SignJournal.CheckAndRecord(action, pubKey.GetMiningKeyBase58(common.BlsConsensus), record); err != nil {
+		return NewConsensusError(SignJournalConflictError, err)
+	}
+	return nil
+}
+
 func (e *BLSBFT_V2) ProcessBFTMsg(msgBFT *wire.MessageBFT) {
 	switch msgBFT.Type {
 	case MSG_PROPOSE:
@@ -602,6 +725,32 @@ func (e *BLSBFT_V2) ProcessBFTMsg(msgBFT *wire.MessageBFT) {
 	}
 }
 
+//handleBFTMsg same as ProcessBFTMsg, but process the message in caller goroutine instead of the actor loop
+func (e *BLSBFT_V2) handleBFTMsg(msgBFT *wire.MessageBFT) {
+	switch msgBFT.Type {
+	case MSG_PROPOSE:
+		var msgPropose BFTPropose
+		err := json.Unmarshal(msgBFT.Content, &msgPropose)
+		if err != nil {
+			e.Logger.Error(err)
+			return
+		}
+		msgPropose.PeerID = msgBFT.PeerID
+		e.processProposeMsg(msgPropose)
+	case MSG_VOTE:
+		var msgVote BFTVote
+		err := json.Unmarshal(msgBFT.Content, &msgVote)
+		if err != nil {
+			e.Logger.Error(err)
+			return
+		}
+		e.processVoteMsg(msgVote)
+	default:
+		e.Logger.Critical("Unknown BFT message type")
+		return
+	}
+}
+
 func (e *BLSBFT_V2) preValidateVote(blockHash []byte, Vote *BFTVote, candidate []byte) error {
 	data := []byte{}
 	data = append(data, blockHash...)
@@ -612,17 +761,6 @@ func (e *BLSBFT_V2) preValidateVote(blockHash []byte, Vote *BFTVote, candidate [
 	return err
 }
 
-func (s *BFTVote) signVote(key *signatureschemes2.MiningKey) error {
-	data := []byte{}
-	data = append(data, s.BlockHash...)
-	data = append(data, s.BLS...)
-	data = append(data, s.BRI...)
-	data = common.HashB(data)
-	var err error
-	s.Confirmation, err = key.BriSignData(data)
-	return err
-}
-
 func (s *BFTVote) validateVoteOwner(ownerPk []byte) error {
 	data := []byte{}
 	data = append(data, s.BlockHash...)
@@ -0,0 +1,228 @@
+package blsbftv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/banmanager"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+type signedMessage struct {
+	height    uint64
+	blockHash string
+	msg       metadata.SignedHeader
+}
+
+type evidence struct {
+	metadata.EquivocationEvidence
+	height   uint64 // higher height of the two messages
+	reported bool   // returned by getEvidences
+}
+
+// evidencePool keep the first message each validator signed in a timeslot,
+// a second message for another block in the same timeslot is an equivocation evidence
+type evidencePool struct {
+	lock      sync.RWMutex
+	signed    map[string]*signedMessage // type-validator-timeslot -> first signed message
+	evidences map[string]*evidence      // type-validator-timeslot -> evidence
+}
+
+func newEvidencePool() *evidencePool {
+	return &evidencePool{
+		signed:    make(map[string]*signedMessage),
+		evidences: make(map[string]*evidence),
+	}
+}
+
+func evidenceKey(evidenceType string, validator string, timeSlot int64) string {
+	return fmt.Sprintf("%v-%v-%v", evidenceType, validator, timeSlot)
+}
+
+// add - record a signed message, return true if it conflicts with a message signed before
+func (p *evidencePool) add(evidenceType string, chainID int, validator string, timeSlot int64, height uint64, blockHash string, msg metadata.SignedHeader) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := evidenceKey(evidenceType, validator, timeSlot)
+	first, ok := p.signed[key]
+	if !ok {
+		p.signed[key] = &signedMessage{height: height, blockHash: blockHash, msg: msg}
+		return false
+	}
+	if first.blockHash == blockHash {
+		return false
+	}
+	if _, ok := p.evidences[key]; ok {
+		return true
+	}
+	if first.height > height {
+		height = first.height
+	}
+	p.evidences[key] = &evidence{
+		EquivocationEvidence: metadata.EquivocationEvidence{
+			Type:      evidenceType,
+			ChainID:   chainID,
+			Validator: validator,
+			Messages:  []metadata.SignedHeader{first.msg, msg},
+		},
+		height: height,
+	}
+	return true
+}
+
+// clean - signed messages of finalized heights can not be used to build new evidence anymore,
+// and evidences of finalized heights are not needed anymore once they have been reported
+func (p *evidencePool) clean(finalHeight uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key, signed := range p.signed {
+		if signed.height <= finalHeight {
+			delete(p.signed, key)
+		}
+	}
+	for key, evidence := range p.evidences {
+		if evidence.reported && evidence.height <= finalHeight {
+			delete(p.evidences, key)
+		}
+	}
+}
+
+// forgetSlashed - drop evidences of chainID whose report is already included on chain
+func (p *evidencePool) forgetSlashed(chainID int, slashes []*statedb.EquivocationSlashState) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, slash := range slashes {
+		if slash.ChainID() != chainID {
+			continue
+		}
+		committeePublicKey := incognitokey.CommitteePublicKey{}
+		if err := committeePublicKey.FromBase58(slash.CommitteePublicKey()); err != nil {
+			continue
+		}
+		validator := committeePublicKey.GetMiningKeyBase58(common.BlsConsensus)
+		delete(p.evidences, evidenceKey(slash.EvidenceType(), validator, slash.TimeSlot()))
+	}
+}
+
+// getEvidences - every evidence in the pool, which is then reported
+func (p *evidencePool) getEvidences() []metadata.EquivocationEvidence {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	keys := []string{}
+	for key := range p.evidences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res := []metadata.EquivocationEvidence{}
+	for _, key := range keys {
+		p.evidences[key].reported = true
+		res = append(res, p.evidences[key].EquivocationEvidence)
+	}
+	return res
+}
+
+func getBlockHeader(block common.BlockInterface) (json.RawMessage, error) {
+	blockData, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+	temp := struct {
+		Header json.RawMessage
+	}{}
+	if err := json.Unmarshal(blockData, &temp); err != nil {
+		return nil, err
+	}
+	return temp.Header, nil
+}
+
+// observePropose - record the signed proposal of the block proposer, false if block is not signed by its proposer
+func (e *BLSBFT_V2) observePropose(block common.BlockInterface) bool {
+	if err := ValidateProducerSig(block); err != nil {
+		return false
+	}
+	valData, err := DecodeValidationData(block.GetValidationField())
+	if err != nil {
+		return false
+	}
+	producerKey := incognitokey.CommitteePublicKey{}
+	if err := producerKey.FromBase58(block.GetProposer()); err != nil {
+		return false
+	}
+	header, err := getBlockHeader(block)
+	if err != nil {
+		return true
+	}
+	validator := producerKey.GetMiningKeyBase58(common.BlsConsensus)
+	timeSlot := common.CalculateTimeSlot(block.GetProposeTime())
+	msg := metadata.SignedHeader{Header: header, ProducerSig: valData.ProducerBLSSig}
+	if e.evidences.add(metadata.EquivocationPropose, e.ChainID, validator, timeSlot, block.GetHeight(), block.Hash().String(), msg) {
+		e.Logger.Errorf("%v Proposer %v proposed conflicting blocks in timeslot %v", e.ChainKey, validator, timeSlot)
+		e.banEquivocation(validator, fmt.Sprintf("proposed conflicting blocks in timeslot %v on %v", timeSlot, e.ChainKey))
+	}
+	return true
+}
+
+// observeVote - record the signed vote of a committee member for the block, false if vote is not signed by a committee member
+func (e *BLSBFT_V2) observeVote(block common.BlockInterface, vote *BFTVote) bool {
+	_, committeePk := GetValidatorIndex(e.Chain.GetBestView(), vote.Validator)
+	if committeePk == nil {
+		return false
+	}
+	if err := vote.validateVoteOwner(committeePk.MiningPubKey[common.BridgeConsensus]); err != nil {
+		return false
+	}
+	header, err := getBlockHeader(block)
+	if err != nil {
+		return true
+	}
+	timeSlot := common.CalculateTimeSlot(block.GetProposeTime())
+	msg := metadata.SignedHeader{Header: header, BLS: vote.BLS, BRI: vote.BRI, Confirmation: vote.Confirmation}
+	if e.evidences.add(metadata.EquivocationVote, e.ChainID, vote.Validator, timeSlot, block.GetHeight(), vote.BlockHash, msg) {
+		e.Logger.Errorf("%v Validator %v voted for conflicting blocks in timeslot %v", e.ChainKey, vote.Validator, timeSlot)
+		e.banEquivocation(vote.Validator, fmt.Sprintf("voted for conflicting blocks in timeslot %v on %v", timeSlot, e.ChainKey))
+	}
+	return true
+}
+
+// banEquivocation - ban the mining key of a validator which signed conflicting messages.
+// Only validly signed messages are evidences, so a validator cannot be banned by messages forged in its name
+func (e *BLSBFT_V2) banEquivocation(validator string, message string) {
+	if e.Bans == nil {
+		return
+	}
+	if _, err := e.Bans.Ban(banmanager.KeyMiningKey, validator, banmanager.ReasonEquivocation, message); err != nil {
+		e.Logger.Errorf("%v Cannot ban validator %v: %v", e.ChainKey, validator, err)
+	}
+}
+
+// isBannedValidator - true if the mining key (base58 BLS key) of validator is banned
+func (e *BLSBFT_V2) isBannedValidator(validator string) bool {
+	return e.Bans != nil && e.Bans.IsBanned(banmanager.KeyMiningKey, validator)
+}
+
+func (e *BLSBFT_V2) isBannedProposer(block common.BlockInterface) bool {
+	if e.Bans == nil {
+		return false
+	}
+	producerKey := incognitokey.CommitteePublicKey{}
+	if err := producerKey.FromBase58(block.GetProposer()); err != nil {
+		return false
+	}
+	return e.isBannedValidator(producerKey.GetMiningKeyBase58(common.BlsConsensus))
+}
+
+// GetEquivocationEvidences - evidences of validators signing conflicting messages, observed by this node.
+// Returned evidences are dropped once their height is final
+func (e *BLSBFT_V2) GetEquivocationEvidences() []metadata.EquivocationEvidence {
+	return e.evidences.getEvidences()
+}
+
+// ForgetSlashedEvidences - drop evidences whose report is included on chain
+func (e *BLSBFT_V2) ForgetSlashedEvidences(slashes []*statedb.EquivocationSlashState) {
+	e.evidences.forgetSlashed(e.ChainID, slashes)
+}
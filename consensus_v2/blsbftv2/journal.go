@@ -0,0 +1,58 @@
+package blsbftv2
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/consensusjournal"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+func (e *BLSBFT_V2) nowMilli() int64 {
+	return e.now().UnixNano() / 1e6
+}
+
+// journalProposal - record a proposal signed by its proposer (see observePropose)
+func (e *BLSBFT_V2) journalProposal(block common.BlockInterface, peerID string) {
+	proposer := block.GetProposer()
+	proposerKey := incognitokey.CommitteePublicKey{}
+	if err := proposerKey.FromBase58(proposer); err == nil {
+		proposer = proposerKey.GetMiningKeyBase58(common.BlsConsensus)
+	}
+	e.Journal.AddProposal(common.CalculateTimeSlot(e.now().Unix()), consensusjournal.ProposalRecord{
+		BlockHash:     block.Hash().String(),
+		Height:        block.GetHeight(),
+		BlockTimeSlot: common.CalculateTimeSlot(block.GetProposeTime()),
+		Proposer:      proposer,
+		PeerID:        peerID,
+		ReceiveTime:   e.nowMilli(),
+	})
+}
+
+// journalVote - record a vote signed by its validator (see observeVote), in the timeslot it was received
+func (e *BLSBFT_V2) journalVote(vote *BFTVote) {
+	receiveTime := vote.receiveTime
+	if receiveTime.IsZero() {
+		receiveTime = e.now()
+	}
+	validatorIndex, _ := GetValidatorIndex(e.Chain.GetBestView(), vote.Validator)
+	e.Journal.AddVote(common.CalculateTimeSlot(receiveTime.Unix()), consensusjournal.VoteRecord{
+		BlockHash:      vote.BlockHash,
+		ValidatorIndex: validatorIndex,
+		Validator:      vote.Validator,
+		ReceiveTime:    receiveTime.UnixNano() / 1e6,
+	})
+}
+
+func (e *BLSBFT_V2) journalThreshold(block common.BlockInterface, voteCount int, committeeSize int) {
+	e.Journal.AddThreshold(common.CalculateTimeSlot(e.now().Unix()), consensusjournal.ThresholdRecord{
+		BlockHash:     block.Hash().String(),
+		Height:        block.GetHeight(),
+		VoteCount:     voteCount,
+		CommitteeSize: committeeSize,
+		ReachTime:     e.nowMilli(),
+	})
+}
+
+// GetConsensusJournal - timeslot records from fromTimeSlot to toTimeSlot (inclusive) kept by this process
+func (e *BLSBFT_V2) GetConsensusJournal(fromTimeSlot int64, toTimeSlot int64) []consensusjournal.TimeSlotRecord {
+	return e.Journal.Get(fromTimeSlot, toTimeSlot)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	signatureschemes2 "github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
 	"sort"
 
 	"github.com/incognitochain/incognito-chain/common"
@@ -12,7 +13,17 @@ import (
 )
 
 func (e *BLSBFT_V2) LoadUserKeys(miningKey []signatureschemes2.MiningKey) error {
-	e.UserKeySet = miningKey
+	signers := []signer.Signer{}
+	for i := range miningKey {
+		signers = append(signers, signer.NewLocalSigner(&miningKey[i]))
+	}
+	e.UserKeySet = signers
+	return nil
+}
+
+// LoadUserSigners - load signers of user mining keys, which can be held in process or by a remote signer
+func (e *BLSBFT_V2) LoadUserSigners(signers []signer.Signer) error {
+	e.UserKeySet = signers
 	return nil
 }
 
@@ -25,7 +36,7 @@ func (e *BLSBFT_V2) GetUserPublicKey() *incognitokey.CommitteePublicKey {
 
 func (e BLSBFT_V2) SignData(data []byte) (string, error) {
 	if e.UserKeySet != nil && len(e.UserKeySet) > 0 {
-		result, err := e.UserKeySet[0].BriSignData(data)
+		result, err := e.UserKeySet[0].SignData(data)
 		if err != nil {
 			return "", NewConsensusError(SignDataError, err)
 		}
@@ -29,6 +29,8 @@ type BFTVote struct {
 	Confirmation  []byte
 	IsValid       int // 0 not process, 1 valid, -1 not valid
 	TimeSlot      uint64
+
+	receiveTime time.Time // when the node received the vote, not sent to peers
 }
 
 type BFTRequestBlock struct {
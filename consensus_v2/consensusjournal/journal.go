@@ -0,0 +1,195 @@
+package consensusjournal
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultCapacity - number of timeslots kept per chain (~ 3 hours with 10s timeslot)
+const DefaultCapacity = 1000
+
+// ProposalRecord - a block proposal received by the node
+type ProposalRecord struct {
+	BlockHash     string `json:"BlockHash"`
+	Height        uint64 `json:"Height"`
+	BlockTimeSlot int64  `json:"BlockTimeSlot"` // timeslot of the block propose time
+	Proposer      string `json:"Proposer"`      // base58 of bls mining key
+	PeerID        string `json:"PeerID"`
+	ReceiveTime   int64  `json:"ReceiveTime"` // unix millisecond
+}
+
+// VoteRecord - a vote received by the node (including its own votes)
+type VoteRecord struct {
+	BlockHash      string `json:"BlockHash"`
+	ValidatorIndex int    `json:"ValidatorIndex"` // index in committee of best view, -1 if unknown
+	Validator      string `json:"Validator"`      // base58 of bls mining key
+	ReceiveTime    int64  `json:"ReceiveTime"`    // unix millisecond
+}
+
+// ThresholdRecord - a block collected enough votes to be committed
+type ThresholdRecord struct {
+	BlockHash     string `json:"BlockHash"`
+	Height        uint64 `json:"Height"`
+	VoteCount     int    `json:"VoteCount"`
+	CommitteeSize int    `json:"CommitteeSize"`
+	ReachTime     int64  `json:"ReachTime"` // unix millisecond
+}
+
+// TimeSlotRecord - what the node observed during one timeslot of a chain.
+// Proposals, votes and thresholds are recorded in the timeslot they arrive
+type TimeSlotRecord struct {
+	ChainID          int               `json:"ChainID"`
+	TimeSlot         int64             `json:"TimeSlot"`
+	BestViewHeight   uint64            `json:"BestViewHeight"`
+	ExpectedProposer string            `json:"ExpectedProposer"` // base58 of bls mining key
+	ProposerIndex    int               `json:"ProposerIndex"`
+	Proposals        []ProposalRecord  `json:"Proposals"`
+	Votes            []VoteRecord      `json:"Votes"`
+	Voted            bool              `json:"Voted"`
+	VotedBlockHash   string            `json:"VotedBlockHash"`
+	Thresholds       []ThresholdRecord `json:"Thresholds"`
+}
+
+func (r *TimeSlotRecord) copy() TimeSlotRecord {
+	res := *r
+	res.Proposals = append([]ProposalRecord{}, r.Proposals...)
+	res.Votes = append([]VoteRecord{}, r.Votes...)
+	res.Thresholds = append([]ThresholdRecord{}, r.Thresholds...)
+	return res
+}
+
+// Journal - bounded ring buffer of the latest timeslot records of one chain.
+// It is written by the consensus actor and read by rpc, so every access is locked
+type Journal struct {
+	lock     sync.RWMutex
+	chainID  int
+	capacity int
+	records  []*TimeSlotRecord // sorted by timeslot
+	onClose  func(TimeSlotRecord)
+}
+
+func NewJournal(chainID int, capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Journal{
+		chainID:  chainID,
+		capacity: capacity,
+		records:  []*TimeSlotRecord{},
+	}
+}
+
+// SetOnClose - f is called with the record of a timeslot when the journal moves to a newer timeslot
+func (j *Journal) SetOnClose(f func(TimeSlotRecord)) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.onClose = f
+}
+
+// getOrCreate - return the record of timeSlot, nil if the timeslot is already evicted
+func (j *Journal) getOrCreate(timeSlot int64) *TimeSlotRecord {
+	n := len(j.records)
+	if n > 0 && j.records[n-1].TimeSlot == timeSlot {
+		return j.records[n-1]
+	}
+	idx := sort.Search(n, func(i int) bool { return j.records[i].TimeSlot >= timeSlot })
+	if idx < n && j.records[idx].TimeSlot == timeSlot {
+		return j.records[idx]
+	}
+	if idx == 0 && n >= j.capacity {
+		return nil
+	}
+	record := &TimeSlotRecord{
+		ChainID:       j.chainID,
+		TimeSlot:      timeSlot,
+		ProposerIndex: -1,
+	}
+	j.records = append(j.records, nil)
+	copy(j.records[idx+1:], j.records[idx:])
+	j.records[idx] = record
+	if idx == n && n > 0 && j.onClose != nil {
+		j.onClose(j.records[n-1].copy())
+	}
+	if len(j.records) > j.capacity {
+		j.records[0] = nil
+		j.records = j.records[1:]
+	}
+	return record
+}
+
+// StartTimeSlot - the node enters a new timeslot, with its best view height and the proposer it expects
+func (j *Journal) StartTimeSlot(timeSlot int64, bestViewHeight uint64, expectedProposer string, proposerIndex int) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	record := j.getOrCreate(timeSlot)
+	if record == nil {
+		return
+	}
+	record.BestViewHeight = bestViewHeight
+	record.ExpectedProposer = expectedProposer
+	record.ProposerIndex = proposerIndex
+}
+
+// AddProposal - a block received again in the same timeslot is recorded once
+func (j *Journal) AddProposal(timeSlot int64, proposal ProposalRecord) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	record := j.getOrCreate(timeSlot)
+	if record == nil {
+		return
+	}
+	for _, p := range record.Proposals {
+		if p.BlockHash == proposal.BlockHash {
+			return
+		}
+	}
+	record.Proposals = append(record.Proposals, proposal)
+}
+
+// AddVote - a vote of a validator for a block received again in the same timeslot is recorded once
+func (j *Journal) AddVote(timeSlot int64, vote VoteRecord) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	record := j.getOrCreate(timeSlot)
+	if record == nil {
+		return
+	}
+	for _, v := range record.Votes {
+		if v.BlockHash == vote.BlockHash && v.Validator == vote.Validator {
+			return
+		}
+	}
+	record.Votes = append(record.Votes, vote)
+}
+
+// SetVoted - the node sent its vote for blockHash in timeSlot
+func (j *Journal) SetVoted(timeSlot int64, blockHash string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if record := j.getOrCreate(timeSlot); record != nil {
+		record.Voted = true
+		record.VotedBlockHash = blockHash
+	}
+}
+
+func (j *Journal) AddThreshold(timeSlot int64, threshold ThresholdRecord) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if record := j.getOrCreate(timeSlot); record != nil {
+		record.Thresholds = append(record.Thresholds, threshold)
+	}
+}
+
+// Get - copy of the records from fromTimeSlot to toTimeSlot (inclusive)
+func (j *Journal) Get(fromTimeSlot int64, toTimeSlot int64) []TimeSlotRecord {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	res := []TimeSlotRecord{}
+	for _, record := range j.records {
+		if record.TimeSlot < fromTimeSlot || record.TimeSlot > toTimeSlot {
+			continue
+		}
+		res = append(res, record.copy())
+	}
+	return res
+}
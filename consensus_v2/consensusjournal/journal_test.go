@@ -0,0 +1,96 @@
+package consensusjournal
+
+import (
+	"testing"
+)
+
+func TestJournal_Record(t *testing.T) {
+	j := NewJournal(0, 3)
+	closed := []int64{}
+	j.SetOnClose(func(record TimeSlotRecord) {
+		closed = append(closed, record.TimeSlot)
+	})
+
+	j.StartTimeSlot(100, 10, "proposerA", 1)
+	j.AddProposal(100, ProposalRecord{BlockHash: "hashA", Height: 11, BlockTimeSlot: 100, Proposer: "proposerA", PeerID: "peer1", ReceiveTime: 1000})
+	j.AddVote(100, VoteRecord{BlockHash: "hashA", ValidatorIndex: 0, Validator: "validator0", ReceiveTime: 1001})
+	j.SetVoted(100, "hashA")
+	j.AddThreshold(100, ThresholdRecord{BlockHash: "hashA", Height: 11, VoteCount: 3, CommitteeSize: 4, ReachTime: 1002})
+	j.StartTimeSlot(101, 11, "proposerB", 2)
+
+	records := j.Get(100, 101)
+	if len(records) != 2 {
+		t.Fatalf("expect 2 records, got %v", len(records))
+	}
+	r := records[0]
+	if r.ChainID != 0 || r.BestViewHeight != 10 || r.ExpectedProposer != "proposerA" || r.ProposerIndex != 1 {
+		t.Errorf("unexpected timeslot info %+v", r)
+	}
+	if len(r.Proposals) != 1 || r.Proposals[0].PeerID != "peer1" {
+		t.Errorf("unexpected proposals %+v", r.Proposals)
+	}
+	if len(r.Votes) != 1 || r.Votes[0].Validator != "validator0" {
+		t.Errorf("unexpected votes %+v", r.Votes)
+	}
+	if !r.Voted || r.VotedBlockHash != "hashA" {
+		t.Errorf("expect voted for hashA, got %v %v", r.Voted, r.VotedBlockHash)
+	}
+	if len(r.Thresholds) != 1 || r.Thresholds[0].VoteCount != 3 {
+		t.Errorf("unexpected thresholds %+v", r.Thresholds)
+	}
+	if len(closed) != 1 || closed[0] != 100 {
+		t.Errorf("expect timeslot 100 closed, got %v", closed)
+	}
+
+	// returned records are copies
+	records[0].Votes[0].Validator = "modified"
+	if j.Get(100, 100)[0].Votes[0].Validator != "validator0" {
+		t.Error("journal record is modified through returned copy")
+	}
+}
+
+func TestJournal_Capacity(t *testing.T) {
+	j := NewJournal(-1, 3)
+	for ts := int64(1); ts <= 5; ts++ {
+		j.StartTimeSlot(ts, uint64(ts), "", 0)
+	}
+	records := j.Get(0, 10)
+	if len(records) != 3 || records[0].TimeSlot != 3 || records[2].TimeSlot != 5 {
+		t.Fatalf("expect timeslot 3..5, got %+v", records)
+	}
+
+	// evicted timeslot is not recorded again
+	j.AddVote(1, VoteRecord{BlockHash: "hashA"})
+	if records := j.Get(0, 10); len(records) != 3 || records[0].TimeSlot != 3 {
+		t.Errorf("expect evicted timeslot is dropped, got %+v", records)
+	}
+
+	// late message of a kept timeslot is inserted in order
+	j = NewJournal(-1, 3)
+	j.StartTimeSlot(1, 1, "", 0)
+	j.StartTimeSlot(3, 3, "", 0)
+	j.AddVote(2, VoteRecord{BlockHash: "hashA"})
+	records = j.Get(0, 10)
+	if len(records) != 3 || records[0].TimeSlot != 1 || records[1].TimeSlot != 2 || records[2].TimeSlot != 3 {
+		t.Errorf("expect timeslot 1..3, got %+v", records)
+	}
+}
+
+func TestJournal_Dedupe(t *testing.T) {
+	j := NewJournal(0, 3)
+	j.AddProposal(1, ProposalRecord{BlockHash: "hashA", PeerID: "peer1"})
+	j.AddProposal(1, ProposalRecord{BlockHash: "hashA", PeerID: "peer2"})
+	j.AddProposal(1, ProposalRecord{BlockHash: "hashB", PeerID: "peer2"})
+	j.AddVote(1, VoteRecord{BlockHash: "hashA", Validator: "validator0", ReceiveTime: 1})
+	j.AddVote(1, VoteRecord{BlockHash: "hashA", Validator: "validator0", ReceiveTime: 2})
+	j.AddVote(1, VoteRecord{BlockHash: "hashA", Validator: "validator1"})
+	j.AddVote(1, VoteRecord{BlockHash: "hashB", Validator: "validator0"})
+
+	r := j.Get(1, 1)[0]
+	if len(r.Proposals) != 2 || r.Proposals[0].PeerID != "peer1" {
+		t.Errorf("expect first proposal of each block, got %+v", r.Proposals)
+	}
+	if len(r.Votes) != 3 || r.Votes[0].ReceiveTime != 1 {
+		t.Errorf("expect first vote of each validator for each block, got %+v", r.Votes)
+	}
+}
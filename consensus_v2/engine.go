@@ -3,16 +3,22 @@ package consensus_v2
 import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/metrics/monitor"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/incognitochain/incognito-chain/common/consensus"
+	"github.com/incognitochain/incognito-chain/consensus_v2/consensusjournal"
 	signatureschemes2 "github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/consensus_v2/blsbft"
 	blsbft2 "github.com/incognitochain/incognito-chain/consensus_v2/blsbftv2"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/wire"
 )
 
@@ -152,11 +158,21 @@ func (s *Engine) WatchCommitteeChange() {
 			}
 		}
 		validatorMiningKey := []signatureschemes2.MiningKey{}
+		validatorSigner := []signer.Signer{}
 		for _, validator := range validators {
 			validatorMiningKey = append(validatorMiningKey, validator.MiningKey)
+			validatorSigner = append(validatorSigner, validator.Signer)
 		}
 
-		s.BFTProcess[chainID].LoadUserKeys(validatorMiningKey)
+		//remote signer is only supported by consensus v2, v1 sign with private key in process
+		if bftv2, ok := s.BFTProcess[chainID].(*blsbft2.BLSBFT_V2); ok {
+			bftv2.LoadUserSigners(validatorSigner)
+		} else if s.config.RemoteSigner != nil {
+			Logger.Log.Errorf("CONSENSUS: %v runs consensus v1, which can not sign with remote signer", chainName)
+			continue
+		} else {
+			s.BFTProcess[chainID].LoadUserKeys(validatorMiningKey)
+		}
 		s.BFTProcess[chainID].Start()
 		miningProc = s.BFTProcess[chainID]
 	}
@@ -188,11 +204,19 @@ func (engine *Engine) initProcess(chainID int, chainName string) {
 			engine.BFTProcess[chainID] = blsbft.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, Logger.Log)
 		}
 	} else {
+		var process *blsbft2.BLSBFT_V2
 		if chainID == -1 {
-			engine.BFTProcess[chainID] = blsbft2.NewInstance(engine.config.Blockchain.BeaconChain, chainName, chainID, engine.config.Node, Logger.Log)
+			process = blsbft2.NewInstance(engine.config.Blockchain.BeaconChain, chainName, chainID, engine.config.Node, engine.config.SignJournal, engine.config.BanManager, Logger.Log)
 		} else {
-			engine.BFTProcess[chainID] = blsbft2.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, Logger.Log)
+			process = blsbft2.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, engine.config.SignJournal, engine.config.BanManager, Logger.Log)
 		}
+		if engine.config.PubSubManager != nil {
+			pubSubManager := engine.config.PubSubManager
+			process.Journal.SetOnClose(func(record consensusjournal.TimeSlotRecord) {
+				pubSubManager.PublishMessage(pubsub.NewMessage(pubsub.ConsensusJournalTopic, record))
+			})
+		}
+		engine.BFTProcess[chainID] = process
 	}
 }
 
@@ -219,7 +243,25 @@ func (engine *Engine) Init(config *EngineConfig) {
 func (engine *Engine) Start() error {
 	defer Logger.Log.Infof("CONSENSUS: Start")
 
-	if engine.config.Node.GetPrivateKey() != "" {
+	if engine.config.RemoteSigner != nil {
+		//consensus v1 signs with the private key in process, remote signer is only supported by consensus v2
+		consensusV2Epoch := engine.config.Blockchain.GetConfig().ChainParams.ConsensusV2Epoch
+		if engine.config.Blockchain.BeaconChain.GetEpoch() < consensusV2Epoch {
+			return NewConsensusError(RemoteSignerError, fmt.Errorf("remote signer requires consensus v2, which starts at epoch %v", consensusV2Epoch))
+		}
+		client, err := signer.DialRemote(engine.config.RemoteSigner)
+		if err != nil {
+			return NewConsensusError(RemoteSignerError, err)
+		}
+		signers, err := client.GetSigners()
+		if err != nil {
+			return NewConsensusError(RemoteSignerError, err)
+		}
+		engine.validators, err = newRemoteValidators(signers)
+		if err != nil {
+			return err
+		}
+	} else if engine.config.Node.GetPrivateKey() != "" {
 		privateSeed, err := engine.GenMiningKeyFromPrivateKey(engine.config.Node.GetPrivateKey())
 		if err != nil {
 			panic(err)
@@ -228,7 +270,7 @@ func (engine *Engine) Start() error {
 		if err != nil {
 			panic(err)
 		}
-		engine.validators = []*consensus.Validator{&consensus.Validator{PrivateSeed: privateSeed, MiningKey: *miningKey}}
+		engine.validators = []*consensus.Validator{&consensus.Validator{PrivateSeed: privateSeed, MiningKey: *miningKey, Signer: signer.NewLocalSigner(miningKey)}}
 	} else if engine.config.Node.GetMiningKeys() != "" {
 		keys := strings.Split(engine.config.Node.GetMiningKeys(), ",")
 		engine.validators = []*consensus.Validator{}
@@ -237,7 +279,7 @@ func (engine *Engine) Start() error {
 			if err != nil {
 				panic(err)
 			}
-			engine.validators = append(engine.validators, &consensus.Validator{PrivateSeed: key, MiningKey: *miningKey})
+			engine.validators = append(engine.validators, &consensus.Validator{PrivateSeed: key, MiningKey: *miningKey, Signer: signer.NewLocalSigner(miningKey)})
 		}
 		engine.validators = engine.validators[:1] //allow only 1 key
 	}
@@ -245,6 +287,19 @@ func (engine *Engine) Start() error {
 	return nil
 }
 
+//newRemoteValidators - validator of the only mining key held by remote signer
+func newRemoteValidators(signers []signer.Signer) ([]*consensus.Validator, error) {
+	//allow only 1 key
+	if len(signers) != 1 {
+		return nil, NewConsensusError(RemoteSignerError, fmt.Errorf("remote signer must hold exactly 1 mining key, it holds %v", len(signers)))
+	}
+	//private key is held by remote signer, node only know the public key
+	return []*consensus.Validator{&consensus.Validator{
+		MiningKey: signatureschemes2.MiningKey{PubKey: signers[0].GetPublicKey().MiningPubKey},
+		Signer:    signers[0],
+	}}, nil
+}
+
 func (engine *Engine) Stop() error {
 	Logger.Log.Infof("CONSENSUS: Stop")
 	for _, BFTProcess := range engine.BFTProcess {
@@ -265,7 +320,11 @@ func (engine *Engine) OnBFTMsg(msg *wire.MessageBFT) {
 func (engine *Engine) GetAllValidatorKeyState() map[string]consensus.MiningState {
 	result := make(map[string]consensus.MiningState)
 	for _, validator := range engine.validators {
-		result[validator.PrivateSeed] = validator.State
+		key := validator.PrivateSeed
+		if key == "" { //private key is held by remote signer
+			key = validator.MiningKey.GetPublicKeyBase58()
+		}
+		result[key] = validator.State
 	}
 	return result
 }
@@ -276,3 +335,46 @@ func (engine *Engine) IsCommitteeInShard(shardID byte) bool {
 	}
 	return false
 }
+
+// GetEquivocationEvidences - evidences of conflicting signed messages observed by running consensus processes,
+// except the ones already slashed by beacon
+func (engine *Engine) GetEquivocationEvidences() []metadata.EquivocationEvidence {
+	res := []metadata.EquivocationEvidence{}
+	chainIDs := []int{}
+	for chainID := range engine.BFTProcess {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Ints(chainIDs)
+	slashes := []*statedb.EquivocationSlashState{}
+	if engine.config != nil && engine.config.Blockchain != nil {
+		slashes = statedb.GetAllEquivocationSlashes(engine.config.Blockchain.GetBeaconBestState().GetBeaconSlashStateDB())
+	}
+	for _, chainID := range chainIDs {
+		if process, ok := engine.BFTProcess[chainID].(interface {
+			ForgetSlashedEvidences(slashes []*statedb.EquivocationSlashState)
+		}); ok {
+			process.ForgetSlashedEvidences(slashes)
+		}
+		if process, ok := engine.BFTProcess[chainID].(interface {
+			GetEquivocationEvidences() []metadata.EquivocationEvidence
+		}); ok {
+			res = append(res, process.GetEquivocationEvidences()...)
+		}
+	}
+	return res
+}
+
+// GetConsensusJournal - timeslot records of a chain from fromTimeSlot to toTimeSlot (inclusive)
+func (engine *Engine) GetConsensusJournal(chainID int, fromTimeSlot int64, toTimeSlot int64) ([]consensusjournal.TimeSlotRecord, error) {
+	process, ok := engine.BFTProcess[chainID]
+	if !ok {
+		return nil, NewConsensusError(ConsensusJournalNotFoundError, fmt.Errorf("no consensus process for chain %v", chainID))
+	}
+	journalProcess, ok := process.(interface {
+		GetConsensusJournal(fromTimeSlot int64, toTimeSlot int64) []consensusjournal.TimeSlotRecord
+	})
+	if !ok {
+		return nil, NewConsensusError(ConsensusJournalNotFoundError, fmt.Errorf("consensus process of chain %v does not keep journal", chainID))
+	}
+	return journalProcess.GetConsensusJournal(fromTimeSlot, toTimeSlot), nil
+}
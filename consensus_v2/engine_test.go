@@ -0,0 +1,46 @@
+package consensus_v2
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMiningKey(seed []byte) *signatureschemes.MiningKey {
+	miningKey := &signatureschemes.MiningKey{
+		PriKey: map[string][]byte{},
+		PubKey: map[string][]byte{},
+	}
+	blsPriKey, blsPubKey := blsmultisig.KeyGen(seed)
+	miningKey.PriKey[common.BlsConsensus] = blsmultisig.SKBytes(blsPriKey)
+	miningKey.PubKey[common.BlsConsensus] = blsmultisig.PKBytes(blsPubKey)
+	bridgePriKey, bridgePubKey := bridgesig.KeyGen(seed)
+	miningKey.PriKey[common.BridgeConsensus] = bridgesig.SKBytes(&bridgePriKey)
+	miningKey.PubKey[common.BridgeConsensus] = bridgesig.PKBytes(&bridgePubKey)
+	return miningKey
+}
+
+// Remote signer must hold exactly 1 key, its validator only knows the public key
+func TestNewRemoteValidators(t *testing.T) {
+	key1, key2 := newTestMiningKey([]byte("key1")), newTestMiningKey([]byte("key2"))
+	_, err := newRemoteValidators(nil)
+	assert.NotNil(t, err)
+	_, err = newRemoteValidators([]signer.Signer{signer.NewLocalSigner(key1), signer.NewLocalSigner(key2)})
+	assert.NotNil(t, err)
+
+	validators, err := newRemoteValidators([]signer.Signer{signer.NewLocalSigner(key1)})
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(validators)) {
+		assert.Equal(t, "", validators[0].PrivateSeed)
+		assert.Nil(t, validators[0].MiningKey.PriKey)
+		assert.Equal(t, key1.GetPublicKeyBase58(), validators[0].MiningKey.GetPublicKeyBase58())
+	}
+	engine := &Engine{validators: validators}
+	_, ok := engine.GetAllValidatorKeyState()[key1.GetPublicKeyBase58()]
+	assert.True(t, ok)
+}
@@ -18,24 +18,28 @@ const (
 	DecodeValidationDataError
 	EncodeValidationDataError
 	BlockCreationError
+	ConsensusJournalNotFoundError
+	RemoteSignerError
 )
 
 var ErrCodeMessage = map[int]struct {
 	Code    int
 	message string
 }{
-	UnExpectedError:              {-1000, "Unexpected error"},
-	ConsensusTypeNotExistError:   {-1001, "Consensus type isn't exist"},
-	ProducerSignatureError:       {-1002, "Producer signature error"},
-	CommitteeSignatureError:      {-1003, "Committee signature error"},
-	CombineSignatureError:        {-1004, "Combine signature error"},
-	SignDataError:                {-1005, "Sign data error"},
-	LoadKeyError:                 {-1006, "Load key error"},
-	ConsensusAlreadyStartedError: {-1007, "consensus already started error"},
-	ConsensusAlreadyStoppedError: {-1008, "consensus already stopped error"},
-	DecodeValidationDataError:    {-1009, "Decode Validation Data error"},
-	EncodeValidationDataError:    {-1010, "Encode Validation Data Error"},
-	BlockCreationError:           {-1011, "Block Creation Error"},
+	UnExpectedError:               {-1000, "Unexpected error"},
+	ConsensusTypeNotExistError:    {-1001, "Consensus type isn't exist"},
+	ProducerSignatureError:        {-1002, "Producer signature error"},
+	CommitteeSignatureError:       {-1003, "Committee signature error"},
+	CombineSignatureError:         {-1004, "Combine signature error"},
+	SignDataError:                 {-1005, "Sign data error"},
+	LoadKeyError:                  {-1006, "Load key error"},
+	ConsensusAlreadyStartedError:  {-1007, "consensus already started error"},
+	ConsensusAlreadyStoppedError:  {-1008, "consensus already stopped error"},
+	DecodeValidationDataError:     {-1009, "Decode Validation Data error"},
+	EncodeValidationDataError:     {-1010, "Encode Validation Data Error"},
+	BlockCreationError:            {-1011, "Block Creation Error"},
+	ConsensusJournalNotFoundError: {-1012, "Consensus journal not found"},
+	RemoteSignerError:             {-1013, "Remote signer error"},
 }
 
 type ConsensusError struct {
@@ -1,9 +1,12 @@
 package consensus_v2
 
 import (
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	signatureschemes2 "github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -14,6 +17,9 @@ type EngineConfig struct {
 	Node          NodeInterface
 	Blockchain    *blockchain.BlockChain
 	PubSubManager *pubsub.PubSubManager
+	SignJournal   *signjournal.Journal
+	RemoteSigner  *signer.RemoteConfig   // nil to sign with keys loaded in process
+	BanManager    *banmanager.BanManager // nil to disable ban of validators
 }
 
 type NodeInterface interface {
@@ -0,0 +1,46 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnExpectedError = iota
+	SignError
+	KeyNotFoundError
+	SlashingProtectionError
+	ConnectSignerError
+	LoadCertificateError
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	message string
+}{
+	UnExpectedError:         {-1000, "Unexpected error"},
+	SignError:               {-1001, "Sign data error"},
+	KeyNotFoundError:        {-1002, "Mining key not found in signer"},
+	SlashingProtectionError: {-1003, "Request rejected by slashing protection"},
+	ConnectSignerError:      {-1004, "Connect remote signer error"},
+	LoadCertificateError:    {-1005, "Load TLS certificate error"},
+}
+
+type SignerError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e SignerError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewSignerError(key int, err error) error {
+	return &SignerError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].message),
+	}
+}
@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	unixAddressPrefix = "unix://"
+	defaultTimeout    = 2 * time.Second
+)
+
+type RemoteConfig struct {
+	Address  string // unix:///path/to/socket or host:port
+	CertFile string // client certificate, required for tcp address (mTLS)
+	KeyFile  string
+	CAFile   string // CA which signed the certificate of signer daemon
+	Timeout  time.Duration
+}
+
+// RemoteClient - connection from node to an incognito-signer daemon
+type RemoteClient struct {
+	conn    *grpc.ClientConn
+	timeout time.Duration
+}
+
+func DialRemote(config *RemoteConfig) (*RemoteClient, error) {
+	opts := []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName))}
+	target := config.Address
+	if strings.HasPrefix(config.Address, unixAddressPrefix) {
+		target = strings.TrimPrefix(config.Address, unixAddressPrefix)
+		opts = append(opts, grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+		}))
+	} else {
+		if config.CertFile == "" || config.KeyFile == "" || config.CAFile == "" {
+			return nil, NewSignerError(ConnectSignerError, errors.New("remote signer over tcp requires client certificate, key and CA"))
+		}
+		tlsConfig, err := LoadClientTLSConfig(config.CertFile, config.KeyFile, config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, NewSignerError(ConnectSignerError, err)
+	}
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &RemoteClient{conn: conn, timeout: timeout}, nil
+}
+
+func (c *RemoteClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *RemoteClient) invoke(method string, req interface{}, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.conn.Invoke(ctx, "/"+serviceName+"/"+method, req, resp)
+}
+
+// GetSigners - get a signer for every mining key held by the daemon
+func (c *RemoteClient) GetSigners() ([]Signer, error) {
+	resp := new(PublicKeysResponse)
+	if err := c.invoke("GetPublicKeys", &PublicKeysRequest{}, resp); err != nil {
+		return nil, NewSignerError(ConnectSignerError, err)
+	}
+	signers := []Signer{}
+	for _, key := range resp.Keys {
+		signers = append(signers, &RemoteSigner{
+			client:    c,
+			publicKey: key,
+			validator: key.GetMiningKeyBase58(common.BlsConsensus),
+		})
+	}
+	return signers, nil
+}
+
+// RemoteSigner - sign with one mining key held by an incognito-signer daemon
+type RemoteSigner struct {
+	client    *RemoteClient
+	publicKey incognitokey.CommitteePublicKey
+	validator string
+}
+
+func (s *RemoteSigner) GetPublicKey() *incognitokey.CommitteePublicKey {
+	key := s.publicKey
+	return &key
+}
+
+func (s *RemoteSigner) SignVote(req *VoteRequest) (*VoteSignature, error) {
+	req.Validator = s.validator
+	resp := new(VoteSignature)
+	if err := s.client.invoke("SignVote", req, resp); err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return resp, nil
+}
+
+func (s *RemoteSigner) SignPropose(req *ProposeRequest) ([]byte, error) {
+	req.Validator = s.validator
+	resp := new(SignatureResponse)
+	if err := s.client.invoke("SignPropose", req, resp); err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return resp.Signature, nil
+}
+
+func (s *RemoteSigner) SignData(data []byte) ([]byte, error) {
+	resp := new(SignatureResponse)
+	if err := s.client.invoke("SignData", &SignDataRequest{Validator: s.validator, Data: data}, resp); err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return resp.Signature, nil
+}
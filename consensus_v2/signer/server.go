@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Server - signing service of the incognito-signer daemon.
+// It holds the mining keys, enforce slashing protection with its own sign journal
+// and log every request it receives
+type Server struct {
+	signers map[string]*LocalSigner // bls public key in base58 -> signer
+	keys    []incognitokey.CommitteePublicKey
+	journal *signjournal.Journal
+	server  *grpc.Server
+}
+
+// NewServer - creds is nil when serving on unix socket, otherwise it should require client certificate (mTLS)
+func NewServer(miningKeys []*signatureschemes.MiningKey, journal *signjournal.Journal, creds credentials.TransportCredentials) (*Server, error) {
+	if journal == nil {
+		return nil, NewSignerError(UnExpectedError, errors.New("sign journal is required"))
+	}
+	s := &Server{
+		signers: make(map[string]*LocalSigner),
+		journal: journal,
+	}
+	for _, key := range miningKeys {
+		pk := key.GetPublicKey()
+		s.signers[pk.GetMiningKeyBase58(common.BlsConsensus)] = NewLocalSigner(key)
+		s.keys = append(s.keys, *pk)
+	}
+	if creds != nil {
+		s.server = grpc.NewServer(grpc.Creds(creds))
+	} else {
+		s.server = grpc.NewServer()
+	}
+	registerSignerServer(s.server, s)
+	return s, nil
+}
+
+func (s *Server) Serve(l net.Listener) error {
+	return s.server.Serve(l)
+}
+
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
+
+func (s *Server) getSigner(validator string) (*LocalSigner, error) {
+	if sg, ok := s.signers[validator]; ok {
+		return sg, nil
+	}
+	return nil, NewSignerError(KeyNotFoundError, fmt.Errorf("validator %v", validator))
+}
+
+func logRequest(ctx context.Context, method string, format string, args ...interface{}) {
+	from := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		from = p.Addr.String()
+	}
+	log.Printf("[signer] %v from %v: %v", method, from, fmt.Sprintf(format, args...))
+}
+
+func (s *Server) GetPublicKeys(ctx context.Context, req *PublicKeysRequest) (*PublicKeysResponse, error) {
+	logRequest(ctx, "GetPublicKeys", "%v keys", len(s.keys))
+	return &PublicKeysResponse{Keys: s.keys}, nil
+}
+
+func (s *Server) SignVote(ctx context.Context, req *VoteRequest) (*VoteSignature, error) {
+	logRequest(ctx, "SignVote", "validator %v chain %v height %v timeslot %v block %v", req.Validator, req.ChainID, req.Height, req.TimeSlot, req.BlockHash.String())
+	sg, err := s.getSigner(req.Validator)
+	if err != nil {
+		log.Println("[signer] SignVote rejected", err)
+		return nil, err
+	}
+	record := signjournal.SignRecord{ChainID: req.ChainID, Height: req.Height, TimeSlot: req.TimeSlot, BlockHash: req.BlockHash.String()}
+	if err := s.journal.CheckAndRecord(signjournal.ActionVote, req.Validator, record); err != nil {
+		log.Println("[signer] SignVote rejected", err)
+		return nil, NewSignerError(SlashingProtectionError, err)
+	}
+	return sg.SignVote(req)
+}
+
+func (s *Server) SignPropose(ctx context.Context, req *ProposeRequest) (*SignatureResponse, error) {
+	logRequest(ctx, "SignPropose", "validator %v chain %v height %v timeslot %v block %v", req.Validator, req.ChainID, req.Height, req.TimeSlot, req.BlockHash.String())
+	sg, err := s.getSigner(req.Validator)
+	if err != nil {
+		log.Println("[signer] SignPropose rejected", err)
+		return nil, err
+	}
+	record := signjournal.SignRecord{ChainID: req.ChainID, Height: req.Height, TimeSlot: req.TimeSlot, BlockHash: req.BlockHash.String()}
+	if err := s.journal.CheckAndRecord(signjournal.ActionPropose, req.Validator, record); err != nil {
+		log.Println("[signer] SignPropose rejected", err)
+		return nil, NewSignerError(SlashingProtectionError, err)
+	}
+	sig, err := sg.SignPropose(req)
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureResponse{Signature: sig}, nil
+}
+
+func (s *Server) SignData(ctx context.Context, req *SignDataRequest) (*SignatureResponse, error) {
+	logRequest(ctx, "SignData", "validator %v data length %v", req.Validator, len(req.Data))
+	sg, err := s.getSigner(req.Validator)
+	if err != nil {
+		log.Println("[signer] SignData rejected", err)
+		return nil, err
+	}
+	// block proposal and vote confirmation sign a hash, do not allow signing any hash here to bypass the sign journal
+	if len(req.Data) == common.HashSize {
+		err := NewSignerError(SlashingProtectionError, errors.New("can not sign hash-sized data"))
+		log.Println("[signer] SignData rejected", err)
+		return nil, err
+	}
+	sig, err := sg.SignData(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureResponse{Signature: sig}, nil
+}
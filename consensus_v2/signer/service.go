@@ -0,0 +1,134 @@
+package signer
+
+import (
+	"context"
+
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"google.golang.org/grpc"
+)
+
+const serviceName = "signer.Signer"
+
+type PublicKeysRequest struct{}
+
+type PublicKeysResponse struct {
+	Keys []incognitokey.CommitteePublicKey
+}
+
+type SignDataRequest struct {
+	Validator string
+	Data      []byte
+}
+
+type SignatureResponse struct {
+	Signature []byte
+}
+
+// signerServer - grpc service implemented by the incognito-signer daemon
+type signerServer interface {
+	GetPublicKeys(context.Context, *PublicKeysRequest) (*PublicKeysResponse, error)
+	SignVote(context.Context, *VoteRequest) (*VoteSignature, error)
+	SignPropose(context.Context, *ProposeRequest) (*SignatureResponse, error)
+	SignData(context.Context, *SignDataRequest) (*SignatureResponse, error)
+}
+
+func registerSignerServer(s *grpc.Server, srv signerServer) {
+	s.RegisterService(&signerServiceDesc, srv)
+}
+
+func getPublicKeysHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublicKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(signerServer).GetPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/GetPublicKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(signerServer).GetPublicKeys(ctx, req.(*PublicKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func signVoteHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(signerServer).SignVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/SignVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(signerServer).SignVote(ctx, req.(*VoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func signProposeHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(signerServer).SignPropose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/SignPropose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(signerServer).SignPropose(ctx, req.(*ProposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func signDataHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(signerServer).SignData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/SignData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(signerServer).SignData(ctx, req.(*SignDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var signerServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*signerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPublicKeys",
+			Handler:    getPublicKeysHandler,
+		},
+		{
+			MethodName: "SignVote",
+			Handler:    signVoteHandler,
+		},
+		{
+			MethodName: "SignPropose",
+			Handler:    signProposeHandler,
+		},
+		{
+			MethodName: "SignData",
+			Handler:    signDataHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signer",
+}
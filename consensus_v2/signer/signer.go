@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// Signer - sign consensus messages with one mining key.
+// The default implementation (LocalSigner) holds the key in process,
+// RemoteSigner forwards every request to an incognito-signer daemon which holds the key
+type Signer interface {
+	GetPublicKey() *incognitokey.CommitteePublicKey
+	// SignVote - create BLS signature, bridge signature (if requested) and vote confirmation of a block
+	SignVote(req *VoteRequest) (*VoteSignature, error)
+	// SignPropose - create producer signature of a proposed block
+	SignPropose(req *ProposeRequest) ([]byte, error)
+	// SignData - sign arbitrary data with bridge key
+	SignData(data []byte) ([]byte, error)
+}
+
+type VoteRequest struct {
+	Validator string // bls public key in base58 of signing key
+	ChainID   int
+	Height    uint64
+	TimeSlot  int64
+	BlockHash common.Hash
+	Committee [][]byte // bls public keys of committee
+	SelfIdx   int      // index of signing key in committee
+	BridgeSig bool     // block has bridge/portal instructions
+}
+
+type VoteSignature struct {
+	BLS          []byte
+	BRI          []byte
+	Confirmation []byte
+}
+
+type ProposeRequest struct {
+	Validator string
+	ChainID   int
+	Height    uint64
+	TimeSlot  int64
+	BlockHash common.Hash
+}
+
+type LocalSigner struct {
+	key *signatureschemes.MiningKey
+}
+
+func NewLocalSigner(key *signatureschemes.MiningKey) *LocalSigner {
+	return &LocalSigner{key: key}
+}
+
+func (s *LocalSigner) GetPublicKey() *incognitokey.CommitteePublicKey {
+	return s.key.GetPublicKey()
+}
+
+func (s *LocalSigner) GetMiningKey() *signatureschemes.MiningKey {
+	return s.key
+}
+
+func (s *LocalSigner) SignVote(req *VoteRequest) (*VoteSignature, error) {
+	committee := []blsmultisig.PublicKey{}
+	for _, pk := range req.Committee {
+		committee = append(committee, pk)
+	}
+	blsSig, err := s.key.BLSSignData(req.BlockHash.GetBytes(), req.SelfIdx, committee)
+	if err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	bridgeSig := []byte{}
+	if req.BridgeSig {
+		bridgeSig, err = s.key.BriSignData(req.BlockHash.GetBytes())
+		if err != nil {
+			return nil, NewSignerError(SignError, err)
+		}
+	}
+	confirmation, err := s.key.BriSignData(VoteConfirmationData(req.BlockHash.String(), blsSig, bridgeSig))
+	if err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return &VoteSignature{
+		BLS:          blsSig,
+		BRI:          bridgeSig,
+		Confirmation: confirmation,
+	}, nil
+}
+
+func (s *LocalSigner) SignPropose(req *ProposeRequest) ([]byte, error) {
+	sig, err := s.key.BriSignData(req.BlockHash.GetBytes())
+	if err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return sig, nil
+}
+
+func (s *LocalSigner) SignData(data []byte) ([]byte, error) {
+	sig, err := s.key.BriSignData(data)
+	if err != nil {
+		return nil, NewSignerError(SignError, err)
+	}
+	return sig, nil
+}
+
+// VoteConfirmationData - data signed by the validator to confirm a vote belongs to it
+func VoteConfirmationData(blockHash string, blsSig []byte, bridgeSig []byte) []byte {
+	data := []byte{}
+	data = append(data, blockHash...)
+	data = append(data, blsSig...)
+	data = append(data, bridgeSig...)
+	return common.HashB(data)
+}
@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
+)
+
+func newTestMiningKey(seed []byte) *signatureschemes.MiningKey {
+	miningKey := &signatureschemes.MiningKey{
+		PriKey: map[string][]byte{},
+		PubKey: map[string][]byte{},
+	}
+	blsPriKey, blsPubKey := blsmultisig.KeyGen(seed)
+	miningKey.PriKey[common.BlsConsensus] = blsmultisig.SKBytes(blsPriKey)
+	miningKey.PubKey[common.BlsConsensus] = blsmultisig.PKBytes(blsPubKey)
+	bridgePriKey, bridgePubKey := bridgesig.KeyGen(seed)
+	miningKey.PriKey[common.BridgeConsensus] = bridgesig.SKBytes(&bridgePriKey)
+	miningKey.PubKey[common.BridgeConsensus] = bridgesig.PKBytes(&bridgePubKey)
+	return miningKey
+}
+
+func startTestServer(t *testing.T, key *signatureschemes.MiningKey) (*RemoteClient, func()) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := signjournal.NewJournal(filepath.Join(dir, signjournal.DataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer([]*signatureschemes.MiningKey{key}, journal, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	socketPath := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(l)
+	client, err := DialRemote(&RemoteConfig{Address: unixAddressPrefix + socketPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, func() {
+		client.Close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRemoteSigner_SameAsLocalSigner(t *testing.T) {
+	key := newTestMiningKey([]byte("signer test seed"))
+	client, stop := startTestServer(t, key)
+	defer stop()
+
+	signers, err := client.GetSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 {
+		t.Fatalf("expect 1 remote signer, got %v", len(signers))
+	}
+	remote := signers[0]
+	local := NewLocalSigner(key)
+	if remote.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus) != local.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus) {
+		t.Fatal("remote signer public key mismatch")
+	}
+
+	req := VoteRequest{
+		ChainID:   0,
+		Height:    10,
+		TimeSlot:  100,
+		BlockHash: common.HashH([]byte("block")),
+		Committee: [][]byte{key.PubKey[common.BlsConsensus]},
+		SelfIdx:   0,
+		BridgeSig: true,
+	}
+	localReq, remoteReq := req, req
+	localSig, err := local.SignVote(&localReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteSig, err := remote.SignVote(&remoteReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := blsmultisig.Verify(remoteSig.BLS, req.BlockHash.GetBytes(), []int{0}, []blsmultisig.PublicKey{key.PubKey[common.BlsConsensus]})
+	if err != nil || !ok {
+		t.Errorf("invalid remote BLS signature %v", err)
+	}
+	confirmData := VoteConfirmationData(req.BlockHash.String(), remoteSig.BLS, remoteSig.BRI)
+	ok, err = bridgesig.Verify(key.PubKey[common.BridgeConsensus], confirmData, remoteSig.Confirmation)
+	if err != nil || !ok {
+		t.Errorf("invalid remote vote confirmation %v", err)
+	}
+	if string(localSig.BLS) != string(remoteSig.BLS) {
+		t.Error("remote and local BLS signature mismatch")
+	}
+}
+
+func TestRemoteSigner_SlashingProtection(t *testing.T) {
+	key := newTestMiningKey([]byte("signer slashing seed"))
+	client, stop := startTestServer(t, key)
+	defer stop()
+	signers, err := client.GetSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := signers[0]
+
+	propose := func(ts int64, block string) error {
+		_, err := remote.SignPropose(&ProposeRequest{ChainID: 1, Height: 5, TimeSlot: ts, BlockHash: common.HashH([]byte(block))})
+		return err
+	}
+	if err := propose(50, "blockA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := propose(50, "blockA"); err != nil {
+		t.Errorf("re-sign same proposal must be allowed, got %v", err)
+	}
+	if err := propose(50, "blockB"); err == nil {
+		t.Error("expect conflicting proposal to be rejected")
+	}
+	if err := propose(49, "blockC"); err == nil {
+		t.Error("expect proposal for older timeslot to be rejected")
+	}
+	hash := common.HashH([]byte("blockB"))
+	if _, err := remote.SignData(hash.GetBytes()); err == nil {
+		t.Error("expect signing raw hash to be rejected")
+	}
+	if _, err := remote.SignData([]byte("peer id to sign")); err != nil {
+		t.Errorf("sign data error %v", err)
+	}
+}
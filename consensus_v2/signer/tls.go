@@ -0,0 +1,48 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+func loadTLSConfig(certFile string, keyFile string, caFile string) (*tls.Config, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, NewSignerError(LoadCertificateError, err)
+	}
+	caPem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, nil, NewSignerError(LoadCertificateError, err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPem) {
+		return nil, nil, NewSignerError(LoadCertificateError, errors.New("no CA certificate found"))
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, caPool, nil
+}
+
+// LoadServerTLSConfig - tls config of signer daemon, only accept node which has certificate signed by CA
+func LoadServerTLSConfig(certFile string, keyFile string, caFile string) (*tls.Config, error) {
+	config, caPool, err := loadTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	config.ClientCAs = caPool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
+
+// LoadClientTLSConfig - tls config of node, only connect to signer daemon which has certificate signed by CA
+func LoadClientTLSConfig(certFile string, keyFile string, caFile string) (*tls.Config, error) {
+	config, caPool, err := loadTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	config.RootCAs = caPool
+	return config, nil
+}
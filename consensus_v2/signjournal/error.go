@@ -0,0 +1,42 @@
+package signjournal
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnExpectedError = iota
+	LoadJournalError
+	SaveJournalError
+	ConflictSignError
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	message string
+}{
+	UnExpectedError:   {-1000, "Unexpected error"},
+	LoadJournalError:  {-1001, "Load sign journal error"},
+	SaveJournalError:  {-1002, "Save sign journal error"},
+	ConflictSignError: {-1003, "Signing conflicts with sign journal"},
+}
+
+type SignJournalError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e SignJournalError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewSignJournalError(key int, err error) error {
+	return &SignJournalError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].message),
+	}
+}
@@ -0,0 +1,215 @@
+package signjournal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	ActionVote    = "vote"
+	ActionPropose = "propose"
+
+	version  = 1
+	DataFile = "signjournal.json"
+)
+
+// SignRecord is the last message a validator key signed on a chain for one action (vote or propose)
+type SignRecord struct {
+	ChainID   int    `json:"ChainID"`
+	Height    uint64 `json:"Height"`
+	TimeSlot  int64  `json:"TimeSlot"`
+	BlockHash string `json:"BlockHash"`
+}
+
+// data structure of journal which need to be saving in file
+type serializedJournal struct {
+	Version int                    `json:"Version"`
+	Records map[string]*SignRecord `json:"Records"` // recordKey -> last signed record
+}
+
+// Journal - durable record of the last signed vote/proposal of every (chain, validator key).
+// It must be consulted (and written) before any signature leaves the node,
+// so that a validator which restarts inside a timeslot cannot sign a conflicting message.
+type Journal struct {
+	mtx      sync.Mutex
+	filePath string
+	records  map[string]*SignRecord
+}
+
+// NewJournal - load the journal stored at filePath, or create an empty one if the file does not exist.
+// An empty filePath gives an in-memory journal (used by test and simulation)
+func NewJournal(filePath string) (*Journal, error) {
+	j := &Journal{
+		filePath: filePath,
+		records:  make(map[string]*SignRecord),
+	}
+	if filePath == "" {
+		return j, nil
+	}
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, NewSignJournalError(LoadJournalError, err)
+	}
+	defer f.Close()
+	records, err := decode(f)
+	if err != nil {
+		return nil, err
+	}
+	j.records = records
+	return j, nil
+}
+
+func recordKey(action string, chainID int, validator string) string {
+	return fmt.Sprintf("%s-%d-%s", action, chainID, validator)
+}
+
+// checkConflict - a new signature is allowed only if it is for a later timeslot than the last one,
+// or it is exactly the same message as the last one (re-broadcast)
+func checkConflict(last *SignRecord, record SignRecord) error {
+	if last == nil {
+		return nil
+	}
+	if record.TimeSlot < last.TimeSlot {
+		return fmt.Errorf("timeslot %v is older than last signed timeslot %v (height %v, block %v)", record.TimeSlot, last.TimeSlot, last.Height, last.BlockHash)
+	}
+	if record.TimeSlot == last.TimeSlot && (record.Height != last.Height || record.BlockHash != last.BlockHash) {
+		return fmt.Errorf("already signed block %v height %v in timeslot %v", last.BlockHash, last.Height, last.TimeSlot)
+	}
+	return nil
+}
+
+// Check - return error if signing record (for action by validator) conflicts with the journal
+func (j *Journal) Check(action string, validator string, record SignRecord) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	if err := checkConflict(j.records[recordKey(action, record.ChainID, validator)], record); err != nil {
+		return NewSignJournalError(ConflictSignError, err)
+	}
+	return nil
+}
+
+// CheckAndRecord - check the record against the journal, then persist it.
+// The caller must only release the signature when this function return nil
+func (j *Journal) CheckAndRecord(action string, validator string, record SignRecord) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	key := recordKey(action, record.ChainID, validator)
+	last := j.records[key]
+	if err := checkConflict(last, record); err != nil {
+		return NewSignJournalError(ConflictSignError, err)
+	}
+	if last != nil && *last == record {
+		return nil
+	}
+	newRecord := record
+	j.records[key] = &newRecord
+	if err := j.save(); err != nil {
+		if last == nil {
+			delete(j.records, key)
+		} else {
+			j.records[key] = last
+		}
+		return err
+	}
+	return nil
+}
+
+// GetLastRecord - get last signed record of validator on chain for action
+func (j *Journal) GetLastRecord(action string, chainID int, validator string) *SignRecord {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	if r, ok := j.records[recordKey(action, chainID, validator)]; ok {
+		record := *r
+		return &record
+	}
+	return nil
+}
+
+// Export - write all records of the journal in json format
+func (j *Journal) Export(w io.Writer) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	return encode(w, j.records)
+}
+
+// Import - merge records exported from another host, keeping the latest timeslot for every key
+func (j *Journal) Import(r io.Reader) error {
+	records, err := decode(r)
+	if err != nil {
+		return err
+	}
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	for key, record := range records {
+		if record == nil {
+			continue
+		}
+		if last, ok := j.records[key]; ok && last.TimeSlot >= record.TimeSlot {
+			continue
+		}
+		j.records[key] = record
+	}
+	return j.save()
+}
+
+// save - write journal to a temp file, sync and rename, so the journal on disk is never partially written
+func (j *Journal) save() error {
+	if j.filePath == "" {
+		return nil
+	}
+	f, err := ioutil.TempFile(filepath.Dir(j.filePath), filepath.Base(j.filePath)+".tmp")
+	if err != nil {
+		return NewSignJournalError(SaveJournalError, err)
+	}
+	tmpPath := f.Name()
+	if err := encode(f, j.records); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return NewSignJournalError(SaveJournalError, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return NewSignJournalError(SaveJournalError, err)
+	}
+	if err := os.Rename(tmpPath, j.filePath); err != nil {
+		os.Remove(tmpPath)
+		return NewSignJournalError(SaveJournalError, err)
+	}
+	return nil
+}
+
+func encode(w io.Writer, records map[string]*SignRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(serializedJournal{Version: version, Records: records}); err != nil {
+		return NewSignJournalError(SaveJournalError, err)
+	}
+	return nil
+}
+
+func decode(r io.Reader) (map[string]*SignRecord, error) {
+	var data serializedJournal
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, NewSignJournalError(LoadJournalError, err)
+	}
+	if data.Version != version {
+		return nil, NewSignJournalError(LoadJournalError, fmt.Errorf("unknown journal version %v", data.Version))
+	}
+	if data.Records == nil {
+		data.Records = make(map[string]*SignRecord)
+	}
+	return data.Records, nil
+}
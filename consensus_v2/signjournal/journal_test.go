@@ -0,0 +1,83 @@
+package signjournal
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal_CheckAndRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signjournal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, DataFile)
+
+	j, err := NewJournal(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := "validator1"
+	tests := []struct {
+		name    string
+		action  string
+		record  SignRecord
+		wantErr bool
+	}{
+		{"first vote", ActionVote, SignRecord{0, 10, 100, "hashA"}, false},
+		{"re-broadcast same vote", ActionVote, SignRecord{0, 10, 100, "hashA"}, false},
+		{"conflict vote same timeslot", ActionVote, SignRecord{0, 10, 100, "hashB"}, true},
+		{"vote older timeslot", ActionVote, SignRecord{0, 10, 99, "hashC"}, true},
+		{"vote newer timeslot same height", ActionVote, SignRecord{0, 10, 101, "hashD"}, false},
+		{"vote other chain", ActionVote, SignRecord{1, 10, 100, "hashB"}, false},
+		{"propose is journaled separately", ActionPropose, SignRecord{0, 10, 100, "hashB"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := j.CheckAndRecord(tt.action, validator, tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAndRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	//reload from disk as after a restart
+	reloaded, err := NewJournal(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.CheckAndRecord(ActionVote, validator, SignRecord{0, 10, 101, "hashE"}); err == nil {
+		t.Error("expect conflict after restart")
+	}
+	if r := reloaded.GetLastRecord(ActionVote, 0, validator); r == nil || r.BlockHash != "hashD" {
+		t.Errorf("unexpected last record %+v", r)
+	}
+}
+
+func TestJournal_ExportImport(t *testing.T) {
+	oldHost, _ := NewJournal("")
+	if err := oldHost.CheckAndRecord(ActionPropose, "validator1", SignRecord{0, 5, 50, "hashA"}); err != nil {
+		t.Fatal(err)
+	}
+	newHost, _ := NewJournal("")
+	if err := newHost.CheckAndRecord(ActionPropose, "validator1", SignRecord{0, 4, 40, "hashB"}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := oldHost.Export(buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := newHost.Import(buf); err != nil {
+		t.Fatal(err)
+	}
+	if r := newHost.GetLastRecord(ActionPropose, 0, "validator1"); r == nil || r.TimeSlot != 50 {
+		t.Errorf("import must keep latest record, got %+v", r)
+	}
+	if err := newHost.Check(ActionPropose, "validator1", SignRecord{0, 5, 50, "hashC"}); err == nil {
+		t.Error("expect conflict with imported record")
+	}
+}
@@ -1,8 +1,7 @@
-package main
+package simulation
 
 import (
 	"encoding/json"
-	"time"
 
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
@@ -11,21 +10,25 @@ import (
 )
 
 type Chain struct {
-	multiview *multiview.MultiView
-	chainID   int
-	chainName string
+	multiview    *multiview.MultiView
+	views        map[common.Hash]multiview.View //every inserted view, as the block database of a real chain
+	chainID      int
+	chainName    string
+	blockVersion int
 }
 
-func NewChain(chainID int, chainName string, committee []incognitokey.CommitteePublicKey) *Chain {
+func NewChain(chainID int, chainName string, committee []incognitokey.CommitteePublicKey, genesisTime int64, blockVersion int) *Chain {
 	c := new(Chain)
 	c.chainID = chainID
 	c.chainName = chainName
+	c.blockVersion = blockVersion
 	c.multiview = multiview.NewMultiView()
+	c.views = make(map[common.Hash]multiview.View)
 	state := &State{
-		NewBlock(1, 1, "Genesis", common.Hash{}),
+		NewBlock(blockVersion, 1, genesisTime, "Genesis", common.Hash{}),
 		committee,
 	}
-	c.multiview.AddView(state)
+	c.addView(state)
 	return c
 }
 
@@ -47,21 +50,33 @@ func (s *Chain) IsReady() bool {
 
 func (s *Chain) UnmarshalBlock(blockString []byte) (common.BlockInterface, error) {
 	blk := &blockchain.ShardBlock{}
-	json.Unmarshal(blockString, blk)
+	//fake block has no tx/committee root so its sanity check always fail, but the header is already decoded
+	if err := json.Unmarshal(blockString, blk); err != nil && blk.Header.Height == 0 {
+		return nil, err
+	}
 	return blk, nil
 }
 
 func (c *Chain) CreateNewBlock(version int, proposer string, round int, startTime int64) (common.BlockInterface, error) {
-	newBlock := NewBlock(c.GetBestView().GetHeight()+1, time.Now().Unix(), proposer, *c.GetBestView().GetHash())
+	newBlock := NewBlock(c.blockVersion, c.GetBestView().GetHeight()+1, startTime, proposer, *c.GetBestView().GetHash())
 	return newBlock, nil
 }
 
 func (c *Chain) CreateNewBlockFromOldBlock(oldBlock common.BlockInterface, proposer string, startTime int64) (common.BlockInterface, error) {
-	//TODO: must using the old block data, and timestamp
-	oldBlock.(*blockchain.ShardBlock).Header.Proposer = proposer
-	oldBlock.(*blockchain.ShardBlock).Header.ProposeTime = time.Now().Unix()
-	// newBlock := NewBlock(c.GetBestView().GetHeight()+1, oldBlock.GetProduceTime(), proposer, *c.GetBestView().GetHash())
-	return oldBlock, nil
+	//copy the old block, it is still referenced by the consensus memory
+	data, err := json.Marshal(oldBlock)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := c.UnmarshalBlock(data)
+	if err != nil {
+		return nil, err
+	}
+	newBlock := blk.(*blockchain.ShardBlock)
+	newBlock.Header.Proposer = proposer
+	newBlock.Header.ProposeTime = startTime
+	newBlock.ValidationData = ""
+	return newBlock, nil
 }
 
 func (s *Chain) InsertAndBroadcastBlock(block common.BlockInterface) error {
@@ -69,7 +84,7 @@ func (s *Chain) InsertAndBroadcastBlock(block common.BlockInterface) error {
 		block,
 		s.multiview.GetBestView().GetCommittee(),
 	}
-	s.multiview.AddView(state)
+	s.addView(state)
 	return nil
 }
 
@@ -84,3 +99,60 @@ func (s *Chain) GetShardID() int {
 func (c Chain) GetViewByHash(hash common.Hash) multiview.View {
 	return c.multiview.GetViewByHash(hash)
 }
+
+func (c *Chain) addView(view multiview.View) {
+	if c.multiview.AddView(view) {
+		c.views[*view.GetHash()] = view
+	}
+}
+
+//syncFrom add view of hash from peer chain, together with all of its ancestors this chain does not have
+func (c *Chain) syncFrom(peer *Chain, hash common.Hash) {
+	views := []multiview.View{}
+	for {
+		if _, ok := c.views[hash]; ok {
+			break
+		}
+		v, ok := peer.views[hash]
+		if !ok {
+			break
+		}
+		views = append(views, v)
+		hash = *v.GetPreviousHash()
+	}
+	for i := len(views) - 1; i >= 0; i-- {
+		c.addView(views[i])
+	}
+}
+
+// ViewCount - number of views from final view (included)
+func (c *Chain) ViewCount() int {
+	return len(c.multiview.GetAllViewsWithBFS())
+}
+
+// ForkDepth - length of the longest branch which is not on the path from final view to best view
+func (c *Chain) ForkDepth() int {
+	views := c.multiview.GetAllViewsWithBFS()
+	viewByHash := make(map[common.Hash]multiview.View)
+	for _, v := range views {
+		viewByHash[*v.GetHash()] = v
+	}
+	bestChain := make(map[common.Hash]bool)
+	for v := c.GetBestView(); v != nil; v = viewByHash[*v.GetPreviousHash()] {
+		bestChain[*v.GetHash()] = true
+		if v.GetHeight() <= c.GetFinalView().GetHeight() {
+			break
+		}
+	}
+	depth := 0
+	for _, v := range views {
+		d := 0
+		for cur := v; cur != nil && !bestChain[*cur.GetHash()]; cur = viewByHash[*cur.GetPreviousHash()] {
+			d++
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
@@ -0,0 +1,105 @@
+package simulation
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+type envelope struct {
+	deliverAt int64 // virtual unix time
+	seq       uint64
+	from      int
+	to        int
+	msg       *wire.MessageBFT
+}
+
+// Network - deterministic in-memory message queue between simulated nodes.
+// Messages sent at second T are delivered at T+1 (plus injected delay), in the order they are sent
+type Network struct {
+	sim   *Simulation
+	seq   uint64
+	queue []*envelope
+}
+
+func newNetwork(sim *Simulation) *Network {
+	return &Network{sim: sim}
+}
+
+// linked - whether there is no partition between node a and b in timeslot ts
+func (n *Network) linked(ts int64, a int, b int) bool {
+	for i := range n.sim.scenario.Faults {
+		f := &n.sim.scenario.Faults[i]
+		if f.Type == FaultPartition && f.activeAt(ts) && !f.connected(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Network) send(from int, msg *wire.MessageBFT) {
+	ts := n.sim.CurrentTimeSlot()
+	now := n.sim.clock.Now().Unix()
+	for to := range n.sim.nodes {
+		if to == from || !n.linked(ts, from, to) {
+			continue
+		}
+		drop := false
+		delay := int64(0)
+		copies := 1
+		for i := range n.sim.scenario.Faults {
+			f := &n.sim.scenario.Faults[i]
+			if !f.match(ts, msg.Type, from, to) {
+				continue
+			}
+			switch f.Type {
+			case FaultDrop:
+				drop = true
+			case FaultDelay:
+				delay += f.Delay
+			case FaultDuplicate:
+				copies += f.Copies
+			}
+		}
+		if drop {
+			continue
+		}
+		for i := 0; i < copies; i++ {
+			n.seq++
+			n.queue = append(n.queue, &envelope{
+				deliverAt: now + 1 + delay,
+				seq:       n.seq,
+				from:      from,
+				to:        to,
+				msg:       msg,
+			})
+		}
+	}
+}
+
+// deliver - hand every message due at now to its receiver, message to a crashed node is lost
+func (n *Network) deliver(now int64) {
+	due := []*envelope{}
+	pending := []*envelope{}
+	for _, e := range n.queue {
+		if e.deliverAt <= now {
+			due = append(due, e)
+		} else {
+			pending = append(pending, e)
+		}
+	}
+	n.queue = pending
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].deliverAt != due[j].deliverAt {
+			return due[i].deliverAt < due[j].deliverAt
+		}
+		return due[i].seq < due[j].seq
+	})
+	for _, e := range due {
+		node := n.sim.nodes[e.to]
+		if !node.running {
+			continue
+		}
+		node.consensus.HandleBFTMsg(e.msg)
+	}
+}
@@ -0,0 +1,184 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	FaultDrop      = "drop"      // drop matching messages
+	FaultDelay     = "delay"     // deliver matching messages Delay seconds later
+	FaultDuplicate = "duplicate" // deliver Copies extra copies of matching messages
+	FaultPartition = "partition" // only nodes in the same group can talk to each other
+	FaultCrash     = "crash"     // stop Nodes at the beginning of FromTimeSlot
+	FaultRestart   = "restart"   // restart Nodes at the beginning of FromTimeSlot (keep chain and sign journal)
+
+	MsgPropose = "propose"
+	MsgVote    = "vote"
+
+	defaultTimeSlotDuration = 10
+	defaultBlockVersion     = 2
+)
+
+// Scenario - describe committee, faults and expected result of one simulation.
+// Timeslots are numbered from 1, the proposer of timeslot t is node (t-1) % CommitteeSize.
+// Genesis block is in timeslot 0
+type Scenario struct {
+	Name             string   `json:"Name"`
+	CommitteeSize    int      `json:"CommitteeSize"`
+	TimeSlots        int64    `json:"TimeSlots"`        // number of timeslots to run
+	TimeSlotDuration uint64   `json:"TimeSlotDuration"` // in seconds, default 10
+	BlockVersion     int      `json:"BlockVersion"`     // 1 finalizes the parent of the best view, 2 needs sequential timeslots, default 2
+	Faults           []Fault  `json:"Faults"`
+	Expects          []Expect `json:"Expects"`
+}
+
+// Fault - fault injected in timeslots [FromTimeSlot, ToTimeSlot].
+// Empty From/To/MsgType match every sender/receiver/message
+type Fault struct {
+	Type         string  `json:"Type"`
+	FromTimeSlot int64   `json:"FromTimeSlot"`
+	ToTimeSlot   int64   `json:"ToTimeSlot"` // 0 means FromTimeSlot
+	MsgType      string  `json:"MsgType"`
+	From         []int   `json:"From"`
+	To           []int   `json:"To"`
+	Delay        int64   `json:"Delay"`  // for delay, in seconds
+	Copies       int     `json:"Copies"` // for duplicate
+	Groups       [][]int `json:"Groups"` // for partition, node not in any group is isolated
+	Nodes        []int   `json:"Nodes"`  // for crash and restart
+}
+
+// Expect - assertion on chain state of Nodes at the end of TimeSlot (0 means end of simulation).
+// Zero values are not checked
+type Expect struct {
+	TimeSlot       int64  `json:"TimeSlot"`
+	Nodes          []int  `json:"Nodes"` // empty means every running node
+	BestHeight     uint64 `json:"BestHeight"`
+	BestTimeSlot   int64  `json:"BestTimeSlot"`
+	FinalHeight    uint64 `json:"FinalHeight"`
+	FinalTimeSlot  int64  `json:"FinalTimeSlot"`
+	MinBestHeight  uint64 `json:"MinBestHeight"`  // liveness
+	MinFinalHeight uint64 `json:"MinFinalHeight"` // liveness
+	ViewCount      int    `json:"ViewCount"`
+	MaxForkDepth   *int   `json:"MaxForkDepth"`
+}
+
+// LoadScenario - read scenario from json file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scenario := new(Scenario)
+	if err := json.Unmarshal(data, scenario); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	if scenario.Name == "" {
+		scenario.Name = path
+	}
+	return scenario, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.CommitteeSize <= 0 {
+		return fmt.Errorf("committee size must be positive")
+	}
+	if s.TimeSlots <= 0 {
+		return fmt.Errorf("number of timeslots must be positive")
+	}
+	if s.TimeSlotDuration == 1 {
+		return fmt.Errorf("timeslot duration must be at least 2 seconds")
+	}
+	if s.BlockVersion < 0 || s.BlockVersion > 2 {
+		return fmt.Errorf("unknown block version %v", s.BlockVersion)
+	}
+	checkNodes := func(nodes []int) error {
+		for _, n := range nodes {
+			if n < 0 || n >= s.CommitteeSize {
+				return fmt.Errorf("node %v out of range", n)
+			}
+		}
+		return nil
+	}
+	for i, f := range s.Faults {
+		switch f.Type {
+		case FaultDrop, FaultDelay, FaultDuplicate, FaultPartition, FaultCrash, FaultRestart:
+		default:
+			return fmt.Errorf("fault %v: unknown type %v", i, f.Type)
+		}
+		switch f.MsgType {
+		case "", MsgPropose, MsgVote:
+		default:
+			return fmt.Errorf("fault %v: unknown message type %v", i, f.MsgType)
+		}
+		if f.FromTimeSlot <= 0 || (f.ToTimeSlot != 0 && f.ToTimeSlot < f.FromTimeSlot) {
+			return fmt.Errorf("fault %v: invalid timeslot range [%v, %v]", i, f.FromTimeSlot, f.ToTimeSlot)
+		}
+		if f.Type == FaultDelay && f.Delay <= 0 {
+			return fmt.Errorf("fault %v: delay must be positive", i)
+		}
+		if f.Type == FaultDuplicate && f.Copies <= 0 {
+			return fmt.Errorf("fault %v: copies must be positive", i)
+		}
+		if (f.Type == FaultCrash || f.Type == FaultRestart) && len(f.Nodes) == 0 {
+			return fmt.Errorf("fault %v: no node to %v", i, f.Type)
+		}
+		nodes := append(append(append([]int{}, f.From...), f.To...), f.Nodes...)
+		for _, g := range f.Groups {
+			nodes = append(nodes, g...)
+		}
+		if err := checkNodes(nodes); err != nil {
+			return fmt.Errorf("fault %v: %v", i, err)
+		}
+	}
+	for i, e := range s.Expects {
+		if e.TimeSlot < 0 || e.TimeSlot > s.TimeSlots {
+			return fmt.Errorf("expect %v: timeslot %v out of range", i, e.TimeSlot)
+		}
+		if err := checkNodes(e.Nodes); err != nil {
+			return fmt.Errorf("expect %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (f *Fault) activeAt(ts int64) bool {
+	to := f.ToTimeSlot
+	if to == 0 {
+		to = f.FromTimeSlot
+	}
+	return ts >= f.FromTimeSlot && ts <= to
+}
+
+func (f *Fault) match(ts int64, msgType string, from int, to int) bool {
+	if !f.activeAt(ts) {
+		return false
+	}
+	if f.MsgType != "" && f.MsgType != msgType {
+		return false
+	}
+	return containsOrEmpty(f.From, from) && containsOrEmpty(f.To, to)
+}
+
+// connected - whether node a and b are in the same group of this partition
+func (f *Fault) connected(a int, b int) bool {
+	for _, g := range f.Groups {
+		if containsOrEmpty(g, a) && containsOrEmpty(g, b) && len(g) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrEmpty(list []int, n int) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, i := range list {
+		if i == n {
+			return true
+		}
+	}
+	return false
+}
@@ -1,56 +1,238 @@
-package main
+package simulation
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+const (
+	genesisUnixTime  = 1600000000
+	cleanMemInterval = 5 * 60 //same as the consensus actor loop, in seconds
 )
 
+// Clock - virtual clock of the simulation, advanced one second at a time
+type Clock struct {
+	now int64
+}
+
+func (c *Clock) Now() time.Time {
+	return time.Unix(c.now, 0)
+}
+
+// Simulation - run N in-process BLSBFT_V2 actors over fake chains with a virtual clock.
+// Every second of virtual time, due messages are delivered then every running node ticks in index order,
+// so a scenario always gives the same result.
+// Run sets common.TIMESLOT until it returns, so simulations must not run in parallel
 type Simulation struct {
-	nodeList      []*Node
-	fd            os.File
-	maxTimeSlot   uint64
-	startTimeSlot uint64
-	scenario      Scenario
-	expected      Expected
+	scenario      *Scenario
+	clock         *Clock
+	network       *Network
+	nodes         []*Node
+	nodeByPeerID  map[string]*Node
+	logBackend    *common.Backend
+	startTimeSlot int64
+	finalized     map[uint64]common.Hash //height -> finalized block hash, across all nodes
+}
+
+// NewSimulation - create nodes of scenario, node logs are written to logWriter (nil to discard)
+func NewSimulation(scenario *Scenario, logWriter io.Writer) (*Simulation, error) {
+	if err := scenario.validate(); err != nil {
+		return nil, fmt.Errorf("scenario %v: %v", scenario.Name, err)
+	}
+	if scenario.TimeSlotDuration == 0 {
+		scenario.TimeSlotDuration = defaultTimeSlotDuration
+	}
+	if scenario.BlockVersion == 0 {
+		scenario.BlockVersion = defaultBlockVersion
+	}
+	if logWriter == nil {
+		logWriter = ioutil.Discard
+	}
+
+	n := int64(scenario.CommitteeSize)
+	duration := int64(scenario.TimeSlotDuration)
+	s := &Simulation{
+		scenario:     scenario,
+		clock:        new(Clock),
+		nodeByPeerID: make(map[string]*Node),
+		logBackend:   common.NewBackend(logWriter),
+		finalized:    make(map[uint64]common.Hash),
+	}
+	//proposer of timeslot t is node (t-1) % n
+	s.startTimeSlot = (genesisUnixTime/duration/n + 1) * n
+	s.clock.now = (s.startTimeSlot - 1) * duration
+	s.network = newNetwork(s)
+
+	committee := []incognitokey.CommitteePublicKey{}
+	keys := []*signatureschemes.MiningKey{}
+	for i := 0; i < scenario.CommitteeSize; i++ {
+		key := NewMiningKey(i)
+		keys = append(keys, key)
+		committee = append(committee, *key.GetPublicKey())
+	}
+	for i := 0; i < scenario.CommitteeSize; i++ {
+		node := newNode(s, i, committee, keys[i], s.clock.now)
+		s.nodes = append(s.nodes, node)
+		s.nodeByPeerID[node.GetSelfPeerID().String()] = node
+	}
+	return s, nil
 }
 
-type Scenario struct {
-	proposeComm map[uint64][]int // timeSlot -> sender -> receiver
-	voteComm    map[uint64]map[string][]int
-	sync        map[string][]string
+// CurrentTimeSlot - timeslot of virtual clock, relative to the start of simulation
+func (s *Simulation) CurrentTimeSlot() int64 {
+	return s.timeSlotOf(s.clock.now) - s.startTimeSlot + 1
 }
 
-type Expected struct {
+// RelativeTimeSlot - timeslot of view, relative to the start of simulation (genesis is 0)
+func (s *Simulation) RelativeTimeSlot(view multiview.View) int64 {
+	return s.timeSlotOf(view.GetBlock().GetProposeTime()) - s.startTimeSlot + 1
 }
 
-var simulation *Simulation
+func (s *Simulation) timeSlotOf(unixTime int64) int64 {
+	return unixTime / int64(s.scenario.TimeSlotDuration)
+}
 
-func InitSimulation(scenario []map[string]Scenario, expected []map[string]Expected) *Simulation {
-	return simulation
+func (s *Simulation) Nodes() []*Node {
+	return s.nodes
 }
 
-func GetSimulation() *Simulation {
-	if simulation == nil {
-		simulation = new(Simulation)
-		simulation.scenario = Scenario{
-			proposeComm: make(map[uint64][]int),
-			voteComm:    make(map[uint64]map[string][]int),
-			sync:        make(map[string][]string),
+// Run - run all timeslots of scenario, return the first violated expectation or safety error
+func (s *Simulation) Run() error {
+	timeSlotDuration := common.TIMESLOT
+	common.TIMESLOT = s.scenario.TimeSlotDuration
+	defer func() {
+		common.TIMESLOT = timeSlotDuration
+	}()
+	for _, node := range s.nodes {
+		node.start()
+	}
+	defer func() {
+		for _, node := range s.nodes {
+			node.stop()
+		}
+	}()
+
+	duration := int64(s.scenario.TimeSlotDuration)
+	for ts := int64(1); ts <= s.scenario.TimeSlots; ts++ {
+		s.applyNodeFaults(ts)
+		for sec := int64(0); sec < duration; sec++ {
+			s.clock.now = (s.startTimeSlot+ts-1)*duration + sec
+			s.network.deliver(s.clock.now)
+			for _, node := range s.nodes {
+				if node.running {
+					node.consensus.HandleTick()
+				}
+			}
+			if s.clock.now%cleanMemInterval == 0 {
+				for _, node := range s.nodes {
+					if node.running {
+						node.consensus.HandleCleanMem()
+					}
+				}
+			}
+			if err := s.checkSafety(); err != nil {
+				return fmt.Errorf("scenario %v, timeslot %v: %v", s.scenario.Name, ts, err)
+			}
 		}
+		if err := s.checkExpects(ts); err != nil {
+			return fmt.Errorf("scenario %v, timeslot %v: %v", s.scenario.Name, ts, err)
+		}
+	}
+	if err := s.checkExpects(0); err != nil {
+		return fmt.Errorf("scenario %v, end: %v", s.scenario.Name, err)
 	}
-	return simulation
+	return nil
 }
 
-func (s *Simulation) setMaxTimeSlot(max uint64) {
-	fmt.Println("Set Max Time Slot", max)
-	s.maxTimeSlot = max
+func (s *Simulation) applyNodeFaults(ts int64) {
+	for _, f := range s.scenario.Faults {
+		if f.FromTimeSlot != ts {
+			continue
+		}
+		for _, i := range f.Nodes {
+			switch f.Type {
+			case FaultCrash:
+				if s.nodes[i].running {
+					s.nodes[i].stop()
+				}
+			case FaultRestart:
+				if !s.nodes[i].running {
+					s.nodes[i].start()
+				}
+			}
+		}
+	}
 }
-func (s *Simulation) setStartTimeSlot(start uint64) {
-	fmt.Println("Set Start Time Slot", start)
-	s.startTimeSlot = start
+
+// checkSafety - two nodes must never finalize different blocks at the same height
+func (s *Simulation) checkSafety() error {
+	for _, node := range s.nodes {
+		finalView := node.chain.GetFinalView()
+		hash, ok := s.finalized[finalView.GetHeight()]
+		if !ok {
+			s.finalized[finalView.GetHeight()] = *finalView.GetHash()
+			continue
+		}
+		if !hash.IsEqual(finalView.GetHash()) {
+			return fmt.Errorf("safety violation: node %v finalized %v at height %v, but %v was finalized before", node.index, finalView.GetHash().String(), finalView.GetHeight(), hash.String())
+		}
+	}
+	return nil
 }
 
-func (s *Simulation) setLogfile(fd os.File) {
-	s.fd = fd
+func (s *Simulation) checkExpects(ts int64) error {
+	for _, e := range s.scenario.Expects {
+		if e.TimeSlot != ts {
+			continue
+		}
+		for _, node := range s.nodes {
+			if len(e.Nodes) == 0 && !node.running {
+				continue
+			}
+			if !containsOrEmpty(e.Nodes, node.index) {
+				continue
+			}
+			if err := s.checkExpect(e, node); err != nil {
+				return fmt.Errorf("node %v: %v", node.index, err)
+			}
+		}
+	}
+	return nil
+}
 
+func (s *Simulation) checkExpect(e Expect, node *Node) error {
+	bestView := node.chain.GetBestView()
+	finalView := node.chain.GetFinalView()
+	if e.BestHeight != 0 && bestView.GetHeight() != e.BestHeight {
+		return fmt.Errorf("best height %v, expect %v", bestView.GetHeight(), e.BestHeight)
+	}
+	if e.BestTimeSlot != 0 && s.RelativeTimeSlot(bestView) != e.BestTimeSlot {
+		return fmt.Errorf("best view timeslot %v, expect %v", s.RelativeTimeSlot(bestView), e.BestTimeSlot)
+	}
+	if e.FinalHeight != 0 && finalView.GetHeight() != e.FinalHeight {
+		return fmt.Errorf("final height %v, expect %v", finalView.GetHeight(), e.FinalHeight)
+	}
+	if e.FinalTimeSlot != 0 && s.RelativeTimeSlot(finalView) != e.FinalTimeSlot {
+		return fmt.Errorf("final view timeslot %v, expect %v", s.RelativeTimeSlot(finalView), e.FinalTimeSlot)
+	}
+	if bestView.GetHeight() < e.MinBestHeight {
+		return fmt.Errorf("best height %v, expect at least %v", bestView.GetHeight(), e.MinBestHeight)
+	}
+	if finalView.GetHeight() < e.MinFinalHeight {
+		return fmt.Errorf("final height %v, expect at least %v", finalView.GetHeight(), e.MinFinalHeight)
+	}
+	if e.ViewCount != 0 && node.chain.ViewCount() != e.ViewCount {
+		return fmt.Errorf("view count %v, expect %v", node.chain.ViewCount(), e.ViewCount)
+	}
+	if e.MaxForkDepth != nil && node.chain.ForkDepth() > *e.MaxForkDepth {
+		return fmt.Errorf("fork depth %v, expect at most %v", node.chain.ForkDepth(), *e.MaxForkDepth)
+	}
+	return nil
 }
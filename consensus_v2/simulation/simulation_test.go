@@ -0,0 +1,87 @@
+package simulation
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no scenario in testdata")
+	}
+	for _, file := range files {
+		scenario, err := LoadScenario(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(scenario.Name, func(t *testing.T) {
+			var logWriter io.Writer
+			if os.Getenv("SIMULATION_LOG") != "" {
+				logWriter = os.Stdout
+			}
+			sim, err := NewSimulation(scenario, logWriter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sim.Run(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	scenario, err := LoadScenario(filepath.Join("testdata", "delay_vote.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bestViews := [][]string{}
+	for i := 0; i < 2; i++ {
+		sim, err := NewSimulation(scenario, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Run(); err != nil {
+			t.Fatal(err)
+		}
+		hashes := []string{}
+		for _, node := range sim.Nodes() {
+			hashes = append(hashes, node.Chain().GetBestView().GetHash().String())
+		}
+		bestViews = append(bestViews, hashes)
+	}
+	for i := range bestViews[0] {
+		if bestViews[0][i] != bestViews[1][i] {
+			t.Fatalf("node %v: best view %v in first run, %v in second run", i, bestViews[0][i], bestViews[1][i])
+		}
+	}
+}
+
+func TestRunRestoresTimeSlot(t *testing.T) {
+	scenario, err := LoadScenario(filepath.Join("testdata", "normal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scenario.TimeSlots = 2
+	scenario.Expects = nil
+	scenario.TimeSlotDuration = 5
+	timeSlotDuration := common.TIMESLOT
+	sim, err := NewSimulation(scenario, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if common.TIMESLOT != timeSlotDuration {
+		t.Fatalf("common.TIMESLOT is %v after the simulation, expect %v", common.TIMESLOT, timeSlotDuration)
+	}
+}
@@ -1,10 +1,14 @@
-package main
+package simulation
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
 )
 
 func failOnError(err error) {
@@ -17,10 +21,10 @@ func NewEmptyBlock() common.BlockInterface {
 	return &blockchain.ShardBlock{}
 }
 
-func NewBlock(height uint64, time int64, producer string, prev common.Hash) common.BlockInterface {
+func NewBlock(version int, height uint64, time int64, producer string, prev common.Hash) common.BlockInterface {
 	return &blockchain.ShardBlock{
 		Header: blockchain.ShardHeader{
-			Version:           1,
+			Version:           version,
 			Height:            height,
 			Round:             1,
 			Epoch:             1,
@@ -34,6 +38,22 @@ func NewBlock(height uint64, time int64, producer string, prev common.Hash) comm
 	}
 }
 
+// NewMiningKey - deterministic mining key of simulated node index
+func NewMiningKey(index int) *signatureschemes.MiningKey {
+	seed := common.HashB([]byte(fmt.Sprintf("simulation-node-%d", index)))
+	miningKey := &signatureschemes.MiningKey{
+		PriKey: map[string][]byte{},
+		PubKey: map[string][]byte{},
+	}
+	blsPriKey, blsPubKey := blsmultisig.KeyGen(seed)
+	miningKey.PriKey[common.BlsConsensus] = blsmultisig.SKBytes(blsPriKey)
+	miningKey.PubKey[common.BlsConsensus] = blsmultisig.PKBytes(blsPubKey)
+	bridgePriKey, bridgePubKey := bridgesig.KeyGen(seed)
+	miningKey.PriKey[common.BridgeConsensus] = bridgesig.SKBytes(&bridgePriKey)
+	miningKey.PubKey[common.BridgeConsensus] = bridgesig.PKBytes(&bridgePubKey)
+	return miningKey
+}
+
 func GetIndexOfBytes(b []byte, arr [][]byte) int {
 	for i, item := range arr {
 		if bytes.Equal(b, item) {
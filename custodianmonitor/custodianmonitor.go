@@ -0,0 +1,196 @@
+package custodianmonitor
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/pubsub"
+)
+
+// ChainReader - portal states and params the monitor reads
+type ChainReader interface {
+	GetBeaconFinalHeight() uint64
+	GetPortalStateByHeight(beaconHeight uint64) (*blockchain.CurrentPortalState, error)
+	GetPortalParams(beaconHeight uint64) blockchain.PortalParams
+}
+
+// Alert - health level of a custodian for a portal token crossed TP130 or TP120, in either direction
+type Alert struct {
+	IncognitoAddress string
+	TokenID          string
+	BeaconHeight     uint64
+	PreviousLevel    string
+	Level            string
+	Ratio            uint64
+}
+
+// Monitor - collateral health of every custodian, re-evaluated after each beacon block.
+// Threshold crossings are published to pubsub.CustodianHealthTopic
+type Monitor struct {
+	mtx           sync.RWMutex
+	chain         ChainReader
+	pubSubManager *pubsub.PubSubManager
+	beaconHeight  uint64
+	healths       map[string]*blockchain.CustodianHealth // key: custodian incognito address
+	subID         uint
+	subscribed    bool
+	quit          chan struct{}
+}
+
+func NewMonitor(chain ChainReader, pubSubManager *pubsub.PubSubManager) *Monitor {
+	return &Monitor{
+		chain:         chain,
+		pubSubManager: pubSubManager,
+		healths:       map[string]*blockchain.CustodianHealth{},
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start - evaluate custodians at the final beacon height, then after every new beacon block
+func (m *Monitor) Start() {
+	var newBlockCh chan *pubsub.Message
+	if m.pubSubManager != nil {
+		subID, subChan, err := m.pubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+		if err != nil {
+			Logger.log.Error(err)
+		} else {
+			m.subID = subID
+			m.subscribed = true
+			newBlockCh = subChan
+		}
+	}
+	go func() {
+		if _, err := m.Evaluate(m.chain.GetBeaconFinalHeight()); err != nil {
+			Logger.log.Error(err)
+		}
+		for {
+			select {
+			case <-m.quit:
+				return
+			case msg := <-newBlockCh:
+				block, ok := msg.Value.(*blockchain.BeaconBlock)
+				if !ok {
+					continue
+				}
+				if _, err := m.Evaluate(block.Header.Height); err != nil {
+					Logger.log.Error(err)
+				}
+			}
+		}
+	}()
+}
+
+func (m *Monitor) Stop() {
+	if m.subscribed {
+		m.pubSubManager.Unsubscribe(pubsub.NewBeaconBlockTopic, m.subID)
+		m.subscribed = false
+	}
+	close(m.quit)
+}
+
+// Evaluate - compute the health of every custodian with the portal state after the beacon block at beaconHeight
+// and the params liquidation of the next block uses, returns and publishes threshold crossings since the last evaluation
+func (m *Monitor) Evaluate(beaconHeight uint64) ([]Alert, error) {
+	portalState, err := m.chain.GetPortalStateByHeight(beaconHeight)
+	if err != nil {
+		return nil, NewCustodianMonitorError(LoadPortalStateError, err)
+	}
+	portalParams := m.chain.GetPortalParams(beaconHeight + 1)
+
+	healths := map[string]*blockchain.CustodianHealth{}
+	for _, custodianState := range portalState.CustodianPoolState {
+		health, err := blockchain.CalCustodianHealth(portalState, custodianState, beaconHeight, portalParams)
+		if err != nil {
+			Logger.log.Errorf("Calculate health of custodian %v error: %v", custodianState.GetIncognitoAddress(), err)
+			continue
+		}
+		healths[health.IncognitoAddress] = health
+	}
+
+	m.mtx.Lock()
+	// blocks of a fork can be inserted after a higher one
+	if beaconHeight < m.beaconHeight {
+		m.mtx.Unlock()
+		return nil, nil
+	}
+	alerts := diffCustodianHealths(m.healths, healths, beaconHeight)
+	m.healths = healths
+	m.beaconHeight = beaconHeight
+	m.mtx.Unlock()
+
+	if m.pubSubManager != nil {
+		for _, alert := range alerts {
+			m.pubSubManager.PublishMessage(pubsub.NewMessage(pubsub.CustodianHealthTopic, alert))
+		}
+	}
+	return alerts, nil
+}
+
+// diffCustodianHealths - level changes per custodian and token, a token missing from a health is healthy
+func diffCustodianHealths(previous map[string]*blockchain.CustodianHealth, current map[string]*blockchain.CustodianHealth, beaconHeight uint64) []Alert {
+	levelOf := func(healths map[string]*blockchain.CustodianHealth, address string, tokenID string) (string, uint64) {
+		health, ok := healths[address]
+		if !ok {
+			return blockchain.CustodianHealthLevelHealthy, 0
+		}
+		tokenHealth, ok := health.Tokens[tokenID]
+		if !ok {
+			return blockchain.CustodianHealthLevelHealthy, 0
+		}
+		return tokenHealth.Level, tokenHealth.Ratio
+	}
+
+	keys := map[string]map[string]bool{}
+	for _, healths := range []map[string]*blockchain.CustodianHealth{previous, current} {
+		for address, health := range healths {
+			if keys[address] == nil {
+				keys[address] = map[string]bool{}
+			}
+			for tokenID := range health.Tokens {
+				keys[address][tokenID] = true
+			}
+		}
+	}
+
+	alerts := []Alert{}
+	for address, tokenIDs := range keys {
+		for tokenID := range tokenIDs {
+			previousLevel, _ := levelOf(previous, address, tokenID)
+			level, ratio := levelOf(current, address, tokenID)
+			if previousLevel == level {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				IncognitoAddress: address,
+				TokenID:          tokenID,
+				BeaconHeight:     beaconHeight,
+				PreviousLevel:    previousLevel,
+				Level:            level,
+				Ratio:            ratio,
+			})
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].IncognitoAddress != alerts[j].IncognitoAddress {
+			return alerts[i].IncognitoAddress < alerts[j].IncognitoAddress
+		}
+		return alerts[i].TokenID < alerts[j].TokenID
+	})
+	return alerts
+}
+
+// GetCustodianHealth - health of the custodian at the last evaluated beacon height
+func (m *Monitor) GetCustodianHealth(incAddress string) (*blockchain.CustodianHealth, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	if m.beaconHeight == 0 {
+		return nil, NewCustodianMonitorError(NotEvaluatedError, errors.New("no beacon block evaluated yet"))
+	}
+	health, ok := m.healths[incAddress]
+	if !ok {
+		return nil, NewCustodianMonitorError(CustodianNotFoundError, errors.New(incAddress))
+	}
+	return health, nil
+}
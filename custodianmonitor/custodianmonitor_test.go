@@ -0,0 +1,113 @@
+package custodianmonitor
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCustodian1 = "custodian1"
+	testCustodian2 = "custodian2"
+)
+
+// fakeChain - portal states after beacon blocks
+type fakeChain struct {
+	states map[uint64]*blockchain.CurrentPortalState
+	final  uint64
+}
+
+func (c *fakeChain) GetBeaconFinalHeight() uint64 { return c.final }
+
+func (c *fakeChain) GetPortalStateByHeight(beaconHeight uint64) (*blockchain.CurrentPortalState, error) {
+	return c.states[beaconHeight], nil
+}
+
+func (c *fakeChain) GetPortalParams(beaconHeight uint64) blockchain.PortalParams {
+	return blockchain.PortalParams{TP120: 120, TP130: 130}
+}
+
+func newTestCustodian(incAddress string, holdBNB uint64, lockedPRV uint64) *statedb.CustodianState {
+	return statedb.NewCustodianStateWithValue(
+		incAddress, lockedPRV, 0,
+		map[string]uint64{common.PortalBNBIDStr: holdBNB},
+		map[string]uint64{common.PortalBNBIDStr: lockedPRV},
+		map[string]string{}, map[string]uint64{}, map[string]uint64{}, map[string]uint64{}, map[string]map[string]uint64{},
+	)
+}
+
+// newTestPortalState - custodians hold 1 BNB each against 30 PRV, 1 PRV is 1 USDT
+func newTestPortalState(bnbRate uint64, custodians ...string) *blockchain.CurrentPortalState {
+	state := &blockchain.CurrentPortalState{
+		CustodianPoolState: map[string]*statedb.CustodianState{},
+		FinalExchangeRatesState: statedb.NewFinalExchangeRatesStateWithValue(map[string]statedb.FinalExchangeRatesDetail{
+			common.PRVIDStr:       {Amount: 1000000},
+			common.PortalBNBIDStr: {Amount: bnbRate},
+		}),
+	}
+	for _, incAddress := range custodians {
+		state.CustodianPoolState[incAddress] = newTestCustodian(incAddress, 1e9, 30e9)
+	}
+	return state
+}
+
+func TestEvaluate(t *testing.T) {
+	chain := &fakeChain{
+		states: map[uint64]*blockchain.CurrentPortalState{
+			10: newTestPortalState(20000000, testCustodian1, testCustodian2),
+			11: newTestPortalState(24000000, testCustodian1, testCustodian2),
+			12: newTestPortalState(26000000, testCustodian1),
+			13: newTestPortalState(20000000, testCustodian1),
+		},
+		final: 10,
+	}
+	m := NewMonitor(chain, nil)
+
+	_, err := m.GetCustodianHealth(testCustodian1)
+	assert.NotNil(t, err)
+
+	alerts, err := m.Evaluate(10)
+	assert.Nil(t, err)
+	assert.Len(t, alerts, 0)
+	health, err := m.GetCustodianHealth(testCustodian1)
+	assert.Nil(t, err)
+	assert.Equal(t, blockchain.CustodianHealthLevelHealthy, health.Level)
+	assert.Equal(t, uint64(150), health.Tokens[common.PortalBNBIDStr].Ratio)
+	assert.InDelta(t, 25, health.Tokens[common.PortalBNBIDStr].PubTokenRateRiseToLiquidation, 0.001)
+	assert.InDelta(t, 20, health.Tokens[common.PortalBNBIDStr].CollateralRateDropToLiquidation, 0.001)
+
+	// both custodians go down to TP130
+	alerts, err = m.Evaluate(11)
+	assert.Nil(t, err)
+	assert.Equal(t, []Alert{
+		{testCustodian1, common.PortalBNBIDStr, 11, blockchain.CustodianHealthLevelHealthy, blockchain.CustodianHealthLevelTP130, 125},
+		{testCustodian2, common.PortalBNBIDStr, 11, blockchain.CustodianHealthLevelHealthy, blockchain.CustodianHealthLevelTP130, 125},
+	}, alerts)
+
+	// custodian 2 left the pool, it is healthy again
+	alerts, err = m.Evaluate(12)
+	assert.Nil(t, err)
+	assert.Equal(t, []Alert{
+		{testCustodian1, common.PortalBNBIDStr, 12, blockchain.CustodianHealthLevelTP130, blockchain.CustodianHealthLevelTP120, 115},
+		{testCustodian2, common.PortalBNBIDStr, 12, blockchain.CustodianHealthLevelTP130, blockchain.CustodianHealthLevelHealthy, 0},
+	}, alerts)
+	_, err = m.GetCustodianHealth(testCustodian2)
+	assert.NotNil(t, err)
+
+	// an older block does not roll the monitor back
+	alerts, err = m.Evaluate(11)
+	assert.Nil(t, err)
+	assert.Len(t, alerts, 0)
+	health, err = m.GetCustodianHealth(testCustodian1)
+	assert.Nil(t, err)
+	assert.Equal(t, blockchain.CustodianHealthLevelTP120, health.Level)
+
+	alerts, err = m.Evaluate(13)
+	assert.Nil(t, err)
+	assert.Equal(t, []Alert{
+		{testCustodian1, common.PortalBNBIDStr, 13, blockchain.CustodianHealthLevelTP120, blockchain.CustodianHealthLevelHealthy, 150},
+	}, alerts)
+}
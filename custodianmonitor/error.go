@@ -0,0 +1,42 @@
+package custodianmonitor
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnexpectedError = iota
+	LoadPortalStateError
+	NotEvaluatedError
+	CustodianNotFoundError
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	message string
+}{
+	UnexpectedError:        {-1000, "Unexpected error"},
+	LoadPortalStateError:   {-1001, "Load portal state error"},
+	NotEvaluatedError:      {-1002, "Custodian health is not evaluated yet"},
+	CustodianNotFoundError: {-1003, "Custodian not found"},
+}
+
+type CustodianMonitorError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e CustodianMonitorError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewCustodianMonitorError(key int, err error) error {
+	return &CustodianMonitorError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].message),
+	}
+}
@@ -0,0 +1,16 @@
+package custodianmonitor
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+type CustodianMonitorLogger struct {
+	log common.Logger
+}
+
+func (custodianMonitorLogger *CustodianMonitorLogger) Init(inst common.Logger) {
+	custodianMonitorLogger.log = inst
+}
+
+// Global instant to use
+var Logger = CustodianMonitorLogger{}
@@ -766,6 +766,31 @@ func StorePortalExchangeRateStatus(stateDB *StateDB, txID string, statusContent
 	return nil
 }
 
+// StorePortalExchangeRateOracleState stores the sliding window of feeder submissions
+func StorePortalExchangeRateOracleState(stateDB *StateDB, stateContent []byte) error {
+	statusType := PortalExchangeRateOracleStatePrefix()
+	statusSuffix := []byte("oracle")
+	err := StorePortalStatus(stateDB, statusType, statusSuffix, stateContent)
+	if err != nil {
+		return NewStatedbError(StorePortalExchangeRateOracleStateError, err)
+	}
+
+	return nil
+}
+
+// GetPortalExchangeRateOracleState returns the stored oracle state, has is false before the oracle is enabled
+func GetPortalExchangeRateOracleState(stateDB *StateDB) ([]byte, bool, error) {
+	key := GeneratePortalStatusObjectKey(PortalExchangeRateOracleStatePrefix(), []byte("oracle"))
+	s, has, err := stateDB.getPortalStatusByKey(key)
+	if err != nil {
+		return []byte{}, false, NewStatedbError(GetPortalExchangeRateOracleStateError, err)
+	}
+	if !has {
+		return []byte{}, false, nil
+	}
+	return s.statusContent, true, nil
+}
+
 func StorePortalUnlockOverRateCollaterals(stateDB *StateDB, txID string, statusContent []byte) error {
 	statusType := PortalUnlockOverRateCollateralsRequestStatusPrefix()
 	statusSuffix := []byte(txID)
@@ -31,3 +31,25 @@ func RemoveAllEmptyProducerBlackList(stateDB *StateDB, blackListProducerKey stri
 		}
 	}
 }
+
+func StoreEquivocationSlash(stateDB *StateDB, equivocationSlash *EquivocationSlashState) error {
+	key := GenerateEquivocationSlashObjectKey(equivocationSlash.committeePublicKey, equivocationSlash.evidenceType, equivocationSlash.chainID, equivocationSlash.height, equivocationSlash.timeSlot)
+	err := stateDB.SetStateObject(EquivocationSlashObjectType, key, equivocationSlash)
+	if err != nil {
+		return NewStatedbError(StoreEquivocationSlashError, err)
+	}
+	return nil
+}
+
+func HasEquivocationSlash(stateDB *StateDB, committeePublicKey string, evidenceType string, chainID int, height uint64, timeSlot int64) (bool, error) {
+	key := GenerateEquivocationSlashObjectKey(committeePublicKey, evidenceType, chainID, height, timeSlot)
+	_, has, err := stateDB.getEquivocationSlashState(key)
+	if err != nil {
+		return false, NewStatedbError(GetEquivocationSlashError, err)
+	}
+	return has, nil
+}
+
+func GetAllEquivocationSlashes(stateDB *StateDB) []*EquivocationSlashState {
+	return stateDB.getAllEquivocationSlashState()
+}
@@ -52,6 +52,9 @@ const (
 	PortalExternalTxObjectType
 	PortalConfirmProofObjectType
 	PortalUnlockOverRateCollaterals
+
+	// slashing
+	EquivocationSlashObjectType
 )
 
 // Prefix length
@@ -17,6 +17,7 @@ const (
 	ErrInvalidCommitteeRewardStateType           = "invalid reward receiver state type "
 	ErrInvalidRewardRequestStateType             = "invalid reward request state type"
 	ErrInvalidBlackListProducerStateType         = "invalid black list producer state type"
+	ErrInvalidEquivocationSlashStateType         = "invalid equivocation slash state type"
 	ErrInvalidSerialNumberStateType              = "invalid serial number state type"
 	ErrInvalidCommitmentStateType                = "invalid commitment state type"
 	ErrInvalidSNDerivatorStateType               = "invalid snderivator state type"
@@ -192,6 +193,12 @@ const (
 	GetWithdrawCollateralConfirmError
 	StorePortalUnlockOverRateCollateralsError
 	GetPortalUnlockOverRateCollateralsStatusError
+	StorePortalExchangeRateOracleStateError
+	GetPortalExchangeRateOracleStateError
+
+	// slashing
+	StoreEquivocationSlashError
+	GetEquivocationSlashError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -329,12 +336,18 @@ var ErrCodeMessage = map[int]struct {
 	// portal unlock over rate collaterals
 	StorePortalUnlockOverRateCollateralsError:     {-14048, "Store portal unlock over rate collaterals error"},
 	GetPortalUnlockOverRateCollateralsStatusError: {-14049, "Get portal unlock over rate collaterals error"},
+	// portal exchange rate oracle
+	StorePortalExchangeRateOracleStateError: {-14050, "Store portal exchange rate oracle state error"},
+	GetPortalExchangeRateOracleStateError:   {-14051, "Get portal exchange rate oracle state error"},
 	// feature reward
 	StoreRewardFeatureError:              {-15000, "Store reward feature state error"},
 	GetRewardFeatureError:                {-15001, "Get reward feature state error"},
 	GetAllRewardFeatureError:             {-15002, "Get all reward feature state error"},
 	GetRewardFeatureAmountByTokenIDError: {-15004, "Get reward feature amount by tokenID error"},
 	InvalidStakerInfoTypeError:           {-15005, "Staker info invalid"},
+	// -16xxx: slashing error
+	StoreEquivocationSlashError: {-16000, "Store equivocation slash error"},
+	GetEquivocationSlashError:   {-16001, "Get equivocation slash error"},
 }
 
 type StatedbError struct {
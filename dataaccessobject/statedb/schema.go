@@ -20,6 +20,7 @@ var (
 	committeeRewardPrefix              = []byte("committee-reward-")
 	rewardRequestPrefix                = []byte("reward-request-")
 	blackListProducerPrefix            = []byte("black-list-")
+	equivocationSlashPrefix            = []byte("equivocation-slash-")
 	serialNumberPrefix                 = []byte("serial-number-")
 	commitmentPrefix                   = []byte("com-value-")
 	commitmentIndexPrefix              = []byte("com-index-")
@@ -47,6 +48,7 @@ var (
 	// portal
 	portalFinaExchangeRatesStatePrefix                   = []byte("portalfinalexchangeratesstate-")
 	portalExchangeRatesRequestStatusPrefix               = []byte("portalexchangeratesrequeststatus-")
+	portalExchangeRateOracleStatePrefix                  = []byte("portalexchangerateoraclestate-")
 	portalUnlockOverRateCollateralsRequestStatusPrefix   = []byte("portalunlockoverratecollateralsstatus-")
 	portalUnlockOverRateCollateralsRequestTxStatusPrefix = []byte("portalunlockoverratecollateralstxstatus-")
 	portalPortingRequestStatusPrefix                     = []byte("portalportingrequeststatus-")
@@ -158,6 +160,11 @@ func GetBlackListProducerPrefix() []byte {
 	return h[:][:prefixHashKeyLength]
 }
 
+func GetEquivocationSlashPrefix() []byte {
+	h := common.HashH(equivocationSlashPrefix)
+	return h[:][:prefixHashKeyLength]
+}
+
 func GetSerialNumberPrefix(tokenID common.Hash, shardID byte) []byte {
 	h := common.HashH(append(serialNumberPrefix, append(tokenID[:], shardID)...))
 	return h[:][:prefixHashKeyLength]
@@ -321,6 +328,10 @@ func PortalExchangeRatesRequestStatusPrefix() []byte {
 	return portalExchangeRatesRequestStatusPrefix
 }
 
+func PortalExchangeRateOracleStatePrefix() []byte {
+	return portalExchangeRateOracleStatePrefix
+}
+
 func PortalUnlockOverRateCollateralsRequestStatusPrefix() []byte {
 	return portalUnlockOverRateCollateralsRequestStatusPrefix
 }
@@ -516,6 +527,13 @@ var _ = func() (_ struct{}) {
 		panic("black-list-" + " same prefix " + v)
 	}
 	m[string(tempBlackListProducer)] = "black-list-"
+	// equivocation slash
+	tempEquivocationSlash := GetEquivocationSlashPrefix()
+	prefixs = append(prefixs, tempEquivocationSlash)
+	if v, ok := m[string(tempEquivocationSlash)]; ok {
+		panic("equivocation-slash-" + " same prefix " + v)
+	}
+	m[string(tempEquivocationSlash)] = "equivocation-slash-"
 	for i, v1 := range prefixs {
 		for j, v2 := range prefixs {
 			if i == j {
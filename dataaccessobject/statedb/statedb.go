@@ -915,6 +915,37 @@ func (stateDB *StateDB) getAllRewardRequestState(epoch uint64) ([]common.Hash, [
 	return keys, m
 }
 
+// ================================= Equivocation Slash OBJECT =======================================
+func (stateDB *StateDB) getEquivocationSlashState(key common.Hash) (*EquivocationSlashState, bool, error) {
+	equivocationSlashState, err := stateDB.getStateObject(EquivocationSlashObjectType, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if equivocationSlashState != nil {
+		return equivocationSlashState.GetValue().(*EquivocationSlashState), true, nil
+	}
+	return NewEquivocationSlashState(), false, nil
+}
+
+func (stateDB *StateDB) getAllEquivocationSlashState() []*EquivocationSlashState {
+	equivocationSlashStates := []*EquivocationSlashState{}
+	prefix := GetEquivocationSlashPrefix()
+	temp := stateDB.trie.NodeIterator(prefix)
+	it := trie.NewIterator(temp)
+	for it.Next() {
+		value := it.Value
+		newValue := make([]byte, len(value))
+		copy(newValue, value)
+		equivocationSlashState := NewEquivocationSlashState()
+		err := json.Unmarshal(newValue, equivocationSlashState)
+		if err != nil {
+			panic("wrong value type")
+		}
+		equivocationSlashStates = append(equivocationSlashStates, equivocationSlashState)
+	}
+	return equivocationSlashStates
+}
+
 // ================================= Black List Producer OBJECT =======================================
 func (stateDB *StateDB) getBlackListProducerState(key common.Hash) (*BlackListProducerState, bool, error) {
 	blackListProducerState, err := stateDB.getStateObject(BlackListProducerObjectType, key)
@@ -31,6 +31,8 @@ func newStateObjectWithValue(db *StateDB, objectType int, hash common.Hash, valu
 		return newRewardRequestObjectWithValue(db, hash, value)
 	case BlackListProducerObjectType:
 		return newBlackListProducerObjectWithValue(db, hash, value)
+	case EquivocationSlashObjectType:
+		return newEquivocationSlashObjectWithValue(db, hash, value)
 	case TokenObjectType:
 		return newTokenObjectWithValue(db, hash, value)
 	case SerialNumberObjectType:
@@ -108,6 +110,8 @@ func newStateObject(db *StateDB, objectType int, hash common.Hash) StateObject {
 		return newRewardRequestObject(db, hash)
 	case BlackListProducerObjectType:
 		return newBlackListProducerObject(db, hash)
+	case EquivocationSlashObjectType:
+		return newEquivocationSlashObject(db, hash)
 	case SerialNumberObjectType:
 		return newSerialNumberObject(db, hash)
 	case CommitmentObjectType:
@@ -0,0 +1,271 @@
+package statedb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+type EquivocationSlashState struct {
+	// base58 string of committee public key
+	committeePublicKey string
+	evidenceType       string
+	chainID            int
+	height             uint64
+	timeSlot           int64
+	punishedEpoches    uint8
+	forceUnstake       bool
+	beaconHeight       uint64
+	txReqID            common.Hash
+}
+
+func NewEquivocationSlashStateWithValue(committeePublicKey string, evidenceType string, chainID int, height uint64, timeSlot int64, punishedEpoches uint8, forceUnstake bool, beaconHeight uint64, txReqID common.Hash) *EquivocationSlashState {
+	return &EquivocationSlashState{
+		committeePublicKey: committeePublicKey,
+		evidenceType:       evidenceType,
+		chainID:            chainID,
+		height:             height,
+		timeSlot:           timeSlot,
+		punishedEpoches:    punishedEpoches,
+		forceUnstake:       forceUnstake,
+		beaconHeight:       beaconHeight,
+		txReqID:            txReqID,
+	}
+}
+
+func NewEquivocationSlashState() *EquivocationSlashState {
+	return &EquivocationSlashState{}
+}
+
+func (es EquivocationSlashState) CommitteePublicKey() string {
+	return es.committeePublicKey
+}
+
+func (es EquivocationSlashState) EvidenceType() string {
+	return es.evidenceType
+}
+
+func (es EquivocationSlashState) ChainID() int {
+	return es.chainID
+}
+
+func (es EquivocationSlashState) Height() uint64 {
+	return es.height
+}
+
+func (es EquivocationSlashState) TimeSlot() int64 {
+	return es.timeSlot
+}
+
+func (es EquivocationSlashState) PunishedEpoches() uint8 {
+	return es.punishedEpoches
+}
+
+func (es EquivocationSlashState) ForceUnstake() bool {
+	return es.forceUnstake
+}
+
+func (es EquivocationSlashState) BeaconHeight() uint64 {
+	return es.beaconHeight
+}
+
+func (es EquivocationSlashState) TxReqID() common.Hash {
+	return es.txReqID
+}
+
+func (es EquivocationSlashState) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(struct {
+		CommitteePublicKey string
+		EvidenceType       string
+		ChainID            int
+		Height             uint64
+		TimeSlot           int64
+		PunishedEpoches    uint8
+		ForceUnstake       bool
+		BeaconHeight       uint64
+		TxReqID            common.Hash
+	}{
+		CommitteePublicKey: es.committeePublicKey,
+		EvidenceType:       es.evidenceType,
+		ChainID:            es.chainID,
+		Height:             es.height,
+		TimeSlot:           es.timeSlot,
+		PunishedEpoches:    es.punishedEpoches,
+		ForceUnstake:       es.forceUnstake,
+		BeaconHeight:       es.beaconHeight,
+		TxReqID:            es.txReqID,
+	})
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+func (es *EquivocationSlashState) UnmarshalJSON(data []byte) error {
+	temp := struct {
+		CommitteePublicKey string
+		EvidenceType       string
+		ChainID            int
+		Height             uint64
+		TimeSlot           int64
+		PunishedEpoches    uint8
+		ForceUnstake       bool
+		BeaconHeight       uint64
+		TxReqID            common.Hash
+	}{}
+	err := json.Unmarshal(data, &temp)
+	if err != nil {
+		return err
+	}
+	es.committeePublicKey = temp.CommitteePublicKey
+	es.evidenceType = temp.EvidenceType
+	es.chainID = temp.ChainID
+	es.height = temp.Height
+	es.timeSlot = temp.TimeSlot
+	es.punishedEpoches = temp.PunishedEpoches
+	es.forceUnstake = temp.ForceUnstake
+	es.beaconHeight = temp.BeaconHeight
+	es.txReqID = temp.TxReqID
+	return nil
+}
+
+type EquivocationSlashObject struct {
+	db *StateDB
+	// Write caches.
+	trie Trie // storage trie, which becomes non-nil on first access
+
+	version                int
+	equivocationSlashHash  common.Hash
+	equivocationSlashState *EquivocationSlashState
+	objectType             int
+	deleted                bool
+
+	// DB error.
+	// State objects are used by the consensus core and VM which are
+	// unable to deal with database-level errors. Any error that occurs
+	// during a database read is memoized here and will eventually be returned
+	// by StateDB.Commit.
+	dbErr error
+}
+
+func newEquivocationSlashObject(db *StateDB, hash common.Hash) *EquivocationSlashObject {
+	return &EquivocationSlashObject{
+		version:                defaultVersion,
+		db:                     db,
+		equivocationSlashHash:  hash,
+		equivocationSlashState: NewEquivocationSlashState(),
+		objectType:             EquivocationSlashObjectType,
+		deleted:                false,
+	}
+}
+
+func newEquivocationSlashObjectWithValue(db *StateDB, key common.Hash, data interface{}) (*EquivocationSlashObject, error) {
+	var newEquivocationSlashState = NewEquivocationSlashState()
+	var ok bool
+	var dataBytes []byte
+	if dataBytes, ok = data.([]byte); ok {
+		err := json.Unmarshal(dataBytes, newEquivocationSlashState)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		newEquivocationSlashState, ok = data.(*EquivocationSlashState)
+		if !ok {
+			return nil, fmt.Errorf("%+v, got type %+v", ErrInvalidEquivocationSlashStateType, reflect.TypeOf(data))
+		}
+	}
+	return &EquivocationSlashObject{
+		version:                defaultVersion,
+		equivocationSlashHash:  key,
+		equivocationSlashState: newEquivocationSlashState,
+		db:                     db,
+		objectType:             EquivocationSlashObjectType,
+		deleted:                false,
+	}, nil
+}
+
+// GenerateEquivocationSlashObjectKey - one slash per validator, chain, height, timeslot and evidence type
+func GenerateEquivocationSlashObjectKey(committeePublicKey string, evidenceType string, chainID int, height uint64, timeSlot int64) common.Hash {
+	prefixHash := GetEquivocationSlashPrefix()
+	record := committeePublicKey + evidenceType + strconv.Itoa(chainID) + strconv.FormatUint(height, 10) + strconv.FormatInt(timeSlot, 10)
+	valueHash := common.HashH([]byte(record))
+	return common.BytesToHash(append(prefixHash, valueHash[:][:prefixKeyLength]...))
+}
+
+func (es EquivocationSlashObject) GetVersion() int {
+	return es.version
+}
+
+// setError remembers the first non-nil error it is called with.
+func (es *EquivocationSlashObject) SetError(err error) {
+	if es.dbErr == nil {
+		es.dbErr = err
+	}
+}
+
+func (es EquivocationSlashObject) GetTrie(db DatabaseAccessWarper) Trie {
+	return es.trie
+}
+
+func (es *EquivocationSlashObject) SetValue(data interface{}) error {
+	var newEquivocationSlashState = NewEquivocationSlashState()
+	var ok bool
+	var dataBytes []byte
+	if dataBytes, ok = data.([]byte); ok {
+		err := json.Unmarshal(dataBytes, newEquivocationSlashState)
+		if err != nil {
+			return err
+		}
+	} else {
+		newEquivocationSlashState, ok = data.(*EquivocationSlashState)
+		if !ok {
+			return fmt.Errorf("%+v, got type %+v", ErrInvalidEquivocationSlashStateType, reflect.TypeOf(data))
+		}
+	}
+	es.equivocationSlashState = newEquivocationSlashState
+	return nil
+}
+
+func (es EquivocationSlashObject) GetValue() interface{} {
+	return es.equivocationSlashState
+}
+
+func (es EquivocationSlashObject) GetValueBytes() []byte {
+	data := es.GetValue()
+	value, err := json.Marshal(data)
+	if err != nil {
+		panic("failed to marshal equivocation slash state")
+	}
+	return []byte(value)
+}
+
+func (es EquivocationSlashObject) GetHash() common.Hash {
+	return es.equivocationSlashHash
+}
+
+func (es EquivocationSlashObject) GetType() int {
+	return es.objectType
+}
+
+// MarkDelete will delete an object in trie
+func (es *EquivocationSlashObject) MarkDelete() {
+	es.deleted = true
+}
+
+func (es *EquivocationSlashObject) Reset() bool {
+	es.equivocationSlashState = NewEquivocationSlashState()
+	return true
+}
+
+func (es EquivocationSlashObject) IsDeleted() bool {
+	return es.deleted
+}
+
+// value is either default or nil
+func (es EquivocationSlashObject) IsEmpty() bool {
+	temp := NewEquivocationSlashState()
+	return reflect.DeepEqual(temp, es.equivocationSlashState) || es.equivocationSlashState == nil
+}
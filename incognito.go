@@ -14,7 +14,6 @@ import (
 	"github.com/incognitochain/incognito-chain/metrics/monitor"
 	bnbrelaying "github.com/incognitochain/incognito-chain/relaying/bnb"
 
-	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/databasemp"
@@ -36,27 +35,43 @@ var (
 var winServiceMain func() (bool, error)
 
 func getBTCRelayingChain(btcRelayingChainID string, btcDataFolderName string) (*btcrelaying.BlockChain, error) {
-	relayingChainParams := map[string]*chaincfg.Params{
-		blockchain.TestnetBTCChainID:  btcrelaying.GetTestNet3Params(),
-		blockchain.Testnet2BTCChainID: btcrelaying.GetTestNet3ParamsForInc2(),
-		blockchain.MainnetBTCChainID:  btcrelaying.GetMainNetParams(),
-	}
-	relayingChainGenesisBlkHeight := map[string]int32{
-		blockchain.TestnetBTCChainID:  int32(1896910),
-		blockchain.Testnet2BTCChainID: int32(1863675),
-		blockchain.MainnetBTCChainID:  int32(634140),
-	}
+	relayingChainParams, relayingChainGenesisBlkHeight := blockchain.GetBTCRelayingChainParams(btcRelayingChainID)
 	return btcrelaying.GetChainV2(
 		filepath.Join(cfg.DataDir, btcDataFolderName),
-		relayingChainParams[btcRelayingChainID],
-		relayingChainGenesisBlkHeight[btcRelayingChainID],
+		relayingChainParams,
+		relayingChainGenesisBlkHeight,
 	)
 }
 
+// getUTXORelayingChains creates header chains of UTXO portal tokens other than BTC, by relaying header meta type
+func getUTXORelayingChains(portalTokens map[string]blockchain.PortalTokenProcessor) (map[int]*btcrelaying.BlockChain, error) {
+	utxoChains := map[int]*btcrelaying.BlockChain{}
+	for _, portalToken := range portalTokens {
+		utxoToken, ok := portalToken.(*blockchain.PortalUTXOTokenProcessor)
+		if !ok || utxoToken.HeaderChainParams == nil {
+			continue
+		}
+		err := btcrelaying.RegisterUTXOChainParams(utxoToken.HeaderChainParams)
+		if err != nil {
+			return nil, err
+		}
+		utxoChain, err := btcrelaying.GetChainV2(
+			filepath.Join(cfg.DataDir, utxoToken.HeaderChainDataFolderName),
+			utxoToken.HeaderChainParams,
+			utxoToken.HeaderChainGenesisBlockHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		utxoChains[utxoToken.RelayingHeaderMetaType] = utxoChain
+	}
+	return utxoChains, nil
+}
+
 func getBNBRelayingChainState(bnbRelayingChainID string) (*bnbrelaying.BNBChainState, error) {
 	bnbChainState := new(bnbrelaying.BNBChainState)
 	err := bnbChainState.LoadBNBChainState(
-		filepath.Join(cfg.DataDir, "bnbrelayingv3"),
+		filepath.Join(cfg.DataDir, blockchain.BNBRelayingDataFolderName),
 		bnbRelayingChainID,
 	)
 	if err != nil {
@@ -157,6 +172,19 @@ func mainMaster(serverChan chan<- *Server) error {
 		db.Close()
 	}()
 
+	// Create relaying chains of other UTXO portal tokens
+	utxoChains, err := getUTXORelayingChains(activeNetParams.Params.PortalTokens)
+	if err != nil {
+		Logger.log.Error("could not get or create utxo relaying chains")
+		Logger.log.Error(err)
+		panic(err)
+	}
+	defer func() {
+		for _, utxoChain := range utxoChains {
+			utxoChain.GetDB().Close()
+		}
+	}()
+
 	// Create bnbrelaying chain state
 	bnbChainState, err := getBNBRelayingChainState(activeNetParams.Params.BNBRelayingHeaderChainID)
 	if err != nil {
@@ -174,7 +202,7 @@ func mainMaster(serverChan chan<- *Server) error {
 	server := Server{}
 	server.wallet = walletObj
 	activeNetParams.Params.IsBackup = cfg.ForceBackup
-	err = server.NewServer(cfg.Listener, db, dbmp, activeNetParams.Params, version, btcChain, bnbChainState, interrupt)
+	err = server.NewServer(cfg.Listener, db, dbmp, activeNetParams.Params, version, btcChain, utxoChains, bnbChainState, interrupt)
 	if err != nil {
 		Logger.log.Errorf("Unable to start server on %+v", cfg.Listener)
 		Logger.log.Error(err)
@@ -43,6 +43,8 @@ const (
 	ValidateAggSignatureForCrossShardBlockError
 	DuplicateSerialNumbersHashError
 	CouldNotGetExchangeRateError
+	RejectRateLimitedTx
+	InboundQueueFullError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -84,6 +86,8 @@ var ErrCodeMessage = map[int]struct {
 	CouldNotGetExchangeRateError:                {-1032, "Could not get the exchange rate error"},
 	RejectSanityTxLocktime:                      {-1033, "Wrong tx locktime"},
 	RejectMetadataWithBlockchainTx:              {-1034, "Reject invalid metadata with blockchain"},
+	RejectRateLimitedTx:                         {-1035, "Reject tx from peer sending faster than its rate"},
+	InboundQueueFullError:                       {-1036, "Inbound tx queue is full"},
 }
 
 type MempoolTxError struct {
@@ -0,0 +1,318 @@
+package mempool
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+// default value of InboundTxQueueConfig
+const (
+	defaultInboundQueueSize = 2000
+	defaultInboundPeerRate  = 50
+	defaultInboundPeerBurst = 200
+	defaultInboundBatchSize = 32
+	defaultInboundBatchWait = 100 * time.Millisecond
+	// token buckets which are full again are forgotten when there are more peers than this
+	maxInboundPeerBuckets = 4096
+)
+
+// InboundTxQueueConfig - limits of transactions received from gossip, zero values are replaced by defaults
+type InboundTxQueueConfig struct {
+	QueueSize int           // transactions waiting for pre-check, new ones are dropped while the queue is full
+	PeerRate  float64       // transactions per second a peer can send in the long run
+	PeerBurst int           // transactions a peer can send at once
+	Workers   int           // verification workers, default is half of the CPUs
+	BatchSize int           // max transactions verified in a batch
+	BatchWait time.Duration // max time a pre-checked transaction waits for its batch to fill
+}
+
+// inboundTxPool - stages of TxPool run by InboundTxQueue
+type inboundTxPool interface {
+	precheckTransaction(tx metadata.Transaction) error
+	maybeAcceptInboundBatch(txs []metadata.Transaction) []error
+}
+
+type inboundTx struct {
+	tx         metadata.Transaction
+	onAccepted func()
+}
+
+// tokenBucket - a peer earns PeerRate tokens per second up to PeerBurst, a transaction takes a token
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// InboundTxQueue - bounded queue of transactions received from gossip, in front of TxPool.
+// A peer sending faster than its token bucket allows has its transactions dropped.
+// Queued transactions go through cheap pre-checks one by one, then their proofs are verified
+// in batches on a worker pool, so verification does not hold the pool lock.
+// While workers are busy the pre-check stage blocks and the queue fills, new transactions are dropped
+type InboundTxQueue struct {
+	config  InboundTxQueueConfig
+	pool    inboundTxPool
+	queue   chan inboundTx
+	batches chan []inboundTx
+	mtx     sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func NewInboundTxQueue(tp *TxPool, config InboundTxQueueConfig) *InboundTxQueue {
+	return newInboundTxQueue(tp, config)
+}
+
+func newInboundTxQueue(pool inboundTxPool, config InboundTxQueueConfig) *InboundTxQueue {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultInboundQueueSize
+	}
+	if config.PeerRate <= 0 {
+		config.PeerRate = defaultInboundPeerRate
+	}
+	if config.PeerBurst <= 0 {
+		config.PeerBurst = defaultInboundPeerBurst
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU() / 2
+		if config.Workers == 0 {
+			config.Workers = 1
+		}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultInboundBatchSize
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = defaultInboundBatchWait
+	}
+	return &InboundTxQueue{
+		config:  config,
+		pool:    pool,
+		queue:   make(chan inboundTx, config.QueueSize),
+		batches: make(chan []inboundTx),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Push - queue tx received from peerID, onAccepted is called after tx enters pool.
+// Return an error without queueing tx if the peer sends faster than its rate or the queue is full
+func (q *InboundTxQueue) Push(peerID string, tx metadata.Transaction, onAccepted func()) error {
+	if !q.allow(peerID) {
+		return NewMempoolTxError(RejectRateLimitedTx, fmt.Errorf("peer %v sends more than %v txs per second, drop tx %v", peerID, q.config.PeerRate, tx.Hash().String()))
+	}
+	select {
+	case q.queue <- inboundTx{tx: tx, onAccepted: onAccepted}:
+		return nil
+	default:
+		return NewMempoolTxError(InboundQueueFullError, fmt.Errorf("drop tx %v from peer %v", tx.Hash().String(), peerID))
+	}
+}
+
+// Start - pre-check queued transactions and verify them on the worker pool until cQuit is closed
+func (q *InboundTxQueue) Start(cQuit chan struct{}) {
+	for i := 0; i < q.config.Workers; i++ {
+		go q.verifyWorker(cQuit)
+	}
+	q.precheckLoop(cQuit)
+}
+
+// allow - take a token from the bucket of peerID
+func (q *InboundTxQueue) allow(peerID string) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	now := q.now()
+	bucket, ok := q.buckets[peerID]
+	if !ok {
+		if len(q.buckets) >= maxInboundPeerBuckets {
+			q.pruneBuckets(now)
+		}
+		bucket = &tokenBucket{tokens: float64(q.config.PeerBurst), last: now}
+		q.buckets[peerID] = bucket
+	}
+	bucket.tokens = math.Min(float64(q.config.PeerBurst), bucket.tokens+now.Sub(bucket.last).Seconds()*q.config.PeerRate)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// pruneBuckets - forget peers whose bucket is full again, they are the same as new peers
+func (q *InboundTxQueue) pruneBuckets(now time.Time) {
+	for peerID, bucket := range q.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*q.config.PeerRate >= float64(q.config.PeerBurst) {
+			delete(q.buckets, peerID)
+		}
+	}
+}
+
+// precheckLoop - drop queued transactions failing pre-check, hand the others to workers
+// in batches of BatchSize, or smaller after BatchWait
+func (q *InboundTxQueue) precheckLoop(cQuit chan struct{}) {
+	var batch []inboundTx
+	var wait <-chan time.Time
+	flush := func() bool {
+		select {
+		case q.batches <- batch:
+			batch = nil
+			wait = nil
+			return true
+		case <-cQuit:
+			return false
+		}
+	}
+	for {
+		select {
+		case <-cQuit:
+			return
+		case item := <-q.queue:
+			if err := q.pool.precheckTransaction(item.tx); err != nil {
+				Logger.log.Debugf("Drop tx %v failing pre-check: %v", item.tx.Hash().String(), err)
+				continue
+			}
+			batch = append(batch, item)
+			if len(batch) == 1 {
+				wait = time.After(q.config.BatchWait)
+			}
+			if len(batch) >= q.config.BatchSize && !flush() {
+				return
+			}
+		case <-wait:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
+
+func (q *InboundTxQueue) verifyWorker(cQuit chan struct{}) {
+	for {
+		select {
+		case <-cQuit:
+			return
+		case batch := <-q.batches:
+			txs := make([]metadata.Transaction, len(batch))
+			for i, item := range batch {
+				txs[i] = item.tx
+			}
+			errs := q.pool.maybeAcceptInboundBatch(txs)
+			for i, item := range batch {
+				if errs[i] == nil && item.onAccepted != nil {
+					item.onAccepted()
+				}
+			}
+		}
+	}
+}
+
+/*
+// precheckTransaction run the cheap checks of a transaction received from gossip, before its proof is verified:
+1. Transaction can enter pool: relayed shard, pool size, type
+2. Validate sanity data of tx
+3. Validate duplicate tx
+4. Do not accept a salary tx
+5. Validate serial numbers and snd outputs with other txs in mempool, unless tx may replace one
+6. Validate serial numbers with blockchain, for tx without metadata
+*/
+func (tp *TxPool) precheckTransaction(tx metadata.Transaction) error {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+	if err := tp.checkNewTransaction(tx); err != nil {
+		return err
+	}
+	txHash := tx.Hash()
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	shardView := tp.config.BlockChain.ShardChain[shardID].GetBestView().(*blockchain.ShardBestState)
+	if validated, err := tx.ValidateSanityData(tp.config.BlockChain, shardView, beaconView, 0); !validated {
+		return NewMempoolTxError(RejectSanityTx, fmt.Errorf("transaction's sansity %v is error %v", txHash.String(), err))
+	}
+	if tp.isTxInPool(txHash) {
+		return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", txHash.String()))
+	}
+	if tx.IsSalaryTx() {
+		return NewMempoolTxError(RejectSalaryTx, fmt.Errorf("%+v is salary tx", txHash.String()))
+	}
+	if err := tx.ValidateTxWithCurrentMempool(tp); err != nil {
+		// the fee of a replacement tx is checked with the pool lock held for writes
+		if _, isReplacement := tp.poolSerialNumberHash[common.HashArrayOfHashArray(tx.ListSerialNumbersHashH())]; !isReplacement {
+			return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, err)
+		}
+	}
+	// some metadata skip double spend check, see ValidateTxWithBlockChain
+	if tx.GetMetadata() == nil {
+		if err := tx.ValidateDoubleSpendWithBlockchain(shardID, shardView.GetCopiedTransactionStateDB(), nil); err != nil {
+			return NewMempoolTxError(RejectDoubleSpendWithBlockchainTx, err)
+		}
+	}
+	return nil
+}
+
+// maybeAcceptInboundBatch - verify proofs of pre-checked transactions without the pool lock, then add them to pool.
+// Transactions of the same shard are batch verified, when a batch fails its transactions are verified one by one,
+// so an invalid transaction does not get the valid ones rejected
+func (tp *TxPool) maybeAcceptInboundBatch(txs []metadata.Transaction) []error {
+	errs := make([]error, len(txs))
+	beaconHeight := int64(tp.config.BlockChain.GetBeaconBestState().BestBlock.GetHeight())
+	isNewZKP := tp.config.BlockChain.IsAfterNewZKPCheckPoint(uint64(beaconHeight))
+	shardTxs := make(map[byte][]int)
+	for i, tx := range txs {
+		shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+		shardTxs[shardID] = append(shardTxs[shardID], i)
+	}
+	for shardID, indexes := range shardTxs {
+		batchTxs := []metadata.Transaction{}
+		for _, i := range indexes {
+			if !isVerifiedOneByOne(txs[i]) {
+				batchTxs = append(batchTxs, txs[i])
+			}
+		}
+		beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+		shardView := tp.config.BlockChain.ShardChain[shardID].GetBestView().(*blockchain.ShardBestState)
+		ok := false
+		if len(batchTxs) > 0 {
+			boolParams := make(map[string]bool)
+			boolParams["isNewTransaction"] = true
+			boolParams["isBatch"] = true
+			boolParams["isNewZKP"] = isNewZKP
+			var err error
+			ok, err, _ = transaction.NewBatchTransaction(batchTxs).Validate(shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), boolParams)
+			if !ok {
+				Logger.log.Infof("Verify batch of %v txs from shard %v failed, verify them one by one: %v", len(batchTxs), shardID, err)
+			}
+		}
+		for _, i := range indexes {
+			tx := txs[i]
+			if !ok || isVerifiedOneByOne(tx) {
+				boolParams := make(map[string]bool)
+				boolParams["hasPrivacy"] = tx.IsPrivacy()
+				boolParams["isNewTransaction"] = true
+				boolParams["isNewZKP"] = isNewZKP
+				validated, err := tx.ValidateTxByItself(boolParams, shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain, shardID, nil, nil)
+				if !validated {
+					errs[i] = NewMempoolTxError(RejectInvalidTx, err)
+					Logger.log.Error(errs[i])
+					continue
+				}
+			}
+			tp.mtx.Lock()
+			_, _, errs[i] = tp.maybeAcceptNewTransaction(tx, beaconHeight, true)
+			tp.mtx.Unlock()
+		}
+	}
+	return errs
+}
+
+// isVerifiedOneByOne - batch verification skips the range proof of the pToken of a privacy token tx
+func isVerifiedOneByOne(tx metadata.Transaction) bool {
+	return tx.GetType() == common.TxCustomTokenPrivacyType
+}
@@ -0,0 +1,125 @@
+package mempool
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInboundPool struct {
+	mtx     sync.Mutex
+	invalid map[common.Hash]bool
+	batches chan []metadata.Transaction
+}
+
+func (p *fakeInboundPool) precheckTransaction(tx metadata.Transaction) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.invalid[*tx.Hash()] {
+		return NewMempoolTxError(RejectSanityTx, nil)
+	}
+	return nil
+}
+
+func (p *fakeInboundPool) maybeAcceptInboundBatch(txs []metadata.Transaction) []error {
+	p.batches <- txs
+	return make([]error, len(txs))
+}
+
+func newInboundTestTx(fee uint64) metadata.Transaction {
+	return &transaction.Tx{Version: 1, Type: common.TxNormalType, Fee: fee}
+}
+
+func errCode(err error) int {
+	if e, ok := err.(*MempoolTxError); ok {
+		return e.Code
+	}
+	return 0
+}
+
+func TestInboundTxQueue_RateLimit(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	q := newInboundTxQueue(&fakeInboundPool{}, InboundTxQueueConfig{QueueSize: 100, PeerRate: 10, PeerBurst: 2})
+	q.now = func() time.Time { return now }
+
+	assert.Nil(t, q.Push("peer1", newInboundTestTx(1), nil))
+	assert.Nil(t, q.Push("peer1", newInboundTestTx(2), nil))
+	err := q.Push("peer1", newInboundTestTx(3), nil)
+	assert.Equal(t, ErrCodeMessage[RejectRateLimitedTx].Code, errCode(err))
+	//other peers have their own bucket
+	assert.Nil(t, q.Push("peer2", newInboundTestTx(4), nil))
+
+	//a token is earned every 100ms
+	now = now.Add(100 * time.Millisecond)
+	assert.Nil(t, q.Push("peer1", newInboundTestTx(5), nil))
+	err = q.Push("peer1", newInboundTestTx(6), nil)
+	assert.Equal(t, ErrCodeMessage[RejectRateLimitedTx].Code, errCode(err))
+
+	//bucket never holds more than burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, q.Push("peer1", newInboundTestTx(uint64(10+i)), nil))
+	}
+	err = q.Push("peer1", newInboundTestTx(12), nil)
+	assert.Equal(t, ErrCodeMessage[RejectRateLimitedTx].Code, errCode(err))
+}
+
+func TestInboundTxQueue_Full(t *testing.T) {
+	q := newInboundTxQueue(&fakeInboundPool{}, InboundTxQueueConfig{QueueSize: 1})
+	assert.Nil(t, q.Push("peer1", newInboundTestTx(1), nil))
+	err := q.Push("peer2", newInboundTestTx(2), nil)
+	assert.Equal(t, ErrCodeMessage[InboundQueueFullError].Code, errCode(err))
+}
+
+func TestInboundTxQueue_Batch(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	invalidTx := newInboundTestTx(100)
+	pool := &fakeInboundPool{
+		invalid: map[common.Hash]bool{*invalidTx.Hash(): true},
+		batches: make(chan []metadata.Transaction),
+	}
+	q := newInboundTxQueue(pool, InboundTxQueueConfig{Workers: 1, BatchSize: 3, BatchWait: 200 * time.Millisecond})
+	cQuit := make(chan struct{})
+	defer close(cQuit)
+	go q.Start(cQuit)
+
+	accepted := make(chan struct{}, 10)
+	onAccepted := func() { accepted <- struct{}{} }
+	txs := []metadata.Transaction{newInboundTestTx(1), invalidTx, newInboundTestTx(2), newInboundTestTx(3), newInboundTestTx(4)}
+	for _, tx := range txs {
+		assert.Nil(t, q.Push("peer1", tx, onAccepted))
+	}
+
+	//transactions failing pre-check are not verified
+	select {
+	case batch := <-pool.batches:
+		assert.Equal(t, []metadata.Transaction{txs[0], txs[2], txs[3]}, batch)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expect a full batch before BatchWait")
+	}
+	//a batch which is not full is verified after BatchWait
+	select {
+	case batch := <-pool.batches:
+		assert.Equal(t, []metadata.Transaction{txs[4]}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("expect a partial batch after BatchWait")
+	}
+	for i := 0; i < 4; i++ {
+		select {
+		case <-accepted:
+		case <-time.After(time.Second):
+			t.Fatalf("expect 4 accepted txs, got %v", i)
+		}
+	}
+}
+
+// batch verification skips pToken range proofs, privacy token txs are verified one by one
+func TestInboundTxQueue_VerifiedOneByOne(t *testing.T) {
+	assert.False(t, isVerifiedOneByOne(newInboundTestTx(1)))
+	assert.True(t, isVerifiedOneByOne(&transaction.TxCustomTokenPrivacy{Tx: transaction.Tx{Version: 1, Type: common.TxCustomTokenPrivacyType}}))
+}
@@ -223,31 +223,22 @@ func (tp *TxPool) MaybeAcceptTransaction(tx metadata.Transaction, beaconHeight i
 	go func(txHash common.Hash) {
 		tp.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.TransactionHashEnterNodeTopic, txHash))
 	}(*tx.Hash())
-	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
-	if !tp.checkRelayShard(tx) && !tp.checkPublicKeyRole(tx) {
-		err := NewMempoolTxError(UnexpectedTransactionError, errors.New("Unexpected Transaction From Shard "+fmt.Sprintf("%d", senderShardID)))
-		Logger.log.Error(err)
+	return tp.maybeAcceptNewTransaction(tx, beaconHeight, false)
+}
+
+/*
+// maybeAcceptNewTransaction add a new free-standing transaction into pool.
+// The proof of tx is not verified again if isVerified, e.g. it is batch verified with other transactions.
+// This function MUST be called with the mempool lock held (for writes).
+*/
+func (tp *TxPool) maybeAcceptNewTransaction(tx metadata.Transaction, beaconHeight int64, isVerified bool) (*common.Hash, *TxDesc, error) {
+	if err := tp.checkNewTransaction(tx); err != nil {
 		return &common.Hash{}, &TxDesc{}, err
 	}
+	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
 	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
 	shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
-	//==========
-	if uint64(len(tp.pool)) >= tp.config.MaxTx {
-		return nil, nil, NewMempoolTxError(MaxPoolSizeError, errors.New("Pool reach max number of transaction"))
-	}
-	if tx.GetType() == common.TxReturnStakingType{
-		return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a return staking tx", tx.Hash().String()))
-	}
-	if tx.GetType() == common.TxCustomTokenPrivacyType{
-		tempTx, ok := tx.(*transaction.TxCustomTokenPrivacy)
-		if !ok {
-			return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("cannot detect transaction type for tx %+v", tx.Hash().String()))
-		}
-		if tempTx.TxPrivacyTokenData.Mintable{
-			return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a minteable tx", tx.Hash().String()))
-		}
-	}
-	hash, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, beaconHeight)
+	hash, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, isVerified, beaconHeight)
 	//==========
 	if err != nil {
 		Logger.log.Error(err)
@@ -265,6 +256,36 @@ func (tp *TxPool) MaybeAcceptTransaction(tx metadata.Transaction, beaconHeight i
 	return hash, txDesc, err
 }
 
+/*
+// checkNewTransaction reject a new transaction which this node does not relay
+// or which can not enter the pool whatever its proof is.
+// This function MUST be called with the mempool lock held (for reads).
+*/
+func (tp *TxPool) checkNewTransaction(tx metadata.Transaction) error {
+	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	if !tp.checkRelayShard(tx) && !tp.checkPublicKeyRole(tx) {
+		err := NewMempoolTxError(UnexpectedTransactionError, errors.New("Unexpected Transaction From Shard "+fmt.Sprintf("%d", senderShardID)))
+		Logger.log.Error(err)
+		return err
+	}
+	if uint64(len(tp.pool)) >= tp.config.MaxTx {
+		return NewMempoolTxError(MaxPoolSizeError, errors.New("Pool reach max number of transaction"))
+	}
+	if tx.GetType() == common.TxReturnStakingType {
+		return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a return staking tx", tx.Hash().String()))
+	}
+	if tx.GetType() == common.TxCustomTokenPrivacyType {
+		tempTx, ok := tx.(*transaction.TxCustomTokenPrivacy)
+		if !ok {
+			return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("cannot detect transaction type for tx %+v", tx.Hash().String()))
+		}
+		if tempTx.TxPrivacyTokenData.Mintable {
+			return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a minteable tx", tx.Hash().String()))
+		}
+	}
+	return nil
+}
+
 // This function is safe for concurrent access.
 func (tp *TxPool) MaybeAcceptTransactionForBlockProducing(tx metadata.Transaction, beaconHeight int64, shardView *blockchain.ShardBestState) (*metadata.TxDesc, error) {
 	tp.mtx.Lock()
@@ -276,7 +297,7 @@ func (tp *TxPool) MaybeAcceptTransactionForBlockProducing(tx metadata.Transactio
 		Logger.log.Error(err)
 		return nil, err
 	}
-	_, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, false, false, int64(bHeight))
+	_, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, false, false, false, int64(bHeight))
 	if err != nil {
 		Logger.log.Error(err)
 		return nil, err
@@ -342,10 +363,11 @@ func (tp *TxPool) maybeAcceptBatchTransaction(shardView *blockchain.ShardBestSta
 // #1: tx
 // #2: store into db
 // #3: default nil, contain input coins hash, which are used for creating this tx
+// #4: proof of tx is already verified in a batch
 */
-func (tp *TxPool) maybeAcceptTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, isStore bool, isNewTransaction bool, beaconHeight int64) (*common.Hash, *TxDesc, error) {
+func (tp *TxPool) maybeAcceptTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, isStore bool, isNewTransaction bool, isBatch bool, beaconHeight int64) (*common.Hash, *TxDesc, error) {
 	// validate tx
-	err := tp.validateTransaction(shardView, beaconView, tx, beaconHeight, false, isNewTransaction)
+	err := tp.validateTransaction(shardView, beaconView, tx, beaconHeight, isBatch, isNewTransaction)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -7,9 +7,10 @@ import (
 )
 
 type AccumulatedValues struct {
-	UniqETHTxsUsed   [][]byte
-	DBridgeTokenPair map[string][]byte
-	CBridgeTokens    []*common.Hash
+	UniqETHTxsUsed        [][]byte
+	DBridgeTokenPair      map[string][]byte
+	CBridgeTokens         []*common.Hash
+	UniqEquivocationSlash []common.Hash
 }
 
 func (ac AccumulatedValues) CanProcessTokenPair(
@@ -45,3 +46,14 @@ func (ac AccumulatedValues) CanProcessCIncToken(
 	_, found := ac.DBridgeTokenPair[incTokenIDStr]
 	return !found
 }
+
+func (ac AccumulatedValues) CanProcessEquivocationSlash(
+	slashKey common.Hash,
+) bool {
+	for _, key := range ac.UniqEquivocationSlash {
+		if key == slashKey {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	ec "github.com/ethereum/go-ethereum/common"
 	"github.com/incognitochain/incognito-chain/common"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -32,8 +33,12 @@ func ParseMetadata(meta interface{}) (Metadata, error) {
 	if err != nil {
 		return nil, err
 	}
+	mtType, ok := mtTemp["Type"].(float64)
+	if !ok {
+		return nil, errors.Errorf("Could not parse metadata without type: %v", mtTemp["Type"])
+	}
 	var md Metadata
-	switch int(mtTemp["Type"].(float64)) {
+	switch int(mtType) {
 	case IssuingRequestMeta:
 		md = &IssuingRequest{}
 	case IssuingResponseMeta:
@@ -62,6 +67,8 @@ func ParseMetadata(meta interface{}) (Metadata, error) {
 		md = &WithDrawRewardResponse{}
 	case StopAutoStakingMeta:
 		md = &StopAutoStakingMetadata{}
+	case ReportEquivocationMeta:
+		md = &ReportEquivocationMetadata{}
 	case PDEContributionMeta:
 		md = &PDEContribution{}
 	case PDEPRVRequiredContributionRequestMeta:
@@ -74,6 +81,12 @@ func ParseMetadata(meta interface{}) (Metadata, error) {
 		md = &PDECrossPoolTradeRequest{}
 	case PDECrossPoolTradeResponseMeta:
 		md = &PDECrossPoolTradeResponse{}
+	case PDERoutedTradeRequestMeta:
+		md = &PDERoutedTradeRequest{}
+	case PDETradeRequestMetaV2:
+		md = &PDETradeRequestV2{}
+	case PDECrossPoolTradeRequestMetaV2:
+		md = &PDECrossPoolTradeRequestV2{}
 	case PDEWithdrawalRequestMeta:
 		md = &PDEWithdrawalRequest{}
 	case PDEWithdrawalResponseMeta:
@@ -157,8 +170,12 @@ func ParseMetadata(meta interface{}) (Metadata, error) {
 	case PortalTopUpWaitingPortingRequestMetaV3:
 		md = &PortalTopUpWaitingPortingRequestV3{}
 	default:
+		if IsRelayingUTXOHeaderMeta(int(mtType)) {
+			md = &RelayingHeader{}
+			break
+		}
 		Logger.log.Debug("[db] parse meta err: %+v\n", meta)
-		return nil, errors.Errorf("Could not parse metadata with type: %d", int(mtTemp["Type"].(float64)))
+		return nil, errors.Errorf("Could not parse metadata with type: %d", int(mtType))
 	}
 
 	err = json.Unmarshal(metaInBytes, &md)
@@ -206,6 +223,22 @@ func HasPortalInstructions(instructions [][]string) bool {
 	return false
 }
 
+// getValidationBeaconHeight - txs validated by mempool have beaconHeight 0, they are validated at the beacon height of the shard view
+func getValidationBeaconHeight(shardViewRetriever ShardViewRetriever, beaconHeight uint64) uint64 {
+	if beaconHeight == 0 && shardViewRetriever != nil {
+		return shardViewRetriever.GetBeaconHeight()
+	}
+	return beaconHeight
+}
+
+// legacyRuneString - string(v) of an integer as used by hash of old metadata, values out of unicode range are the replacement rune
+func legacyRuneString(v uint64) string {
+	if v > utf8.MaxRune {
+		return string(utf8.RuneError)
+	}
+	return string(rune(v))
+}
+
 // Validate portal external addresses for collateral tokens (ETH/ERC20)
 func ValidatePortalExternalAddress(chainName string, tokenID string, address string) (bool, error) {
 	switch chainName {
@@ -216,18 +249,19 @@ func ValidatePortalExternalAddress(chainName string, tokenID string, address str
 }
 
 // Validate portal remote addresses for portal tokens (BTC, BNB)
-func ValidatePortalRemoteAddresses(remoteAddresses map[string]string, chainRetriever ChainRetriever) (bool, error) {
+func ValidatePortalRemoteAddresses(remoteAddresses map[string]string, chainRetriever ChainRetriever, beaconHeight uint64) (bool, error) {
 	if len(remoteAddresses) == 0 {
 		return false, errors.New("remote addresses should be at least one address")
 	}
 	for tokenID, remoteAddr := range remoteAddresses {
-		if !IsPortalToken(tokenID) {
+		if !chainRetriever.IsPortalToken(beaconHeight, tokenID) {
 			return false, errors.New("TokenID in remote address is invalid")
 		}
 		if len(remoteAddr) == 0 {
 			return false, errors.New("Remote address is invalid")
 		}
-		if !IsValidPortalRemoteAddress(chainRetriever, remoteAddr, tokenID) {
+		isValid, err := chainRetriever.IsValidPortalRemoteAddress(tokenID, remoteAddr, beaconHeight)
+		if err != nil || !isValid {
 			return false, fmt.Errorf("Remote address %v is not a valid address of tokenID %v", remoteAddr, tokenID)
 		}
 	}
@@ -27,6 +27,9 @@ const (
 	StopAutoStakingMeta = 127
 	BeaconStakingMeta   = 64
 
+	// slashing
+	ReportEquivocationMeta = 150
+
 	// Incognito -> Ethereum bridge
 	BeaconSwapConfirmMeta = 70
 	BridgeSwapConfirmMeta = 71
@@ -48,6 +51,9 @@ const (
 	PDEFeeWithdrawalRequestMeta           = 207
 	PDEFeeWithdrawalResponseMeta          = 208
 	PDETradingFeesDistributionMeta        = 209
+	PDERoutedTradeRequestMeta             = 210
+	PDETradeRequestMetaV2                 = 211
+	PDECrossPoolTradeRequestMetaV2        = 212
 
 	// portal
 	PortalCustodianDepositMeta                  = 100
@@ -106,6 +112,10 @@ const (
 	RelayingBNBHeaderMeta = 200
 	RelayingBTCHeaderMeta = 201
 
+	// reserved for header chains of UTXO portal tokens defined by params
+	RelayingUTXOHeaderMetaMin = 220
+	RelayingUTXOHeaderMetaMax = 239
+
 	PortalTopUpWaitingPortingRequestMeta  = 202
 	PortalTopUpWaitingPortingResponseMeta = 203
 
@@ -172,8 +182,10 @@ var (
 //	EthereumLightNodePort     = "8545"
 //)
 const (
-	StopAutoStakingAmount = 0
-	ETHConfirmationBlocks = 15
+	StopAutoStakingAmount    = 0
+	ReportEquivocationAmount = 0
+	ETHConfirmationBlocks    = 15
+	PDEMaxTradeHops          = 3 // pools a routed trade can go through
 )
 
 var AcceptedWithdrawRewardRequestVersion = []int{0, 1}
@@ -42,6 +42,12 @@ const (
 	CouldNotGetExchangeRateError
 	RejectInvalidFee
 	PDEFeeWithdrawalRequestFromMapError
+	PDERoutedTradeRequestTypeAssertionError
+	PDERoutedTradeRequestNotActivatedError
+	PDERoutedTradeRequestInvalidPathError
+	PDERoutedTradeRequestPoolNotFoundError
+	PDETradeRequestV2NotActivatedError
+	PDETradeRequestV2InvalidDeadlineError
 
 	// portal
 	PortalRequestPTokenParamError
@@ -56,6 +62,13 @@ const (
 	PortalCustodianDepositV3ValidateSanityDataError
 	NewPortalCustodianDepositV3MetaFromMapError
 	PortalUnlockOverRateCollateralsError
+
+	// slashing
+	ReportEquivocationTypeAssertionError
+	ReportEquivocationInvalidEvidenceError
+	ReportEquivocationBuildReqActionsError
+	ReportEquivocationDecodeActionError
+	ReportEquivocationNotActivatedError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -98,9 +111,15 @@ var ErrCodeMessage = map[int]struct {
 	WrongIncognitoDAOPaymentAddressError: {-5001, "Invalid dev account"},
 
 	// pde
-	PDEWithdrawalRequestFromMapError: {-6001, "PDE withdrawal request Error"},
-	CouldNotGetExchangeRateError:     {-6002, "Could not get the exchange rate error"},
-	RejectInvalidFee:                 {-6003, "Reject invalid fee"},
+	PDEWithdrawalRequestFromMapError:        {-6001, "PDE withdrawal request Error"},
+	CouldNotGetExchangeRateError:            {-6002, "Could not get the exchange rate error"},
+	RejectInvalidFee:                        {-6003, "Reject invalid fee"},
+	PDERoutedTradeRequestTypeAssertionError: {-6004, "PDE routed trade request type assertion error"},
+	PDERoutedTradeRequestNotActivatedError:  {-6005, "PDE routed trade request is not activated error"},
+	PDERoutedTradeRequestInvalidPathError:   {-6006, "PDE routed trade request invalid path error"},
+	PDERoutedTradeRequestPoolNotFoundError:  {-6007, "PDE routed trade request pool of path not found error"},
+	PDETradeRequestV2NotActivatedError:      {-6008, "PDE trade request with deadline is not activated error"},
+	PDETradeRequestV2InvalidDeadlineError:   {-6009, "PDE trade request invalid deadline error"},
 
 	// portal
 	PortalRequestPTokenParamError:                {-7001, "Portal request ptoken param error"},
@@ -115,6 +134,13 @@ var ErrCodeMessage = map[int]struct {
 	PortalCustodianDepositV3ValidateSanityDataError: {-9002, "Validate sanity data tx portal custodian deposit v3 error"},
 	NewPortalCustodianDepositV3MetaFromMapError:     {-9003, "New portal custodian deposit v3 metadata from map error"},
 	PortalUnlockOverRateCollateralsError:            {-9004, "Validate with blockchain tx portal custodian unlock over rate v3 error"},
+
+	// -10xxx slashing
+	ReportEquivocationTypeAssertionError:   {-10001, "Report equivocation type assertion error"},
+	ReportEquivocationInvalidEvidenceError: {-10002, "Report equivocation invalid evidence error"},
+	ReportEquivocationBuildReqActionsError: {-10003, "Report equivocation build request action error"},
+	ReportEquivocationDecodeActionError:    {-10004, "Report equivocation decode action error"},
+	ReportEquivocationNotActivatedError:    {-10005, "Report equivocation is not activated error"},
 }
 
 type MetadataTxError struct {
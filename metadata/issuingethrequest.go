@@ -160,7 +160,7 @@ func (iReq IssuingETHRequest) ValidateMetadataByItself() bool {
 func (iReq IssuingETHRequest) Hash() *common.Hash {
 	record := iReq.BlockHash.String()
 	// TODO: @hung change to record += fmt.Sprint(iReq.TxIndex)
-	record += string(iReq.TxIndex)
+	record += legacyRuneString(uint64(iReq.TxIndex))
 	proofStrs := iReq.ProofStrs
 	for _, proofStr := range proofStrs {
 		record += proofStr
@@ -178,7 +178,7 @@ func (iReq IssuingRequest) Hash() *common.Hash {
 	record := iReq.ReceiverAddress.String()
 	record += iReq.TokenID.String()
 	// TODO: @hung change to record += fmt.Sprint(iReq.DepositedAmount)
-	record += string(iReq.DepositedAmount)
+	record += legacyRuneString(iReq.DepositedAmount)
 	record += iReq.TokenName
 	record += iReq.MetadataBase.Hash().String()
 
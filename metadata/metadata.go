@@ -6,13 +6,11 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
 	"github.com/incognitochain/incognito-chain/privacy"
-	"github.com/incognitochain/incognito-chain/relaying/bnb"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
-	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
 )
 
 // Interface for all types of metadata in tx
@@ -58,16 +56,20 @@ type MempoolRetriever interface {
 type ChainRetriever interface {
 	GetETHRemoveBridgeSigEpoch() uint64
 	GetBCHeightBreakPointPortalV3() uint64
+	GetBCHeightBreakPointEquivocation() uint64
+	GetBCHeightBreakPointPDERoutedTrade() uint64
+	GetBCHeightBreakPointPDETradeV2() uint64
 	GetStakingAmountShard() uint64
 	GetCentralizedWebsitePaymentAddress(uint64) string
 	GetBeaconHeightBreakPointBurnAddr() uint64
 	GetBurningAddress(blockHeight uint64) string
 	GetTransactionByHash(common.Hash) (byte, common.Hash, uint64, int, Transaction, error)
 	ListPrivacyTokenAndBridgeTokenAndPRVByShardID(byte) ([]common.Hash, error)
-	GetBNBChainID() string
 	GetBTCChainID() string
-	GetBTCHeaderChain() *btcrelaying.BlockChain
-	GetPortalFeederAddress() string
+	IsPortalToken(beaconHeight uint64, tokenIDStr string) bool
+	IsValidPortalRemoteAddress(tokenIDStr string, remoteAddress string, beaconHeight uint64) (bool, error)
+	GetMinAmountPortalToken(tokenIDStr string, beaconHeight uint64) (uint64, error)
+	GetPortalFeederAddresses(beaconHeight uint64) []string
 	GetFixedRandomForShardIDCommitment(beaconHeight uint64) *privacy.Scalar
 	GetSupportedCollateralTokenIDs(beaconHeight uint64) []string
 	GetPortalETHContractAddrStr() string
@@ -254,33 +256,11 @@ func ConvertPrivacyTokenToNativeToken(
 	)
 }
 
-func IsValidPortalRemoteAddress(
-	bcr ChainRetriever,
-	remoteAddress string,
-	tokenID string,
-) bool {
-	if tokenID == common.PortalBNBIDStr {
-		return bnb.IsValidBNBAddress(remoteAddress, bcr.GetBNBChainID())
-	} else if tokenID == common.PortalBTCIDStr {
-		btcHeaderChain := bcr.GetBTCHeaderChain()
-		if btcHeaderChain == nil {
-			return false
-		}
-		return btcHeaderChain.IsBTCAddressValid(remoteAddress)
-	}
-	return false
-}
-
-func IsPortalToken(tokenIDStr string) bool {
-	isExisted, _ := common.SliceExists(common.PortalSupportedIncTokenIDs, tokenIDStr)
-	return isExisted
-}
-
 func IsSupportedTokenCollateralV3(bcr ChainRetriever, beaconHeight uint64, externalTokenID string) bool {
 	isSupported, _ := common.SliceExists(bcr.GetSupportedCollateralTokenIDs(beaconHeight), externalTokenID)
 	return isSupported
 }
 
 func IsPortalExchangeRateToken(tokenIDStr string, bcr ChainRetriever, beaconHeight uint64) bool {
-	return IsPortalToken(tokenIDStr) || tokenIDStr == common.PRVIDStr || IsSupportedTokenCollateralV3(bcr, beaconHeight, tokenIDStr)
+	return bcr.IsPortalToken(beaconHeight, tokenIDStr) || tokenIDStr == common.PRVIDStr || IsSupportedTokenCollateralV3(bcr, beaconHeight, tokenIDStr)
 }
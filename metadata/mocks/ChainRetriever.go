@@ -4,7 +4,6 @@ package mocks
 
 import (
 	common "github.com/incognitochain/incognito-chain/common"
-	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
 
 	metadata "github.com/incognitochain/incognito-chain/metadata"
 
@@ -18,8 +17,8 @@ type ChainRetriever struct {
 	mock.Mock
 }
 
-// GetBNBChainID provides a mock function with given fields:
-func (_m *ChainRetriever) GetBNBChainID() string {
+// GetBTCChainID provides a mock function with given fields:
+func (_m *ChainRetriever) GetBTCChainID() string {
 	ret := _m.Called()
 
 	var r0 string
@@ -32,31 +31,57 @@ func (_m *ChainRetriever) GetBNBChainID() string {
 	return r0
 }
 
-// GetBTCChainID provides a mock function with given fields:
-func (_m *ChainRetriever) GetBTCChainID() string {
+// GetBCHeightBreakPointEquivocation provides a mock function with given fields:
+func (_m *ChainRetriever) GetBCHeightBreakPointEquivocation() uint64 {
 	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(uint64)
 	}
 
 	return r0
 }
 
-// GetBTCHeaderChain provides a mock function with given fields:
-func (_m *ChainRetriever) GetBTCHeaderChain() *btcrelaying.BlockChain {
+// GetBCHeightBreakPointPortalV3 provides a mock function with given fields:
+func (_m *ChainRetriever) GetBCHeightBreakPointPortalV3() uint64 {
 	ret := _m.Called()
 
-	var r0 *btcrelaying.BlockChain
-	if rf, ok := ret.Get(0).(func() *btcrelaying.BlockChain); ok {
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*btcrelaying.BlockChain)
-		}
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetBCHeightBreakPointPDERoutedTrade provides a mock function with given fields:
+func (_m *ChainRetriever) GetBCHeightBreakPointPDERoutedTrade() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetBCHeightBreakPointPDETradeV2 provides a mock function with given fields:
+func (_m *ChainRetriever) GetBCHeightBreakPointPDETradeV2() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
 	}
 
 	return r0
@@ -104,6 +129,20 @@ func (_m *ChainRetriever) GetCentralizedWebsitePaymentAddress(_a0 uint64) string
 	return r0
 }
 
+// GetETHRemoveBridgeSigEpoch provides a mock function with given fields:
+func (_m *ChainRetriever) GetETHRemoveBridgeSigEpoch() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // GetFixedRandomForShardIDCommitment provides a mock function with given fields: beaconHeight
 func (_m *ChainRetriever) GetFixedRandomForShardIDCommitment(beaconHeight uint64) *privacy.Scalar {
 	ret := _m.Called(beaconHeight)
@@ -120,8 +159,45 @@ func (_m *ChainRetriever) GetFixedRandomForShardIDCommitment(beaconHeight uint64
 	return r0
 }
 
-// GetPortalFeederAddress provides a mock function with given fields:
-func (_m *ChainRetriever) GetPortalFeederAddress() string {
+// GetMinAmountPortalToken provides a mock function with given fields: tokenIDStr, beaconHeight
+func (_m *ChainRetriever) GetMinAmountPortalToken(tokenIDStr string, beaconHeight uint64) (uint64, error) {
+	ret := _m.Called(tokenIDStr, beaconHeight)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(string, uint64) uint64); ok {
+		r0 = rf(tokenIDStr, beaconHeight)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64) error); ok {
+		r1 = rf(tokenIDStr, beaconHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPortalFeederAddresses provides a mock function with given fields: beaconHeight
+func (_m *ChainRetriever) GetPortalFeederAddresses(beaconHeight uint64) []string {
+	ret := _m.Called(beaconHeight)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uint64) []string); ok {
+		r0 = rf(beaconHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetPortalETHContractAddrStr provides a mock function with given fields:
+func (_m *ChainRetriever) GetPortalETHContractAddrStr() string {
 	ret := _m.Called()
 
 	var r0 string
@@ -148,6 +224,22 @@ func (_m *ChainRetriever) GetStakingAmountShard() uint64 {
 	return r0
 }
 
+// GetSupportedCollateralTokenIDs provides a mock function with given fields: beaconHeight
+func (_m *ChainRetriever) GetSupportedCollateralTokenIDs(beaconHeight uint64) []string {
+	ret := _m.Called(beaconHeight)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uint64) []string); ok {
+		r0 = rf(beaconHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // GetTransactionByHash provides a mock function with given fields: _a0
 func (_m *ChainRetriever) GetTransactionByHash(_a0 common.Hash) (byte, common.Hash, uint64, int, metadata.Transaction, error) {
 	ret := _m.Called(_a0)
@@ -201,6 +293,41 @@ func (_m *ChainRetriever) GetTransactionByHash(_a0 common.Hash) (byte, common.Ha
 	return r0, r1, r2, r3, r4, r5
 }
 
+// IsPortalToken provides a mock function with given fields: beaconHeight, tokenIDStr
+func (_m *ChainRetriever) IsPortalToken(beaconHeight uint64, tokenIDStr string) bool {
+	ret := _m.Called(beaconHeight, tokenIDStr)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uint64, string) bool); ok {
+		r0 = rf(beaconHeight, tokenIDStr)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsValidPortalRemoteAddress provides a mock function with given fields: tokenIDStr, remoteAddress, beaconHeight
+func (_m *ChainRetriever) IsValidPortalRemoteAddress(tokenIDStr string, remoteAddress string, beaconHeight uint64) (bool, error) {
+	ret := _m.Called(tokenIDStr, remoteAddress, beaconHeight)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, uint64) bool); ok {
+		r0 = rf(tokenIDStr, remoteAddress, beaconHeight)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, uint64) error); ok {
+		r1 = rf(tokenIDStr, remoteAddress, beaconHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListPrivacyTokenAndBridgeTokenAndPRVByShardID provides a mock function with given fields: _a0
 func (_m *ChainRetriever) ListPrivacyTokenAndBridgeTokenAndPRVByShardID(_a0 byte) ([]common.Hash, error) {
 	ret := _m.Called(_a0)
@@ -27,9 +27,10 @@ type PDECrossPoolTradeRequest struct {
 }
 
 type PDECrossPoolTradeRequestAction struct {
-	Meta    PDECrossPoolTradeRequest
-	TxReqID common.Hash
-	ShardID byte
+	Meta     PDECrossPoolTradeRequest
+	TxReqID  common.Hash
+	ShardID  byte
+	Deadline *PDETradeDeadline `json:",omitempty"` // set by PDECrossPoolTradeRequestV2
 }
 
 type PDECrossPoolTradeAcceptedContent struct {
@@ -84,13 +84,15 @@ func (iRes PDECrossPoolTradeResponse) VerifyMinerCreatedTxBeforeGettingInBlock(
 		}
 		instMetaType := inst[0]
 		if instUsed[i] > 0 ||
-			instMetaType != strconv.Itoa(PDECrossPoolTradeRequestMeta) {
+			(instMetaType != strconv.Itoa(PDECrossPoolTradeRequestMeta) && instMetaType != strconv.Itoa(PDERoutedTradeRequestMeta)) {
 			continue
 		}
 		instTradeStatus := inst[2]
 		if instTradeStatus != iRes.TradeStatus ||
 			(instTradeStatus != common.PDECrossPoolTradeFeeRefundChainStatus &&
 				instTradeStatus != common.PDECrossPoolTradeSellingTokenRefundChainStatus &&
+				instTradeStatus != common.PDECrossPoolTradeExpiredFeeRefundChainStatus &&
+				instTradeStatus != common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus &&
 				instTradeStatus != common.PDECrossPoolTradeAcceptedChainStatus) {
 			continue
 		}
@@ -100,8 +102,7 @@ func (iRes PDECrossPoolTradeResponse) VerifyMinerCreatedTxBeforeGettingInBlock(
 		var receiverAddrStrFromInst string
 		var receivingAmtFromInst uint64
 		var receivingTokenIDStr string
-		if instTradeStatus == common.PDECrossPoolTradeFeeRefundChainStatus ||
-			instTradeStatus == common.PDECrossPoolTradeSellingTokenRefundChainStatus {
+		if instTradeStatus != common.PDECrossPoolTradeAcceptedChainStatus {
 			contentBytes := []byte(inst[3])
 			var pdeRefundCrossPoolTrade PDERefundCrossPoolTrade
 			err := json.Unmarshal(contentBytes, &pdeRefundCrossPoolTrade)
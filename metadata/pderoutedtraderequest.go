@@ -0,0 +1,158 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// PDERoutedTradeRequest - privacy dex trade along an explicit path of pools, e.g. found by getpdebestroute.
+// Path holds the token ids from TokenIDToSellStr to TokenIDToBuyStr, each two consecutive tokens are a pool.
+// It is paid and refunded like PDECrossPoolTradeRequest
+type PDERoutedTradeRequest struct {
+	TokenIDToBuyStr     string
+	TokenIDToSellStr    string
+	Path                []string
+	SellAmount          uint64 // must be equal to vout value
+	MinAcceptableAmount uint64
+	TradingFee          uint64
+	TraderAddressStr    string
+	MetadataBase
+}
+
+type PDERoutedTradeRequestAction struct {
+	Meta    PDERoutedTradeRequest
+	TxReqID common.Hash
+	ShardID byte
+}
+
+func NewPDERoutedTradeRequest(
+	tokenIDToBuyStr string,
+	tokenIDToSellStr string,
+	path []string,
+	sellAmount uint64,
+	minAcceptableAmount uint64,
+	tradingFee uint64,
+	traderAddressStr string,
+	metaType int,
+) (*PDERoutedTradeRequest, error) {
+	metadataBase := MetadataBase{
+		Type: metaType,
+	}
+	pdeRoutedTradeRequest := &PDERoutedTradeRequest{
+		TokenIDToBuyStr:     tokenIDToBuyStr,
+		TokenIDToSellStr:    tokenIDToSellStr,
+		Path:                path,
+		SellAmount:          sellAmount,
+		MinAcceptableAmount: minAcceptableAmount,
+		TradingFee:          tradingFee,
+		TraderAddressStr:    traderAddressStr,
+	}
+	pdeRoutedTradeRequest.MetadataBase = metadataBase
+	return pdeRoutedTradeRequest, nil
+}
+
+// ToCrossPoolTradeRequest - same trade without its path, the payment of both is checked the same way
+func (pc PDERoutedTradeRequest) ToCrossPoolTradeRequest() PDECrossPoolTradeRequest {
+	return PDECrossPoolTradeRequest{
+		TokenIDToBuyStr:     pc.TokenIDToBuyStr,
+		TokenIDToSellStr:    pc.TokenIDToSellStr,
+		SellAmount:          pc.SellAmount,
+		MinAcceptableAmount: pc.MinAcceptableAmount,
+		TradingFee:          pc.TradingFee,
+		TraderAddressStr:    pc.TraderAddressStr,
+		MetadataBase:        pc.MetadataBase,
+	}
+}
+
+// ValidatePDETradePath - path goes from tokenIDToSellStr to tokenIDToBuyStr through at most PDEMaxTradeHops pools,
+// without going through a token twice
+func ValidatePDETradePath(path []string, tokenIDToSellStr string, tokenIDToBuyStr string) error {
+	if len(path) < 2 || len(path) > PDEMaxTradeHops+1 {
+		return fmt.Errorf("path must go through 1 to %v pools, got %v tokens", PDEMaxTradeHops, len(path))
+	}
+	if path[0] != tokenIDToSellStr || path[len(path)-1] != tokenIDToBuyStr {
+		return errors.New("path must start with TokenIDToSellStr and end with TokenIDToBuyStr")
+	}
+	seen := map[string]bool{}
+	for _, tokenIDStr := range path {
+		if _, err := (common.Hash{}).NewHashFromStr(tokenIDStr); err != nil {
+			return fmt.Errorf("invalid token id %v in path", tokenIDStr)
+		}
+		if seen[tokenIDStr] {
+			return fmt.Errorf("token %v is twice in path", tokenIDStr)
+		}
+		seen[tokenIDStr] = true
+	}
+	return nil
+}
+
+// ValidateTxWithBlockChain - every pool of the path exists in the beacon view, pools are checked again by beacon
+// when the trade is processed since they may be withdrawn meanwhile
+func (pc PDERoutedTradeRequest) ValidateTxWithBlockChain(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, transactionStateDB *statedb.StateDB) (bool, error) {
+	if _, ok := tx.GetMetadata().(*PDERoutedTradeRequest); !ok {
+		return false, NewMetadataTxError(PDERoutedTradeRequestTypeAssertionError, fmt.Errorf("Expect *PDERoutedTradeRequest type but get %+v", reflect.TypeOf(tx.GetMetadata())))
+	}
+	beaconHeight := shardViewRetriever.GetBeaconHeight()
+	for i := 0; i+1 < len(pc.Path); i++ {
+		if _, err := statedb.GetPDEPoolForPair(beaconViewRetriever.GetBeaconFeatureStateDB(), beaconHeight, pc.Path[i], pc.Path[i+1]); err != nil {
+			return false, NewMetadataTxError(PDERoutedTradeRequestPoolNotFoundError, err)
+		}
+	}
+	return true, nil
+}
+
+func (pc PDERoutedTradeRequest) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error) {
+	breakPoint := chainRetriever.GetBCHeightBreakPointPDERoutedTrade()
+	if getValidationBeaconHeight(shardViewRetriever, beaconHeight) < breakPoint {
+		return false, false, NewMetadataTxError(PDERoutedTradeRequestNotActivatedError, fmt.Errorf("PDE routed trade request is accepted from beacon height %v", breakPoint))
+	}
+	if err := ValidatePDETradePath(pc.Path, pc.TokenIDToSellStr, pc.TokenIDToBuyStr); err != nil {
+		return false, false, NewMetadataTxError(PDERoutedTradeRequestInvalidPathError, err)
+	}
+	return pc.ToCrossPoolTradeRequest().ValidateSanityData(chainRetriever, shardViewRetriever, beaconViewRetriever, beaconHeight, tx)
+}
+
+func (pc PDERoutedTradeRequest) ValidateMetadataByItself() bool {
+	return pc.Type == PDERoutedTradeRequestMeta
+}
+
+func (pc PDERoutedTradeRequest) Hash() *common.Hash {
+	record := pc.MetadataBase.Hash().String()
+	record += pc.TokenIDToBuyStr
+	record += pc.TokenIDToSellStr
+	record += strings.Join(pc.Path, ",")
+	record += pc.TraderAddressStr
+	record += strconv.FormatUint(pc.SellAmount, 10)
+	record += strconv.FormatUint(pc.MinAcceptableAmount, 10)
+	record += strconv.FormatUint(pc.TradingFee, 10)
+	// final hash
+	hash := common.HashH([]byte(record))
+	return &hash
+}
+
+func (pc *PDERoutedTradeRequest) BuildReqActions(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, shardHeight uint64) ([][]string, error) {
+	actionContent := PDERoutedTradeRequestAction{
+		Meta:    *pc,
+		TxReqID: *tx.Hash(),
+		ShardID: shardID,
+	}
+	actionContentBytes, err := json.Marshal(actionContent)
+	if err != nil {
+		return [][]string{}, err
+	}
+	actionContentBase64Str := base64.StdEncoding.EncodeToString(actionContentBytes)
+	action := []string{strconv.Itoa(pc.Type), actionContentBase64Str}
+	return [][]string{action}, nil
+}
+
+func (pc *PDERoutedTradeRequest) CalculateSize() uint64 {
+	return calculateSize(pc)
+}
@@ -25,9 +25,10 @@ type PDETradeRequest struct {
 }
 
 type PDETradeRequestAction struct {
-	Meta    PDETradeRequest
-	TxReqID common.Hash
-	ShardID byte
+	Meta     PDETradeRequest
+	TxReqID  common.Hash
+	ShardID  byte
+	Deadline *PDETradeDeadline `json:",omitempty"` // set by PDETradeRequestV2
 }
 
 type TokenPoolValueOperation struct {
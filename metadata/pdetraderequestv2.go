@@ -0,0 +1,193 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// PDETradeDeadline - latest beacon height and beacon timestamp a pde trade can be executed at, 0 means no bound.
+// Beacon refunds the trade with an expired status after that
+type PDETradeDeadline struct {
+	DeadlineBeaconHeight uint64
+	DeadlineTimestamp    int64
+}
+
+// IsExpired - whether the trade can not be executed in the beacon block at beaconHeight
+// whose previous block was built at beaconTimestamp
+func (d PDETradeDeadline) IsExpired(beaconHeight uint64, beaconTimestamp int64) bool {
+	if d.DeadlineBeaconHeight > 0 && beaconHeight > d.DeadlineBeaconHeight {
+		return true
+	}
+	return d.DeadlineTimestamp > 0 && beaconTimestamp > d.DeadlineTimestamp
+}
+
+func (d PDETradeDeadline) validate() error {
+	if d.DeadlineTimestamp < 0 {
+		return errors.New("DeadlineTimestamp should not be negative")
+	}
+	if d.DeadlineBeaconHeight == 0 && d.DeadlineTimestamp == 0 {
+		return errors.New("Either DeadlineBeaconHeight or DeadlineTimestamp should be set")
+	}
+	return nil
+}
+
+func (d PDETradeDeadline) hashRecord() string {
+	return strconv.FormatUint(d.DeadlineBeaconHeight, 10) + strconv.FormatInt(d.DeadlineTimestamp, 10)
+}
+
+// PDETradeRequestV2 - privacy dex trade with a deadline
+type PDETradeRequestV2 struct {
+	PDETradeRequest
+	PDETradeDeadline
+}
+
+func NewPDETradeRequestV2(
+	tokenIDToBuyStr string,
+	tokenIDToSellStr string,
+	sellAmount uint64,
+	minAcceptableAmount uint64,
+	tradingFee uint64,
+	traderAddressStr string,
+	deadline PDETradeDeadline,
+	metaType int,
+) (*PDETradeRequestV2, error) {
+	pdeTradeRequest, _ := NewPDETradeRequest(
+		tokenIDToBuyStr,
+		tokenIDToSellStr,
+		sellAmount,
+		minAcceptableAmount,
+		tradingFee,
+		traderAddressStr,
+		metaType,
+	)
+	return &PDETradeRequestV2{
+		PDETradeRequest:  *pdeTradeRequest,
+		PDETradeDeadline: deadline,
+	}, nil
+}
+
+func (pc PDETradeRequestV2) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error) {
+	breakPoint := chainRetriever.GetBCHeightBreakPointPDETradeV2()
+	if getValidationBeaconHeight(shardViewRetriever, beaconHeight) < breakPoint {
+		return false, false, NewMetadataTxError(PDETradeRequestV2NotActivatedError, fmt.Errorf("PDE trade request with deadline is accepted from beacon height %v", breakPoint))
+	}
+	if err := pc.PDETradeDeadline.validate(); err != nil {
+		return false, false, NewMetadataTxError(PDETradeRequestV2InvalidDeadlineError, err)
+	}
+	return pc.PDETradeRequest.ValidateSanityData(chainRetriever, shardViewRetriever, beaconViewRetriever, beaconHeight, tx)
+}
+
+func (pc PDETradeRequestV2) ValidateMetadataByItself() bool {
+	return pc.Type == PDETradeRequestMetaV2
+}
+
+func (pc PDETradeRequestV2) Hash() *common.Hash {
+	record := pc.PDETradeRequest.Hash().String()
+	record += pc.PDETradeDeadline.hashRecord()
+	// final hash
+	hash := common.HashH([]byte(record))
+	return &hash
+}
+
+// BuildReqActions - the action is processed by beacon as a PDETradeRequestMeta one carrying the deadline
+func (pc *PDETradeRequestV2) BuildReqActions(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, shardHeight uint64) ([][]string, error) {
+	deadline := pc.PDETradeDeadline
+	actionContent := PDETradeRequestAction{
+		Meta:     pc.PDETradeRequest,
+		TxReqID:  *tx.Hash(),
+		ShardID:  shardID,
+		Deadline: &deadline,
+	}
+	actionContentBytes, err := json.Marshal(actionContent)
+	if err != nil {
+		return [][]string{}, err
+	}
+	actionContentBase64Str := base64.StdEncoding.EncodeToString(actionContentBytes)
+	action := []string{strconv.Itoa(PDETradeRequestMetaV2), actionContentBase64Str}
+	return [][]string{action}, nil
+}
+
+func (pc *PDETradeRequestV2) CalculateSize() uint64 {
+	return calculateSize(pc)
+}
+
+// PDECrossPoolTradeRequestV2 - privacy dex cross pool trade with a deadline
+type PDECrossPoolTradeRequestV2 struct {
+	PDECrossPoolTradeRequest
+	PDETradeDeadline
+}
+
+func NewPDECrossPoolTradeRequestV2(
+	tokenIDToBuyStr string,
+	tokenIDToSellStr string,
+	sellAmount uint64,
+	minAcceptableAmount uint64,
+	tradingFee uint64,
+	traderAddressStr string,
+	deadline PDETradeDeadline,
+	metaType int,
+) (*PDECrossPoolTradeRequestV2, error) {
+	pdeCrossPoolTradeRequest, _ := NewPDECrossPoolTradeRequest(
+		tokenIDToBuyStr,
+		tokenIDToSellStr,
+		sellAmount,
+		minAcceptableAmount,
+		tradingFee,
+		traderAddressStr,
+		metaType,
+	)
+	return &PDECrossPoolTradeRequestV2{
+		PDECrossPoolTradeRequest: *pdeCrossPoolTradeRequest,
+		PDETradeDeadline:         deadline,
+	}, nil
+}
+
+func (pc PDECrossPoolTradeRequestV2) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error) {
+	breakPoint := chainRetriever.GetBCHeightBreakPointPDETradeV2()
+	if getValidationBeaconHeight(shardViewRetriever, beaconHeight) < breakPoint {
+		return false, false, NewMetadataTxError(PDETradeRequestV2NotActivatedError, fmt.Errorf("PDE trade request with deadline is accepted from beacon height %v", breakPoint))
+	}
+	if err := pc.PDETradeDeadline.validate(); err != nil {
+		return false, false, NewMetadataTxError(PDETradeRequestV2InvalidDeadlineError, err)
+	}
+	return pc.PDECrossPoolTradeRequest.ValidateSanityData(chainRetriever, shardViewRetriever, beaconViewRetriever, beaconHeight, tx)
+}
+
+func (pc PDECrossPoolTradeRequestV2) ValidateMetadataByItself() bool {
+	return pc.Type == PDECrossPoolTradeRequestMetaV2
+}
+
+func (pc PDECrossPoolTradeRequestV2) Hash() *common.Hash {
+	record := pc.PDECrossPoolTradeRequest.Hash().String()
+	record += pc.PDETradeDeadline.hashRecord()
+	// final hash
+	hash := common.HashH([]byte(record))
+	return &hash
+}
+
+// BuildReqActions - the action is processed by beacon as a PDECrossPoolTradeRequestMeta one carrying the deadline
+func (pc *PDECrossPoolTradeRequestV2) BuildReqActions(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, shardHeight uint64) ([][]string, error) {
+	deadline := pc.PDETradeDeadline
+	actionContent := PDECrossPoolTradeRequestAction{
+		Meta:     pc.PDECrossPoolTradeRequest,
+		TxReqID:  *tx.Hash(),
+		ShardID:  shardID,
+		Deadline: &deadline,
+	}
+	actionContentBytes, err := json.Marshal(actionContent)
+	if err != nil {
+		return [][]string{}, err
+	}
+	actionContentBase64Str := base64.StdEncoding.EncodeToString(actionContentBytes)
+	action := []string{strconv.Itoa(PDECrossPoolTradeRequestMetaV2), actionContentBase64Str}
+	return [][]string{action}, nil
+}
+
+func (pc *PDECrossPoolTradeRequestV2) CalculateSize() uint64 {
+	return calculateSize(pc)
+}
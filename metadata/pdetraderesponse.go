@@ -78,7 +78,10 @@ func (iRes PDETradeResponse) VerifyMinerCreatedTxBeforeGettingInBlock(txsInBlock
 			continue
 		}
 		instTradeStatus := inst[2]
-		if instTradeStatus != iRes.TradeStatus || (instTradeStatus != common.PDETradeRefundChainStatus && instTradeStatus != common.PDETradeAcceptedChainStatus) {
+		if instTradeStatus != iRes.TradeStatus ||
+			(instTradeStatus != common.PDETradeRefundChainStatus &&
+				instTradeStatus != common.PDETradeExpiredRefundChainStatus &&
+				instTradeStatus != common.PDETradeAcceptedChainStatus) {
 			continue
 		}
 
@@ -87,7 +90,7 @@ func (iRes PDETradeResponse) VerifyMinerCreatedTxBeforeGettingInBlock(txsInBlock
 		var receiverAddrStrFromInst string
 		var receivingAmtFromInst uint64
 		var receivingTokenIDStr string
-		if instTradeStatus == common.PDETradeRefundChainStatus {
+		if instTradeStatus == common.PDETradeRefundChainStatus || instTradeStatus == common.PDETradeExpiredRefundChainStatus {
 			contentBytes, err := base64.StdEncoding.DecodeString(inst[3])
 			if err != nil {
 				Logger.log.Error("WARNING - VALIDATION: an error occured while parsing instruction content: ", err)
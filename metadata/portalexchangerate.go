@@ -40,6 +40,28 @@ func NewExchangeRatesRequestStatus(status byte, senderAddress string, rates []*E
 	return &ExchangeRatesRequestStatus{Status: status, SenderAddress: senderAddress, Rates: rates}
 }
 
+// ExchangeRateFeederSubmission - one rate of an accepted exchange rates request kept in the oracle window
+type ExchangeRateFeederSubmission struct {
+	FeederAddress string
+	Rate          uint64
+	BeaconHeight  uint64
+	TxReqID       string
+}
+
+// ExchangeRateOracleState - feeder submissions within the sliding window
+// and the latest beacon height each token's final rate reached feeder quorum at
+type ExchangeRateOracleState struct {
+	Submissions       map[string][]ExchangeRateFeederSubmission // key: pTokenID
+	LastQuorumHeights map[string]uint64                         // key: pTokenID
+}
+
+func NewExchangeRateOracleState() *ExchangeRateOracleState {
+	return &ExchangeRateOracleState{
+		Submissions:       map[string][]ExchangeRateFeederSubmission{},
+		LastQuorumHeights: map[string]uint64{},
+	}
+}
+
 func NewPortalExchangeRates(metaType int, senderAddress string, currency []*ExchangeRateInfo) (*PortalExchangeRates, error) {
 	metadataBase := MetadataBase{Type: metaType}
 
@@ -73,9 +95,9 @@ func (portalExchangeRates PortalExchangeRates) ValidateTxWithBlockChain(
 }
 
 func (portalExchangeRates PortalExchangeRates) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, txr Transaction) (bool, bool, error) {
-	feederAddress := chainRetriever.GetPortalFeederAddress()
-	if portalExchangeRates.SenderAddress != feederAddress {
-		return false, false, fmt.Errorf("Sender must be feeder's address %v\n", feederAddress)
+	feederAddresses := chainRetriever.GetPortalFeederAddresses(beaconHeight)
+	if isFeeder, _ := common.SliceExists(feederAddresses, portalExchangeRates.SenderAddress); !isFeeder {
+		return false, false, fmt.Errorf("Sender must be one of feeder's addresses %v\n", feederAddresses)
 	}
 
 	keyWallet, err := wallet.Base58CheckDeserialize(portalExchangeRates.SenderAddress)
@@ -140,7 +140,7 @@ func (req PortalTopUpWaitingPortingRequestV3) ValidateSanityData(chainRetriever
 	}
 
 	// check PortalTokenID
-	if !IsPortalToken(req.PortalTokenID) {
+	if !chainRetriever.IsPortalToken(beaconHeight, req.PortalTokenID) {
 		return false, false, errors.New("TokenID in remote address is invalid")
 	}
 
@@ -108,7 +108,16 @@ func (rh RelayingHeader) ValidateSanityData(chainRetriever ChainRetriever, shard
 }
 
 func (rh RelayingHeader) ValidateMetadataByItself() bool {
-	return rh.Type == RelayingBNBHeaderMeta || rh.Type == RelayingBTCHeaderMeta
+	return IsRelayingHeaderMeta(rh.Type)
+}
+
+// IsRelayingUTXOHeaderMeta - meta type is reserved for a header chain of UTXO portal tokens defined by params
+func IsRelayingUTXOHeaderMeta(metaType int) bool {
+	return metaType >= RelayingUTXOHeaderMetaMin && metaType <= RelayingUTXOHeaderMetaMax
+}
+
+func IsRelayingHeaderMeta(metaType int) bool {
+	return metaType == RelayingBNBHeaderMeta || metaType == RelayingBTCHeaderMeta || IsRelayingUTXOHeaderMeta(metaType)
 }
 
 func (rh RelayingHeader) Hash() *common.Hash {
@@ -0,0 +1,263 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+// evidence types
+const (
+	EquivocationVote    = "vote"
+	EquivocationPropose = "propose"
+)
+
+// EquivocationBeaconChainID - chain id of beacon chain in equivocation evidence
+const EquivocationBeaconChainID = -1
+
+// SignedHeader - a block header together with the signature of the validator over that block
+type SignedHeader struct {
+	// json of ShardHeader or BeaconHeader
+	Header json.RawMessage
+	// vote signature
+	BLS          []byte
+	BRI          []byte
+	Confirmation []byte
+	// propose signature
+	ProducerSig []byte
+}
+
+// EquivocationEvidence - two signed messages of one validator for different blocks in the same timeslot
+type EquivocationEvidence struct {
+	Type      string
+	ChainID   int
+	Validator string // base58 of bls mining key
+	Messages  []SignedHeader
+}
+
+func (evidence EquivocationEvidence) ValidateSanity() error {
+	if evidence.Type != EquivocationVote && evidence.Type != EquivocationPropose {
+		return fmt.Errorf("invalid evidence type %v", evidence.Type)
+	}
+	if evidence.ChainID < EquivocationBeaconChainID || evidence.ChainID >= common.MaxShardNumber {
+		return fmt.Errorf("invalid evidence chain id %v", evidence.ChainID)
+	}
+	if evidence.Validator == "" {
+		return errors.New("evidence validator is empty")
+	}
+	if len(evidence.Messages) != 2 {
+		return fmt.Errorf("evidence should contain 2 messages, got %v", len(evidence.Messages))
+	}
+	for _, msg := range evidence.Messages {
+		if len(msg.Header) == 0 {
+			return errors.New("evidence message has no header")
+		}
+		switch evidence.Type {
+		case EquivocationVote:
+			if len(msg.Confirmation) == 0 || len(msg.BLS) == 0 {
+				return errors.New("evidence vote has no signature")
+			}
+		case EquivocationPropose:
+			if len(msg.ProducerSig) == 0 {
+				return errors.New("evidence proposal has no signature")
+			}
+		}
+	}
+	if bytes.Equal(evidence.Messages[0].Header, evidence.Messages[1].Header) {
+		return errors.New("evidence messages are for the same header")
+	}
+	return nil
+}
+
+// VerifySignature - check that msg is signed by bridgePk for blockHash
+func (evidence EquivocationEvidence) VerifySignature(msg SignedHeader, blockHash common.Hash, bridgePk []byte) error {
+	var data, sig []byte
+	switch evidence.Type {
+	case EquivocationVote:
+		data = equivocationVoteConfirmationData(blockHash.String(), msg.BLS, msg.BRI)
+		sig = msg.Confirmation
+	case EquivocationPropose:
+		data = blockHash.GetBytes()
+		sig = msg.ProducerSig
+	default:
+		return fmt.Errorf("invalid evidence type %v", evidence.Type)
+	}
+	ok, err := bridgesig.Verify(bridgePk, data, sig)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature of %v for block %v", evidence.Validator, blockHash.String())
+	}
+	return nil
+}
+
+// equivocationVoteConfirmationData - same data as the confirmation signed by signer when voting
+func equivocationVoteConfirmationData(blockHash string, blsSig []byte, bridgeSig []byte) []byte {
+	data := []byte{}
+	data = append(data, blockHash...)
+	data = append(data, blsSig...)
+	data = append(data, bridgeSig...)
+	return common.HashB(data)
+}
+
+type ReportEquivocationMetadata struct {
+	MetadataBase
+	Evidence EquivocationEvidence
+}
+
+type ReportEquivocationAction struct {
+	Meta    ReportEquivocationMetadata
+	TxReqID common.Hash
+	ShardID byte
+}
+
+// ReportEquivocationContent - content of accepted/rejected instruction of an equivocation report
+type ReportEquivocationContent struct {
+	Validator          string
+	CommitteePublicKey string
+	Type               string
+	ChainID            int
+	Height             uint64
+	TimeSlot           int64
+	PunishedEpoches    uint8
+	ForceUnstake       bool
+	TxReqID            common.Hash
+}
+
+func NewReportEquivocationMetadata(evidence EquivocationEvidence, metaType int) (*ReportEquivocationMetadata, error) {
+	if metaType != ReportEquivocationMeta {
+		return nil, errors.New("invalid report equivocation type")
+	}
+	metadataBase := NewMetadataBase(metaType)
+	return &ReportEquivocationMetadata{
+		MetadataBase: *metadataBase,
+		Evidence:     evidence,
+	}, nil
+}
+
+func (meta ReportEquivocationMetadata) ValidateMetadataByItself() bool {
+	if meta.Evidence.ValidateSanity() != nil {
+		return false
+	}
+	return meta.Type == ReportEquivocationMeta
+}
+
+// ValidateTxWithBlockChain - signatures and committee membership are verified by beacon
+// against the committee at the height of the evidence
+func (meta ReportEquivocationMetadata) ValidateTxWithBlockChain(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, transactionStateDB *statedb.StateDB) (bool, error) {
+	if _, ok := tx.GetMetadata().(*ReportEquivocationMetadata); !ok {
+		return false, NewMetadataTxError(ReportEquivocationTypeAssertionError, fmt.Errorf("Expect *ReportEquivocationMetadata type but get %+v", reflect.TypeOf(tx.GetMetadata())))
+	}
+	return true, nil
+}
+
+// Have only one receiver
+// Have only one amount corresponding to receiver
+// Receiver Is Burning Address
+func (meta ReportEquivocationMetadata) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error) {
+	breakPoint := chainRetriever.GetBCHeightBreakPointEquivocation()
+	if getValidationBeaconHeight(shardViewRetriever, beaconHeight) < breakPoint {
+		return false, false, NewMetadataTxError(ReportEquivocationNotActivatedError, fmt.Errorf("Report equivocation is accepted from beacon height %v", breakPoint))
+	}
+	if tx.IsPrivacy() {
+		return false, false, errors.New("Report Equivocation Transaction Is No Privacy Transaction")
+	}
+	onlyOne, pubkey, amount := tx.GetUniqueReceiver()
+	if !onlyOne {
+		return false, false, errors.New("report equivocation Transaction Should Have 1 Output Amount crossponding to 1 Receiver")
+	}
+	burningAddress := chainRetriever.GetBurningAddress(beaconHeight)
+	keyWalletBurningAdd, err := wallet.Base58CheckDeserialize(burningAddress)
+	if err != nil {
+		return false, false, err
+	}
+	if !bytes.Equal(pubkey, keyWalletBurningAdd.KeySet.PaymentAddress.Pk) {
+		return false, false, errors.New("receiver Should be Burning Address")
+	}
+	if meta.Type != ReportEquivocationMeta || amount != ReportEquivocationAmount {
+		return false, false, errors.New("receiver amount should be zero")
+	}
+	if err := meta.Evidence.ValidateSanity(); err != nil {
+		return false, false, NewMetadataTxError(ReportEquivocationInvalidEvidenceError, err)
+	}
+	return true, true, nil
+}
+
+func (meta ReportEquivocationMetadata) Hash() *common.Hash {
+	record := meta.MetadataBase.Hash().String()
+	record += meta.Evidence.Type
+	record += strconv.Itoa(meta.Evidence.ChainID)
+	record += meta.Evidence.Validator
+	for _, msg := range meta.Evidence.Messages {
+		record += string(msg.Header)
+		record += string(msg.BLS)
+		record += string(msg.BRI)
+		record += string(msg.Confirmation)
+		record += string(msg.ProducerSig)
+	}
+	// final hash
+	hash := common.HashH([]byte(record))
+	return &hash
+}
+
+func (meta *ReportEquivocationMetadata) BuildReqActions(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, shardHeight uint64) ([][]string, error) {
+	actionContent := ReportEquivocationAction{
+		Meta:    *meta,
+		TxReqID: *tx.Hash(),
+		ShardID: shardID,
+	}
+	actionContentBytes, err := json.Marshal(actionContent)
+	if err != nil {
+		return [][]string{}, NewMetadataTxError(ReportEquivocationBuildReqActionsError, err)
+	}
+	actionContentBase64Str := base64.StdEncoding.EncodeToString(actionContentBytes)
+	action := []string{strconv.Itoa(meta.Type), actionContentBase64Str}
+	return [][]string{action}, nil
+}
+
+func (meta *ReportEquivocationMetadata) CalculateSize() uint64 {
+	return calculateSize(meta)
+}
+
+func ParseReportEquivocationAction(actionContentStr string) (*ReportEquivocationAction, error) {
+	contentBytes, err := base64.StdEncoding.DecodeString(actionContentStr)
+	if err != nil {
+		return nil, NewMetadataTxError(ReportEquivocationDecodeActionError, err)
+	}
+	var action ReportEquivocationAction
+	err = json.Unmarshal(contentBytes, &action)
+	if err != nil {
+		return nil, NewMetadataTxError(ReportEquivocationDecodeActionError, err)
+	}
+	return &action, nil
+}
+
+func ParseReportEquivocationContent(contentStr string) (*ReportEquivocationContent, error) {
+	contentBytes, err := base64.StdEncoding.DecodeString(contentStr)
+	if err != nil {
+		return nil, NewMetadataTxError(ReportEquivocationDecodeActionError, err)
+	}
+	var content ReportEquivocationContent
+	err = json.Unmarshal(contentBytes, &content)
+	if err != nil {
+		return nil, NewMetadataTxError(ReportEquivocationDecodeActionError, err)
+	}
+	if content.CommitteePublicKey != "" {
+		committeePublicKey := new(incognitokey.CommitteePublicKey)
+		if err := committeePublicKey.FromString(content.CommitteePublicKey); err != nil {
+			return nil, NewMetadataTxError(ReportEquivocationDecodeActionError, err)
+		}
+	}
+	return &content, nil
+}
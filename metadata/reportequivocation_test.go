@@ -0,0 +1,151 @@
+package metadata_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+func newEquivocationTestKey(seed string) *signatureschemes.MiningKey {
+	seedBytes := common.HashB([]byte(seed))
+	miningKey := &signatureschemes.MiningKey{
+		PriKey: map[string][]byte{},
+		PubKey: map[string][]byte{},
+	}
+	blsPriKey, blsPubKey := blsmultisig.KeyGen(seedBytes)
+	miningKey.PriKey[common.BlsConsensus] = blsmultisig.SKBytes(blsPriKey)
+	miningKey.PubKey[common.BlsConsensus] = blsmultisig.PKBytes(blsPubKey)
+	bridgePriKey, bridgePubKey := bridgesig.KeyGen(seedBytes)
+	miningKey.PriKey[common.BridgeConsensus] = bridgesig.SKBytes(&bridgePriKey)
+	miningKey.PubKey[common.BridgeConsensus] = bridgesig.PKBytes(&bridgePubKey)
+	return miningKey
+}
+
+func signEquivocationMessage(t *testing.T, s *signer.LocalSigner, evidenceType string, blockHash common.Hash, header string) metadata.SignedHeader {
+	msg := metadata.SignedHeader{Header: json.RawMessage(header)}
+	switch evidenceType {
+	case metadata.EquivocationVote:
+		sig, err := s.SignVote(&signer.VoteRequest{
+			BlockHash: blockHash,
+			Committee: [][]byte{s.GetPublicKey().MiningPubKey[common.BlsConsensus]},
+			SelfIdx:   0,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.BLS, msg.BRI, msg.Confirmation = sig.BLS, sig.BRI, sig.Confirmation
+	case metadata.EquivocationPropose:
+		sig, err := s.SignPropose(&signer.ProposeRequest{BlockHash: blockHash})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.ProducerSig = sig
+	}
+	return msg
+}
+
+func TestEquivocationEvidence_VerifySignature(t *testing.T) {
+	validator := signer.NewLocalSigner(newEquivocationTestKey("validator"))
+	other := signer.NewLocalSigner(newEquivocationTestKey("other"))
+	blockHash := common.HashH([]byte("block"))
+	otherBlockHash := common.HashH([]byte("other block"))
+	bridgePk := validator.GetPublicKey().MiningPubKey[common.BridgeConsensus]
+
+	for _, evidenceType := range []string{metadata.EquivocationVote, metadata.EquivocationPropose} {
+		evidence := metadata.EquivocationEvidence{
+			Type:      evidenceType,
+			ChainID:   0,
+			Validator: validator.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus),
+		}
+		tests := []struct {
+			name      string
+			msg       metadata.SignedHeader
+			blockHash common.Hash
+			wantErr   bool
+		}{
+			{
+				name:      evidenceType + " signed by validator",
+				msg:       signEquivocationMessage(t, validator, evidenceType, blockHash, `{"Height":1}`),
+				blockHash: blockHash,
+				wantErr:   false,
+			},
+			{
+				name:      evidenceType + " signed for other block",
+				msg:       signEquivocationMessage(t, validator, evidenceType, otherBlockHash, `{"Height":1}`),
+				blockHash: blockHash,
+				wantErr:   true,
+			},
+			{
+				name:      evidenceType + " signed by other key",
+				msg:       signEquivocationMessage(t, other, evidenceType, blockHash, `{"Height":1}`),
+				blockHash: blockHash,
+				wantErr:   true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := evidence.VerifySignature(tt.msg, tt.blockHash, bridgePk); (err != nil) != tt.wantErr {
+					t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestEquivocationEvidence_ValidateSanity(t *testing.T) {
+	validator := signer.NewLocalSigner(newEquivocationTestKey("validator"))
+	blockHash := common.HashH([]byte("block"))
+	otherBlockHash := common.HashH([]byte("other block"))
+	msg1 := signEquivocationMessage(t, validator, metadata.EquivocationPropose, blockHash, `{"Height":1}`)
+	msg2 := signEquivocationMessage(t, validator, metadata.EquivocationPropose, otherBlockHash, `{"Height":2}`)
+	validatorKey := validator.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
+	tests := []struct {
+		name     string
+		evidence metadata.EquivocationEvidence
+		wantErr  bool
+	}{
+		{
+			name:     "valid evidence",
+			evidence: metadata.EquivocationEvidence{Type: metadata.EquivocationPropose, ChainID: metadata.EquivocationBeaconChainID, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1, msg2}},
+			wantErr:  false,
+		},
+		{
+			name:     "invalid type",
+			evidence: metadata.EquivocationEvidence{Type: "commit", ChainID: 0, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1, msg2}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid chain id",
+			evidence: metadata.EquivocationEvidence{Type: metadata.EquivocationPropose, ChainID: -2, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1, msg2}},
+			wantErr:  true,
+		},
+		{
+			name:     "one message",
+			evidence: metadata.EquivocationEvidence{Type: metadata.EquivocationPropose, ChainID: 0, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1}},
+			wantErr:  true,
+		},
+		{
+			name:     "same header",
+			evidence: metadata.EquivocationEvidence{Type: metadata.EquivocationPropose, ChainID: 0, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1, msg1}},
+			wantErr:  true,
+		},
+		{
+			name:     "missing vote signature",
+			evidence: metadata.EquivocationEvidence{Type: metadata.EquivocationVote, ChainID: 0, Validator: validatorKey, Messages: []metadata.SignedHeader{msg1, msg2}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.evidence.ValidateSanity(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSanity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/peer"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -41,6 +42,7 @@ type NetSyncConfig struct {
 	BlockChain       *blockchain.BlockChain
 	ChainParam       *blockchain.Params
 	TxMemPool        *mempool.TxPool
+	TxQueue          *mempool.InboundTxQueue // rate limit and batch verify transactions from peers, nil to accept them directly
 	PubSubManager    *pubsub.PubSubManager
 	TransactionEvent pubsub.EventChannel // transaction event
 	// RoleInCommitteesEvent pubsub.EventChannel // role in committees event
@@ -198,26 +200,48 @@ out:
 	Logger.log.Debug("Block handler done")
 }
 
-func (netSync *NetSync) QueueTx(peer *peer.Peer, msg *wire.MessageTx, done chan struct{}) error {
+func (netSync *NetSync) QueueTx(peerID string, msg *wire.MessageTx, done chan struct{}) error {
 	// Don't accept more transactions if we're shutting down.
 	if atomic.LoadInt32(&netSync.shutdown) != 0 {
 		done <- struct{}{}
 		return NewNetSyncError(AlreadyShutdownError, errors.New("We're shutting down"))
 	}
+	if netSync.config.TxQueue != nil {
+		netSync.pushInboundTx(peerID, msg, msg.Transaction)
+		return nil
+	}
 	netSync.cMessage <- msg
 	return nil
 }
 
-func (netSync *NetSync) QueueTxPrivacyToken(peer *peer.Peer, msg *wire.MessageTxPrivacyToken, done chan struct{}) error {
+func (netSync *NetSync) QueueTxPrivacyToken(peerID string, msg *wire.MessageTxPrivacyToken, done chan struct{}) error {
 	// Don't accept more transactions if we're shutting down.
 	if atomic.LoadInt32(&netSync.shutdown) != 0 {
 		done <- struct{}{}
 		return NewNetSyncError(AlreadyShutdownError, errors.New("We're shutting down"))
 	}
+	if netSync.config.TxQueue != nil {
+		netSync.pushInboundTx(peerID, msg, msg.Transaction)
+		return nil
+	}
 	netSync.cMessage <- msg
 	return nil
 }
 
+// pushInboundTx - queue a transaction from peerID to be verified by TxQueue, and relay it once it enters pool
+func (netSync *NetSync) pushInboundTx(peerID string, msg wire.Message, tx metadata.Transaction) {
+	if isAdded := netSync.handleCacheTx(*tx.Hash()); isAdded {
+		Logger.log.Debugf("Transaction %+v found in cache", *tx.Hash())
+		return
+	}
+	err := netSync.config.TxQueue.Push(peerID, tx, func() {
+		netSync.relayTx(msg, *tx.Hash())
+	})
+	if err != nil {
+		Logger.log.Debug(err)
+	}
+}
+
 // QueueBlock adds the passed block message and peer to the block handling
 // queue. Responds to the done channel argument after the block message is
 // processed.
@@ -276,12 +300,7 @@ func (netSync *NetSync) handleMessageTx(msg *wire.MessageTx, beaconHeight int64)
 				metrics.TagValue:         msg.Transaction.Hash().String(),
 			})*/
 			Logger.log.Debugf("there is hash of transaction %s", hash.String())
-			err := netSync.config.Server.PushMessageToAll(msg)
-			if err != nil {
-				Logger.log.Error(err)
-			} else {
-				netSync.config.TxMemPool.MarkForwardedTransaction(*msg.Transaction.Hash())
-			}
+			netSync.relayTx(msg, *msg.Transaction.Hash())
 		}
 	}
 	Logger.log.Debug("Transaction %+v found in cache", *msg.Transaction.Hash())
@@ -300,17 +319,22 @@ func (netSync *NetSync) handleMessageTxPrivacyToken(msg *wire.MessageTxPrivacyTo
 		} else {
 			Logger.log.Debugf("Node got hash of transaction %s", hash.String())
 			// Broadcast to network
-			err := netSync.config.Server.PushMessageToAll(msg)
-			if err != nil {
-				Logger.log.Error(err)
-			} else {
-				netSync.config.TxMemPool.MarkForwardedTransaction(*msg.Transaction.Hash())
-			}
+			netSync.relayTx(msg, *msg.Transaction.Hash())
 		}
 	}
 	Logger.log.Debug("Transaction %+v found in cache", *msg.Transaction.Hash())
 }
 
+// relayTx - broadcast a transaction accepted into pool
+func (netSync *NetSync) relayTx(msg wire.Message, txHash common.Hash) {
+	err := netSync.config.Server.PushMessageToAll(msg)
+	if err != nil {
+		Logger.log.Error(err)
+	} else {
+		netSync.config.TxMemPool.MarkForwardedTransaction(txHash)
+	}
+}
+
 func (netSync *NetSync) handleMessageBFTMsg(msg *wire.MessageBFT) {
 	// go metrics.AnalyzeTimeSeriesMetricData(map[string]interface{}{
 	// 	metrics.Measurement:      metrics.HandleMessageBFTMsg,
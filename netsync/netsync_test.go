@@ -497,7 +497,6 @@ func TestNetSyncQueueTx(t *testing.T) {
 	})
 
 	netSync.config.RoleInCommittees = 0
-	pr := &peer.Peer{}
 	done := make(chan struct{})
 	rawTxBytes, _, err := base58.Base58Check{}.Decode(base58CheckDataTx)
 	if err != nil {
@@ -513,7 +512,7 @@ func TestNetSyncQueueTx(t *testing.T) {
 	go func() {
 		<-done
 	}()
-	netSync.QueueTx(pr, msg, done)
+	netSync.QueueTx("", msg, done)
 	<-time.Tick(1 * time.Second)
 	res := netSync.handleCacheTx(*msg.Transaction.Hash())
 	if res {
@@ -524,7 +523,7 @@ func TestNetSyncQueueTx(t *testing.T) {
 	}
 	// start netsyc
 	netSync.Start()
-	netSync.QueueTx(pr, msg, done)
+	netSync.QueueTx("", msg, done)
 	<-time.Tick(1 * time.Second)
 	res = netSync.handleCacheTx(*msg.Transaction.Hash())
 	if !res {
@@ -544,7 +543,6 @@ func TestNetSyncQueueTxPrivacyToken(t *testing.T) {
 	})
 
 	netSync.config.RoleInCommittees = 0
-	pr := &peer.Peer{}
 	done := make(chan struct{})
 	rawTxBytes, _, err := base58.Base58Check{}.Decode(base58CheckDataTxTokenPrivacy)
 	if err != nil {
@@ -560,7 +558,7 @@ func TestNetSyncQueueTxPrivacyToken(t *testing.T) {
 	go func() {
 		<-done
 	}()
-	netSync.QueueTxPrivacyToken(pr, msg, done)
+	netSync.QueueTxPrivacyToken("", msg, done)
 	<-time.Tick(1 * time.Second)
 	res := netSync.handleCacheTx(*msg.Transaction.Hash())
 	if res {
@@ -571,7 +569,7 @@ func TestNetSyncQueueTxPrivacyToken(t *testing.T) {
 	}
 	// start netsyc
 	netSync.Start()
-	netSync.QueueTxPrivacyToken(pr, msg, done)
+	netSync.QueueTxPrivacyToken("", msg, done)
 	<-time.Tick(1 * time.Second)
 	res = netSync.handleCacheTx(*msg.Transaction.Hash())
 	if !res {
@@ -0,0 +1,46 @@
+package pdeindexer
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnexpectedError = iota
+	InvalidIntervalError
+	InvalidPairError
+	LoadChainDataError
+	StoreIndexError
+	LoadIndexError
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	message string
+}{
+	UnexpectedError:      {-1000, "Unexpected error"},
+	InvalidIntervalError: {-1001, "Invalid candle interval, must be 1m, 1h or 1d"},
+	InvalidPairError:     {-1002, "Invalid token pair"},
+	LoadChainDataError:   {-1003, "Load beacon block or pde state error"},
+	StoreIndexError:      {-1004, "Store pde index error"},
+	LoadIndexError:       {-1005, "Load pde index error"},
+}
+
+type PDEIndexerError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e PDEIndexerError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewPDEIndexerError(key int, err error) error {
+	return &PDEIndexerError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].message),
+	}
+}
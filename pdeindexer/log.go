@@ -0,0 +1,16 @@
+package pdeindexer
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+type PDEIndexerLogger struct {
+	log common.Logger
+}
+
+func (pdeIndexerLogger *PDEIndexerLogger) Init(inst common.Logger) {
+	pdeIndexerLogger.log = inst
+}
+
+// Global instant to use
+var Logger = PDEIndexerLogger{}
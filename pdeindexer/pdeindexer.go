@@ -0,0 +1,627 @@
+package pdeindexer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/pubsub"
+)
+
+// DataDir - directory of the index database in the node data dir
+const DataDir = "pdeindex"
+
+// Intervals - length in seconds of the candles kept for every pair
+var Intervals = map[string]int64{
+	"1m": 60,
+	"1h": 60 * 60,
+	"1d": 24 * 60 * 60,
+}
+
+// ChainReader - beacon blocks and pde states the indexer reads
+type ChainReader interface {
+	GetBeaconFinalHeight() uint64
+	GetBeaconBlockByHeightV1(height uint64) (*blockchain.BeaconBlock, error)
+	GetPDEStateByHeight(beaconHeight uint64) (*blockchain.CurrentPDEState, error)
+}
+
+// Candle - prices of a pair during an interval. Prices are the amount of Token2 for one Token1 from pool values,
+// tokens of a pair are sorted by id
+type Candle struct {
+	Start   int64 // unix time the interval starts at
+	Open    float64
+	High    float64
+	Low     float64
+	Close   float64
+	Volume1 uint64 // Token1 sold and bought
+	Volume2 uint64 // Token2 sold and bought
+	Trades  int
+	Fees    uint64 // PRV trading fees of cross pool trades added to the pool
+}
+
+// Trade - trade in one pool, a cross pool trade is a trade in each pool it goes through
+type Trade struct {
+	BeaconHeight     uint64
+	Timestamp        int64
+	TxReqID          string
+	TokenIDToSellStr string
+	TokenIDToBuyStr  string
+	SellAmount       uint64
+	ReceiveAmount    uint64
+	Fee              uint64
+	Price            float64 // pool price after the trade
+}
+
+// LiquiditySnapshot - pool of a pair after a beacon block changing it
+type LiquiditySnapshot struct {
+	BeaconHeight    uint64
+	Timestamp       int64
+	Token1PoolValue uint64
+	Token2PoolValue uint64
+	Price           float64
+	Contributions   int // contributions matched into the pool by the block
+	Withdrawals     int
+}
+
+// Types of liquidity events
+const (
+	LiquidityEventContribution  = "contribution"
+	LiquidityEventWithdrawal    = "withdrawal"
+	LiquidityEventFeeWithdrawal = "feewithdrawal"
+)
+
+// LiquidityEvent - contribution, withdrawal or trading fee withdrawal request of a contributor, at the beacon block
+// that first handled it. Its status is tracked by the pde status objects of the beacon feature state
+type LiquidityEvent struct {
+	Type                  string
+	BeaconHeight          uint64
+	Timestamp             int64
+	TxReqID               string
+	PDEContributionPairID string // contributions only
+	TokenIDStr            string // contributed token
+	Token1IDStr           string // pair of withdrawals
+	Token2IDStr           string
+	Amount                uint64 // contributed amount, withdrawn shares or withdrawn trading fees
+}
+
+// pairActivity - what a beacon block did to a pair
+type pairActivity struct {
+	token1IDStr   string
+	token2IDStr   string
+	prices        []float64
+	trades        []*Trade
+	volume1       uint64
+	volume2       uint64
+	fees          uint64
+	contributions int
+	withdrawals   int
+}
+
+// Indexer - candles, trades and liquidity snapshots of PDE pairs and liquidity events of contributors,
+// built from finalized beacon blocks and kept in its own database
+type Indexer struct {
+	mtx           sync.Mutex
+	db            incdb.Database
+	chain         ChainReader
+	pubSubManager *pubsub.PubSubManager
+	quit          chan struct{}
+}
+
+func NewIndexer(db incdb.Database, chain ChainReader, pubSubManager *pubsub.PubSubManager) *Indexer {
+	return &Indexer{
+		db:            db,
+		chain:         chain,
+		pubSubManager: pubSubManager,
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start - index blocks finalized since the last run, then blocks finalized as new beacon blocks are inserted
+func (idx *Indexer) Start() {
+	var newBlockCh chan *pubsub.Message
+	if idx.pubSubManager != nil {
+		var err error
+		_, newBlockCh, err = idx.pubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+		if err != nil {
+			Logger.log.Error(err)
+		}
+	}
+	go func() {
+		for {
+			if err := idx.CatchUp(); err != nil {
+				Logger.log.Error(err)
+			}
+			select {
+			case <-idx.quit:
+				return
+			case <-newBlockCh:
+			}
+		}
+	}()
+}
+
+func (idx *Indexer) Stop() {
+	close(idx.quit)
+}
+
+// CatchUp - index beacon blocks from the last indexed one to the final view
+func (idx *Indexer) CatchUp() error {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	lastHeight, _, err := idx.LastIndexed()
+	if err != nil {
+		return err
+	}
+	finalHeight := idx.chain.GetBeaconFinalHeight()
+	for height := lastHeight + 1; height <= finalHeight; height++ {
+		select {
+		case <-idx.quit:
+			return nil
+		default:
+		}
+		if err := idx.indexHeight(height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Indexer) indexHeight(height uint64) error {
+	block, err := idx.chain.GetBeaconBlockByHeightV1(height)
+	if err != nil {
+		return NewPDEIndexerError(LoadChainDataError, err)
+	}
+	if !hasPDEInstruction(block.Body.Instructions) {
+		batch := idx.db.NewBatch()
+		if err := putLastIndexed(batch, height, block.Header.Timestamp); err != nil {
+			return err
+		}
+		return batch.Write()
+	}
+	before := &blockchain.CurrentPDEState{}
+	if height > 1 {
+		before, err = idx.chain.GetPDEStateByHeight(height - 1)
+		if err != nil {
+			return NewPDEIndexerError(LoadChainDataError, err)
+		}
+	}
+	after, err := idx.chain.GetPDEStateByHeight(height)
+	if err != nil {
+		return NewPDEIndexerError(LoadChainDataError, err)
+	}
+	return idx.IndexBeaconBlock(block, before, after)
+}
+
+func hasPDEInstruction(instructions [][]string) bool {
+	for _, inst := range instructions {
+		if len(inst) < 4 {
+			continue
+		}
+		switch inst[0] {
+		case strconv.Itoa(metadata.PDETradeRequestMeta),
+			strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta),
+			strconv.Itoa(metadata.PDERoutedTradeRequestMeta),
+			strconv.Itoa(metadata.PDEContributionMeta),
+			strconv.Itoa(metadata.PDEPRVRequiredContributionRequestMeta),
+			strconv.Itoa(metadata.PDEWithdrawalRequestMeta),
+			strconv.Itoa(metadata.PDEFeeWithdrawalRequestMeta):
+			return true
+		}
+	}
+	return false
+}
+
+// IndexBeaconBlock - index trades, contributions and withdrawals of block, with the pde states before and after it
+func (idx *Indexer) IndexBeaconBlock(block *blockchain.BeaconBlock, before *blockchain.CurrentPDEState, after *blockchain.CurrentPDEState) error {
+	height := block.Header.Height
+	timestamp := block.Header.Timestamp
+	pools := poolsByPair(before)
+	afterPools := poolsByPair(after)
+	activities := map[string]*pairActivity{}
+	activityOf := func(token1IDStr string, token2IDStr string) *pairActivity {
+		pairID, token1IDStr, token2IDStr := pairKey(token1IDStr, token2IDStr)
+		activity, ok := activities[pairID]
+		if !ok {
+			activity = &pairActivity{token1IDStr: token1IDStr, token2IDStr: token2IDStr}
+			if pool, ok := pools[pairID]; ok && poolPrice(pool) > 0 {
+				activity.prices = append(activity.prices, poolPrice(pool))
+			}
+			activities[pairID] = activity
+		}
+		return activity
+	}
+	applyTrade := func(token1IDStr string, token2IDStr string, operation1 metadata.TokenPoolValueOperation, operation2 metadata.TokenPoolValueOperation, tokenIDToBuyStr string, receiveAmount uint64, fee uint64, txReqID common.Hash) {
+		activity := activityOf(token1IDStr, token2IDStr)
+		pairID, _, _ := pairKey(token1IDStr, token2IDStr)
+		pool, ok := pools[pairID]
+		if !ok {
+			pool = &rawdbv2.PDEPoolForPair{Token1IDStr: activity.token1IDStr, Token2IDStr: activity.token2IDStr}
+			pools[pairID] = pool
+		}
+		trade := &Trade{
+			BeaconHeight:    height,
+			Timestamp:       timestamp,
+			TxReqID:         txReqID.String(),
+			TokenIDToBuyStr: tokenIDToBuyStr,
+			ReceiveAmount:   receiveAmount,
+			Fee:             fee,
+		}
+		for _, op := range []struct {
+			tokenIDStr string
+			operation  metadata.TokenPoolValueOperation
+		}{{token1IDStr, operation1}, {token2IDStr, operation2}} {
+			value := &pool.Token1PoolValue
+			if op.tokenIDStr == pool.Token2IDStr {
+				value = &pool.Token2PoolValue
+			}
+			if op.operation.Operator == "+" {
+				*value += op.operation.Value
+				trade.TokenIDToSellStr = op.tokenIDStr
+				trade.SellAmount = op.operation.Value
+			} else if *value >= op.operation.Value {
+				*value -= op.operation.Value
+			} else {
+				*value = 0
+			}
+		}
+		trade.Price = poolPrice(pool)
+		if trade.Price > 0 {
+			activity.prices = append(activity.prices, trade.Price)
+		}
+		if trade.TokenIDToSellStr == activity.token1IDStr {
+			activity.volume1 += trade.SellAmount
+			activity.volume2 += trade.ReceiveAmount
+		} else {
+			activity.volume1 += trade.ReceiveAmount
+			activity.volume2 += trade.SellAmount
+		}
+		activity.fees += fee
+		activity.trades = append(activity.trades, trade)
+	}
+
+	events := map[string]*LiquidityEvent{}
+	addEvent := func(contributorAddressStr string, event *LiquidityEvent) {
+		event.BeaconHeight = height
+		event.Timestamp = timestamp
+		key := string(liquidityEventKey(contributorAddressStr, event.TxReqID))
+		if _, found := events[key]; !found {
+			events[key] = event
+		}
+	}
+
+	contributionPairIDs := map[string]bool{}
+	withdrawalTxReqIDs := map[common.Hash]bool{}
+	for _, inst := range block.Body.Instructions {
+		if len(inst) < 4 {
+			continue
+		}
+		metaType, err := strconv.Atoi(inst[0])
+		if err != nil {
+			continue
+		}
+		switch metaType {
+		case metadata.PDETradeRequestMeta:
+			if inst[2] != common.PDETradeAcceptedChainStatus {
+				continue
+			}
+			var content metadata.PDETradeAcceptedContent
+			if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+				Logger.log.Errorf("ERROR: an error occured while unmarshaling pde trade accepted content at beacon height %v: %+v", height, err)
+				continue
+			}
+			applyTrade(content.Token1IDStr, content.Token2IDStr, content.Token1PoolValueOperation, content.Token2PoolValueOperation, content.TokenIDToBuyStr, content.ReceiveAmount, 0, content.RequestedTxID)
+
+		case metadata.PDECrossPoolTradeRequestMeta, metadata.PDERoutedTradeRequestMeta:
+			if inst[2] != common.PDECrossPoolTradeAcceptedChainStatus {
+				continue
+			}
+			var contents []metadata.PDECrossPoolTradeAcceptedContent
+			if err := json.Unmarshal([]byte(inst[3]), &contents); err != nil {
+				Logger.log.Errorf("ERROR: an error occured while unmarshaling pde cross pool trade accepted contents at beacon height %v: %+v", height, err)
+				continue
+			}
+			for _, content := range contents {
+				applyTrade(content.Token1IDStr, content.Token2IDStr, content.Token1PoolValueOperation, content.Token2PoolValueOperation, content.TokenIDToBuyStr, content.ReceiveAmount, content.AddingFee, content.RequestedTxID)
+			}
+
+		case metadata.PDEContributionMeta, metadata.PDEPRVRequiredContributionRequestMeta:
+			var pairID, tokenIDStr string
+			if inst[2] == common.PDEContributionWaitingChainStatus {
+				var content metadata.PDEWaitingContribution
+				if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+					continue
+				}
+				addEvent(content.ContributorAddressStr, newContributionEvent(content.PDEContributionPairID, content.TokenIDStr, content.ContributedAmount, content.TxReqID))
+				continue
+			} else if inst[2] == common.PDEContributionRefundChainStatus {
+				var content metadata.PDERefundContribution
+				if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+					continue
+				}
+				addEvent(content.ContributorAddressStr, newContributionEvent(content.PDEContributionPairID, content.TokenIDStr, content.ContributedAmount, content.TxReqID))
+				continue
+			} else if inst[2] == common.PDEContributionMatchedChainStatus {
+				var content metadata.PDEMatchedContribution
+				if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+					continue
+				}
+				addEvent(content.ContributorAddressStr, newContributionEvent(content.PDEContributionPairID, content.TokenIDStr, content.ContributedAmount, content.TxReqID))
+				pairID, tokenIDStr = content.PDEContributionPairID, content.TokenIDStr
+			} else if inst[2] == common.PDEContributionMatchedNReturnedChainStatus {
+				var content metadata.PDEMatchedNReturnedContribution
+				if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+					continue
+				}
+				addEvent(content.ContributorAddressStr, newContributionEvent(content.PDEContributionPairID, content.TokenIDStr, content.ActualContributedAmount+content.ReturnedContributedAmount, content.TxReqID))
+				pairID, tokenIDStr = content.PDEContributionPairID, content.TokenIDStr
+			} else {
+				continue
+			}
+			if contributionPairIDs[pairID] {
+				continue // matched and returned contribution has an instruction for each token
+			}
+			contributionPairIDs[pairID] = true
+			waitingContribution, ok := before.WaitingPDEContributions[string(rawdbv2.BuildWaitingPDEContributionKey(height-1, pairID))]
+			if !ok || waitingContribution == nil || waitingContribution.TokenIDStr == tokenIDStr {
+				continue
+			}
+			activityOf(waitingContribution.TokenIDStr, tokenIDStr).contributions++
+
+		case metadata.PDEWithdrawalRequestMeta:
+			if inst[2] == common.PDEWithdrawalRejectedChainStatus {
+				var action metadata.PDEWithdrawalRequestAction
+				if err := unmarshalAction(inst[3], &action); err != nil {
+					continue
+				}
+				addEvent(action.Meta.WithdrawerAddressStr, &LiquidityEvent{
+					Type:        LiquidityEventWithdrawal,
+					TxReqID:     action.TxReqID.String(),
+					Token1IDStr: action.Meta.WithdrawalToken1IDStr,
+					Token2IDStr: action.Meta.WithdrawalToken2IDStr,
+					Amount:      action.Meta.WithdrawalShareAmt,
+				})
+				continue
+			}
+			if inst[2] != common.PDEWithdrawalAcceptedChainStatus {
+				continue
+			}
+			var content metadata.PDEWithdrawalAcceptedContent
+			if err := json.Unmarshal([]byte(inst[3]), &content); err != nil {
+				continue
+			}
+			if withdrawalTxReqIDs[content.TxReqID] {
+				continue // withdrawal has an instruction for each token
+			}
+			withdrawalTxReqIDs[content.TxReqID] = true
+			activityOf(content.PairToken1IDStr, content.PairToken2IDStr).withdrawals++
+			addEvent(content.WithdrawerAddressStr, &LiquidityEvent{
+				Type:        LiquidityEventWithdrawal,
+				TxReqID:     content.TxReqID.String(),
+				Token1IDStr: content.PairToken1IDStr,
+				Token2IDStr: content.PairToken2IDStr,
+				Amount:      content.DeductingShares,
+			})
+
+		case metadata.PDEFeeWithdrawalRequestMeta:
+			var action metadata.PDEFeeWithdrawalRequestAction
+			if err := unmarshalAction(inst[3], &action); err != nil {
+				continue
+			}
+			addEvent(action.Meta.WithdrawerAddressStr, &LiquidityEvent{
+				Type:        LiquidityEventFeeWithdrawal,
+				TxReqID:     action.TxReqID.String(),
+				Token1IDStr: action.Meta.WithdrawalToken1IDStr,
+				Token2IDStr: action.Meta.WithdrawalToken2IDStr,
+				Amount:      action.Meta.WithdrawalFeeAmt,
+			})
+		}
+	}
+	// pools changed by the block without an instruction giving their pair
+	for pairID, pool := range afterPools {
+		beforePool, ok := pools[pairID]
+		if _, found := activities[pairID]; !found && (!ok || beforePool.Token1PoolValue != pool.Token1PoolValue || beforePool.Token2PoolValue != pool.Token2PoolValue) {
+			activityOf(pool.Token1IDStr, pool.Token2IDStr)
+		}
+	}
+
+	batch := idx.db.NewBatch()
+	pairIDs := []string{}
+	for pairID := range activities {
+		pairIDs = append(pairIDs, pairID)
+	}
+	sort.Strings(pairIDs)
+	for _, pairID := range pairIDs {
+		activity := activities[pairID]
+		if pool, ok := afterPools[pairID]; ok {
+			snapshot := &LiquiditySnapshot{
+				BeaconHeight:    height,
+				Timestamp:       timestamp,
+				Token1PoolValue: pool.Token1PoolValue,
+				Token2PoolValue: pool.Token2PoolValue,
+				Price:           poolPrice(pool),
+				Contributions:   activity.contributions,
+				Withdrawals:     activity.withdrawals,
+			}
+			if snapshot.Price > 0 {
+				activity.prices = append(activity.prices, snapshot.Price)
+			}
+			if err := idx.putLiquiditySnapshot(batch, pairID, snapshot); err != nil {
+				return err
+			}
+		}
+		if err := idx.putTrades(batch, pairID, activity.trades); err != nil {
+			return err
+		}
+		if len(activity.prices) == 0 {
+			continue
+		}
+		for interval, seconds := range Intervals {
+			if err := idx.mergeCandle(batch, interval, pairID, timestamp-timestamp%seconds, activity); err != nil {
+				return err
+			}
+		}
+	}
+	if err := idx.putLiquidityEvents(batch, events); err != nil {
+		return err
+	}
+	if err := putLastIndexed(batch, height, timestamp); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return NewPDEIndexerError(StoreIndexError, err)
+	}
+	return nil
+}
+
+func (idx *Indexer) mergeCandle(batch incdb.Batch, interval string, pairID string, start int64, activity *pairActivity) error {
+	candle := &Candle{
+		Start: start,
+		Open:  activity.prices[0],
+		High:  activity.prices[0],
+		Low:   activity.prices[0],
+	}
+	key := candleKey(interval, pairID, start)
+	if data, err := idx.db.Get(key); err == nil {
+		if err := json.Unmarshal(data, candle); err != nil {
+			return NewPDEIndexerError(LoadIndexError, err)
+		}
+	}
+	for _, price := range activity.prices {
+		if price > candle.High {
+			candle.High = price
+		}
+		if price < candle.Low {
+			candle.Low = price
+		}
+	}
+	candle.Close = activity.prices[len(activity.prices)-1]
+	candle.Volume1 += activity.volume1
+	candle.Volume2 += activity.volume2
+	candle.Trades += len(activity.trades)
+	candle.Fees += activity.fees
+	return putJSON(batch, key, candle)
+}
+
+func (idx *Indexer) putTrades(batch incdb.Batch, pairID string, trades []*Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+	for seq, trade := range trades {
+		if err := putJSON(batch, tradeKey(pairID, trade.BeaconHeight, seq), trade); err != nil {
+			return err
+		}
+	}
+	return idx.addCount(batch, tradeCountKey(pairID), uint64(len(trades)))
+}
+
+func (idx *Indexer) putLiquiditySnapshot(batch incdb.Batch, pairID string, snapshot *LiquiditySnapshot) error {
+	if err := putJSON(batch, liquidityKey(pairID, snapshot.BeaconHeight), snapshot); err != nil {
+		return err
+	}
+	return idx.addCount(batch, liquidityCountKey(pairID), 1)
+}
+
+// putLiquidityEvents - events of contributors not indexed by an earlier block
+func (idx *Indexer) putLiquidityEvents(batch incdb.Batch, events map[string]*LiquidityEvent) error {
+	for key, event := range events {
+		has, err := idx.db.Has([]byte(key))
+		if err != nil {
+			return NewPDEIndexerError(LoadIndexError, err)
+		}
+		if has {
+			continue
+		}
+		if err := putJSON(batch, []byte(key), event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Indexer) addCount(batch incdb.Batch, key []byte, n uint64) error {
+	count, err := idx.getCount(key)
+	if err != nil {
+		return err
+	}
+	return putJSON(batch, key, count+n)
+}
+
+func newContributionEvent(pairID string, tokenIDStr string, amount uint64, txReqID common.Hash) *LiquidityEvent {
+	return &LiquidityEvent{
+		Type:                  LiquidityEventContribution,
+		TxReqID:               txReqID.String(),
+		PDEContributionPairID: pairID,
+		TokenIDStr:            tokenIDStr,
+		Amount:                amount,
+	}
+}
+
+// unmarshalAction - request action of a base64 encoded instruction content
+func unmarshalAction(content string, action interface{}) error {
+	contentBytes, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contentBytes, action)
+}
+
+// poolsByPair - copy of the pools of state by pair id
+func poolsByPair(state *blockchain.CurrentPDEState) map[string]*rawdbv2.PDEPoolForPair {
+	pools := map[string]*rawdbv2.PDEPoolForPair{}
+	if state == nil {
+		return pools
+	}
+	for _, pool := range state.PDEPoolPairs {
+		if pool == nil {
+			continue
+		}
+		pairID, token1IDStr, _ := pairKey(pool.Token1IDStr, pool.Token2IDStr)
+		copied := *pool
+		if token1IDStr != pool.Token1IDStr {
+			copied = rawdbv2.PDEPoolForPair{
+				Token1IDStr:     pool.Token2IDStr,
+				Token1PoolValue: pool.Token2PoolValue,
+				Token2IDStr:     pool.Token1IDStr,
+				Token2PoolValue: pool.Token1PoolValue,
+			}
+		}
+		pools[pairID] = &copied
+	}
+	return pools
+}
+
+// pairKey - id of the pair of tokens, and its tokens sorted
+func pairKey(tokenIDStr1 string, tokenIDStr2 string) (string, string, string) {
+	if tokenIDStr2 < tokenIDStr1 {
+		tokenIDStr1, tokenIDStr2 = tokenIDStr2, tokenIDStr1
+	}
+	return tokenIDStr1 + "-" + tokenIDStr2, tokenIDStr1, tokenIDStr2
+}
+
+func poolPrice(pool *rawdbv2.PDEPoolForPair) float64 {
+	if pool == nil || pool.Token1PoolValue == 0 {
+		return 0
+	}
+	return float64(pool.Token2PoolValue) / float64(pool.Token1PoolValue)
+}
+
+func validatePair(tokenIDStr1 string, tokenIDStr2 string) (string, string, string, error) {
+	for _, tokenIDStr := range []string{tokenIDStr1, tokenIDStr2} {
+		if _, err := (common.Hash{}).NewHashFromStr(tokenIDStr); err != nil {
+			return "", "", "", NewPDEIndexerError(InvalidPairError, err)
+		}
+	}
+	if tokenIDStr1 == tokenIDStr2 {
+		return "", "", "", NewPDEIndexerError(InvalidPairError, errors.New("tokens of the pair are the same"))
+	}
+	pairID, token1IDStr, token2IDStr := pairKey(tokenIDStr1, tokenIDStr2)
+	return pairID, token1IDStr, token2IDStr, nil
+}
@@ -0,0 +1,274 @@
+package pdeindexer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTokenA = "0000000000000000000000000000000000000000000000000000000000000061"
+	testTokenB = "0000000000000000000000000000000000000000000000000000000000000062"
+)
+
+// fakeChain - beacon blocks and the pde states after them
+type fakeChain struct {
+	blocks map[uint64]*blockchain.BeaconBlock
+	states map[uint64]*blockchain.CurrentPDEState
+	final  uint64
+}
+
+func (c *fakeChain) GetBeaconFinalHeight() uint64 { return c.final }
+
+func (c *fakeChain) GetBeaconBlockByHeightV1(height uint64) (*blockchain.BeaconBlock, error) {
+	return c.blocks[height], nil
+}
+
+func (c *fakeChain) GetPDEStateByHeight(beaconHeight uint64) (*blockchain.CurrentPDEState, error) {
+	return c.states[beaconHeight], nil
+}
+
+func newTestPDEState(beaconHeight uint64, prvPool uint64, aPool uint64, a2Pool uint64, bPool uint64) *blockchain.CurrentPDEState {
+	prvIDStr := common.PRVCoinID.String()
+	return &blockchain.CurrentPDEState{
+		WaitingPDEContributions: map[string]*rawdbv2.PDEContribution{},
+		PDEPoolPairs: map[string]*rawdbv2.PDEPoolForPair{
+			string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, prvIDStr, testTokenA)):   rawdbv2.NewPDEPoolForPair(prvIDStr, prvPool, testTokenA, aPool),
+			string(rawdbv2.BuildPDEPoolForPairKey(beaconHeight, testTokenA, testTokenB)): rawdbv2.NewPDEPoolForPair(testTokenA, a2Pool, testTokenB, bPool),
+		},
+	}
+}
+
+func newTestCrossPoolTradeInst(contents []metadata.PDECrossPoolTradeAcceptedContent) []string {
+	contentBytes, _ := json.Marshal(contents)
+	return []string{strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta), "0", common.PDECrossPoolTradeAcceptedChainStatus, string(contentBytes)}
+}
+
+func newTestWithdrawalInsts(txReqID common.Hash) [][]string {
+	insts := [][]string{}
+	for _, tokenIDStr := range []string{testTokenA, testTokenB} {
+		contentBytes, _ := json.Marshal(metadata.PDEWithdrawalAcceptedContent{
+			WithdrawalTokenIDStr: tokenIDStr,
+			PairToken1IDStr:      testTokenA,
+			PairToken2IDStr:      testTokenB,
+			TxReqID:              txReqID,
+		})
+		insts = append(insts, []string{strconv.Itoa(metadata.PDEWithdrawalRequestMeta), "0", common.PDEWithdrawalAcceptedChainStatus, string(contentBytes)})
+	}
+	return insts
+}
+
+func TestIndexer(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_pdeindex_")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("leveldb", dbPath)
+	assert.Nil(t, err)
+	prvIDStr := common.PRVCoinID.String()
+	start := int64(1600000000 - 1600000000%86400)
+
+	// block 2: PRV -> A -> B trade, block 3: nothing, block 4: withdrawal from A-B, an hour later
+	sellPRV := metadata.PDECrossPoolTradeAcceptedContent{
+		TokenIDToBuyStr:          testTokenA,
+		ReceiveAmount:            1000,
+		Token1IDStr:              prvIDStr,
+		Token2IDStr:              testTokenA,
+		Token1PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "+", Value: 500},
+		Token2PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "-", Value: 1000},
+		AddingFee:                5,
+	}
+	buyB := metadata.PDECrossPoolTradeAcceptedContent{
+		TokenIDToBuyStr:          testTokenB,
+		ReceiveAmount:            990,
+		Token1IDStr:              testTokenA,
+		Token2IDStr:              testTokenB,
+		Token1PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "+", Value: 1000},
+		Token2PoolValueOperation: metadata.TokenPoolValueOperation{Operator: "-", Value: 990},
+		AddingFee:                5,
+	}
+	chain := &fakeChain{
+		blocks: map[uint64]*blockchain.BeaconBlock{
+			1: {Header: blockchain.BeaconHeader{Height: 1, Timestamp: start}},
+			2: {
+				Header: blockchain.BeaconHeader{Height: 2, Timestamp: start + 10},
+				Body:   blockchain.BeaconBody{Instructions: [][]string{newTestCrossPoolTradeInst([]metadata.PDECrossPoolTradeAcceptedContent{sellPRV, buyB})}},
+			},
+			3: {Header: blockchain.BeaconHeader{Height: 3, Timestamp: start + 20}},
+			4: {
+				Header: blockchain.BeaconHeader{Height: 4, Timestamp: start + 3600},
+				Body:   blockchain.BeaconBody{Instructions: newTestWithdrawalInsts(common.HashH([]byte("withdraw")))},
+			},
+		},
+		states: map[uint64]*blockchain.CurrentPDEState{
+			1: newTestPDEState(1, 100000, 200000, 100000, 100000),
+			2: newTestPDEState(2, 100500, 199000, 101000, 99010),
+			3: newTestPDEState(3, 100500, 199000, 101000, 99010),
+			4: newTestPDEState(4, 100500, 199000, 50500, 49505),
+		},
+		final: 4,
+	}
+	idx := NewIndexer(db, chain, nil)
+	assert.Nil(t, idx.CatchUp())
+	height, timestamp, err := idx.LastIndexed()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), height)
+	assert.Equal(t, start+3600, timestamp)
+
+	candles, err := idx.GetCandles(testTokenB, testTokenA, "1m", 0, 0, 0)
+	assert.Nil(t, err)
+	if assert.Len(t, candles, 2) {
+		assert.Equal(t, start, candles[0].Start)
+		assert.Equal(t, 1.0, candles[0].Open)
+		assert.Equal(t, 1.0, candles[0].High)
+		assert.Equal(t, 99010.0/101000, candles[0].Low)
+		assert.Equal(t, 99010.0/101000, candles[0].Close)
+		assert.Equal(t, uint64(1000), candles[0].Volume1)
+		assert.Equal(t, uint64(990), candles[0].Volume2)
+		assert.Equal(t, 1, candles[0].Trades)
+		assert.Equal(t, uint64(5), candles[0].Fees)
+		assert.Equal(t, 0, candles[1].Trades)
+	}
+	candles, err = idx.GetCandles(testTokenA, testTokenB, "1d", 0, 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, candles, 1)
+	candles, err = idx.GetCandles(testTokenA, testTokenB, "1m", start+60, 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, candles, 1)
+	_, err = idx.GetCandles(testTokenA, testTokenB, "5m", 0, 0, 0)
+	assert.NotNil(t, err)
+
+	trades, total, err := idx.GetTradeHistory(prvIDStr, testTokenA, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), total)
+	if assert.Len(t, trades, 1) {
+		assert.Equal(t, prvIDStr, trades[0].TokenIDToSellStr)
+		assert.Equal(t, uint64(500), trades[0].SellAmount)
+		assert.Equal(t, 199000.0/100500, trades[0].Price)
+	}
+
+	snapshots, total, err := idx.GetLiquidityHistory(testTokenA, testTokenB, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), total)
+	if assert.Len(t, snapshots, 2) {
+		assert.Equal(t, uint64(4), snapshots[0].BeaconHeight)
+		assert.Equal(t, 1, snapshots[0].Withdrawals)
+		assert.Equal(t, uint64(50500), snapshots[0].Token1PoolValue)
+	}
+	snapshots, _, err = idx.GetLiquidityHistory(testTokenA, testTokenB, 1, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, uint64(2), snapshots[0].BeaconHeight)
+	}
+
+	stats, err := idx.GetPairStats(testTokenB, testTokenA)
+	assert.Nil(t, err)
+	assert.Equal(t, testTokenA, stats.Token1IDStr)
+	assert.Equal(t, 1, stats.TradesIn24H)
+	assert.Equal(t, uint64(5), stats.FeesIn24H)
+	assert.Equal(t, 49505.0/50500, stats.Price)
+	assert.InDelta(t, -1.97, stats.PriceChange24H, 0.01)
+	assert.Equal(t, uint64(4), stats.Liquidity.BeaconHeight)
+}
+
+func TestIndexerLiquidityEvents(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_pdeindex_")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("leveldb", dbPath)
+	assert.Nil(t, err)
+	contributor := "12RuEdPjq4yxivzm8xPxRVHmkL74t4eAdUKPdKKhMEnpxPH3k8GEyULbwq4hjwHWmHQr7MmGBJsMpdCHsYAqNE18jipWQwciBf9yqvQ"
+	contributionInst := func(status string, content interface{}) []string {
+		contentBytes, _ := json.Marshal(content)
+		return []string{strconv.Itoa(metadata.PDEContributionMeta), "0", status, string(contentBytes)}
+	}
+	firstTxReqID := common.HashH([]byte("first"))
+	secondTxReqID := common.HashH([]byte("second"))
+	feeTxReqID := common.HashH([]byte("fee"))
+	feeActionBytes, _ := json.Marshal(metadata.PDEFeeWithdrawalRequestAction{
+		Meta: metadata.PDEFeeWithdrawalRequest{
+			WithdrawerAddressStr:  contributor,
+			WithdrawalToken1IDStr: testTokenA,
+			WithdrawalToken2IDStr: testTokenB,
+			WithdrawalFeeAmt:      10,
+		},
+		TxReqID: feeTxReqID,
+	})
+	withdrawalInsts := newTestWithdrawalInsts(common.HashH([]byte("withdraw")))
+	for _, inst := range withdrawalInsts {
+		var content metadata.PDEWithdrawalAcceptedContent
+		assert.Nil(t, json.Unmarshal([]byte(inst[3]), &content))
+		content.WithdrawerAddressStr = contributor
+		content.DeductingShares = 30
+		contentBytes, _ := json.Marshal(content)
+		inst[3] = string(contentBytes)
+	}
+
+	state := newTestPDEState(1, 100000, 200000, 100000, 100000)
+	chain := &fakeChain{
+		blocks: map[uint64]*blockchain.BeaconBlock{
+			1: {
+				Header: blockchain.BeaconHeader{Height: 1, Timestamp: 100},
+				Body: blockchain.BeaconBody{Instructions: [][]string{contributionInst(common.PDEContributionWaitingChainStatus, metadata.PDEWaitingContribution{
+					PDEContributionPairID: "pair", ContributorAddressStr: contributor, ContributedAmount: 100, TokenIDStr: testTokenA, TxReqID: firstTxReqID,
+				})}},
+			},
+			2: {
+				Header: blockchain.BeaconHeader{Height: 2, Timestamp: 200},
+				Body: blockchain.BeaconBody{Instructions: [][]string{
+					contributionInst(common.PDEContributionMatchedNReturnedChainStatus, metadata.PDEMatchedNReturnedContribution{
+						PDEContributionPairID: "pair", ContributorAddressStr: contributor, ActualContributedAmount: 90, ReturnedContributedAmount: 10, TokenIDStr: testTokenB, TxReqID: secondTxReqID,
+					}),
+					contributionInst(common.PDEContributionMatchedNReturnedChainStatus, metadata.PDEMatchedNReturnedContribution{
+						PDEContributionPairID: "pair", ContributorAddressStr: contributor, ActualContributedAmount: 100, TokenIDStr: testTokenA, TxReqID: firstTxReqID,
+					}),
+				}},
+			},
+			3: {
+				Header: blockchain.BeaconHeader{Height: 3, Timestamp: 300},
+				Body: blockchain.BeaconBody{Instructions: append(withdrawalInsts, []string{
+					strconv.Itoa(metadata.PDEFeeWithdrawalRequestMeta), "0", common.PDEFeeWithdrawalRejectedChainStatus, base64.StdEncoding.EncodeToString(feeActionBytes),
+				})},
+			},
+		},
+		states: map[uint64]*blockchain.CurrentPDEState{1: state, 2: state, 3: state},
+		final:  3,
+	}
+	idx := NewIndexer(db, chain, nil)
+	assert.Nil(t, idx.CatchUp())
+
+	events, err := idx.GetLiquidityEvents(contributor)
+	assert.Nil(t, err)
+	if assert.Len(t, events, 4) {
+		for _, event := range events[:2] {
+			assert.Equal(t, uint64(3), event.BeaconHeight)
+			if event.Type == LiquidityEventWithdrawal {
+				assert.Equal(t, uint64(30), event.Amount)
+			} else {
+				assert.Equal(t, LiquidityEventFeeWithdrawal, event.Type)
+				assert.Equal(t, feeTxReqID.String(), event.TxReqID)
+			}
+		}
+		assert.Equal(t, LiquidityEventContribution, events[2].Type)
+		assert.Equal(t, secondTxReqID.String(), events[2].TxReqID)
+		assert.Equal(t, uint64(100), events[2].Amount)
+		assert.Equal(t, uint64(2), events[2].BeaconHeight)
+		assert.Equal(t, firstTxReqID.String(), events[3].TxReqID)
+		assert.Equal(t, uint64(1), events[3].BeaconHeight)
+	}
+	events, err = idx.GetLiquidityEvents(testTokenA)
+	assert.Nil(t, err)
+	assert.Len(t, events, 0)
+}
@@ -0,0 +1,288 @@
+package pdeindexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// MaxQueryLimit - most candles, trades or snapshots returned by one query
+const MaxQueryLimit = 1000
+
+var (
+	lastIndexedKey       = []byte("pdeidx-last")
+	candlePrefix         = []byte("pdeidx-candle-")
+	tradePrefix          = []byte("pdeidx-trade-")
+	tradeCountPrefix     = []byte("pdeidx-tradecount-")
+	liquidityPrefix      = []byte("pdeidx-pool-")
+	liquidityCountPrefix = []byte("pdeidx-poolcount-")
+	liquidityEventPrefix = []byte("pdeidx-contributor-")
+)
+
+type lastIndexed struct {
+	BeaconHeight uint64
+	Timestamp    int64
+}
+
+// PairStats - last 24 hours of a pair before the last indexed beacon block
+type PairStats struct {
+	Token1IDStr    string
+	Token2IDStr    string
+	BeaconHeight   uint64 // last indexed beacon block
+	Price          float64
+	PriceChange24H float64 // percent
+	High24H        float64
+	Low24H         float64
+	Volume1In24H   uint64
+	Volume2In24H   uint64
+	TradesIn24H    int
+	FeesIn24H      uint64
+	Liquidity      *LiquiditySnapshot // latest pool of the pair
+}
+
+func candleKey(interval string, pairID string, start int64) []byte {
+	return []byte(fmt.Sprintf("%s%s-%s-%020d", candlePrefix, interval, pairID, start))
+}
+
+// tradeKey - newest trades first when iterating
+func tradeKey(pairID string, beaconHeight uint64, seq int) []byte {
+	return []byte(fmt.Sprintf("%s%s-%020d-%06d", tradePrefix, pairID, math.MaxUint64-beaconHeight, 999999-seq))
+}
+
+func tradeCountKey(pairID string) []byte {
+	return append(append([]byte{}, tradeCountPrefix...), pairID...)
+}
+
+// liquidityKey - newest snapshots first when iterating
+func liquidityKey(pairID string, beaconHeight uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s-%020d", liquidityPrefix, pairID, math.MaxUint64-beaconHeight))
+}
+
+func liquidityCountKey(pairID string) []byte {
+	return append(append([]byte{}, liquidityCountPrefix...), pairID...)
+}
+
+func liquidityEventKey(contributorAddressStr string, txReqID string) []byte {
+	return []byte(fmt.Sprintf("%s%s-%s", liquidityEventPrefix, contributorAddressStr, txReqID))
+}
+
+func putJSON(batch incdb.Batch, key []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return NewPDEIndexerError(StoreIndexError, err)
+	}
+	if err := batch.Put(key, data); err != nil {
+		return NewPDEIndexerError(StoreIndexError, err)
+	}
+	return nil
+}
+
+func putLastIndexed(batch incdb.Batch, beaconHeight uint64, timestamp int64) error {
+	return putJSON(batch, lastIndexedKey, lastIndexed{BeaconHeight: beaconHeight, Timestamp: timestamp})
+}
+
+// LastIndexed - height and time of the last indexed beacon block, 0 if none
+func (idx *Indexer) LastIndexed() (uint64, int64, error) {
+	has, err := idx.db.Has(lastIndexedKey)
+	if err != nil || !has {
+		return 0, 0, err
+	}
+	data, err := idx.db.Get(lastIndexedKey)
+	if err != nil {
+		return 0, 0, NewPDEIndexerError(LoadIndexError, err)
+	}
+	var last lastIndexed
+	if err := json.Unmarshal(data, &last); err != nil {
+		return 0, 0, NewPDEIndexerError(LoadIndexError, err)
+	}
+	return last.BeaconHeight, last.Timestamp, nil
+}
+
+func (idx *Indexer) getCount(key []byte) (uint64, error) {
+	has, err := idx.db.Has(key)
+	if err != nil || !has {
+		return 0, err
+	}
+	data, err := idx.db.Get(key)
+	if err != nil {
+		return 0, NewPDEIndexerError(LoadIndexError, err)
+	}
+	var count uint64
+	if err := json.Unmarshal(data, &count); err != nil {
+		return 0, NewPDEIndexerError(LoadIndexError, err)
+	}
+	return count, nil
+}
+
+func queryLimit(limit int) int {
+	if limit <= 0 || limit > MaxQueryLimit {
+		return MaxQueryLimit
+	}
+	return limit
+}
+
+// GetCandles - candles of the pair starting from from to to (0 for no bound), oldest first
+func (idx *Indexer) GetCandles(tokenIDStr1 string, tokenIDStr2 string, interval string, from int64, to int64, limit int) ([]*Candle, error) {
+	if _, ok := Intervals[interval]; !ok {
+		return nil, NewPDEIndexerError(InvalidIntervalError, fmt.Errorf("interval %v", interval))
+	}
+	pairID, _, _, err := validatePair(tokenIDStr1, tokenIDStr2)
+	if err != nil {
+		return nil, err
+	}
+	if from < 0 {
+		from = 0
+	}
+	prefix := []byte(fmt.Sprintf("%s%s-%s-", candlePrefix, interval, pairID))
+	iter := idx.db.NewIteratorWithStart(candleKey(interval, pairID, from))
+	defer iter.Release()
+	candles := []*Candle{}
+	for iter.Next() && len(candles) < queryLimit(limit) {
+		if !bytes.HasPrefix(iter.Key(), prefix) {
+			break
+		}
+		candle := &Candle{}
+		if err := json.Unmarshal(iter.Value(), candle); err != nil {
+			return nil, NewPDEIndexerError(LoadIndexError, err)
+		}
+		if to > 0 && candle.Start > to {
+			break
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// GetTradeHistory - trades in the pool of the pair, newest first, and the number of trades indexed for it
+func (idx *Indexer) GetTradeHistory(tokenIDStr1 string, tokenIDStr2 string, skip int, limit int) ([]*Trade, uint64, error) {
+	pairID, _, _, err := validatePair(tokenIDStr1, tokenIDStr2)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := idx.getCount(tradeCountKey(pairID))
+	if err != nil {
+		return nil, 0, err
+	}
+	trades := []*Trade{}
+	err = idx.iterate(append(append([]byte{}, tradePrefix...), pairID+"-"...), skip, limit, func(value []byte) error {
+		trade := &Trade{}
+		if err := json.Unmarshal(value, trade); err != nil {
+			return err
+		}
+		trades = append(trades, trade)
+		return nil
+	})
+	return trades, total, err
+}
+
+// GetLiquidityHistory - snapshots of the pool of the pair, newest first, and the number of snapshots indexed for it
+func (idx *Indexer) GetLiquidityHistory(tokenIDStr1 string, tokenIDStr2 string, skip int, limit int) ([]*LiquiditySnapshot, uint64, error) {
+	pairID, _, _, err := validatePair(tokenIDStr1, tokenIDStr2)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := idx.getCount(liquidityCountKey(pairID))
+	if err != nil {
+		return nil, 0, err
+	}
+	snapshots := []*LiquiditySnapshot{}
+	err = idx.iterate(append(append([]byte{}, liquidityPrefix...), pairID+"-"...), skip, limit, func(value []byte) error {
+		snapshot := &LiquiditySnapshot{}
+		if err := json.Unmarshal(value, snapshot); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	return snapshots, total, err
+}
+
+func (idx *Indexer) iterate(prefix []byte, skip int, limit int, f func(value []byte) error) error {
+	iter := idx.db.NewIteratorWithPrefix(prefix)
+	defer iter.Release()
+	limit = queryLimit(limit)
+	for i := 0; iter.Next() && i < skip+limit; i++ {
+		if i < skip {
+			continue
+		}
+		if err := f(iter.Value()); err != nil {
+			return NewPDEIndexerError(LoadIndexError, err)
+		}
+	}
+	return nil
+}
+
+// GetPairStats - price, volume and fees of the pair in the 24 hours before the last indexed beacon block,
+// from its hourly candles
+func (idx *Indexer) GetPairStats(tokenIDStr1 string, tokenIDStr2 string) (*PairStats, error) {
+	_, token1IDStr, token2IDStr, err := validatePair(tokenIDStr1, tokenIDStr2)
+	if err != nil {
+		return nil, err
+	}
+	lastHeight, lastTimestamp, err := idx.LastIndexed()
+	if err != nil {
+		return nil, err
+	}
+	stats := &PairStats{
+		Token1IDStr:  token1IDStr,
+		Token2IDStr:  token2IDStr,
+		BeaconHeight: lastHeight,
+	}
+	hour := Intervals["1h"]
+	from := lastTimestamp - lastTimestamp%hour - 23*hour
+	candles, err := idx.GetCandles(token1IDStr, token2IDStr, "1h", from, 0, 24)
+	if err != nil {
+		return nil, err
+	}
+	for i, candle := range candles {
+		if i == 0 || candle.High > stats.High24H {
+			stats.High24H = candle.High
+		}
+		if i == 0 || candle.Low < stats.Low24H {
+			stats.Low24H = candle.Low
+		}
+		stats.Volume1In24H += candle.Volume1
+		stats.Volume2In24H += candle.Volume2
+		stats.TradesIn24H += candle.Trades
+		stats.FeesIn24H += candle.Fees
+	}
+	if len(candles) > 0 {
+		stats.Price = candles[len(candles)-1].Close
+		if candles[0].Open > 0 {
+			stats.PriceChange24H = (stats.Price - candles[0].Open) * 100 / candles[0].Open
+		}
+	}
+	snapshots, _, err := idx.GetLiquidityHistory(token1IDStr, token2IDStr, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) > 0 {
+		stats.Liquidity = snapshots[0]
+		if len(candles) == 0 {
+			stats.Price = snapshots[0].Price
+		}
+	}
+	return stats, nil
+}
+
+// GetLiquidityEvents - contributions, withdrawals and trading fee withdrawals of the contributor, newest first
+func (idx *Indexer) GetLiquidityEvents(contributorAddressStr string) ([]*LiquidityEvent, error) {
+	iter := idx.db.NewIteratorWithPrefix([]byte(fmt.Sprintf("%s%s-", liquidityEventPrefix, contributorAddressStr)))
+	defer iter.Release()
+	events := []*LiquidityEvent{}
+	for iter.Next() {
+		event := &LiquidityEvent{}
+		if err := json.Unmarshal(iter.Value(), event); err != nil {
+			return nil, NewPDEIndexerError(LoadIndexError, err)
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].BeaconHeight > events[j].BeaconHeight
+	})
+	return events, nil
+}
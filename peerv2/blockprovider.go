@@ -2,17 +2,20 @@ package peerv2
 
 import (
 	"context"
+	"encoding/json"
 
 	p2pgrpc "github.com/incognitochain/go-libp2p-grpc"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
 	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/pkg/errors"
 )
 
-func NewBlockProvider(p *p2pgrpc.GRPCProtocol, ns NetSync) *BlockProvider {
-	bp := &BlockProvider{NetSync: ns}
+func NewBlockProvider(p *p2pgrpc.GRPCProtocol, ns NetSync, compact *CompactBlocks) *BlockProvider {
+	bp := &BlockProvider{NetSync: ns, CompactBlocks: compact}
 	proto.RegisterHighwayServiceServer(p.GetGRPCServer(), bp)
+	proto.RegisterBlockProviderServiceServer(p.GetGRPCServer(), bp)
 	go p.Serve() // NOTE: must serve after registering all services
 	return bp
 }
@@ -139,7 +142,42 @@ func (bp *BlockProvider) StreamBlockByHash(
 
 type BlockProvider struct {
 	proto.UnimplementedHighwayServiceServer
+	proto.UnimplementedBlockProviderServiceServer
 	NetSync NetSync
+	// recent blocks to serve transactions of compact blocks, nil to disable
+	CompactBlocks *CompactBlocks
+}
+
+// GetBlockTxs - transactions of a recent shard block, for peers rebuilding it from a compact block
+func (bp *BlockProvider) GetBlockTxs(ctx context.Context, req *proto.GetBlockTxsRequest) (*proto.GetBlockTxsResponse, error) {
+	if bp.CompactBlocks == nil {
+		return bp.UnimplementedBlockProviderServiceServer.GetBlockTxs(ctx, req)
+	}
+	blkHash := common.Hash{}
+	if err := blkHash.SetBytes(req.BlockHash); err != nil {
+		return nil, err
+	}
+	Logger.Infof("Receive GetBlockTxs shard %v block %v, %v txs and %v cross shard blocks, uuid = %s", req.Shard, blkHash.String(), len(req.TxIndexes), len(req.CrossShardBlockHashes), req.UUID)
+	block := bp.CompactBlocks.Get(blkHash)
+	if block == nil {
+		for _, msg := range bp.NetSync.GetBlockShardByHash([]common.Hash{blkHash}) {
+			if blkMsg, ok := msg.(*wire.MessageBlockShard); ok {
+				block = blkMsg.Block
+			}
+		}
+	}
+	if block == nil {
+		return nil, errors.Errorf("block %v not found", blkHash.String())
+	}
+	body, err := blockTxs(block, req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetBlockTxsResponse{Data: data}, nil
 }
 
 type NetSync interface {
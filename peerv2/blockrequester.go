@@ -135,6 +135,16 @@ func (c *BlockRequester) ready() bool {
 	return c.conn != nil && c.conn.GetState() == connectivity.Ready
 }
 
+// Conn - ready connection to the highway
+func (c *BlockRequester) Conn() (*grpc.ClientConn, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if !c.ready() {
+		return nil, errors.New("requester not ready")
+	}
+	return c.conn, nil
+}
+
 func (c *BlockRequester) UpdateTarget(p peer.ID) {
 	c.peerIDs <- p
 }
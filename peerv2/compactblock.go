@@ -0,0 +1,361 @@
+package peerv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// CompactTxPool - pending transactions compact blocks are rebuilt from
+type CompactTxPool interface {
+	ListTxsDetail() []metadata.Transaction
+}
+
+// CompactCrossShardPool - received cross shard blocks cross transactions of compact blocks are rebuilt from
+type CompactCrossShardPool interface {
+	GetCrossShardBlockByHash(toShard byte, hash common.Hash) *blockchain.CrossShardBlock
+}
+
+// CompactBlockFetcher - request parts of a block from the peer which announced it, from the highway if pid is empty.
+// Highway does not run BlockProvider, it only serves full blocks. Behind a highway, pid is reached by a connection
+// relayed by the highway
+type CompactBlockFetcher interface {
+	GetBlockTxs(ctx context.Context, pid peer.ID, req *proto.GetBlockTxsRequest) (*blockchain.ShardBody, error)
+	GetBlockShard(ctx context.Context, pid peer.ID, shardID byte, hash common.Hash) (*blockchain.ShardBlock, error)
+}
+
+// CompactBlocks - announce shard blocks by header and short transaction ids (see wire.MessageCompactBlockShard)
+// and rebuild announced blocks from the pools, fetching only what is missing.
+// Recent blocks are cached to serve the transactions other nodes miss
+type CompactBlocks struct {
+	TxPool         CompactTxPool
+	CrossShardPool CompactCrossShardPool
+	Fetcher        CompactBlockFetcher
+
+	mtx    sync.RWMutex
+	blocks map[common.Hash]*blockchain.ShardBlock
+	order  []common.Hash
+}
+
+func NewCompactBlocks(txPool CompactTxPool, crossShardPool CompactCrossShardPool, fetcher CompactBlockFetcher) *CompactBlocks {
+	return &CompactBlocks{
+		TxPool:         txPool,
+		CrossShardPool: crossShardPool,
+		Fetcher:        fetcher,
+		blocks:         make(map[common.Hash]*blockchain.ShardBlock),
+	}
+}
+
+// Announce - compact message of block, the block is kept to serve its transactions
+func (c *CompactBlocks) Announce(block *blockchain.ShardBlock) *wire.MessageCompactBlockShard {
+	c.add(block)
+	return wire.NewMessageCompactBlockShard(block)
+}
+
+// Get - recent block announced or rebuilt, nil if not cached
+func (c *CompactBlocks) Get(hash common.Hash) *blockchain.ShardBlock {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.blocks[hash]
+}
+
+func (c *CompactBlocks) add(block *blockchain.ShardBlock) {
+	hash := *block.Hash()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.blocks[hash]; ok {
+		return
+	}
+	if len(c.order) >= CompactBlockCacheSize {
+		delete(c.blocks, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.blocks[hash] = block
+	c.order = append(c.order, hash)
+}
+
+// Rebuild - block announced by msg, from the pools and the missing transactions sent by origin, the peer which
+// published msg and keeps the block (see Announce), or by from if origin is unknown.
+// If the block cannot be rebuilt, the full block is requested from from, the peer msg was received from.
+// from is empty for a block relayed by highway, then the full block is requested from the highway
+func (c *CompactBlocks) Rebuild(msg *wire.MessageCompactBlockShard, from peer.ID, origin peer.ID) (*blockchain.ShardBlock, error) {
+	hash := msg.BlockHash()
+	if block := c.Get(hash); block != nil {
+		return block, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), CompactBlockFetchTimeout)
+	defer cancel()
+	provider := origin
+	if provider == "" {
+		provider = from
+	}
+	block, err := c.rebuild(ctx, msg, provider)
+	if err != nil {
+		Logger.Infof("Cannot rebuild compact block %v of shard %v: %v, request full block from %v", hash.String(), msg.Header.ShardID, err, from.Pretty())
+		block, err = c.Fetcher.GetBlockShard(ctx, from, msg.Header.ShardID, hash)
+		if err != nil {
+			return nil, err
+		}
+		if *block.Hash() != hash {
+			return nil, errors.Errorf("requested block %v, got %v", hash.String(), block.Hash().String())
+		}
+	}
+	c.add(block)
+	return block, nil
+}
+
+func (c *CompactBlocks) rebuild(ctx context.Context, msg *wire.MessageCompactBlockShard, provider peer.ID) (*blockchain.ShardBlock, error) {
+	if err := msg.VerifyMsgSanity(); err != nil {
+		return nil, err
+	}
+	hash := msg.BlockHash()
+	txs := make([]metadata.Transaction, len(msg.ShortTxIDs))
+	for i, index := range msg.PrefilledIndexes {
+		txs[index] = msg.Prefilled.Transactions[i]
+	}
+
+	// a short id matching several transactions of the pool is requested like a missing one
+	poolTxs := map[uint64]metadata.Transaction{}
+	collided := map[uint64]bool{}
+	for _, tx := range c.TxPool.ListTxsDetail() {
+		shortID := wire.ShortTxID(hash, *tx.Hash())
+		if _, ok := poolTxs[shortID]; ok {
+			collided[shortID] = true
+			continue
+		}
+		poolTxs[shortID] = tx
+	}
+	req := &proto.GetBlockTxsRequest{
+		UUID:      genUUID(),
+		Shard:     int32(msg.Header.ShardID),
+		BlockHash: hash.GetBytes(),
+	}
+	for i, shortID := range msg.ShortTxIDs {
+		if txs[i] != nil {
+			continue
+		}
+		if tx, ok := poolTxs[shortID]; ok && !collided[shortID] {
+			txs[i] = tx
+			continue
+		}
+		req.TxIndexes = append(req.TxIndexes, int32(i))
+	}
+
+	crossTxs := map[common.Hash]blockchain.CrossTransaction{}
+	for _, hashes := range msg.CrossShardBlocks {
+		for _, crossHash := range hashes {
+			crossBlock := c.CrossShardPool.GetCrossShardBlockByHash(msg.Header.ShardID, crossHash)
+			if crossBlock == nil {
+				req.CrossShardBlockHashes = append(req.CrossShardBlockHashes, crossHash.GetBytes())
+				continue
+			}
+			crossTxs[crossHash] = blockchain.CrossTransaction{
+				OutputCoin:       crossBlock.CrossOutputCoin,
+				TokenPrivacyData: crossBlock.CrossTxTokenPrivacyData,
+				BlockHash:        *crossBlock.Hash(),
+				BlockHeight:      crossBlock.Header.Height,
+			}
+		}
+	}
+
+	if len(req.TxIndexes) > 0 || len(req.CrossShardBlockHashes) > 0 {
+		if provider == "" {
+			return nil, errors.Errorf("%v txs and %v cross shard blocks missing, highway only serves full blocks", len(req.TxIndexes), len(req.CrossShardBlockHashes))
+		}
+		Logger.Infof("Request %v txs and %v cross shard blocks of compact block %v from %v, uuid = %s", len(req.TxIndexes), len(req.CrossShardBlockHashes), hash.String(), provider.Pretty(), req.UUID)
+		body, err := c.Fetcher.GetBlockTxs(ctx, provider, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(body.Transactions) != len(req.TxIndexes) {
+			return nil, errors.Errorf("requested %v txs, got %v", len(req.TxIndexes), len(body.Transactions))
+		}
+		for i, index := range req.TxIndexes {
+			txs[index] = body.Transactions[i]
+		}
+		for _, received := range body.CrossTransactions {
+			for _, crossTx := range received {
+				crossTxs[crossTx.BlockHash] = crossTx
+			}
+		}
+	}
+
+	block := &blockchain.ShardBlock{
+		ValidationData: msg.ValidationData,
+		Header:         msg.Header,
+		Body: blockchain.ShardBody{
+			Instructions:      msg.Instructions,
+			CrossTransactions: make(map[byte][]blockchain.CrossTransaction),
+			Transactions:      txs,
+		},
+	}
+	for shardID, hashes := range msg.CrossShardBlocks {
+		for _, crossHash := range hashes {
+			crossTx, ok := crossTxs[crossHash]
+			if !ok {
+				return nil, errors.Errorf("missing cross shard block %v from shard %v", crossHash.String(), shardID)
+			}
+			block.Body.CrossTransactions[shardID] = append(block.Body.CrossTransactions[shardID], crossTx)
+		}
+	}
+	if err := verifyCompactBlockBody(block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// verifyCompactBlockBody - check the rebuilt body matches the roots committed by the header
+func verifyCompactBlockBody(block *blockchain.ShardBlock) error {
+	txMerkleTree := blockchain.Merkle{}.BuildMerkleTreeStore(block.Body.Transactions)
+	txRoot := &common.Hash{}
+	if len(txMerkleTree) > 0 {
+		txRoot = txMerkleTree[len(txMerkleTree)-1]
+	}
+	if *txRoot != block.Header.TxRoot {
+		return errors.Errorf("transaction root %v does not match header %v", txRoot.String(), block.Header.TxRoot.String())
+	}
+	if !blockchain.VerifyMerkleCrossTransaction(block.Body.CrossTransactions, block.Header.CrossTransactionRoot) {
+		return errors.Errorf("cross transaction root does not match header %v", block.Header.CrossTransactionRoot.String())
+	}
+	return nil
+}
+
+// blockTxs - body with the transactions and cross transactions of block requested by req
+func blockTxs(block *blockchain.ShardBlock, req *proto.GetBlockTxsRequest) (*blockchain.ShardBody, error) {
+	body := &blockchain.ShardBody{
+		Instructions:      [][]string{},
+		CrossTransactions: make(map[byte][]blockchain.CrossTransaction),
+		Transactions:      []metadata.Transaction{},
+	}
+	for _, index := range req.TxIndexes {
+		if index < 0 || int(index) >= len(block.Body.Transactions) {
+			return nil, errors.Errorf("tx index %v out of %v txs of block %v", index, len(block.Body.Transactions), block.Hash().String())
+		}
+		body.Transactions = append(body.Transactions, block.Body.Transactions[index])
+	}
+	for _, hashBytes := range req.CrossShardBlockHashes {
+		crossHash := common.Hash{}
+		if err := crossHash.SetBytes(hashBytes); err != nil {
+			return nil, err
+		}
+		found := false
+		for shardID, crossTxs := range block.Body.CrossTransactions {
+			for _, crossTx := range crossTxs {
+				if crossTx.BlockHash == crossHash {
+					body.CrossTransactions[shardID] = append(body.CrossTransactions[shardID], crossTx)
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("cross shard block %v not in block %v", crossHash.String(), block.Hash().String())
+		}
+	}
+	return body, nil
+}
+
+// blockProviderConn - gRPC connection to the BlockProvider of pid, or to the highway if pid is empty.
+// Behind a highway, pid is dialed through the highway (circuit relay) unless it is already connected.
+// release must be called when the connection is not used anymore
+func (cm *ConnManager) blockProviderConn(ctx context.Context, pid peer.ID) (*grpc.ClientConn, func(), error) {
+	if pid == "" || cm.isHighway(pid) {
+		conn, err := cm.Requester.Conn()
+		return conn, func() {}, err
+	}
+	if cm.directPeers != nil && cm.IsDirect() {
+		conn, err := cm.directPeers.clientConn(ctx, pid)
+		return conn, func() {}, err
+	}
+	if highway := cm.Requester.Target(); highway != "" && cm.LocalHost.Host.Network().Connectedness(pid) != network.Connected {
+		addr, err := relayAddr(highway)
+		if err != nil {
+			return nil, nil, err
+		}
+		cm.LocalHost.Host.Peerstore().AddAddr(pid, addr, peerstore.TempAddrTTL)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+	conn, err := cm.LocalHost.GRPC.Dial(dialCtx, pid, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// relayAddr - address of any peer connected to the highway, to dial it through the highway
+func relayAddr(highway string) (multiaddr.Multiaddr, error) {
+	addr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/p2p/%s/p2p-circuit", highway))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return addr, nil
+}
+
+// GetBlockTxs - see CompactBlockFetcher
+func (cm *ConnManager) GetBlockTxs(ctx context.Context, pid peer.ID, req *proto.GetBlockTxsRequest) (*blockchain.ShardBody, error) {
+	conn, release, err := cm.blockProviderConn(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	reply, err := proto.NewBlockProviderServiceClient(conn).GetBlockTxs(ctx, req, grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	body := &blockchain.ShardBody{}
+	if err := json.Unmarshal(reply.Data, body); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return body, nil
+}
+
+// GetBlockShard - see CompactBlockFetcher
+func (cm *ConnManager) GetBlockShard(ctx context.Context, pid peer.ID, shardID byte, hash common.Hash) (*blockchain.ShardBlock, error) {
+	conn, release, err := cm.blockProviderConn(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	uuid := genUUID()
+	reply, err := proto.NewHighwayServiceClient(conn).GetBlockShardByHash(
+		ctx,
+		&proto.GetBlockShardByHashRequest{
+			Shard:  int32(shardID),
+			Hashes: [][]byte{hash.GetBytes()},
+			UUID:   uuid,
+		},
+		grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, data := range reply.Data {
+		var msg wire.Message
+		if wire.IsBinaryMessage(data) {
+			msg, err = wire.DecodeBinaryMessage(data)
+		} else {
+			msg, err = wire.DecodeJSONMessage(string(data))
+		}
+		if err != nil {
+			Logger.Warnf("Cannot decode block shard %v, uuid = %s: %v", hash.String(), uuid, err)
+			continue
+		}
+		if blkMsg, ok := msg.(*wire.MessageBlockShard); ok && blkMsg.Block != nil && *blkMsg.Block.Hash() == hash {
+			return blkMsg.Block, nil
+		}
+	}
+	return nil, errors.Errorf("block %v of shard %v not found, uuid = %s", hash.String(), shardID, uuid)
+}
@@ -0,0 +1,236 @@
+package peerv2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/privacy"
+	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTxPool []metadata.Transaction
+
+func (p fakeTxPool) ListTxsDetail() []metadata.Transaction { return p }
+
+type fakeCrossShardPool map[common.Hash]*blockchain.CrossShardBlock
+
+func (p fakeCrossShardPool) GetCrossShardBlockByHash(toShard byte, hash common.Hash) *blockchain.CrossShardBlock {
+	return p[hash]
+}
+
+// fakeFetcher - serve parts of block like the BlockProvider of the announcing peer
+type fakeFetcher struct {
+	block     *blockchain.ShardBlock
+	tamper    bool
+	requests  []*proto.GetBlockTxsRequest
+	txsFrom   []peer.ID
+	fullBlock int
+	blockFrom []peer.ID
+}
+
+func (f *fakeFetcher) GetBlockTxs(ctx context.Context, pid peer.ID, req *proto.GetBlockTxsRequest) (*blockchain.ShardBody, error) {
+	f.requests = append(f.requests, req)
+	f.txsFrom = append(f.txsFrom, pid)
+	body, err := blockTxs(f.block, req)
+	if err != nil {
+		return nil, err
+	}
+	if f.tamper && len(body.Transactions) > 0 {
+		body.Transactions[0] = newTestCompactTx(100, true)
+	}
+	return body, nil
+}
+
+func (f *fakeFetcher) GetBlockShard(ctx context.Context, pid peer.ID, shardID byte, hash common.Hash) (*blockchain.ShardBlock, error) {
+	f.fullBlock++
+	f.blockFrom = append(f.blockFrom, pid)
+	if *f.block.Hash() != hash {
+		return nil, errors.New("not found")
+	}
+	return f.block, nil
+}
+
+// newTestCompactTx - tx spending a coin, or minted by the producer if !spending
+func newTestCompactTx(i int, spending bool) *transaction.Tx {
+	tx := &transaction.Tx{
+		Version:  1,
+		Type:     common.TxNormalType,
+		LockTime: 1600000000 + int64(i),
+		Fee:      uint64(100 + i),
+		Info:     []byte(fmt.Sprintf("tx %v", i)),
+	}
+	if spending {
+		tx.Proof = &zkp.PaymentProof{}
+		tx.Proof.SetInputCoins([]*privacy.InputCoin{new(privacy.InputCoin).Init()})
+	}
+	return tx
+}
+
+func newTestCompactBlock(txs []metadata.Transaction, crossBlocks []*blockchain.CrossShardBlock) *blockchain.ShardBlock {
+	crossTxs := map[byte][]blockchain.CrossTransaction{}
+	for _, blk := range crossBlocks {
+		crossTxs[blk.Header.ShardID] = append(crossTxs[blk.Header.ShardID], blockchain.CrossTransaction{
+			OutputCoin:       blk.CrossOutputCoin,
+			TokenPrivacyData: blk.CrossTxTokenPrivacyData,
+			BlockHash:        *blk.Hash(),
+			BlockHeight:      blk.Header.Height,
+		})
+	}
+	txRoot := common.Hash{}
+	if tree := (blockchain.Merkle{}).BuildMerkleTreeStore(txs); len(tree) > 0 {
+		txRoot = *tree[len(tree)-1]
+	}
+	crossRoot, _ := blockchain.CreateMerkleCrossTransaction(crossTxs)
+	return &blockchain.ShardBlock{
+		Header: blockchain.ShardHeader{
+			ShardID:              1,
+			Height:               100,
+			TxRoot:               txRoot,
+			CrossTransactionRoot: *crossRoot,
+		},
+		Body: blockchain.ShardBody{
+			Instructions:      [][]string{{"inst"}},
+			CrossTransactions: crossTxs,
+			Transactions:      txs,
+		},
+	}
+}
+
+func newTestCrossShardBlock(shardID byte, height uint64) *blockchain.CrossShardBlock {
+	return &blockchain.CrossShardBlock{
+		Header:    blockchain.ShardHeader{ShardID: shardID, Height: height},
+		ToShardID: 1,
+	}
+}
+
+func TestCompactBlocks_Rebuild(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	txs := []metadata.Transaction{
+		newTestCompactTx(0, false),
+		newTestCompactTx(1, true),
+		newTestCompactTx(2, true),
+		newTestCompactTx(3, true),
+	}
+	crossInPool := newTestCrossShardBlock(0, 5)
+	crossMissing := newTestCrossShardBlock(2, 7)
+	block := newTestCompactBlock(txs, []*blockchain.CrossShardBlock{crossInPool, crossMissing})
+	fetcher := &fakeFetcher{block: block}
+	producer := NewCompactBlocks(fakeTxPool{}, fakeCrossShardPool{}, fetcher)
+	msg := producer.Announce(block)
+	assert.Equal(t, []int{0}, msg.PrefilledIndexes)
+	assert.Equal(t, block, producer.Get(*block.Hash()))
+
+	//tx 3 and one cross shard block are not in the pools of the receiver
+	pool := fakeTxPool{newTestCompactTx(10, true), txs[2], txs[1]}
+	crossPool := fakeCrossShardPool{*crossInPool.Hash(): crossInPool}
+	receiver := NewCompactBlocks(pool, crossPool, fetcher)
+	rebuilt, err := receiver.Rebuild(msg, "QmProducer", "QmProducer")
+	assert.Nil(t, err)
+	assert.Equal(t, *block.Hash(), *rebuilt.Hash())
+	assert.Equal(t, block.Body.Transactions, rebuilt.Body.Transactions)
+	assert.Equal(t, block.Body.CrossTransactions, rebuilt.Body.CrossTransactions)
+	assert.Equal(t, 0, fetcher.fullBlock)
+	if assert.Len(t, fetcher.requests, 1) {
+		assert.Equal(t, []int32{3}, fetcher.requests[0].TxIndexes)
+		assert.Equal(t, [][]byte{crossMissing.Hash().GetBytes()}, fetcher.requests[0].CrossShardBlockHashes)
+	}
+
+	//rebuilt block is cached to serve other peers
+	_, err = receiver.Rebuild(msg, "QmProducer", "QmProducer")
+	assert.Nil(t, err)
+	assert.Len(t, fetcher.requests, 1)
+	assert.NotNil(t, receiver.Get(*block.Hash()))
+}
+
+func TestCompactBlocks_FallBackToFullBlock(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	txs := []metadata.Transaction{newTestCompactTx(1, true), newTestCompactTx(2, true)}
+	block := newTestCompactBlock(txs, nil)
+	msg := wire.NewMessageCompactBlockShard(block)
+
+	//missing tx is replaced, transaction root does not match
+	fetcher := &fakeFetcher{block: block, tamper: true}
+	receiver := NewCompactBlocks(fakeTxPool{txs[0]}, fakeCrossShardPool{}, fetcher)
+	rebuilt, err := receiver.Rebuild(msg, "QmPeer", "QmPeer")
+	assert.Nil(t, err)
+	assert.Equal(t, block, rebuilt)
+	assert.Len(t, fetcher.requests, 1)
+	assert.Equal(t, 1, fetcher.fullBlock)
+
+	//full block not found either
+	other := newTestCompactBlock([]metadata.Transaction{newTestCompactTx(3, true)}, nil)
+	fetcher = &fakeFetcher{block: other, tamper: true}
+	receiver = NewCompactBlocks(fakeTxPool{}, fakeCrossShardPool{}, fetcher)
+	_, err = receiver.Rebuild(msg, "QmPeer", "QmPeer")
+	assert.NotNil(t, err)
+	assert.Nil(t, receiver.Get(*block.Hash()))
+
+	//requested tx index out of the block
+	_, err = blockTxs(block, &proto.GetBlockTxsRequest{TxIndexes: []int32{2}})
+	assert.NotNil(t, err)
+}
+
+// Highway relays compact blocks without serving their transactions, missing ones are requested from the producer
+// through the highway, the full block comes from highway
+func TestCompactBlocks_RebuildFromHighway(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	txs := []metadata.Transaction{newTestCompactTx(1, true), newTestCompactTx(2, true)}
+	block := newTestCompactBlock(txs, nil)
+	msg := wire.NewMessageCompactBlockShard(block)
+
+	//every tx in pool, nothing is requested
+	fetcher := &fakeFetcher{block: block}
+	receiver := NewCompactBlocks(fakeTxPool{txs[1], txs[0]}, fakeCrossShardPool{}, fetcher)
+	rebuilt, err := receiver.Rebuild(msg, "", "QmProducer")
+	assert.Nil(t, err)
+	assert.Equal(t, *block.Hash(), *rebuilt.Hash())
+	assert.Len(t, fetcher.requests, 0)
+	assert.Equal(t, 0, fetcher.fullBlock)
+
+	//a tx is missing, it is requested from the producer
+	fetcher = &fakeFetcher{block: block}
+	receiver = NewCompactBlocks(fakeTxPool{txs[0]}, fakeCrossShardPool{}, fetcher)
+	rebuilt, err = receiver.Rebuild(msg, "", "QmProducer")
+	assert.Nil(t, err)
+	assert.Equal(t, block.Body.Transactions, rebuilt.Body.Transactions)
+	assert.Equal(t, []peer.ID{"QmProducer"}, fetcher.txsFrom)
+	assert.Equal(t, 0, fetcher.fullBlock)
+
+	//producer sends a wrong tx, full block is requested from highway
+	fetcher = &fakeFetcher{block: block, tamper: true}
+	receiver = NewCompactBlocks(fakeTxPool{txs[0]}, fakeCrossShardPool{}, fetcher)
+	rebuilt, err = receiver.Rebuild(msg, "", "QmProducer")
+	assert.Nil(t, err)
+	assert.Equal(t, block, rebuilt)
+	assert.Equal(t, []peer.ID{"QmProducer"}, fetcher.txsFrom)
+	assert.Equal(t, []peer.ID{""}, fetcher.blockFrom)
+
+	//producer unknown, only the full block can be requested from highway
+	fetcher = &fakeFetcher{block: block}
+	receiver = NewCompactBlocks(fakeTxPool{txs[0]}, fakeCrossShardPool{}, fetcher)
+	rebuilt, err = receiver.Rebuild(msg, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, block, rebuilt)
+	assert.Len(t, fetcher.requests, 0)
+	assert.Equal(t, []peer.ID{""}, fetcher.blockFrom)
+}
+
+func TestRelayAddr(t *testing.T) {
+	highway := "QmSPZgwrP4BUp2X4BPuN8LnCNPqkP2BUVbjrQM2pUXHi8c"
+	addr, err := relayAddr(highway)
+	assert.Nil(t, err)
+	assert.Equal(t, "/p2p/"+highway+"/p2p-circuit", addr.String())
+
+	_, err = relayAddr("not a peer id")
+	assert.NotNil(t, err)
+}
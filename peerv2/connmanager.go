@@ -2,11 +2,12 @@ package peerv2
 
 import (
 	"context"
-	"encoding/hex"
+	"fmt"
 	"io"
-	"reflect"
+	"sync"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
 
@@ -33,7 +34,7 @@ func NewConnManager(
 	relayShard []byte,
 ) *ConnManager {
 	pubkey, _ := ikey.ToBase58()
-	return &ConnManager{
+	cm := &ConnManager{
 		info: info{
 			consensusData: cd,
 			pubkey:        pubkey,
@@ -48,8 +49,30 @@ func NewConnManager(
 		disp:                 dispatcher,
 		IsMasterNode:         false,
 		registerRequests:     make(chan peer.ID, 100),
+		defaultCodec:         wire.CodecJSON,
+		codecs:               map[string]int{},
+		mode:                 P2PModeHighway,
+		modeChanged:          make(chan bool, 1),
 		stop:                 make(chan int),
 	}
+	if dispatcher.Seen == nil {
+		dispatcher.Seen = NewSeenCache(SeenCacheSize, SeenCacheWindow)
+	}
+	dispatcher.Seen.SetOnDuplicate(cm.scoreDuplicate)
+	if dispatcher.Stats == nil {
+		dispatcher.Stats = NewP2PStats(nil)
+	}
+	dispatcher.IsRelay = cm.isHighway
+	if dispatcher.Codecs == nil {
+		dispatcher.Codecs = NewPeerCodecs(host.Host.ID().Pretty(), PeerCodecWindow)
+	}
+	if dispatcher.Bans != nil {
+		dispatcher.Bans.SetProtected(func(keyType string, key string) bool {
+			return keyType == banmanager.KeyPeerID && cm.Requester != nil && key == cm.Requester.Target()
+		})
+		dispatcher.Bans.AddOnBan(cm.disconnectBanned)
+	}
+	return cm
 }
 
 func (cm *ConnManager) PublishMessage(msg wire.Message) error {
@@ -66,7 +89,7 @@ func (cm *ConnManager) PublishMessage(msg wire.Message) error {
 				// Logger.Info("[hy]", availableTopic)
 				if (availableTopic.Act == proto.MessageTopicPair_PUB) || (availableTopic.Act == proto.MessageTopicPair_PUBSUB) {
 					topic = availableTopic.Name
-					err := broadcastMessage(msg, topic, cm.ps)
+					err := cm.broadcastMessage(msg, topic)
 					if err != nil {
 						Logger.Errorf("Broadcast to topic %v error %v", topic, err)
 						return err
@@ -86,17 +109,22 @@ func (cm *ConnManager) PublishMessage(msg wire.Message) error {
 }
 
 func (cm *ConnManager) PublishMessageToShard(msg wire.Message, shardID byte) error {
-	publishable := []string{wire.CmdPeerState, wire.CmdBlockShard, wire.CmdCrossShard, wire.CmdBFT}
+	publishable := []string{wire.CmdPeerState, wire.CmdBlockShard, wire.CmdCompactBlockShard, wire.CmdCrossShard, wire.CmdBFT}
 	msgType := msg.MessageType()
+	topicType := msgType
+	if msgType == wire.CmdCompactBlockShard {
+		// compact blocks share the topics of full blocks
+		topicType = wire.CmdBlockShard
+	}
 	subs := cm.Subscriber.GetMsgToTopics()
 	for _, p := range publishable {
 		if msgType == p {
 			// Get topic for mess
-			for _, availableTopic := range subs[msgType] {
+			for _, availableTopic := range subs[topicType] {
 				//Logger.Info(availableTopic)
 				cID := GetCommitteeIDOfTopic(availableTopic.Name)
 				if (byte(cID) == shardID) && ((availableTopic.Act == proto.MessageTopicPair_PUB) || (availableTopic.Act == proto.MessageTopicPair_PUBSUB)) {
-					return broadcastMessage(msg, availableTopic.Name, cm.ps)
+					return cm.broadcastMessage(msg, availableTopic.Name)
 				}
 			}
 		}
@@ -109,28 +137,117 @@ func (cm *ConnManager) PublishMessageToShard(msg wire.Message, shardID byte) err
 func (cm *ConnManager) Start(ns NetSync) {
 	// Pubsub
 	var err error
-	cm.ps, err = pubsub.NewFloodSub(
-		context.Background(),
-		cm.LocalHost.Host,
+	psOpts := []pubsub.Option{
 		pubsub.WithMaxMessageSize(common.MaxPSMsgSize),
 		pubsub.WithPeerOutboundQueueSize(1024),
 		pubsub.WithValidateQueueSize(1024),
-	)
+	}
+	if cm.mode == P2PModeHighway {
+		cm.ps, err = pubsub.NewFloodSub(context.Background(), cm.LocalHost.Host, psOpts...)
+	} else {
+		// GossipSub also talks floodsub with highways
+		cm.ps, err = pubsub.NewGossipSub(context.Background(), cm.LocalHost.Host, psOpts...)
+	}
 	if err != nil {
 		panic(err)
 	}
 	cm.messages = make(chan *pubsub.Message, 1000)
 
-	// NOTE: must Connect after creating FloodSub
-	go cm.keepHighwayConnection()
-
 	cm.Requester = NewRequester(cm.LocalHost.GRPC)
-	cm.Subscriber = NewSubManager(cm.info, cm.ps, cm.Requester, cm.messages)
-	cm.Provider = NewBlockProvider(cm.LocalHost.GRPC, ns)
+	if cm.mode == P2PModeDirect {
+		cm.Subscriber = NewSubManager(cm.info, cm.ps, directRegisterer{}, cm.messages)
+		cm.setDirect(true)
+	} else {
+		cm.Subscriber = NewSubManager(cm.info, cm.ps, cm.Requester, cm.messages)
+		// NOTE: must Connect after creating FloodSub
+		go cm.keepHighwayConnection()
+	}
+	if cm.mode != P2PModeHighway {
+		cm.directPeers = NewDirectPeers(cm.LocalHost.Host, cm.LocalHost.GRPC, cm.staticPeers, cm.disp.Bans)
+		if cm.mdns {
+			if err := cm.directPeers.StartMDNS(context.Background()); err != nil {
+				Logger.Errorf("Failed starting mDNS discovery: %v", err)
+			}
+		}
+		go cm.directPeers.keepConnection(cm.stop)
+	}
+	if cm.mode == P2PModeAuto {
+		go cm.manageHighwayFallback()
+	}
+	cm.Provider = NewBlockProvider(cm.LocalHost.GRPC, ns, cm.CompactBlocks)
 	go cm.manageRoleSubscription()
 	cm.process()
 }
 
+// SetDirectConfig - set p2p mode (see ParseP2PMode), static peers (see ParseStaticPeers) and mDNS discovery
+// used by direct mode. Must be called before Start
+func (cm *ConnManager) SetDirectConfig(mode string, staticPeers string, mdns bool) error {
+	var err error
+	if cm.mode, err = ParseP2PMode(mode); err != nil {
+		return err
+	}
+	if cm.staticPeers, err = ParseStaticPeers(staticPeers); err != nil {
+		return err
+	}
+	cm.mdns = mdns
+	return nil
+}
+
+// IsDirect - true if node is gossiping directly with other nodes instead of via highway
+func (cm *ConnManager) IsDirect() bool {
+	cm.modeLock.RLock()
+	defer cm.modeLock.RUnlock()
+	return cm.direct
+}
+
+func (cm *ConnManager) setDirect(direct bool) {
+	cm.modeLock.Lock()
+	defer cm.modeLock.Unlock()
+	cm.direct = direct
+}
+
+// manageHighwayFallback switches to direct gossip when highway is not reachable for HighwayFallbackTimeout,
+// and back to highway when it is reachable again
+func (cm *ConnManager) manageHighwayFallback() {
+	watchTimestep := time.NewTicker(RegisterTimestep)
+	defer watchTimestep.Stop()
+	lastReady := time.Now()
+	for {
+		select {
+		case <-watchTimestep.C:
+			ready := cm.Requester.IsReady()
+			if ready {
+				lastReady = time.Now()
+			}
+			direct := cm.IsDirect()
+			switch {
+			case !direct && !ready && time.Since(lastReady) > HighwayFallbackTimeout:
+				Logger.Warnf("No highway reachable for %v, fall back to direct gossip", HighwayFallbackTimeout)
+				cm.switchMode(true)
+			case direct && ready:
+				Logger.Info("Highway is reachable, stop direct gossip")
+				cm.switchMode(false)
+			}
+		case <-cm.stop:
+			Logger.Info("Stop managing highway fallback")
+			return
+		}
+	}
+}
+
+func (cm *ConnManager) switchMode(direct bool) {
+	if direct {
+		cm.Subscriber.SetRegisterer(directRegisterer{})
+	} else {
+		cm.Subscriber.SetRegisterer(cm.Requester)
+	}
+	cm.setDirect(direct)
+	select {
+	case cm.modeChanged <- direct:
+	default:
+	}
+}
+
 // BroadcastCommittee floods message to topic `chain_committee` for highways
 // Only masternode actually does the broadcast, other's messages will be ignored by highway
 func (cm *ConnManager) BroadcastCommittee(
@@ -168,6 +285,7 @@ type ForcedSubscriber interface {
 	Subscribe(forced bool) error
 	GetMsgToTopics() msgToTopics
 	SetSyncMode(string)
+	SetRegisterer(Registerer)
 }
 
 type ConnManager struct {
@@ -184,11 +302,26 @@ type ConnManager struct {
 	messages         chan *pubsub.Message // queue messages from all topics
 	registerRequests chan peer.ID
 
+	// codec of published messages: defaultCodec, or codecs[msgType] if set
+	defaultCodec int
+	codecs       map[string]int
+
+	// p2p mode, direct is true while gossiping with directPeers instead of highway
+	mode        string
+	staticPeers []peer.AddrInfo
+	mdns        bool
+	direct      bool
+	modeLock    sync.RWMutex
+	modeChanged chan bool
+	directPeers *DirectPeers
+
 	keeper     *AddrKeeper
 	discoverer HighwayDiscoverer
 	disp       *Dispatcher
 	Requester  *BlockRequester
 	Provider   *BlockProvider
+	// announce shard blocks as compact blocks and serve their transactions, nil to disable. Set before Start
+	CompactBlocks *CompactBlocks
 
 	stop chan int
 }
@@ -201,7 +334,7 @@ func (cm *ConnManager) process() {
 	for {
 		select {
 		case msg := <-cm.messages:
-			err := cm.disp.processInMessageString(string(msg.Data))
+			err := cm.disp.processInMessage(msg)
 			if err != nil {
 				Logger.Warn(err)
 			}
@@ -302,6 +435,16 @@ func (cm *ConnManager) manageRoleSubscription() {
 	for {
 		select {
 		case <-registerTimestep.C:
+			if cm.IsDirect() {
+				err = cm.Subscriber.Subscribe(forced)
+				if err != nil {
+					Logger.Errorf("Subscribe direct topics failed: forced = %v err = %+v", forced, err)
+				} else {
+					forced = false
+				}
+				continue
+			}
+
 			// Check if we are connecting to the target of registration (correct highway peerID)
 			target := cm.Requester.Target()
 			if hwID.Pretty() != target {
@@ -331,62 +474,147 @@ func (cm *ConnManager) manageRoleSubscription() {
 			}
 			hwID = newID
 
+		case <-cm.modeChanged:
+			forced = true // topics of new mode
+
 		case <-cm.stop:
 			Logger.Info("Stop managing role subscription")
-			break
+			return
 		}
 	}
 }
 
-func encodeMessage(msg wire.Message) (string, error) {
-	// NOTE: copy from peerConn.outMessageHandler
-	// Create messageHex
-	messageBytes, err := msg.JsonSerialize()
+// isHighway - true if pid is the highway this node is connected to
+func (cm *ConnManager) isHighway(pid peer.ID) bool {
+	return cm.Requester != nil && pid.Pretty() == cm.Requester.Target()
+}
+
+// scoreDuplicate - ban (or disconnect if ban list is disabled) a peer which keeps sending duplicated messages.
+// Highway relays messages of all topics so it is only warned about, a new highway is chosen if it stops working
+func (cm *ConnManager) scoreDuplicate(from peer.ID, topic string, dupInWindow int) {
+	if dupInWindow <= MaxDuplicatePerPeer {
+		return
+	}
+	if cm.isHighway(from) {
+		if dupInWindow == MaxDuplicatePerPeer+1 {
+			Logger.Warnf("Highway %v sent more than %v duplicated messages in %v, last on topic %v", from.Pretty(), MaxDuplicatePerPeer, SeenCacheWindow, topic)
+		}
+		return
+	}
+	reason := fmt.Sprintf("sent %v duplicated messages in %v, last on topic %v", dupInWindow, SeenCacheWindow, topic)
+	if cm.disp.Bans != nil {
+		// connection is closed by disconnectBanned
+		_, err := cm.disp.Bans.Ban(banmanager.KeyPeerID, from.Pretty(), banmanager.ReasonDuplicateFlood, reason)
+		if err == nil {
+			return
+		}
+		Logger.Errorf("Failed banning peer %v: %v", from.Pretty(), err)
+	}
+	Logger.Warnf("Disconnect peer %v: %v", from.Pretty(), reason)
+	cm.closePeer(from)
+}
+
+// disconnectBanned - close connection to a peer when it is banned
+func (cm *ConnManager) disconnectBanned(ban banmanager.Ban) {
+	if ban.KeyType != banmanager.KeyPeerID {
+		return
+	}
+	pid, err := peer.IDB58Decode(ban.Key)
 	if err != nil {
-		Logger.Error("Can not serialize json format for messageHex:"+msg.MessageType(), err)
-		return "", err
+		return
 	}
+	Logger.Warnf("Disconnect peer %v: banned for %v %v", ban.Key, ban.Reason, ban.Message)
+	cm.closePeer(pid)
+}
 
-	// Add 24 bytes headerBytes into messageHex
-	headerBytes := make([]byte, wire.MessageHeaderSize)
-	// add command type of message
-	cmdType, messageErr := wire.GetCmdType(reflect.TypeOf(msg))
-	if messageErr != nil {
-		Logger.Error("Can not get cmd type for "+msg.MessageType(), messageErr)
-		return "", err
+func (cm *ConnManager) closePeer(pid peer.ID) {
+	if err := cm.LocalHost.Host.Network().ClosePeer(pid); err != nil {
+		Logger.Errorf("Failed closing connection to peer %v: %v", pid.Pretty(), err)
+	}
+}
+
+// DuplicateStats - counters of duplicated messages dropped by dispatcher
+func (cm *ConnManager) DuplicateStats() SeenStats {
+	return cm.disp.Seen.Stats()
+}
+
+// GetP2PStats - traffic counters by topic, message type and committee, with duplicated messages dropped
+func (cm *ConnManager) GetP2PStats() P2PStatsSnapshot {
+	stats := cm.disp.Stats.Snapshot()
+	stats.Duplicates = cm.DuplicateStats()
+	return stats
+}
+
+// GetNATStatus - port mapping and external address discovery of the libp2p host, nil if disabled
+func (cm *ConnManager) GetNATStatus() *NATStatus {
+	if cm.LocalHost == nil || cm.LocalHost.NAT == nil {
+		return nil
 	}
-	copy(headerBytes[:], []byte(cmdType))
-	// add forward type of message at 13st byte
-	forwardType := byte('s')
-	forwardValue := byte(0)
-	copy(headerBytes[wire.MessageCmdTypeSize:], []byte{forwardType})
-	copy(headerBytes[wire.MessageCmdTypeSize+1:], []byte{forwardValue})
-	messageBytes = append(messageBytes, headerBytes...)
-	// Logger.Infof("Encoded message TYPE %s CONTENT %s", cmdType, string(messageBytes))
-
-	// zip data before send
-	messageBytes, err = common.GZipFromBytes(messageBytes)
+	status := cm.LocalHost.NAT.Status()
+	return &status
+}
+
+func encodeMessage(msg wire.Message) (string, error) {
+	messageHex, err := wire.EncodeJSONMessage(msg)
 	if err != nil {
-		Logger.Error("Can not gzip for messageHex:"+msg.MessageType(), err)
+		Logger.Error("Can not encode message "+msg.MessageType(), err)
 		return "", err
 	}
-	messageHex := hex.EncodeToString(messageBytes)
-	//log.Debugf("Content in hex encode: %s", string(messageHex))
-	// add end character to messageHex (delim '\n')
-	// messageHex += "\n"
 	return messageHex, nil
 }
 
-func broadcastMessage(msg wire.Message, topic string, ps *pubsub.PubSub) error {
-	// Encode message to string first
+// SetCodecs - set codec of published messages from config (see wire.ParseCodecs).
+// Received messages are decoded whatever codec they use, and binary codec is only published while
+// every peer advertises it (see PeerCodecs), so nodes can switch to binary codec one by one
+func (cm *ConnManager) SetCodecs(config string) error {
+	defaultCodec, codecs, err := wire.ParseCodecs(config)
+	if err != nil {
+		return err
+	}
+	cm.defaultCodec = defaultCodec
+	cm.codecs = codecs
+	return nil
+}
+
+func (cm *ConnManager) codecOf(msgType string) int {
+	if codec, ok := cm.codecs[msgType]; ok {
+		return codec
+	}
+	return cm.defaultCodec
+}
+
+// encodeMessageWithCodec - encode msg with binary codec if it is enabled for this message type, otherwise legacy codec
+func encodeMessageWithCodec(msg wire.Message, codec int) ([]byte, error) {
+	if codec == wire.CodecBinary {
+		if _, ok := msg.(wire.BinaryMessage); ok {
+			return wire.EncodeBinaryMessage(msg)
+		}
+	}
 	messageHex, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(messageHex), nil
+}
+
+func (cm *ConnManager) broadcastMessage(msg wire.Message, topic string) error {
+	// Encode message first
+	codec := cm.codecOf(msg.MessageType())
+	if cm.disp.Codecs != nil {
+		codec = cm.disp.Codecs.Negotiate(codec)
+	}
+	data, err := encodeMessageWithCodec(msg, codec)
 	if err != nil {
 		return err
 	}
 
 	// Broadcast
 	//Logger.Infof("Publishing to topic %s", topic)
-	return ps.Publish(topic, []byte(messageHex))
+	if err := cm.ps.Publish(topic, data); err != nil {
+		return err
+	}
+	cm.disp.Stats.AddOut(topic, msg.MessageType(), len(data))
+	return nil
 }
 
 type HighwayDiscoverer interface {
@@ -468,7 +696,12 @@ func (conn *ConnManager) RequestShardBlocksByHashViaStream(ctx context.Context,
 func (conn *ConnManager) requestBlocksViaStream(ctx context.Context, peerID string, req *proto.BlockByHeightRequest) (blockCh chan common.BlockInterface, err error) {
 	Logger.Infof("[stream] Request Block type %v from peer %v from cID %v, [%v %v] ", req.Type, peerID, req.GetFrom(), req.Heights[0], req.Heights[len(req.Heights)-1])
 	blockCh = make(chan common.BlockInterface, blockchain.DefaultMaxBlkReqPerPeer)
-	stream, err := conn.Requester.StreamBlockByHeight(ctx, req)
+	var stream proto.HighwayService_StreamBlockByHeightClient
+	if conn.IsDirect() {
+		stream, err = conn.directPeers.StreamBlockByHeight(ctx, peerID, req)
+	} else {
+		stream, err = conn.Requester.StreamBlockByHeight(ctx, req)
+	}
 	if err != nil {
 		Logger.Errorf("[stream] %v", err)
 		return nil, err
@@ -528,7 +761,12 @@ func (conn *ConnManager) requestBlocksViaStream(ctx context.Context, peerID stri
 func (conn *ConnManager) requestBlocksByHashViaStream(ctx context.Context, peerID string, req *proto.BlockByHashRequest) (blockCh chan common.BlockInterface, err error) {
 	Logger.Infof("SYNCKER Request Block by hash from peerID %v, from CID %v, total %v blocks", peerID, req.From, len(req.Hashes))
 	blockCh = make(chan common.BlockInterface, blockchain.DefaultMaxBlkReqPerPeer)
-	stream, err := conn.Requester.StreamBlockByHash(ctx, req)
+	var stream proto.HighwayService_StreamBlockByHashClient
+	if conn.IsDirect() {
+		stream, err = conn.directPeers.StreamBlockByHash(ctx, peerID, req)
+	} else {
+		stream, err = conn.Requester.StreamBlockByHash(ctx, req)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +177,7 @@ func TestPeriodicManageSub(t *testing.T) {
 		registerRequests: make(chan peer.ID, 10),
 		Subscriber:       sc,
 		keeper:           NewAddrKeeper(),
+		direct:           true, // subscribe without waiting for a highway
 	}
 	go cm.manageRoleSubscription()
 	time.Sleep(RegisterTimestep + 50*time.Millisecond)
@@ -195,6 +196,7 @@ func TestForcedSub(t *testing.T) {
 		registerRequests: make(chan peer.ID, 10),
 		Subscriber:       sc,
 		keeper:           NewAddrKeeper(),
+		direct:           true, // subscribe without waiting for a highway
 	}
 	cm.registerRequests <- peer.ID("") // Sent forced, must sub with forced = True next time
 	go cm.manageRoleSubscription()
@@ -222,6 +224,10 @@ func (subCounter *subscribeCounter) GetMsgToTopics() msgToTopics {
 	return msgToTopics{}
 }
 
+func (subCounter *subscribeCounter) SetSyncMode(string) {}
+
+func (subCounter *subscribeCounter) SetRegisterer(Registerer) {}
+
 func setupHost() (*mocks.Host, *mocks.Network) {
 	net := &mocks.Network{}
 	h := &mocks.Host{}
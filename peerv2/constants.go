@@ -2,6 +2,20 @@ package peerv2
 
 import "time"
 
+// p2p mode
+const (
+	P2PModeHighway = "highway" // publish and subscribe via highways
+	P2PModeDirect  = "direct"  // gossip directly with static peers and peers found by mDNS, no highway
+	P2PModeAuto    = "auto"    // via highways, fall back to direct gossip while no highway is reachable
+)
+
+// nat mode
+const (
+	NATModeNone     = "none"     // external address from --externaladdress only
+	NATModeDiscover = "discover" // learn external address from peers with AutoNAT and identify
+	NATModeUPnP     = "upnp"     // map listening port on the gateway with UPnP or NAT-PMP, discover if mapping fails
+)
+
 // block type
 const (
 	blockShard         = 0
@@ -24,6 +38,22 @@ var (
 	defaultMaxBlkReqPerPeer   = 900
 	defaultMaxBlkReqPerTime   = 900
 
+	SeenCacheSize       = 20000           // Hashes of received messages to drop duplicates
+	SeenCacheWindow     = 2 * time.Minute // Time to remember a received message
+	MaxDuplicatePerPeer = 2000            // Duplicated messages a peer can send in SeenCacheWindow before being disconnected
+	PeerCodecWindow     = 1 * time.Minute // Time a peer counts in codec negotiation after its last message
+
+	DirectPeerTimestep     = 10 * time.Second // Reconnect static peers in direct mode
+	HighwayFallbackTimeout = 1 * time.Minute  // Switch to direct mode if highway is not reachable for this long
+	MDNSInterval           = 10 * time.Second // Interval of mDNS queries
+	MDNSServiceTag         = "incognito-direct"
+
+	NATUpdateInterval  = 1 * time.Minute  // Check external address and renew port mapping
+	NATMappingLifetime = 20 * time.Minute // Port mapping is renewed after a third of its lifetime
+
+	CompactBlockCacheSize    = 64              // Recent shard blocks kept to serve transactions of compact blocks
+	CompactBlockFetchTimeout = 5 * time.Second // Time to fetch missing transactions or the full block
+
 	IgnoreRPCDuration = 60 * time.Minute  // Ignore an address after a failed RPC
 	IgnoreHWDuration  = 360 * time.Minute // Ignore a highway when cannot connect
 )
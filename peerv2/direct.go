@@ -0,0 +1,280 @@
+package peerv2
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/banmanager"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// ParseP2PMode - check mode is one of P2PModeHighway (default if empty), P2PModeDirect, P2PModeAuto
+func ParseP2PMode(mode string) (string, error) {
+	switch mode {
+	case "", P2PModeHighway:
+		return P2PModeHighway, nil
+	case P2PModeDirect, P2PModeAuto:
+		return mode, nil
+	}
+	return "", errors.Errorf("invalid p2p mode %v, must be %v, %v or %v", mode, P2PModeHighway, P2PModeDirect, P2PModeAuto)
+}
+
+// ParseStaticPeers - parse comma separated libp2p addresses, e.g. /ip4/1.2.3.4/tcp/9433/p2p/QmPeer
+func ParseStaticPeers(s string) ([]peer.AddrInfo, error) {
+	res := []peer.AddrInfo{}
+	for _, addr := range strings.Split(s, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		addrInfo, err := getAddressInfo(addr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *addrInfo)
+	}
+	return res, nil
+}
+
+// DirectTopic - name of the gossip topic of a message in direct mode.
+// Committee ID is the 2nd element like highway's topics so GetCommitteeIDOfTopic works on both
+func DirectTopic(msg string, cID byte) string {
+	return fmt.Sprintf("%s-%d-direct", msg, cID)
+}
+
+// directRegisterer replaces highway's Register in direct mode: topics are computed locally,
+// every node wanting a message of a committee subscribes to the same gossip topic
+type directRegisterer struct{}
+
+var _ Registerer = directRegisterer{}
+
+func (directRegisterer) Register(
+	ctx context.Context,
+	pubkey string,
+	messages []string,
+	committeeIDs []byte,
+	selfID peer.ID,
+	role string,
+) ([]*proto.MessageTopicPair, *proto.UserRole, error) {
+	pairs := []*proto.MessageTopicPair{}
+	for _, msg := range messages {
+		pair := &proto.MessageTopicPair{Message: msg}
+		added := map[byte]bool{}
+		add := func(cID byte, act proto.MessageTopicPair_Action) {
+			if added[cID] {
+				return
+			}
+			added[cID] = true
+			pair.Topic = append(pair.Topic, DirectTopic(msg, cID))
+			pair.Act = append(pair.Act, act)
+		}
+
+		if msg == wire.CmdBlockBeacon {
+			add(HighwayBeaconID, proto.MessageTopicPair_PUBSUB)
+			pairs = append(pairs, pair)
+			continue
+		}
+		isBeacon := false
+		for _, cID := range committeeIDs {
+			isBeacon = isBeacon || cID == HighwayBeaconID
+			add(cID, proto.MessageTopicPair_PUBSUB)
+		}
+		for sID := 0; sID < common.MaxShardNumber; sID++ {
+			switch {
+			case isBeacon && msg == wire.CmdBlockShard:
+				// beacon and full nodes follow blocks of all shards
+				add(byte(sID), proto.MessageTopicPair_PUBSUB)
+			case msg == wire.CmdCrossShard || msg == wire.CmdTx || msg == wire.CmdPrivacyCustomToken:
+				// sent to other shards
+				add(byte(sID), proto.MessageTopicPair_PUB)
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+
+	topicRole := &proto.UserRole{Role: role, Shard: -1}
+	if len(committeeIDs) > 0 {
+		topicRole.Shard = int32(committeeIDs[0])
+	}
+	return pairs, topicRole, nil
+}
+
+func (directRegisterer) Target() string {
+	return ""
+}
+
+func (directRegisterer) UpdateTarget(peer.ID) {}
+
+// DirectPeers keeps connections to static peers and peers found by mDNS,
+// and streams blocks from them via their BlockProvider
+type DirectPeers struct {
+	host   host.Host
+	dialer GRPCDialer
+	static []peer.AddrInfo
+	found  chan peer.AddrInfo
+	bans   *banmanager.BanManager // banned peers are not connected nor streamed from, nil to disable
+
+	conns map[peer.ID]*grpc.ClientConn
+	sync.Mutex
+}
+
+func NewDirectPeers(h host.Host, dialer GRPCDialer, static []peer.AddrInfo, bans *banmanager.BanManager) *DirectPeers {
+	return &DirectPeers{
+		host:   h,
+		dialer: dialer,
+		static: static,
+		found:  make(chan peer.AddrInfo, 100),
+		bans:   bans,
+		conns:  map[peer.ID]*grpc.ClientConn{},
+	}
+}
+
+func (dp *DirectPeers) isBanned(pid peer.ID) bool {
+	return dp.bans != nil && dp.bans.IsBanned(banmanager.KeyPeerID, pid.Pretty())
+}
+
+// HandlePeerFound - implement discovery.Notifee
+func (dp *DirectPeers) HandlePeerFound(addrInfo peer.AddrInfo) {
+	if addrInfo.ID == dp.host.ID() {
+		return
+	}
+	select {
+	case dp.found <- addrInfo:
+	default:
+	}
+}
+
+// StartMDNS - discover peers on local network
+func (dp *DirectPeers) StartMDNS(ctx context.Context) error {
+	service, err := discovery.NewMdnsService(ctx, dp.host, MDNSInterval, MDNSServiceTag)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	service.RegisterNotifee(dp)
+	return nil
+}
+
+// keepConnection connects to static peers periodically and to discovered peers when found
+func (dp *DirectPeers) keepConnection(stop chan int) {
+	watchTimestep := time.NewTicker(DirectPeerTimestep)
+	defer watchTimestep.Stop()
+	dp.connectStatic()
+	for {
+		select {
+		case <-watchTimestep.C:
+			dp.connectStatic()
+		case addrInfo := <-dp.found:
+			Logger.Infof("Found direct peer %v", addrInfo.ID.Pretty())
+			dp.connect(addrInfo)
+		case <-stop:
+			Logger.Info("Stop keeping connection to direct peers")
+			return
+		}
+	}
+}
+
+func (dp *DirectPeers) connectStatic() {
+	for _, addrInfo := range dp.static {
+		dp.connect(addrInfo)
+	}
+}
+
+func (dp *DirectPeers) connect(addrInfo peer.AddrInfo) {
+	if dp.host.Network().Connectedness(addrInfo.ID) == network.Connected || dp.isBanned(addrInfo.ID) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DialTimeout)
+	defer cancel()
+	if err := dp.host.Connect(ctx, addrInfo); err != nil {
+		Logger.Warnf("Could not connect to direct peer %v: %v", addrInfo.ID.Pretty(), err)
+	}
+}
+
+// choosePeer - return peerID if it is connected, otherwise a random connected peer. Banned peers are never chosen
+func (dp *DirectPeers) choosePeer(peerID string) (peer.ID, error) {
+	if pid, err := peer.IDB58Decode(peerID); err == nil && dp.host.Network().Connectedness(pid) == network.Connected && !dp.isBanned(pid) {
+		return pid, nil
+	}
+	peers := []peer.ID{}
+	for _, pid := range dp.host.Network().Peers() {
+		if !dp.isBanned(pid) {
+			peers = append(peers, pid)
+		}
+	}
+	if len(peers) == 0 {
+		return "", errors.New("no direct peer connected")
+	}
+	return peers[rand.Intn(len(peers))], nil
+}
+
+// clientConn - return a ready gRPC connection to the BlockProvider of a peer, dial if needed
+func (dp *DirectPeers) clientConn(ctx context.Context, pid peer.ID) (*grpc.ClientConn, error) {
+	dp.Lock()
+	defer dp.Unlock()
+	if conn, ok := dp.conns[pid]; ok {
+		if conn.GetState() == connectivity.Ready || conn.GetState() == connectivity.Idle {
+			return conn, nil
+		}
+		conn.Close()
+		delete(dp.conns, pid)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+	conn, err := dp.dialer.Dial(dialCtx, pid, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dp.conns[pid] = conn
+	return conn, nil
+}
+
+func (dp *DirectPeers) StreamBlockByHeight(
+	ctx context.Context,
+	peerID string,
+	req *proto.BlockByHeightRequest,
+) (proto.HighwayService_StreamBlockByHeightClient, error) {
+	pid, err := dp.choosePeer(peerID)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dp.clientConn(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	req.UUID = genUUID()
+	Logger.Infof("[stream] Requesting direct peer %v stream block type %v, height [%v..%v], uuid = %s", pid.Pretty(), req.Type, req.Heights[0], req.Heights[len(req.Heights)-1], req.UUID)
+	client := proto.NewHighwayServiceClient(conn)
+	return client.StreamBlockByHeight(ctx, req, grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize))
+}
+
+func (dp *DirectPeers) StreamBlockByHash(
+	ctx context.Context,
+	peerID string,
+	req *proto.BlockByHashRequest,
+) (proto.HighwayService_StreamBlockByHashClient, error) {
+	pid, err := dp.choosePeer(peerID)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dp.clientConn(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	req.UUID = genUUID()
+	Logger.Infof("[stream] Requesting direct peer %v stream block type %v by %v hashes, uuid = %s", pid.Pretty(), req.Type, len(req.Hashes), req.UUID)
+	client := proto.NewHighwayServiceClient(conn)
+	return client.StreamBlockByHash(ctx, req, grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize))
+}
@@ -0,0 +1,63 @@
+package peerv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func directTopics(t *testing.T, messages []string, committeeIDs []byte) map[string]map[string]proto.MessageTopicPair_Action {
+	pairs, _, err := directRegisterer{}.Register(context.Background(), "", messages, committeeIDs, peer.ID(""), "")
+	assert.Nil(t, err)
+	res := map[string]map[string]proto.MessageTopicPair_Action{}
+	for _, p := range pairs {
+		res[p.Message] = map[string]proto.MessageTopicPair_Action{}
+		for i, topic := range p.Topic {
+			res[p.Message][topic] = p.Act[i]
+		}
+	}
+	return res
+}
+
+// Shard validator subscribes to topics of its shard, publishes cross shard messages to all shards
+func TestDirectRegistererShard(t *testing.T) {
+	topics := directTopics(t, getMessagesForLayer(common.ShardRole, []byte{1}), []byte{1})
+
+	assert.Equal(t, map[string]proto.MessageTopicPair_Action{"blockbeacon-255-direct": proto.MessageTopicPair_PUBSUB}, topics[wire.CmdBlockBeacon])
+	assert.Equal(t, map[string]proto.MessageTopicPair_Action{"bft-1-direct": proto.MessageTopicPair_PUBSUB}, topics[wire.CmdBFT])
+	assert.Equal(t, map[string]proto.MessageTopicPair_Action{"blockshard-1-direct": proto.MessageTopicPair_PUBSUB}, topics[wire.CmdBlockShard])
+	assert.Equal(t, common.MaxShardNumber, len(topics[wire.CmdCrossShard]))
+	assert.Equal(t, proto.MessageTopicPair_PUBSUB, topics[wire.CmdCrossShard]["crossshard-1-direct"])
+	assert.Equal(t, proto.MessageTopicPair_PUB, topics[wire.CmdCrossShard]["crossshard-2-direct"])
+	assert.Equal(t, 1, GetCommitteeIDOfTopic("crossshard-1-direct"))
+}
+
+// Beacon validator follows shard blocks of all shards
+func TestDirectRegistererBeacon(t *testing.T) {
+	topics := directTopics(t, getMessagesForLayer(common.BeaconRole, []byte{HighwayBeaconID}), []byte{HighwayBeaconID})
+
+	assert.Equal(t, map[string]proto.MessageTopicPair_Action{"bft-255-direct": proto.MessageTopicPair_PUBSUB}, topics[wire.CmdBFT])
+	assert.Equal(t, common.MaxShardNumber+1, len(topics[wire.CmdBlockShard]))
+	for _, act := range topics[wire.CmdBlockShard] {
+		assert.Equal(t, proto.MessageTopicPair_PUBSUB, act)
+	}
+}
+
+func TestParseDirectConfig(t *testing.T) {
+	mode, err := ParseP2PMode("")
+	assert.Nil(t, err)
+	assert.Equal(t, P2PModeHighway, mode)
+	_, err = ParseP2PMode("mesh")
+	assert.NotNil(t, err)
+
+	peers, err := ParseStaticPeers(testHighwayAddress + ", " + testHighwayAddress2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(peers))
+	_, err = ParseStaticPeers("/ip4/127.0.0.1/tcp/9330")
+	assert.NotNil(t, err)
+}
@@ -1,13 +1,12 @@
 package peerv2
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
 	"reflect"
+	"time"
 
+	pubsub "github.com/incognitochain/go-libp2p-pubsub"
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
-	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/peer"
 	"github.com/incognitochain/incognito-chain/wire"
 	libp2p "github.com/libp2p/go-libp2p-core/peer"
@@ -19,104 +18,80 @@ type Dispatcher struct {
 	PublishableMessage []string
 	BC                 *blockchain.BlockChain
 	CurrentHWPeerID    libp2p.ID
+
+	// drop duplicated messages before decoding them, nil to disable
+	Seen *SeenCache
+	// traffic counters, nil to disable
+	Stats *P2PStats
+	// drop messages from banned peers, nil to disable
+	Bans *banmanager.BanManager
+	// codecs decoded by peers, learned from their messages, nil to disable
+	Codecs *PeerCodecs
+	// IsRelay - true for peers relaying messages of others (highway), they are not given to
+	// message listeners as sender of the message. nil if every peer is the sender of its messages
+	IsRelay func(libp2p.ID) bool
 }
 
-//TODO hy parse msg here
-// processInMessageString - this is sub-function of InMessageHandler
-// after receiving a good message from stream,
-// we need analyze it and process with corresponding message type
-func (d *Dispatcher) processInMessageString(msgStr string) error {
-	// NOTE: copy from peerConn.processInMessageString
-	// Parse Message header from last 24 bytes header message
-	jsonDecodeBytesRaw, err := hex.DecodeString(msgStr)
-	if err != nil {
-		return errors.Wrapf(err, "msgStr: %v", msgStr)
+// processInMessage - drop a message already received on another topic or from another peer,
+// otherwise decode and process it
+func (d *Dispatcher) processInMessage(msg *pubsub.Message) error {
+	topic := ""
+	if topics := msg.GetTopicIDs(); len(topics) > 0 {
+		topic = topics[0]
 	}
-
-	// TODO(0xbunyip): separate caching from peerConn
-	// // cache message hash
-	// hashMsgRaw := common.HashH(jsonDecodeBytesRaw).String()
-	// if peerConn.listenerPeer != nil {
-	// 	if err := peerConn.listenerPeer.HashToPool(hashMsgRaw); err != nil {
-	// 		Logger.Error(err)
-	// 		return NewPeerError(HashToPoolError, err, nil)
-	// 	}
-	// }
-	// unzip data before process
-	jsonDecodeBytes, err := common.GZipToBytes(jsonDecodeBytesRaw)
-	if err != nil {
-		return errors.WithStack(err)
+	if d.Bans != nil && d.Bans.IsBanned(banmanager.KeyPeerID, msg.ReceivedFrom.Pretty()) {
+		Logger.Debugf("Drop message on topic %v from banned peer %v", topic, msg.ReceivedFrom.Pretty())
+		return nil
 	}
-
-	// fmt.Printf("In message content : %s", string(jsonDecodeBytes))
-
-	// Parse Message body
-	messageBody := jsonDecodeBytes[:len(jsonDecodeBytes)-wire.MessageHeaderSize]
-
-	messageHeader := jsonDecodeBytes[len(jsonDecodeBytes)-wire.MessageHeaderSize:]
-
-	// get cmd type in header message
-	commandInHeader := bytes.Trim(messageHeader[:wire.MessageCmdTypeSize], "\x00")
-	commandType := string(messageHeader[:len(commandInHeader)])
-	// convert to particular message from message cmd type
-	message, err := wire.MakeEmptyMessage(string(commandType))
-	if err != nil {
-		return errors.WithStack(err)
+	if d.Seen != nil && d.Seen.CheckAndAdd(msg.Data, topic, msg.ReceivedFrom) {
+		Logger.Debugf("Drop duplicated message on topic %v from %v", topic, msg.ReceivedFrom.Pretty())
+		return nil
 	}
-
-	if len(jsonDecodeBytes) > message.MaxPayloadLength(wire.Version) {
-		return errors.Errorf("Message size too lagre %v, it must be less than %v", len(jsonDecodeBytes), message.MaxPayloadLength(wire.Version))
+	if d.Codecs != nil && len(msg.GetFrom()) > 0 {
+		d.Codecs.Seen(libp2p.ID(msg.GetFrom()).Pretty())
+	}
+	from := msg.ReceivedFrom
+	if d.IsRelay != nil && d.IsRelay(from) {
+		from = d.CurrentHWPeerID
 	}
-	// check forward TODO
-	/*if peerConn.config.MessageListeners.GetCurrentRoleShard != nil {
-		cRole, cShard := peerConn.config.MessageListeners.GetCurrentRoleShard()
-		if cShard != nil {
-			fT := messageHeader[wire.MessageCmdTypeSize]
-			if fT == MessageToShard {
-				fS := messageHeader[wire.MessageCmdTypeSize+1]
-				if *cShard != fS {
-					if peerConn.config.MessageListeners.PushRawBytesToShard != nil {
-						err1 := peerConn.config.MessageListeners.PushRawBytesToShard(peerConn, &jsonDecodeBytesRaw, *cShard)
-						if err1 != nil {
-							Logger.Error(err1)
-						}
-					}
-					return NewPeerError(CheckForwardError, err, nil)
-				}
-			}
-		}
-		if cRole != "" {
-			fT := messageHeader[wire.MessageCmdTypeSize]
-			if fT == MessageToBeacon && cRole != "beacon" {
-				if peerConn.config.MessageListeners.PushRawBytesToBeacon != nil {
-					err1 := peerConn.config.MessageListeners.PushRawBytesToBeacon(peerConn, &jsonDecodeBytesRaw)
-					if err1 != nil {
-						Logger.Error(err1)
-					}
-				}
-				return NewPeerError(CheckForwardError, err, nil)
-			}
-		}
-	}*/
+	// highway relays messages of many peers, origin is the peer which published the message
+	origin := from
+	if len(msg.GetFrom()) > 0 {
+		origin = libp2p.ID(msg.GetFrom())
+	}
+	return d.processInMessageString(string(msg.Data), topic, from, origin)
+}
 
-	err = json.Unmarshal(messageBody, &message)
+// processInMessageString - this is sub-function of InMessageHandler
+// after receiving a good message from stream,
+// we need analyze it and process with corresponding message type
+func (d *Dispatcher) processInMessageString(msgStr string, topic string, from libp2p.ID, origin libp2p.ID) error {
+	var message wire.Message
+	var err error
+	// binary codec, legacy message is a hex string
+	if wire.IsBinaryMessage([]byte(msgStr)) {
+		message, err = wire.DecodeBinaryMessage([]byte(msgStr))
+	} else {
+		message, err = wire.DecodeJSONMessage(msgStr)
+	}
 	if err != nil {
+		if d.Stats != nil {
+			d.Stats.AddIn(topic, "", len(msgStr))
+			d.Stats.AddDecodeFailure(topic)
+		}
 		return errors.WithStack(err)
 	}
 	realType := reflect.TypeOf(message)
-	// fmt.Printf("Cmd message type of struct %s", realType.String())
-
-	// // cache message hash
-	// if peerConn.listenerPeer != nil {
-	// 	hashMsg := message.Hash()
-	// 	if err := peerConn.listenerPeer.HashToPool(hashMsg); err != nil {
-	// 		Logger.Error(err)
-	// 		return NewPeerError(CacheMessageHashError, err, nil)
-	// 	}
-	// }
+	if d.Stats != nil {
+		d.Stats.AddIn(topic, message.MessageType(), len(msgStr))
+	}
 
 	// process message for each of message type
-	errProcessMessage := d.processMessageForEachType(realType, message)
+	start := time.Now()
+	errProcessMessage := d.processMessageForEachType(realType, message, from, origin)
+	if d.Stats != nil {
+		d.Stats.AddProcessTime(message.MessageType(), time.Since(start))
+	}
 	if errProcessMessage != nil {
 		return errors.WithStack(errProcessMessage)
 	}
@@ -127,26 +102,33 @@ func (d *Dispatcher) processInMessageString(msgStr string) error {
 }
 
 // process message for each of message type
-func (d *Dispatcher) processMessageForEachType(messageType reflect.Type, message wire.Message) error {
+func (d *Dispatcher) processMessageForEachType(messageType reflect.Type, message wire.Message, from libp2p.ID, origin libp2p.ID) error {
 	// NOTE: copy from peerConn.processInMessageString
 	Logger.Debugf("Processing msgType %s", message.MessageType())
 	peerConn := &peer.PeerConn{}
-	peerConn.SetRemotePeerID(d.CurrentHWPeerID)
+	peerConn.SetRemotePeerID(from)
 	//fmt.Printf("[stream2] %v\n", peerConn.GetRemotePeerID())
+	// transactions are rate limited by the peer which published them
+	originConn := &peer.PeerConn{}
+	originConn.SetRemotePeerID(origin)
 	switch messageType {
 	case reflect.TypeOf(&wire.MessageTx{}):
 		if d.MessageListeners.OnTx != nil {
-			d.MessageListeners.OnTx(peerConn, message.(*wire.MessageTx))
+			d.MessageListeners.OnTx(originConn, message.(*wire.MessageTx))
 		}
 	case reflect.TypeOf(&wire.MessageTxPrivacyToken{}):
 		if d.MessageListeners.OnTxPrivacyToken != nil {
-			d.MessageListeners.OnTxPrivacyToken(peerConn, message.(*wire.MessageTxPrivacyToken))
+			d.MessageListeners.OnTxPrivacyToken(originConn, message.(*wire.MessageTxPrivacyToken))
 		}
 	case reflect.TypeOf(&wire.MessageBlockShard{}):
 		// Logger.Infof("Processing msgContent %+v", message.(*wire.MessageBlockShard).Block)
 		if d.MessageListeners.OnBlockShard != nil {
 			d.MessageListeners.OnBlockShard(peerConn, message.(*wire.MessageBlockShard))
 		}
+	case reflect.TypeOf(&wire.MessageCompactBlockShard{}):
+		if d.MessageListeners.OnCompactBlockShard != nil {
+			d.MessageListeners.OnCompactBlockShard(peerConn, originConn, message.(*wire.MessageCompactBlockShard))
+		}
 	case reflect.TypeOf(&wire.MessageBlockBeacon{}):
 		// Logger.Infof("Processing msgContent %+v", message.(*wire.MessageBlockBeacon).Block)
 		if d.MessageListeners.OnBlockBeacon != nil {
@@ -183,7 +165,7 @@ func (d *Dispatcher) processMessageForEachType(messageType reflect.Type, message
 			d.MessageListeners.OnGetAddr(peerConn, message.(*wire.MessageGetAddr))
 		}
 	case reflect.TypeOf(&wire.MessageAddr{}):
-		if d.MessageListeners.OnGetAddr != nil {
+		if d.MessageListeners.OnAddr != nil {
 			d.MessageListeners.OnAddr(peerConn, message.(*wire.MessageAddr))
 		}
 	case reflect.TypeOf(&wire.MessageBFT{}):
@@ -191,6 +173,10 @@ func (d *Dispatcher) processMessageForEachType(messageType reflect.Type, message
 			d.MessageListeners.OnBFTMsg(peerConn, message.(*wire.MessageBFT))
 		}
 	case reflect.TypeOf(&wire.MessagePeerState{}):
+		if d.Codecs != nil {
+			peerState := message.(*wire.MessagePeerState)
+			d.Codecs.Advertise(peerState.SenderID, peerState.MaxCodec)
+		}
 		if d.MessageListeners.OnPeerState != nil {
 			d.MessageListeners.OnPeerState(peerConn, message.(*wire.MessagePeerState))
 		}
@@ -215,15 +201,17 @@ type MessageListeners struct {
 	OnTx             func(p *peer.PeerConn, msg *wire.MessageTx)
 	OnTxPrivacyToken func(p *peer.PeerConn, msg *wire.MessageTxPrivacyToken)
 	OnBlockShard     func(p *peer.PeerConn, msg *wire.MessageBlockShard)
-	OnBlockBeacon    func(p *peer.PeerConn, msg *wire.MessageBlockBeacon)
-	OnCrossShard     func(p *peer.PeerConn, msg *wire.MessageCrossShard)
-	OnGetBlockBeacon func(p *peer.PeerConn, msg *wire.MessageGetBlockBeacon)
-	OnGetBlockShard  func(p *peer.PeerConn, msg *wire.MessageGetBlockShard)
-	OnGetCrossShard  func(p *peer.PeerConn, msg *wire.MessageGetCrossShard)
-	OnVersion        func(p *peer.PeerConn, msg *wire.MessageVersion)
-	OnVerAck         func(p *peer.PeerConn, msg *wire.MessageVerAck)
-	OnGetAddr        func(p *peer.PeerConn, msg *wire.MessageGetAddr)
-	OnAddr           func(p *peer.PeerConn, msg *wire.MessageAddr)
+	// compact block, the remote peer id of p is empty if relayed by a highway, origin is the peer which published it
+	OnCompactBlockShard func(p *peer.PeerConn, origin *peer.PeerConn, msg *wire.MessageCompactBlockShard)
+	OnBlockBeacon       func(p *peer.PeerConn, msg *wire.MessageBlockBeacon)
+	OnCrossShard        func(p *peer.PeerConn, msg *wire.MessageCrossShard)
+	OnGetBlockBeacon    func(p *peer.PeerConn, msg *wire.MessageGetBlockBeacon)
+	OnGetBlockShard     func(p *peer.PeerConn, msg *wire.MessageGetBlockShard)
+	OnGetCrossShard     func(p *peer.PeerConn, msg *wire.MessageGetCrossShard)
+	OnVersion           func(p *peer.PeerConn, msg *wire.MessageVersion)
+	OnVerAck            func(p *peer.PeerConn, msg *wire.MessageVerAck)
+	OnGetAddr           func(p *peer.PeerConn, msg *wire.MessageGetAddr)
+	OnAddr              func(p *peer.PeerConn, msg *wire.MessageAddr)
 
 	//PBFT
 	OnBFTMsg    func(p *peer.PeerConn, msg wire.Message)
@@ -0,0 +1,78 @@
+package peerv2
+
+import (
+	"testing"
+
+	pubsub "github.com/incognitochain/go-libp2p-pubsub"
+	pb "github.com/incognitochain/go-libp2p-pubsub/pb"
+	"github.com/incognitochain/incognito-chain/banmanager"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peer"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wire"
+	libp2p "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Messages of banned peers are dropped, others are given to listeners with the peer as sender, except for highway
+func TestDispatcherBannedPeer(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	bans, err := banmanager.NewBanManager("")
+	assert.Nil(t, err)
+	senders := []libp2p.ID{}
+	highway := libp2p.ID("highway")
+	d := &Dispatcher{
+		MessageListeners: &MessageListeners{
+			OnPeerState: func(p *peer.PeerConn, msg *wire.MessagePeerState) {
+				senders = append(senders, p.GetRemotePeerID())
+			},
+		},
+		Bans:    bans,
+		IsRelay: func(pid libp2p.ID) bool { return pid == highway },
+	}
+	data, err := wire.EncodeJSONMessage(&wire.MessagePeerState{Timestamp: 1})
+	assert.Nil(t, err)
+	msg := func(from libp2p.ID) *pubsub.Message {
+		return &pubsub.Message{Message: &pb.Message{Data: []byte(data), TopicIDs: []string{"peerstate-1-direct"}}, ReceivedFrom: from}
+	}
+
+	good, bad := libp2p.ID("good"), libp2p.ID("bad")
+	_, err = bans.Ban(banmanager.KeyPeerID, bad.Pretty(), banmanager.ReasonInvalidBlock, "")
+	assert.Nil(t, err)
+	assert.Nil(t, d.processInMessage(msg(bad)))
+	assert.Nil(t, d.processInMessage(msg(good)))
+	assert.Nil(t, d.processInMessage(msg(highway)))
+	assert.Equal(t, []libp2p.ID{good, ""}, senders)
+}
+
+// Transactions relayed by highway are given to listeners with the peer which published them as sender
+func TestDispatcherTxOrigin(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	senders := []libp2p.ID{}
+	highway := libp2p.ID("highway")
+	d := &Dispatcher{
+		MessageListeners: &MessageListeners{
+			OnTx: func(p *peer.PeerConn, msg *wire.MessageTx) {
+				senders = append(senders, p.GetRemotePeerID())
+			},
+			OnPeerState: func(p *peer.PeerConn, msg *wire.MessagePeerState) {
+				senders = append(senders, p.GetRemotePeerID())
+			},
+		},
+		IsRelay:         func(pid libp2p.ID) bool { return pid == highway },
+		CurrentHWPeerID: highway,
+	}
+	publish := func(origin libp2p.ID, msg wire.Message) {
+		data, err := wire.EncodeJSONMessage(msg)
+		assert.Nil(t, err)
+		assert.Nil(t, d.processInMessage(&pubsub.Message{
+			Message:      &pb.Message{From: []byte(origin), Data: []byte(data), TopicIDs: []string{"txs-1-direct"}},
+			ReceivedFrom: highway,
+		}))
+	}
+
+	origin := libp2p.ID("origin")
+	publish(origin, &wire.MessageTx{Transaction: &transaction.Tx{Version: 1, Type: common.TxNormalType, Fee: 1}})
+	publish(origin, &wire.MessagePeerState{Timestamp: 1})
+	assert.Equal(t, []libp2p.ID{origin, highway}, senders)
+}
@@ -0,0 +1,40 @@
+package peerv2
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+// A message received from a gossip topic, in legacy or binary codec
+func FuzzProcessInMessageString(f *testing.F) {
+	blockchain.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	transaction.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	msgs := []wire.Message{
+		&wire.MessageBFT{Type: "vote", ChainKey: "beacon", Content: []byte(`{"BlockHash":"abc"}`), TimeSlot: 1},
+		&wire.MessageBlockShard{Block: blockchain.NewShardBlock()},
+		&wire.MessageTx{Transaction: &transaction.Tx{Version: 1, Type: common.TxNormalType, Fee: 10}},
+		&wire.MessagePeerState{Shards: map[byte]wire.ChainState{1: {Height: 10}}, CrossShardPool: map[byte]map[byte][]uint64{}},
+	}
+	for _, msg := range msgs {
+		data, err := wire.EncodeJSONMessage(msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		if binaryData, err := wire.EncodeBinaryMessage(msg); err == nil {
+			f.Add(string(binaryData))
+		}
+	}
+	d := &Dispatcher{
+		MessageListeners: &MessageListeners{},
+		Stats:            NewP2PStats(metrics.NewRegistry()),
+	}
+	f.Fuzz(func(t *testing.T, msgStr string) {
+		d.processInMessageString(msgStr, "bft-1-direct", "", "")
+	})
+}
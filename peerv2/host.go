@@ -40,9 +40,11 @@ type Host struct {
 	Host     host.Host
 	SelfPeer *Peer
 	GRPC     *p2pgrpc.GRPCProtocol
+	NAT      *NATManager
 }
 
-func NewHost(version string, pubIP string, port int, privateKey string) *Host {
+// NewHost - natMgr adds the external address of the node to the addresses advertised by identify, nil to disable
+func NewHost(version string, pubIP string, port int, privateKey string, natMgr *NATManager) *Host {
 	// TODO(@bunyip): handle errors
 	var privKey crypto.PrivKey
 	if len(privateKey) == 0 {
@@ -64,6 +66,9 @@ func NewHost(version string, pubIP string, port int, privateKey string) *Host {
 		libp2p.ListenAddrs(listenAddr),
 		libp2p.Identity(privKey),
 	}
+	if natMgr != nil {
+		opts = append(opts, libp2p.AddrsFactory(natMgr.AddrsFactory))
+	}
 
 	p2pHost, err := libp2p.New(ctx, opts...)
 	if err != nil {
@@ -83,6 +88,10 @@ func NewHost(version string, pubIP string, port int, privateKey string) *Host {
 		SelfPeer: selfPeer,
 		Version:  version,
 		GRPC:     p2pgrpc.NewGRPCProtocol(ctx, p2pHost),
+		NAT:      natMgr,
+	}
+	if natMgr != nil {
+		natMgr.Start(p2pHost)
 	}
 
 	Logger.Infof("selfPeer: %v %v %v", selfPeer.PeerID.String(), selfPeer.IP, selfPeer.Port)
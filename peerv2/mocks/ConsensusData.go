@@ -2,6 +2,7 @@
 
 package mocks
 
+import consensus "github.com/incognitochain/incognito-chain/common/consensus"
 import mock "github.com/stretchr/testify/mock"
 
 // ConsensusData is an autogenerated mock type for the ConsensusData type
@@ -9,30 +10,34 @@ type ConsensusData struct {
 	mock.Mock
 }
 
-// GetUserRole provides a mock function with given fields:
-func (_m *ConsensusData) GetUserRole() (string, string, int) {
+// GetOneValidator provides a mock function with given fields:
+func (_m *ConsensusData) GetOneValidator() *consensus.Validator {
 	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
+	var r0 *consensus.Validator
+	if rf, ok := ret.Get(0).(func() *consensus.Validator); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*consensus.Validator)
+		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func() string); ok {
-		r1 = rf()
-	} else {
-		r1 = ret.Get(1).(string)
-	}
+	return r0
+}
 
-	var r2 int
-	if rf, ok := ret.Get(2).(func() int); ok {
-		r2 = rf()
+// GetOneValidatorForEachConsensusProcess provides a mock function with given fields:
+func (_m *ConsensusData) GetOneValidatorForEachConsensusProcess() map[int]*consensus.Validator {
+	ret := _m.Called()
+
+	var r0 map[int]*consensus.Validator
+	if rf, ok := ret.Get(0).(func() map[int]*consensus.Validator); ok {
+		r0 = rf()
 	} else {
-		r2 = ret.Get(2).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]*consensus.Validator)
+		}
 	}
 
-	return r0, r1, r2
+	return r0
 }
@@ -4,7 +4,7 @@ package mocks
 
 import mock "github.com/stretchr/testify/mock"
 
-import pubsub "github.com/libp2p/go-libp2p-pubsub"
+import pubsub "github.com/incognitochain/go-libp2p-pubsub"
 
 // Subscriber is an autogenerated mock type for the Subscriber type
 type Subscriber struct {
@@ -0,0 +1,333 @@
+package peerv2
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	basichost "github.com/libp2p/go-libp2p/p2p/host/basic"
+	"github.com/libp2p/go-nat"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+const natMappingDescription = "incognito"
+
+// ParseNATMode - check mode is one of NATModeNone (default if empty), NATModeDiscover, NATModeUPnP
+func ParseNATMode(mode string) (string, error) {
+	switch mode {
+	case "", NATModeNone:
+		return NATModeNone, nil
+	case NATModeDiscover, NATModeUPnP:
+		return mode, nil
+	}
+	return "", errors.Errorf("invalid nat mode %v, must be %v, %v or %v", mode, NATModeNone, NATModeDiscover, NATModeUPnP)
+}
+
+// NATStatus - state of NAT traversal, shown in getnetworkinfo
+type NATStatus struct {
+	Mode              string   `json:"Mode"`
+	Reachability      string   `json:"Reachability"`      // Unknown, Public or Private, as dialed back by peers with AutoNAT
+	Device            string   `json:"Device"`            // kind of gateway mapping the listening port, e.g. UPNP (IG2) or NAT-PMP
+	MappedAddress     string   `json:"MappedAddress"`     // address mapped on the gateway
+	ObservedAddresses []string `json:"ObservedAddresses"` // addresses peers see this node at, from identify
+	ExternalAddress   string   `json:"ExternalAddress"`   // address reported to highways and bootnodes
+	Error             string   `json:"Error"`             // last error of gateway discovery or port mapping
+}
+
+// NATManager - map the listening port on the NAT gateway and discover the external address of the node.
+// The external address is, by priority: --externaladdress, the mapped address, then the address
+// AutoNAT found dialable when the node is publicly reachable.
+// It is advertised to highways by identify (see AddrsFactory) and to bootnodes by OnExternalAddress listeners
+type NATManager struct {
+	mode            string
+	port            int
+	configured      multiaddr.Multiaddr
+	discoverGateway func() (nat.NAT, error) // nat.DiscoverGateway, a stub device in tests
+	now             func() time.Time
+
+	mtx               sync.RWMutex
+	host              host.Host
+	device            nat.NAT
+	mapped            multiaddr.Multiaddr
+	mappedAt          time.Time
+	reachability      network.Reachability
+	publicAddr        multiaddr.Multiaddr
+	observed          []multiaddr.Multiaddr
+	external          multiaddr.Multiaddr
+	lastErr           error
+	onExternalAddress []func(addr multiaddr.Multiaddr)
+
+	cQuit     chan struct{}
+	closeOnce sync.Once
+}
+
+// NewNATManager - mode is checked by ParseNATMode, port is the listening TCP port and
+// externalAddress the host:port given by --externaladdress, if any
+func NewNATManager(mode string, port int, externalAddress string) (*NATManager, error) {
+	mode, err := ParseNATMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	n := &NATManager{
+		mode:            mode,
+		port:            port,
+		discoverGateway: nat.DiscoverGateway,
+		now:             time.Now,
+		cQuit:           make(chan struct{}),
+	}
+	if externalAddress != "" {
+		if n.configured, err = HostPortToMultiaddr(externalAddress); err != nil {
+			Logger.Warnf("Ignore external address %v: %v", externalAddress, err)
+		}
+	}
+	n.external = n.configured
+	return n, nil
+}
+
+// AddOnExternalAddress - f is called when the external address changes, with nil if it is lost
+func (n *NATManager) AddOnExternalAddress(f func(addr multiaddr.Multiaddr)) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.onExternalAddress = append(n.onExternalAddress, f)
+}
+
+// AddrsFactory - add the external address to the addresses of the libp2p host, see libp2p.AddrsFactory
+func (n *NATManager) AddrsFactory(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
+	external := n.ExternalAddress()
+	if external == nil {
+		return addrs
+	}
+	for _, addr := range addrs {
+		if addr.Equal(external) {
+			return addrs
+		}
+	}
+	return append(addrs, external)
+}
+
+// ExternalAddress - address the node is reachable at from the internet, nil if unknown
+func (n *NATManager) ExternalAddress() multiaddr.Multiaddr {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	return n.external
+}
+
+// Start - map the port and watch reachability of h until Close, in background
+func (n *NATManager) Start(h host.Host) {
+	n.mtx.Lock()
+	n.host = h
+	n.mtx.Unlock()
+	if n.mode == NATModeNone {
+		return
+	}
+	sub, err := h.EventBus().Subscribe(new(event.EvtLocalReachabilityChanged))
+	if err != nil {
+		Logger.Warnf("Cannot watch reachability changes: %v", err)
+		sub = nil
+	}
+	go n.run(sub)
+}
+
+// Close - stop updating and delete the port mapping
+func (n *NATManager) Close() {
+	n.closeOnce.Do(func() {
+		close(n.cQuit)
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+		if n.device != nil && n.mapped != nil {
+			if err := n.device.DeletePortMapping("tcp", n.port); err != nil {
+				Logger.Warnf("Cannot delete port mapping of %v: %v", n.port, err)
+			}
+			n.mapped = nil
+		}
+	})
+}
+
+// Status - current state for getnetworkinfo
+func (n *NATManager) Status() NATStatus {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	status := NATStatus{
+		Mode:              n.mode,
+		Reachability:      n.reachability.String(),
+		ObservedAddresses: []string{},
+	}
+	if n.device != nil {
+		status.Device = n.device.Type()
+	}
+	if n.mapped != nil {
+		status.MappedAddress = n.mapped.String()
+	}
+	for _, addr := range n.observed {
+		status.ObservedAddresses = append(status.ObservedAddresses, addr.String())
+	}
+	if n.external != nil {
+		status.ExternalAddress = n.external.String()
+	}
+	if n.lastErr != nil {
+		status.Error = n.lastErr.Error()
+	}
+	return status
+}
+
+func (n *NATManager) run(sub event.Subscription) {
+	var reachabilityChanged <-chan interface{}
+	if sub != nil {
+		defer sub.Close()
+		reachabilityChanged = sub.Out()
+	}
+	ticker := time.NewTicker(NATUpdateInterval)
+	defer ticker.Stop()
+	n.update()
+	for {
+		select {
+		case <-n.cQuit:
+			return
+		case <-ticker.C:
+			n.update()
+		case <-reachabilityChanged:
+			n.update()
+		}
+	}
+}
+
+// update - renew the port mapping if needed, collect what peers tell about this node
+// and notify listeners if the external address changed
+func (n *NATManager) update() {
+	if n.mode == NATModeUPnP {
+		n.renewMapping()
+	}
+	n.discover()
+
+	n.mtx.Lock()
+	external := n.configured
+	switch {
+	case external != nil:
+	case n.mapped != nil:
+		external = n.mapped
+	case n.reachability == network.ReachabilityPublic && n.publicAddr != nil:
+		external = n.publicAddr
+	}
+	changed := (external == nil) != (n.external == nil) || (external != nil && !external.Equal(n.external))
+	n.external = external
+	listeners := n.onExternalAddress
+	n.mtx.Unlock()
+
+	if changed {
+		Logger.Infof("External address changed to %v", external)
+		for _, f := range listeners {
+			f(external)
+		}
+	}
+}
+
+// renewMapping - find the gateway and map the listening port, again after a third of the mapping lifetime
+func (n *NATManager) renewMapping() {
+	n.mtx.RLock()
+	device, mapped, mappedAt := n.device, n.mapped, n.mappedAt
+	n.mtx.RUnlock()
+	if mapped != nil && n.now().Sub(mappedAt) < NATMappingLifetime/3 {
+		return
+	}
+
+	var err error
+	if device == nil {
+		if device, err = n.discoverGateway(); err != nil {
+			n.setMapping(nil, nil, errors.Wrap(err, "discover gateway"))
+			return
+		}
+		Logger.Infof("Found NAT gateway %v", device.Type())
+	}
+	externalPort, err := device.AddPortMapping("tcp", n.port, natMappingDescription, NATMappingLifetime)
+	if err != nil {
+		n.setMapping(device, nil, errors.Wrapf(err, "map port %v", n.port))
+		return
+	}
+	externalIP, err := device.GetExternalAddress()
+	if err != nil {
+		n.setMapping(device, nil, errors.Wrap(err, "get external address of gateway"))
+		return
+	}
+	addr, err := HostPortToMultiaddr(net.JoinHostPort(externalIP.String(), strconv.Itoa(externalPort)))
+	if err != nil {
+		n.setMapping(device, nil, err)
+		return
+	}
+	n.setMapping(device, addr, nil)
+}
+
+func (n *NATManager) setMapping(device nat.NAT, mapped multiaddr.Multiaddr, err error) {
+	if err != nil {
+		Logger.Warnf("NAT port mapping failed: %v", err)
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.device = device
+	n.mapped = mapped
+	n.mappedAt = n.now()
+	n.lastErr = err
+}
+
+// discover - read reachability from AutoNAT and addresses peers observed from identify
+func (n *NATManager) discover() {
+	n.mtx.RLock()
+	h := n.host
+	n.mtx.RUnlock()
+	bh, ok := h.(*basichost.BasicHost)
+	if !ok {
+		return
+	}
+	reachability := network.ReachabilityUnknown
+	var publicAddr multiaddr.Multiaddr
+	if bh.AutoNat != nil {
+		reachability = bh.AutoNat.Status()
+		publicAddr, _ = bh.AutoNat.PublicAddr()
+	}
+	observed := bh.IDService().OwnObservedAddrs()
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.reachability = reachability
+	n.publicAddr = publicAddr
+	n.observed = observed
+}
+
+// HostPortToMultiaddr - convert host:port to a libp2p TCP address, e.g. 1.2.3.4:9433 to /ip4/1.2.3.4/tcp/9433
+func HostPortToMultiaddr(hostPort string) (multiaddr.Multiaddr, error) {
+	h, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil, err
+	}
+	proto := "dns"
+	if ip := net.ParseIP(h); ip != nil {
+		proto = "ip6"
+		if ip.To4() != nil {
+			proto = "ip4"
+		}
+	}
+	return multiaddr.NewMultiaddr("/" + proto + "/" + h + "/tcp/" + port)
+}
+
+// MultiaddrToHostPort - convert a libp2p TCP address to host:port, e.g. /ip4/1.2.3.4/tcp/9433 to 1.2.3.4:9433
+func MultiaddrToHostPort(addr multiaddr.Multiaddr) (string, error) {
+	var h string
+	var err error
+	for _, code := range []int{multiaddr.P_IP4, multiaddr.P_IP6, multiaddr.P_DNS, multiaddr.P_DNS4, multiaddr.P_DNS6} {
+		if h, err = addr.ValueForProtocol(code); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", errors.Errorf("no host in address %v", addr)
+	}
+	port, err := addr.ValueForProtocol(multiaddr.P_TCP)
+	if err != nil {
+		return "", errors.Errorf("no tcp port in address %v", addr)
+	}
+	return net.JoinHostPort(h, port), nil
+}
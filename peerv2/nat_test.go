@@ -0,0 +1,119 @@
+package peerv2
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-nat"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubNAT - gateway mapping internal port p to external port p+1000
+type stubNAT struct {
+	mapped  map[int]time.Duration
+	deleted []int
+	fail    bool
+}
+
+func (s *stubNAT) Type() string { return "stub" }
+
+func (s *stubNAT) GetDeviceAddress() (net.IP, error) { return net.ParseIP("192.168.1.1"), nil }
+
+func (s *stubNAT) GetExternalAddress() (net.IP, error) { return net.ParseIP("203.0.113.7"), nil }
+
+func (s *stubNAT) GetInternalAddress() (net.IP, error) { return net.ParseIP("192.168.1.2"), nil }
+
+func (s *stubNAT) AddPortMapping(protocol string, internalPort int, description string, timeout time.Duration) (int, error) {
+	if s.fail {
+		return 0, errors.New("mapping refused")
+	}
+	s.mapped[internalPort] = timeout
+	return internalPort + 1000, nil
+}
+
+func (s *stubNAT) DeletePortMapping(protocol string, internalPort int) error {
+	delete(s.mapped, internalPort)
+	s.deleted = append(s.deleted, internalPort)
+	return nil
+}
+
+func TestNATManager_UPnP(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	now := time.Unix(1600000000, 0)
+	device := &stubNAT{mapped: map[int]time.Duration{}}
+	n, err := NewNATManager(NATModeUPnP, 9433, "")
+	assert.Nil(t, err)
+	n.discoverGateway = func() (nat.NAT, error) { return device, nil }
+	n.now = func() time.Time { return now }
+	reported := []string{}
+	n.AddOnExternalAddress(func(addr multiaddr.Multiaddr) {
+		if addr == nil {
+			reported = append(reported, "")
+			return
+		}
+		hostPort, err := MultiaddrToHostPort(addr)
+		assert.Nil(t, err)
+		reported = append(reported, hostPort)
+	})
+
+	n.update()
+	assert.Equal(t, NATMappingLifetime, device.mapped[9433])
+	assert.Equal(t, []string{"203.0.113.7:10433"}, reported)
+	status := n.Status()
+	assert.Equal(t, "stub", status.Device)
+	assert.Equal(t, "/ip4/203.0.113.7/tcp/10433", status.MappedAddress)
+	assert.Equal(t, "/ip4/203.0.113.7/tcp/10433", status.ExternalAddress)
+	assert.Equal(t, "", status.Error)
+
+	//the mapped address is advertised by the libp2p host
+	h, err := libp2p.New(context.Background(), libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"), libp2p.AddrsFactory(n.AddrsFactory))
+	assert.Nil(t, err)
+	defer h.Close()
+	assert.Contains(t, h.Addrs(), n.ExternalAddress())
+
+	//mapping is renewed after a third of its lifetime, when the gateway stops mapping the address is lost
+	device.fail = true
+	now = now.Add(NATMappingLifetime / 4)
+	n.update()
+	assert.Equal(t, []string{"203.0.113.7:10433"}, reported)
+	now = now.Add(NATMappingLifetime / 4)
+	n.update()
+	assert.Equal(t, []string{"203.0.113.7:10433", ""}, reported)
+	assert.Contains(t, n.Status().Error, "mapping refused")
+
+	device.fail = false
+	now = now.Add(time.Second)
+	n.update()
+	n.Close()
+	assert.Equal(t, []int{9433}, device.deleted)
+	assert.Empty(t, device.mapped)
+}
+
+func TestNATManager_ConfiguredAddress(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	device := &stubNAT{mapped: map[int]time.Duration{}}
+	n, err := NewNATManager(NATModeUPnP, 9433, "1.2.3.4:9000")
+	assert.Nil(t, err)
+	n.discoverGateway = func() (nat.NAT, error) { return device, nil }
+
+	//--externaladdress is preferred to the mapped address
+	n.update()
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/9000", n.Status().ExternalAddress)
+	assert.Equal(t, "/ip4/203.0.113.7/tcp/10433", n.Status().MappedAddress)
+
+	n, err = NewNATManager(NATModeUPnP, 9433, "")
+	assert.Nil(t, err)
+	n.discoverGateway = func() (nat.NAT, error) { return nil, nat.ErrNoNATFound }
+	n.update()
+	assert.Nil(t, n.ExternalAddress())
+	assert.Contains(t, n.Status().Error, nat.ErrNoNATFound.Error())
+
+	_, err = NewNATManager("pcp", 9433, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,87 @@
+package peerv2
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+// PeerCodecs negotiates the codec of published messages with peers. Every peer heard from is assumed to decode
+// only wire.CodecJSON until its peer state advertises a higher codec (wire.MessagePeerState.MaxCodec).
+// A published message reaches all peers of its topic, so it uses a codec only if every peer heard from
+// in the last `window` decodes it, otherwise it falls back to wire.CodecJSON
+type PeerCodecs struct {
+	mtx    sync.Mutex
+	self   string
+	window time.Duration
+	peers  map[string]*peerCodec // pretty peer id => codec
+
+	now func() time.Time
+}
+
+type peerCodec struct {
+	maxCodec int
+	lastSeen time.Time
+}
+
+// NewPeerCodecs - self is the pretty peer id of this node, its own messages are ignored
+func NewPeerCodecs(self string, window time.Duration) *PeerCodecs {
+	return &PeerCodecs{
+		self:   self,
+		window: window,
+		peers:  map[string]*peerCodec{},
+		now:    time.Now,
+	}
+}
+
+// Seen - a message originated from peerID, it keeps the codec it advertised before
+func (c *PeerCodecs) Seen(peerID string) {
+	c.update(peerID, func(p *peerCodec) {})
+}
+
+// Advertise - peerID decodes codecs up to maxCodec
+func (c *PeerCodecs) Advertise(peerID string, maxCodec int) {
+	c.update(peerID, func(p *peerCodec) {
+		p.maxCodec = maxCodec
+	})
+}
+
+func (c *PeerCodecs) update(peerID string, f func(p *peerCodec)) {
+	if peerID == "" || peerID == c.self {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	p, ok := c.peers[peerID]
+	if !ok {
+		p = &peerCodec{maxCodec: wire.CodecJSON}
+		c.peers[peerID] = p
+	}
+	f(p)
+	p.lastSeen = c.now()
+}
+
+// Negotiate - codec if every peer heard from recently decodes it, otherwise wire.CodecJSON.
+// Before any peer is heard from, wire.CodecJSON is used
+func (c *PeerCodecs) Negotiate(codec int) int {
+	if codec == wire.CodecJSON {
+		return codec
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := c.now()
+	for peerID, p := range c.peers {
+		if now.Sub(p.lastSeen) > c.window {
+			delete(c.peers, peerID)
+			continue
+		}
+		if p.maxCodec < codec {
+			codec = wire.CodecJSON
+		}
+	}
+	if len(c.peers) == 0 {
+		return wire.CodecJSON
+	}
+	return codec
+}
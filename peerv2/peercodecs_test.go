@@ -0,0 +1,66 @@
+package peerv2
+
+import (
+	"testing"
+	"time"
+
+	pubsub "github.com/incognitochain/go-libp2p-pubsub"
+	pb "github.com/incognitochain/go-libp2p-pubsub/pb"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/wire"
+	libp2p "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Binary codec is published only while every peer heard from advertises it
+func TestPeerCodecsNegotiate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	c := NewPeerCodecs("self", time.Minute)
+	c.now = func() time.Time { return now }
+
+	// nobody heard from yet
+	assert.Equal(t, wire.CodecJSON, c.Negotiate(wire.CodecBinary))
+
+	c.Advertise("upgraded", wire.CodecBinary)
+	c.Seen("self")
+	assert.Equal(t, wire.CodecBinary, c.Negotiate(wire.CodecBinary))
+	assert.Equal(t, wire.CodecJSON, c.Negotiate(wire.CodecJSON))
+
+	// old node: its peer state has no MaxCodec
+	c.Advertise("old", 0)
+	assert.Equal(t, wire.CodecJSON, c.Negotiate(wire.CodecBinary))
+
+	// old node left, a node publishing txs without peer state joined
+	now = now.Add(50 * time.Second)
+	c.Seen("upgraded")
+	c.Seen("fullnode")
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, wire.CodecJSON, c.Negotiate(wire.CodecBinary))
+	c.Advertise("fullnode", wire.CodecBinary)
+	assert.Equal(t, wire.CodecBinary, c.Negotiate(wire.CodecBinary))
+}
+
+// Dispatcher learns codecs from the originator of messages and from peer states, also through highway
+func TestDispatcherPeerCodecs(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	highway := libp2p.ID("highway")
+	d := &Dispatcher{
+		MessageListeners: &MessageListeners{},
+		Codecs:           NewPeerCodecs("self", time.Minute),
+		IsRelay:          func(pid libp2p.ID) bool { return pid == highway },
+	}
+	publish := func(from libp2p.ID, msg wire.Message) {
+		data, err := wire.EncodeJSONMessage(msg)
+		assert.Nil(t, err)
+		assert.Nil(t, d.processInMessage(&pubsub.Message{
+			Message:      &pb.Message{From: []byte(from), Data: []byte(data), TopicIDs: []string{"peerstate-1-direct"}},
+			ReceivedFrom: highway,
+		}))
+	}
+
+	upgraded, old := libp2p.ID("upgraded"), libp2p.ID("old")
+	publish(upgraded, &wire.MessagePeerState{Timestamp: 1, SenderID: upgraded.Pretty(), MaxCodec: wire.CodecBinary})
+	assert.Equal(t, wire.CodecBinary, d.Codecs.Negotiate(wire.CodecBinary))
+	publish(old, &wire.MessagePeerState{Timestamp: 2, SenderID: old.Pretty()})
+	assert.Equal(t, wire.CodecJSON, d.Codecs.Negotiate(wire.CodecBinary))
+}
@@ -0,0 +1,107 @@
+package proto
+
+import (
+	context "context"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// BlockProviderService is served by nodes next to HighwayService, for requests between nodes.
+// Highways do not implement it, requests relayed by a highway fail with codes.Unimplemented.
+// Messages are declared by struct tags only, they are not part of highway.proto
+
+// GetBlockTxsRequest - transactions of a block by index, and cross transactions by the hash of their cross shard block
+type GetBlockTxsRequest struct {
+	UUID                  string   `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	Shard                 int32    `protobuf:"varint,2,opt,name=Shard,proto3" json:"Shard,omitempty"`
+	BlockHash             []byte   `protobuf:"bytes,3,opt,name=BlockHash,proto3" json:"BlockHash,omitempty"`
+	TxIndexes             []int32  `protobuf:"varint,4,rep,packed,name=TxIndexes,proto3" json:"TxIndexes,omitempty"`
+	CrossShardBlockHashes [][]byte `protobuf:"bytes,5,rep,name=CrossShardBlockHashes,proto3" json:"CrossShardBlockHashes,omitempty"`
+}
+
+func (m *GetBlockTxsRequest) Reset()         { *m = GetBlockTxsRequest{} }
+func (m *GetBlockTxsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockTxsRequest) ProtoMessage()    {}
+
+// GetBlockTxsResponse - Data is the json of a shard block body with the requested transactions, in requested order,
+// and the requested cross transactions
+type GetBlockTxsResponse struct {
+	Data []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (m *GetBlockTxsResponse) Reset()         { *m = GetBlockTxsResponse{} }
+func (m *GetBlockTxsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBlockTxsResponse) ProtoMessage()    {}
+
+// BlockProviderServiceClient is the client API for BlockProviderService service.
+type BlockProviderServiceClient interface {
+	GetBlockTxs(ctx context.Context, in *GetBlockTxsRequest, opts ...grpc.CallOption) (*GetBlockTxsResponse, error)
+}
+
+type blockProviderServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBlockProviderServiceClient(cc *grpc.ClientConn) BlockProviderServiceClient {
+	return &blockProviderServiceClient{cc}
+}
+
+func (c *blockProviderServiceClient) GetBlockTxs(ctx context.Context, in *GetBlockTxsRequest, opts ...grpc.CallOption) (*GetBlockTxsResponse, error) {
+	out := new(GetBlockTxsResponse)
+	err := c.cc.Invoke(ctx, "/BlockProviderService/GetBlockTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlockProviderServiceServer is the server API for BlockProviderService service.
+type BlockProviderServiceServer interface {
+	GetBlockTxs(context.Context, *GetBlockTxsRequest) (*GetBlockTxsResponse, error)
+}
+
+// UnimplementedBlockProviderServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBlockProviderServiceServer struct {
+}
+
+func (*UnimplementedBlockProviderServiceServer) GetBlockTxs(ctx context.Context, req *GetBlockTxsRequest) (*GetBlockTxsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockTxs not implemented")
+}
+
+func RegisterBlockProviderServiceServer(s *grpc.Server, srv BlockProviderServiceServer) {
+	s.RegisterService(&_BlockProviderService_serviceDesc, srv)
+}
+
+func _BlockProviderService_GetBlockTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockTxsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockProviderServiceServer).GetBlockTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BlockProviderService/GetBlockTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockProviderServiceServer).GetBlockTxs(ctx, req.(*GetBlockTxsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BlockProviderService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "BlockProviderService",
+	HandlerType: (*BlockProviderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlockTxs",
+			Handler:    _BlockProviderService_GetBlockTxs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "blockprovider.go",
+}
@@ -0,0 +1,147 @@
+package peerv2
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// SeenStats - number of received and duplicated messages since node started
+type SeenStats struct {
+	Received        uint64
+	Duplicated      uint64
+	DuplicateTopics map[string]uint64
+	DuplicatePeers  map[string]uint64
+}
+
+// SeenCache remembers hashes of raw received messages for a time window, so a message
+// published on several topics (or relayed by several peers) is decoded and dispatched once.
+// It keeps at most `capacity` hashes, the oldest is dropped first.
+type SeenCache struct {
+	mtx      sync.Mutex
+	capacity int
+	window   time.Duration
+
+	seen  map[common.Hash]time.Time
+	queue []common.Hash // ring buffer of hashes in order of arrival
+	head  int           // index of the oldest hash in queue
+	size  int
+
+	stats SeenStats
+
+	// number of duplicates each peer sent in current window, reset every window
+	peerDups    map[peer.ID]int
+	windowStart time.Time
+
+	onDuplicate func(from peer.ID, topic string, dupInWindow int)
+
+	now func() time.Time
+}
+
+func NewSeenCache(capacity int, window time.Duration) *SeenCache {
+	return &SeenCache{
+		capacity: capacity,
+		window:   window,
+		seen:     make(map[common.Hash]time.Time, capacity),
+		queue:    make([]common.Hash, capacity),
+		stats: SeenStats{
+			DuplicateTopics: map[string]uint64{},
+			DuplicatePeers:  map[string]uint64{},
+		},
+		peerDups: map[peer.ID]int{},
+		now:      time.Now,
+	}
+}
+
+// SetOnDuplicate - set the hook called (without holding the cache lock) every time a peer sends a duplicate,
+// with number of duplicates it sent in current window
+func (c *SeenCache) SetOnDuplicate(f func(from peer.ID, topic string, dupInWindow int)) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.onDuplicate = f
+}
+
+// CheckAndAdd - return true if data was seen in current window, otherwise remember it and return false
+func (c *SeenCache) CheckAndAdd(data []byte, topic string, from peer.ID) bool {
+	hash := common.HashH(data)
+
+	c.mtx.Lock()
+	now := c.now()
+	c.removeExpired(now)
+	c.stats.Received++
+	if _, ok := c.seen[hash]; !ok {
+		c.add(hash, now)
+		c.mtx.Unlock()
+		return false
+	}
+
+	c.stats.Duplicated++
+	c.stats.DuplicateTopics[topic]++
+	c.stats.DuplicatePeers[from.Pretty()]++
+	if now.Sub(c.windowStart) > c.window {
+		c.peerDups = map[peer.ID]int{}
+		c.windowStart = now
+	}
+	c.peerDups[from]++
+	dupInWindow := c.peerDups[from]
+	onDuplicate := c.onDuplicate
+	c.mtx.Unlock()
+
+	if onDuplicate != nil {
+		onDuplicate(from, topic, dupInWindow)
+	}
+	return true
+}
+
+// Stats - return a copy of the duplicate counters
+func (c *SeenCache) Stats() SeenStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	res := SeenStats{
+		Received:        c.stats.Received,
+		Duplicated:      c.stats.Duplicated,
+		DuplicateTopics: make(map[string]uint64, len(c.stats.DuplicateTopics)),
+		DuplicatePeers:  make(map[string]uint64, len(c.stats.DuplicatePeers)),
+	}
+	for topic, count := range c.stats.DuplicateTopics {
+		res.DuplicateTopics[topic] = count
+	}
+	for pid, count := range c.stats.DuplicatePeers {
+		res.DuplicatePeers[pid] = count
+	}
+	return res
+}
+
+// Len - number of remembered hashes
+func (c *SeenCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.size
+}
+
+func (c *SeenCache) add(hash common.Hash, now time.Time) {
+	if c.capacity <= 0 {
+		return
+	}
+	if c.size == c.capacity {
+		c.removeOldest()
+	}
+	c.queue[(c.head+c.size)%c.capacity] = hash
+	c.size++
+	c.seen[hash] = now
+}
+
+// removeExpired - hashes are queued in order of arrival, so expired ones are at the head
+func (c *SeenCache) removeExpired(now time.Time) {
+	for c.size > 0 && now.Sub(c.seen[c.queue[c.head]]) > c.window {
+		c.removeOldest()
+	}
+}
+
+func (c *SeenCache) removeOldest() {
+	delete(c.seen, c.queue[c.head])
+	c.head = (c.head + 1) % c.capacity
+	c.size--
+}
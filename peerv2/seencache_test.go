@@ -0,0 +1,51 @@
+package peerv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Duplicates are dropped and counted per topic and per peer, scoring hook gets duplicates of the peer in current window
+func TestSeenCacheDuplicate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	c := NewSeenCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+	hooked := map[peer.ID]int{}
+	c.SetOnDuplicate(func(from peer.ID, topic string, dupInWindow int) {
+		hooked[from] = dupInWindow
+	})
+
+	peerA, peerB := peer.ID("peerA"), peer.ID("peerB")
+	assert.False(t, c.CheckAndAdd([]byte("block"), "blockshard-0", peerA))
+	assert.True(t, c.CheckAndAdd([]byte("block"), "blockshard-0", peerA))
+	assert.True(t, c.CheckAndAdd([]byte("block"), "bft-0", peerB))
+	assert.False(t, c.CheckAndAdd([]byte("tx"), "tx-0", peerB))
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(4), stats.Received)
+	assert.Equal(t, uint64(2), stats.Duplicated)
+	assert.Equal(t, map[string]uint64{"blockshard-0": 1, "bft-0": 1}, stats.DuplicateTopics)
+	assert.Equal(t, map[string]uint64{peerA.Pretty(): 1, peerB.Pretty(): 1}, stats.DuplicatePeers)
+	assert.Equal(t, map[peer.ID]int{peerA: 1, peerB: 1}, hooked)
+
+	// next window: message is forgotten, duplicates of peer are counted again
+	now = now.Add(2 * time.Minute)
+	assert.False(t, c.CheckAndAdd([]byte("block"), "blockshard-0", peerA))
+	assert.True(t, c.CheckAndAdd([]byte("block"), "blockshard-0", peerA))
+	assert.Equal(t, 1, hooked[peerA])
+	assert.Equal(t, 1, c.Len())
+}
+
+// Oldest hashes are dropped when cache is full
+func TestSeenCacheCapacity(t *testing.T) {
+	c := NewSeenCache(3, time.Minute)
+	for _, data := range []string{"a", "b", "c", "d"} {
+		assert.False(t, c.CheckAndAdd([]byte(data), "", ""))
+	}
+	assert.Equal(t, 3, c.Len())
+	assert.False(t, c.CheckAndAdd([]byte("a"), "", ""))
+	assert.True(t, c.CheckAndAdd([]byte("d"), "", ""))
+}
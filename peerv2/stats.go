@@ -0,0 +1,170 @@
+package peerv2
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// TrafficStats - counters of messages and bytes sent and received
+type TrafficStats struct {
+	InMessages     uint64
+	InBytes        uint64
+	OutMessages    uint64
+	OutBytes       uint64
+	DecodeFailures uint64
+}
+
+// TopicStats - traffic of a gossip topic
+type TopicStats struct {
+	CommitteeID int
+	TrafficStats
+}
+
+// MessageTypeStats - traffic of a message type and time spent processing it, times are in microseconds
+type MessageTypeStats struct {
+	TrafficStats
+	Processed      uint64
+	ProcessTimeSum int64
+	ProcessTimeMax int64
+}
+
+// P2PStatsSnapshot - copy of all counters, returned by getp2pstats RPC
+type P2PStatsSnapshot struct {
+	Since        int64 // unix time the counters started
+	Topics       map[string]TopicStats
+	MessageTypes map[string]MessageTypeStats
+	Committees   map[int]TrafficStats
+	Duplicates   SeenStats
+}
+
+// P2PStats counts traffic of peerv2 by topic, by message type and by committee ID of the topic.
+// Counters are also kept in metrics.DefaultRegistry so they are exported with other metrics
+type P2PStats struct {
+	since        time.Time
+	topics       map[string]*TopicStats
+	messageTypes map[string]*MessageTypeStats
+	registry     metrics.Registry
+	mtx          sync.Mutex
+}
+
+func NewP2PStats(registry metrics.Registry) *P2PStats {
+	if registry == nil {
+		registry = metrics.DefaultRegistry
+	}
+	return &P2PStats{
+		since:        time.Now(),
+		topics:       map[string]*TopicStats{},
+		messageTypes: map[string]*MessageTypeStats{},
+		registry:     registry,
+	}
+}
+
+func (s *P2PStats) topic(topic string) *TopicStats {
+	stats, ok := s.topics[topic]
+	if !ok {
+		stats = &TopicStats{CommitteeID: GetCommitteeIDOfTopic(topic)}
+		s.topics[topic] = stats
+	}
+	return stats
+}
+
+func (s *P2PStats) messageType(msgType string) *MessageTypeStats {
+	stats, ok := s.messageTypes[msgType]
+	if !ok {
+		stats = &MessageTypeStats{}
+		s.messageTypes[msgType] = stats
+	}
+	return stats
+}
+
+func (s *P2PStats) inc(name string, value int) {
+	metrics.GetOrRegisterCounter(name, s.registry).Inc(int64(value))
+}
+
+// AddIn - a message of size bytes received on topic, msgType is empty if it could not be decoded
+func (s *P2PStats) AddIn(topic string, msgType string, size int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	t := s.topic(topic)
+	t.InMessages++
+	t.InBytes += uint64(size)
+	s.inc(fmt.Sprintf("p2p/topic/%s/in/messages", topic), 1)
+	s.inc(fmt.Sprintf("p2p/topic/%s/in/bytes", topic), size)
+	s.inc(fmt.Sprintf("p2p/committee/%d/in/bytes", t.CommitteeID), size)
+	if msgType == "" {
+		return
+	}
+	m := s.messageType(msgType)
+	m.InMessages++
+	m.InBytes += uint64(size)
+	s.inc(fmt.Sprintf("p2p/message/%s/in/messages", msgType), 1)
+	s.inc(fmt.Sprintf("p2p/message/%s/in/bytes", msgType), size)
+}
+
+// AddDecodeFailure - a message received on topic could not be decoded
+func (s *P2PStats) AddDecodeFailure(topic string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.topic(topic).DecodeFailures++
+	s.inc(fmt.Sprintf("p2p/topic/%s/decodefailures", topic), 1)
+}
+
+// AddOut - a message of size bytes published to topic
+func (s *P2PStats) AddOut(topic string, msgType string, size int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	t := s.topic(topic)
+	t.OutMessages++
+	t.OutBytes += uint64(size)
+	m := s.messageType(msgType)
+	m.OutMessages++
+	m.OutBytes += uint64(size)
+	s.inc(fmt.Sprintf("p2p/topic/%s/out/messages", topic), 1)
+	s.inc(fmt.Sprintf("p2p/topic/%s/out/bytes", topic), size)
+	s.inc(fmt.Sprintf("p2p/committee/%d/out/bytes", t.CommitteeID), size)
+	s.inc(fmt.Sprintf("p2p/message/%s/out/messages", msgType), 1)
+	s.inc(fmt.Sprintf("p2p/message/%s/out/bytes", msgType), size)
+}
+
+// AddProcessTime - time spent in processMessageForEachType for a message
+func (s *P2PStats) AddProcessTime(msgType string, d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	m := s.messageType(msgType)
+	m.Processed++
+	us := d.Microseconds()
+	m.ProcessTimeSum += us
+	if us > m.ProcessTimeMax {
+		m.ProcessTimeMax = us
+	}
+	metrics.GetOrRegisterTimer(fmt.Sprintf("p2p/message/%s/process", msgType), s.registry).Update(d)
+}
+
+// Snapshot - copy of counters, committee counters are summed from topic counters
+func (s *P2PStats) Snapshot() P2PStatsSnapshot {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	res := P2PStatsSnapshot{
+		Since:        s.since.Unix(),
+		Topics:       map[string]TopicStats{},
+		MessageTypes: map[string]MessageTypeStats{},
+		Committees:   map[int]TrafficStats{},
+	}
+	for topic, t := range s.topics {
+		res.Topics[topic] = *t
+		c := res.Committees[t.CommitteeID]
+		c.InMessages += t.InMessages
+		c.InBytes += t.InBytes
+		c.OutMessages += t.OutMessages
+		c.OutBytes += t.OutBytes
+		c.DecodeFailures += t.DecodeFailures
+		res.Committees[t.CommitteeID] = c
+	}
+	for msgType, m := range s.messageTypes {
+		res.MessageTypes[msgType] = *m
+	}
+	return res
+}
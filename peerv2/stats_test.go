@@ -0,0 +1,45 @@
+package peerv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+// Traffic is counted by topic, by message type and summed by committee ID of topics
+func TestP2PStats(t *testing.T) {
+	registry := metrics.NewRegistry()
+	s := NewP2PStats(registry)
+	s.AddIn("blockshard-1-direct", wire.CmdBlockShard, 100)
+	s.AddIn("blockshard-1-direct", wire.CmdBlockShard, 50)
+	s.AddIn("bft-1-direct", "", 10)
+	s.AddDecodeFailure("bft-1-direct")
+	s.AddOut("tx-2-direct", wire.CmdTx, 30)
+	s.AddProcessTime(wire.CmdBlockShard, 2*time.Millisecond)
+	s.AddProcessTime(wire.CmdBlockShard, time.Millisecond)
+
+	stats := s.Snapshot()
+	assert.Equal(t, TopicStats{CommitteeID: 1, TrafficStats: TrafficStats{InMessages: 2, InBytes: 150}}, stats.Topics["blockshard-1-direct"])
+	assert.Equal(t, uint64(1), stats.Topics["bft-1-direct"].DecodeFailures)
+	assert.Equal(t, TrafficStats{InMessages: 3, InBytes: 160, DecodeFailures: 1}, stats.Committees[1])
+	assert.Equal(t, TrafficStats{OutMessages: 1, OutBytes: 30}, stats.Committees[2])
+
+	blockShard := stats.MessageTypes[wire.CmdBlockShard]
+	assert.Equal(t, uint64(150), blockShard.InBytes)
+	assert.Equal(t, uint64(2), blockShard.Processed)
+	assert.Equal(t, int64(3000), blockShard.ProcessTimeSum)
+	assert.Equal(t, int64(2000), blockShard.ProcessTimeMax)
+	assert.Equal(t, uint64(30), stats.MessageTypes[wire.CmdTx].OutBytes)
+
+	assert.Equal(t, int64(150), registry.Get("p2p/topic/blockshard-1-direct/in/bytes").(metrics.Counter).Count())
+	assert.Equal(t, int64(160), registry.Get("p2p/committee/1/in/bytes").(metrics.Counter).Count())
+	assert.Equal(t, int64(2), registry.Get("p2p/message/blockshard/process").(metrics.Timer).Count())
+}
+
+func TestGetCommitteeIDOfTopic(t *testing.T) {
+	assert.Equal(t, 3, GetCommitteeIDOfTopic("blockshard-3-direct"))
+	assert.Equal(t, -1, GetCommitteeIDOfTopic("blockshard"))
+}
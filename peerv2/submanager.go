@@ -78,6 +78,11 @@ func (sub *SubManager) SetSyncMode(s string) {
 	sub.syncMode = s
 }
 
+// SetRegisterer - change where topics are registered: highway or direct mode, next Subscribe must be forced
+func (sub *SubManager) SetRegisterer(r Registerer) {
+	sub.registerer = r
+}
+
 // Subscribe registers to proxy and save the list of new topics if needed
 func (sub *SubManager) Subscribe(forced bool) error {
 	rolehash := ""
@@ -139,7 +144,7 @@ func (sub *SubManager) Subscribe(forced bool) error {
 
 		// Registering mining
 		for chainID, validator := range newRole {
-			if validator.PrivateSeed != "" {
+			if validator.Signer != nil { //relayed shards have an empty validator
 				topics, _, err := sub.registerToProxy(
 					validator.MiningKey.GetPublicKeyBase58(),
 					validator.State.Layer,
@@ -383,6 +388,4 @@ func getMessagesForLayer(layer string, shardID []byte) []string {
 		}
 		return msgs
 	}
-
-	return []string{}
 }
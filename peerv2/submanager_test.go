@@ -6,6 +6,7 @@ import (
 
 	pubsub "github.com/incognitochain/go-libp2p-pubsub"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/consensus"
 	"github.com/incognitochain/incognito-chain/peerv2/mocks"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -14,146 +15,127 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func TestGetShardIDsNormalAutoMode(t *testing.T) {
-	role := userRole{shardID: -2}
-	nodeMode := common.NodeModeAuto
-	relayShard := []byte{}
-	shardIDs := getWantedShardIDs(role, nodeMode, relayShard)
-
-	// Must return shardID 255 to be able to get beacon's topics
-	assert.Equal(t, []byte{255}, shardIDs, "incorrect shardIDs")
-}
-
-func TestGetShardIDsValidatorAutoMode(t *testing.T) {
-	role := userRole{shardID: 3}
-	nodeMode := common.NodeModeAuto
-	relayShard := []byte{}
-	shardIDs := getWantedShardIDs(role, nodeMode, relayShard)
-
-	// shardID = 3 is enough when we want msg blockbeacon
-	assert.Equal(t, []byte{3}, shardIDs, "incorrect shardIDs")
+// newConsensusData - node validating nothing, new map each call since Subscribe adds relayed shards to it
+func newConsensusData() *mocks.ConsensusData {
+	consensusData := &mocks.ConsensusData{}
+	consensusData.On("GetOneValidatorForEachConsensusProcess").Return(func() map[int]*consensus.Validator {
+		return map[int]*consensus.Validator{}
+	})
+	consensusData.On("GetOneValidator").Return(nil)
+	return consensusData
 }
 
-func TestGetShardIDsRelayBeacon(t *testing.T) {
-	role := userRole{shardID: -2}
-	nodeMode := common.NodeModeRelay
-	relayShard := []byte{}
-	shardIDs := getWantedShardIDs(role, nodeMode, relayShard)
+func TestSubscribeRelayBeacon(t *testing.T) {
+	registerer := &mocks.Registerer{}
+	var pairs []*proto.MessageTopicPair
+	var err error
+	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
+	sub := &SubManager{
+		info:       info{consensusData: newConsensusData()},
+		registerer: registerer,
+	}
+	err = sub.Subscribe(false)
+	assert.Nil(t, err)
 
-	assert.Equal(t, []byte{255}, shardIDs, "incorrect shardIDs")
+	// Must register shardID 255 to be able to get beacon's topics
+	registerer.AssertNumberOfCalls(t, "Register", 1)
+	registerer.AssertCalled(t, "Register", mock.Anything, mock.Anything, mock.Anything, []byte{255}, mock.Anything, "")
 }
 
-func TestGetShardIDsRelayShards(t *testing.T) {
-	role := userRole{shardID: -2}
-	nodeMode := common.NodeModeRelay
-	relayShard := []byte{1, 2, 5, 7}
-	shardIDs := getWantedShardIDs(role, nodeMode, relayShard)
+func TestSubscribeRelayShards(t *testing.T) {
+	registerer := &mocks.Registerer{}
+	var pairs []*proto.MessageTopicPair
+	var err error
+	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
+	sub := &SubManager{
+		info: info{
+			consensusData: newConsensusData(),
+			relayShard:    []byte{1, 2, 5, 7},
+		},
+		registerer: registerer,
+	}
+	err = sub.Subscribe(false)
+	assert.Nil(t, err)
 
-	assert.Equal(t, []byte{1, 2, 5, 7, 255}, shardIDs, "incorrect shardIDs")
+	// relayed shards have no validator, they are registered once as relay
+	registerer.AssertNumberOfCalls(t, "Register", 1)
+	registerer.AssertCalled(t, "Register", mock.Anything, mock.Anything, mock.Anything, []byte{1, 2, 5, 7}, mock.Anything, "")
 }
 
 func TestSubscribeNoChange(t *testing.T) {
-	role := userRole{
-		layer:   "",
-		role:    "",
-		shardID: -2,
-	}
-	consensusData := &mocks.ConsensusData{}
-	consensusData.On("GetUserRole").Return(role.layer, role.role, role.shardID)
+	registerer := &mocks.Registerer{}
 	sub := &SubManager{
-		info: info{consensusData: consensusData},
-		role: role,
+		info:       info{consensusData: newConsensusData()},
+		registerer: registerer,
+		rolehash:   common.HashH([]byte("")).String(),
 	}
 	forced := false
 	err := sub.Subscribe(forced)
 	assert.Nil(t, err)
+	registerer.AssertNumberOfCalls(t, "Register", 0)
 }
 
 func TestSubscribeRoleChanged(t *testing.T) {
-	role := userRole{
-		layer:   "",
-		role:    "",
-		shardID: -2,
-	}
 	registerer := &mocks.Registerer{}
 	var pairs []*proto.MessageTopicPair
 	err := fmt.Errorf("error preventing further advance")
 	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
-	consensusData := &mocks.ConsensusData{}
-	consensusData.On("GetUserRole").Once().Return(common.ShardRole, common.PendingRole, 1)
 	sub := &SubManager{
-		info: info{
-			consensusData: consensusData,
-			syncMode:      common.NodeModeAuto,
-			relayShard:    []byte{},
-		},
-		role:       role,
+		info:       info{consensusData: newConsensusData()},
 		registerer: registerer,
+		rolehash:   "old rolehash",
 	}
 	forced := false
-	sub.Subscribe(forced)
-	consensusData.AssertNumberOfCalls(t, "GetUserRole", 1)
+	assert.Equal(t, err, sub.Subscribe(forced))
+	registerer.AssertNumberOfCalls(t, "Register", 1)
+	assert.Equal(t, "old rolehash", sub.rolehash, "rolehash must not be saved when registering fails")
 }
 
 func TestSubscribeForced(t *testing.T) {
-	role := userRole{
-		layer:   "",
-		role:    "",
-		shardID: -2,
-	}
 	registerer := &mocks.Registerer{}
 	var pairs []*proto.MessageTopicPair
 	err := fmt.Errorf("error preventing further advance")
 	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
-	consensusData := &mocks.ConsensusData{}
-	consensusData.On("GetUserRole").Return(role.layer, role.role, role.shardID)
 	sub := &SubManager{
-		info: info{
-			consensusData: consensusData,
-			syncMode:      common.NodeModeAuto,
-			relayShard:    []byte{},
-		},
-		role:       role,
+		info:       info{consensusData: newConsensusData()},
 		registerer: registerer,
+		rolehash:   common.HashH([]byte("")).String(),
 	}
 	forced := true
 	sub.Subscribe(forced)
-	consensusData.AssertNumberOfCalls(t, "GetUserRole", 1)
+	registerer.AssertNumberOfCalls(t, "Register", 1)
 }
 
 func TestGetMessage(t *testing.T) {
 	testCases := []struct {
 		desc    string
-		mode    string
 		layer   string
 		shardID []byte
 		out     []string
 	}{
 		{
-			desc:  "Nodemode auto, normal role",
-			mode:  common.NodeModeAuto,
-			layer: "",
-			out:   []string{wire.CmdBlockBeacon, wire.CmdTx, wire.CmdPrivacyCustomToken, wire.CmdPeerState},
-		},
-		{
-			desc:    "Nodemode relay beacon",
-			mode:    common.NodeModeRelay,
+			desc:    "Normal role, relay beacon",
 			layer:   "",
 			shardID: []byte{255},
 			out:     []string{wire.CmdBlockBeacon, wire.CmdTx, wire.CmdPrivacyCustomToken, wire.CmdPeerState},
 		},
 		{
-			desc:    "Nodemode relay shards",
-			mode:    common.NodeModeRelay,
+			desc:    "Normal role, relay shards",
 			layer:   "",
 			shardID: []byte{1, 2, 3},
 			out:     []string{wire.CmdBlockBeacon, wire.CmdBlockShard, wire.CmdTx, wire.CmdPrivacyCustomToken, wire.CmdPeerState},
 		},
+		{
+			desc:    "Beacon layer",
+			layer:   common.BeaconRole,
+			shardID: []byte{255},
+			out:     []string{wire.CmdBlockBeacon, wire.CmdBFT, wire.CmdPeerState, wire.CmdBlockShard},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			msgs := getMessagesForLayer(tc.mode, tc.layer, tc.shardID)
+			msgs := getMessagesForLayer(tc.layer, tc.shardID)
 			compareMsgs(t, tc.out, msgs)
 		})
 	}
@@ -293,8 +275,7 @@ func TestRegisterToProxy(t *testing.T) {
 
 	sub := &SubManager{
 		info: info{
-			syncMode: common.NodeModeAuto,
-			peerID:   peer.ID(""),
+			peerID: peer.ID(""),
 		},
 		registerer: registerer,
 	}
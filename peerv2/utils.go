@@ -41,7 +41,7 @@ func generateRand() []byte {
 // GetCommitteeIDOfTopic handle error later TODO handle error pls
 func GetCommitteeIDOfTopic(topic string) int {
 	topicElements := strings.Split(topic, "-")
-	if len(topicElements) == 0 {
+	if len(topicElements) < 2 {
 		return -1
 	}
 	if topicElements[1] == "" {
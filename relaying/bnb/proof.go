@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"github.com/binance-chain/go-sdk/client/rpc"
 	bnbtx "github.com/binance-chain/go-sdk/types/tx"
 	"github.com/tendermint/tendermint/rpc/client"
@@ -19,12 +20,13 @@ func getProofByTxHash(txHashStr string, url string) (*types.TxProof, int64, *BNB
 	client := client.NewHTTP(url, "/websocket")
 	err = client.Start()
 	if err != nil {
-		// handle error
+		return nil, 0, NewBNBRelayingError(UnexpectedErr, err)
 	}
 	defer client.Stop()
 	tx, err := client.Tx(txHash, true)
-	//fmt.Printf("tx: %+v\n", tx)
-
+	if err != nil {
+		return nil, 0, NewBNBRelayingError(UnexpectedErr, err)
+	}
 
 	return &tx.Proof, tx.Height, nil
 }
@@ -43,10 +45,13 @@ func GetBlock(blockHeight int64, url string) (*types.Block, *BNBRelayingError) {
 	client := client.NewHTTP(url, "/websocket")
 	err := client.Start()
 	if err != nil {
-		// handle error
+		return nil, NewBNBRelayingError(UnexpectedErr, err)
 	}
 	defer client.Stop()
 	block, err := client.Block(&blockHeight)
+	if err != nil {
+		return nil, NewBNBRelayingError(UnexpectedErr, err)
+	}
 
 	return block.Block, nil
 }
@@ -141,3 +146,21 @@ func BuildProofFromTxID(txID string, url string) (string, error) {
 
 	return bnbProofStr, nil
 }
+
+// BuildProofFromBlock builds the proof of tx txHash in block, without calling a bnb fullnode
+func BuildProofFromBlock(block *types.Block, txHash []byte) (string, error) {
+	txIndex := block.Txs.IndexByHash(txHash)
+	if txIndex < 0 {
+		return "", NewBNBRelayingError(ParseProofErr, fmt.Errorf("tx %X is not in block %v", txHash, block.Height))
+	}
+	proof := block.Txs.Proof(txIndex)
+	bnbProof := BNBProof{
+		Proof:       &proof,
+		BlockHeight: block.Height,
+	}
+	bnbProofBytes, err := json.Marshal(bnbProof)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(bnbProofBytes), nil
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/binance-chain/go-sdk/types/msg"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/types"
 	"testing"
 )
 
 func TestGetProofByTxHash(t *testing.T) {
-	txProof, err := getProofByTxHash("421B68266AC570DEC49A12B1DDA0518D59205F4A874A24DB0F9448D4E03720A3", MainnetURLRemote)
+	skipIfNoBNBNode(t, MainnetURLRemote)
+	txProof, _, err := getProofByTxHash("421B68266AC570DEC49A12B1DDA0518D59205F4A874A24DB0F9448D4E03720A3", MainnetURLRemote)
 	assert.Nil(t, err)
 	fmt.Printf("txProof %v\n", txProof.Data)
 
@@ -41,6 +43,7 @@ func TestParseBNBTxFromData(t *testing.T) {
 }
 
 func TestBNBProof(t *testing.T) {
+	skipIfNoBNBNode(t, MainnetURLRemote)
 	txIndex := 0
 	blockHeight := int64(60479432)
 
@@ -100,6 +103,7 @@ func TestBuildAndPushBNBProof(t *testing.T) {
 	txIndex := 0
 	blockHeight := int64(80536994)
 	url := TestnetURLRemote
+	skipIfNoBNBNode(t, url)
 
 	portingProof, err := BuildProof(txIndex, blockHeight, url)
 	if err != nil {
@@ -113,3 +117,25 @@ func TestBuildAndPushBNBProof(t *testing.T) {
 	//}
 	//fmt.Printf("BNB redeemProof: %+v\n", redeemProof)
 }
+
+func TestBuildProofFromBlock(t *testing.T) {
+	txData, _ := hex.DecodeString("A702F0625DEE0A482A2C87FA0A200A141C4693E2455A9DA63C5D8F1240BE3D8466CD0E4612080A03424E4210904E12200A141C4693E2455A9DA63C5D8F1240BE3D8466CD0E4612080A03424E4210904E12710A26EB5AE98721037985B53085AEF69B8B481B5BF35BDE7B20DBF98DB970909048F725849412E3AC12408120FDA9DD3326D440C8D058851AFB5830BC6E12EF896B513EE0095D47273DFF0A640382A848E9B628F6B24672515C33776ABE38EAA5FA188DC8467DA1A63C3618A25C20D0EC031A647B2270726F746F636F6C223A22616C6570682D6F6666636861696E222C2276657273696F6E223A312C22636F6E74656E74223A22516D63644C5A6853736D47364C72445A44397364616A647077616266345978355650483950376152393765324D52227D")
+	block := &types.Block{}
+	block.Height = 100
+	block.Txs = types.Txs{types.Tx("tx0"), types.Tx(txData), types.Tx("tx2")}
+
+	proofStr, err := BuildProofFromBlock(block, types.Tx(txData).Hash())
+	assert.Nil(t, err)
+	proof, err2 := ParseBNBProofFromB64EncodeStr(proofStr)
+	assert.Nil(t, err2)
+	assert.Equal(t, int64(100), proof.BlockHeight)
+	isValid, err2 := proof.Verify(block.Txs.Hash())
+	assert.Nil(t, err2)
+	assert.True(t, isValid)
+	tx, err2 := ParseTxFromData(proof.Proof.Data)
+	assert.Nil(t, err2)
+	assert.Equal(t, "bnb1r3rf8cj9t2w6v0za3ufyp03as3nv6rjxteku6g", tx.Msgs[0].(msg.SendMsg).Outputs[0].Address.String())
+
+	_, err = BuildProofFromBlock(block, types.Tx("tx3").Hash())
+	assert.NotNil(t, err)
+}
@@ -3,7 +3,6 @@ package bnb
 import (
 	"encoding/hex"
 	"errors"
-	client2 "github.com/binance-chain/go-sdk/client"
 	"github.com/binance-chain/go-sdk/common/bech32"
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/go-sdk/keys"
@@ -52,8 +51,7 @@ func IsValidBNBAddress(bnbAddr string, chainID string) bool {
 
 func generateBNBAddress(network types.ChainNetwork) string {
 	km, _ := keys.NewKeyManager()
-	client, _ := client2.NewDexClient("dex.binance.org", network, km) // api string can be "https://testnet-dex.binance.org" for testnet
-	accn := client.GetKeyManager().GetAddr().String()
+	accn, _ := bech32.ConvertAndEncode(network.Bech32Prefixes(), km.GetAddr().Bytes())
 	return accn
 }
 
@@ -10,6 +10,10 @@ import (
 func TestGenerateBNBAddress(t *testing.T) {
 	addr := generateBNBAddress(types.ProdNetwork)
 	fmt.Printf("addr %v\n", addr)
+	assert.Equal(t, true, IsValidBNBAddress(addr, MainnetBNBChainID))
+
+	addr = generateBNBAddress(types.TestNetwork)
+	assert.Equal(t, true, IsValidBNBAddress(addr, TestnetBNBChainID))
 }
 
 func TestIsValidBNBAddress(t *testing.T) {
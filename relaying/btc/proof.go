@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -37,6 +38,37 @@ func ParseBTCProofFromB64EncodeStr(b64EncodedStr string) (*BTCProof, error) {
 	return &proof, nil
 }
 
+// BuildBTCProof builds the proof of tx txHash in block, block must contain all of its txs
+func BuildBTCProof(block *wire.MsgBlock, txHash *chainhash.Hash) (*BTCProof, error) {
+	txHashes := make([]*chainhash.Hash, len(block.Transactions))
+	var btcTx *wire.MsgTx
+	for i, tx := range block.Transactions {
+		hash := tx.TxHash()
+		txHashes[i] = &hash
+		if hash.IsEqual(txHash) {
+			btcTx = tx
+		}
+	}
+	if btcTx == nil {
+		return nil, fmt.Errorf("tx %v is not in block %v", txHash, block.BlockHash())
+	}
+	blockHash := block.BlockHash()
+	return &BTCProof{
+		MerkleProofs: buildMerkleProof(txHashes, txHash),
+		BTCTx:        btcTx,
+		BlockHash:    &blockHash,
+	}, nil
+}
+
+// EncodeBTCProofToB64Str encodes proof the way ParseBTCProofFromB64EncodeStr parses it
+func EncodeBTCProofToB64Str(proof *BTCProof) (string, error) {
+	jsonBytes, err := json.Marshal(proof)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(jsonBytes), nil
+}
+
 func buildMerkleTreeStoreFromTxHashes(txHashes []*chainhash.Hash) []*chainhash.Hash {
 	nextPoT := nextPowerOfTwo(len(txHashes))
 	arraySize := nextPoT*2 - 1
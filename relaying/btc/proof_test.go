@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMerkleVerification1(t *testing.T) {
@@ -81,3 +84,34 @@ func TestBuildTxMsg(t *testing.T) {
 		t.Errorf("Want tx hash %s but got %s", txID, msgTx.TxHash())
 	}
 }
+
+func TestBuildBTCProof(t *testing.T) {
+	block := wire.NewMsgBlock(&wire.BlockHeader{})
+	for i := 0; i < 3; i++ {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.LockTime = uint32(i)
+		block.AddTransaction(tx)
+	}
+	txs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = btcutil.NewTx(tx)
+	}
+	merkleTree := BuildMerkleTreeStore(txs, false)
+	block.Header.MerkleRoot = *merkleTree[len(merkleTree)-1]
+
+	txHash := block.Transactions[2].TxHash()
+	proof, err := BuildBTCProof(block, &txHash)
+	assert.Nil(t, err)
+	assert.Equal(t, block.BlockHash(), *proof.BlockHash)
+	assert.True(t, verify(&block.Header.MerkleRoot, proof.MerkleProofs, &txHash))
+
+	proofStr, err := EncodeBTCProofToB64Str(proof)
+	assert.Nil(t, err)
+	decodedProof, err := ParseBTCProofFromB64EncodeStr(proofStr)
+	assert.Nil(t, err)
+	assert.Equal(t, txHash, decodedProof.BTCTx.TxHash())
+	assert.True(t, verify(&block.Header.MerkleRoot, decodedProof.MerkleProofs, &txHash))
+
+	_, err = BuildBTCProof(block, &chainhash.Hash{})
+	assert.NotNil(t, err)
+}
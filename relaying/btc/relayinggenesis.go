@@ -89,3 +89,19 @@ func GetTestNet3ParamsForInc2() *chaincfg.Params {
 	genesisBlock, genesisHash := getHardcodedTestNet3GenesisBlockForInc2()
 	return putGenesisBlockIntoChainParams(genesisHash, genesisBlock, chaincfg.TestNet3Params)
 }
+
+// GetUTXOChainParams returns params of a btcd-compatible header chain relayed from genesisBlock,
+// a recent header of the chain instead of its real genesis block
+func GetUTXOChainParams(chainParams chaincfg.Params, genesisBlock *wire.MsgBlock) *chaincfg.Params {
+	genesisHash := genesisBlock.BlockHash()
+	return putGenesisBlockIntoChainParams(&genesisHash, genesisBlock, chainParams)
+}
+
+// RegisterUTXOChainParams registers address prefixes of a btcd-compatible chain so its addresses can be decoded
+func RegisterUTXOChainParams(chainParams *chaincfg.Params) error {
+	err := chaincfg.Register(chainParams)
+	if err != nil && err != chaincfg.ErrDuplicateNet {
+		return err
+	}
+	return nil
+}
@@ -15,6 +15,10 @@ const (
 	getNodeRole          = "getnoderole"
 	getInOutMessages     = "getinoutmessages"
 	getInOutMessageCount = "getinoutmessagecount"
+	getP2PStats          = "getp2pstats"
+	listBannedPeers      = "listbannedpeers"
+	banPeer              = "banpeer"
+	unbanPeer            = "unbanpeer"
 
 	estimateFee              = "estimatefee"
 	estimateFeeV2            = "estimatefeev2"
@@ -184,10 +188,16 @@ const (
 	getRoleByValidatorKey       = "getrolebyvalidatorkey"
 	getIncognitoPublicKeyRole   = "getincognitopublickeyrole"
 	getMinerRewardFromMiningKey = "getminerrewardfromminingkey"
+	getConsensusJournal         = "getconsensusjournal"
 
 	// slash
 	getProducersBlackList       = "getproducersblacklist"
 	getProducersBlackListDetail = "getproducersblacklistdetail"
+	getEquivocationEvidences    = "getequivocationevidences"
+	getEquivocationSlashes      = "getequivocationslashes"
+
+	createRawTxWithEquivocationReport     = "createrawtxwithequivocationreport"
+	createAndSendTxWithEquivocationReport = "createandsendtxwithequivocationreport"
 
 	// pde
 	getPDEState                                = "getpdestate"
@@ -211,6 +221,12 @@ const (
 	getPDEFeeWithdrawalStatus                  = "getpdefeewithdrawalstatus"
 	convertPDEPrices                           = "convertpdeprices"
 	extractPDEInstsFromBeaconBlock             = "extractpdeinstsfrombeaconblock"
+	getPDEBestRoute                            = "getpdebestroute"
+	simulatePDETrades                          = "simulatepdetrades"
+	getPDECandles                              = "getpdecandles"
+	getPDEPairStats                            = "getpdepairstats"
+	getPDETradeHistory                         = "getpdetradehistory"
+	getPDELiquidityPosition                    = "getpdeliquidityposition"
 
 	// get burning address
 	getBurningAddress = "getburningaddress"
@@ -223,6 +239,7 @@ const (
 	createAndSendRegisterPortingPublicTokens      = "createandsendregisterportingpublictokens"
 	createAndSendPortalExchangeRates              = "createandsendportalexchangerates"
 	getPortalFinalExchangeRates                   = "getportalfinalexchangerates"
+	getPortalExchangeRateHistory                  = "getportalexchangeratehistory"
 	getPortalPortingRequestByKey                  = "getportalportingrequestbykey"
 	getPortalPortingRequestByPortingId            = "getportalportingrequestbyportingid"
 	convertExchangeRates                          = "convertexchangerates"
@@ -235,6 +252,7 @@ const (
 	createAndSendCustodianWithdrawRequest         = "createandsendcustodianwithdrawrequest"
 	getCustodianWithdrawByTxId                    = "getcustodianwithdrawbytxid"
 	getCustodianLiquidationStatus                 = "getcustodianliquidationstatus"
+	getCustodianHealth                            = "getcustodianhealth"
 	createAndSendTxWithReqWithdrawRewardPortal    = "createandsendtxwithreqwithdrawrewardportal"
 	createAndSendTxRedeemFromLiquidationPoolV3    = "createandsendtxredeemfromliquidationpoolv3"
 	createAndSendCustodianTopup                   = "createandsendcustodiantopup"
@@ -265,13 +283,14 @@ const (
 	getPortalUnlockOverRateCollateralsStatus      = "getportalunlockoverratecollateralsbytxidstatus"
 
 	// relaying
-	createAndSendTxWithRelayingBNBHeader = "createandsendtxwithrelayingbnbheader"
-	createAndSendTxWithRelayingBTCHeader = "createandsendtxwithrelayingbtcheader"
-	getRelayingBNBHeaderState            = "getrelayingbnbheaderstate"
-	getRelayingBNBHeaderByBlockHeight    = "getrelayingbnbheaderbyblockheight"
-	getBTCRelayingBestState              = "getbtcrelayingbeststate"
-	getBTCBlockByHash                    = "getbtcblockbyhash"
-	getLatestBNBHeaderBlockHeight        = "getlatestbnbheaderblockheight"
+	createAndSendTxWithRelayingBNBHeader  = "createandsendtxwithrelayingbnbheader"
+	createAndSendTxWithRelayingBTCHeader  = "createandsendtxwithrelayingbtcheader"
+	createAndSendTxWithRelayingUTXOHeader = "createandsendtxwithrelayingutxoheader"
+	getRelayingBNBHeaderState             = "getrelayingbnbheaderstate"
+	getRelayingBNBHeaderByBlockHeight     = "getrelayingbnbheaderbyblockheight"
+	getBTCRelayingBestState               = "getbtcrelayingbeststate"
+	getBTCBlockByHash                     = "getbtcblockbyhash"
+	getLatestBNBHeaderBlockHeight         = "getlatestbnbheaderblockheight"
 
 	// incognito mode for sc
 	getBurnProofForDepositToSC                  = "getburnprooffordeposittosc"
@@ -312,4 +331,6 @@ const (
 	subcribeBeaconBestState                     = "subcribebeaconbeststate"
 	subcribeBeaconPoolBeststate                 = "subcribebeaconpoolbeststate"
 	subcribeShardPoolBeststate                  = "subcribeshardpoolbeststate"
+	subcribeConsensusJournal                    = "subcribeconsensusjournal"
+	subcribeCustodianHealth                     = "subcribecustodianhealth"
 )
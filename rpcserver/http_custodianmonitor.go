@@ -0,0 +1,30 @@
+package rpcserver
+
+import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleGetCustodianHealth - collateral ratios of a custodian per portal token at the last beacon block
+// evaluated by the custodian monitor, with the exchange rate moves that would trigger its liquidation
+// params: [incognitoAddress]
+func (httpServer *HttpServer) handleGetCustodianHealth(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.CustodianMonitor == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetCustodianHealthError, errors.New("Custodian monitor is not enabled, start the node with --custodianmonitor"))
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Param array must be 1"))
+	}
+	incAddress, ok := arrayParams[0].(string)
+	if !ok || incAddress == "" {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Custodian incognito address is invalid"))
+	}
+	health, err := httpServer.config.CustodianMonitor.GetCustodianHealth(incAddress)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetCustodianHealthError, err)
+	}
+	return health, nil
+}
@@ -2,6 +2,9 @@ package rpcserver
 
 import (
 	"errors"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
@@ -39,6 +42,93 @@ func (httpServer *HttpServer) handleGetInOutMessages(params interface{}, closeCh
 	return result, nil
 }
 
+/*
+handleGetP2PStats - return bytes and messages sent and received via highway/gossipsub,
+by topic, message type and committee, with decode failures, process time and duplicated messages
+*/
+func (httpServer *HttpServer) handleGetP2PStats(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.Highway == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetP2PStatsError, errors.New("p2p connection manager is not available"))
+	}
+	return httpServer.config.Highway.GetP2PStats(), nil
+}
+
+/*
+handleListBannedPeers - return peer IDs and mining keys banned now, with reason and expiry
+*/
+func (httpServer *HttpServer) handleListBannedPeers(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.BanManager == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.ListBannedPeersError, errors.New("ban list is not available"))
+	}
+	return httpServer.config.BanManager.List(), nil
+}
+
+/*
+handleBanPeer - ban a peer ID or mining key.
+Params: key, key type (peerid or miningkey), duration in seconds (optional, 0 bans permanently), message (optional)
+*/
+func (httpServer *HttpServer) handleBanPeer(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.BanManager == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.BanPeerError, errors.New("ban list is not available"))
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 2 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 element"))
+	}
+	key, ok := arrayParams[0].(string)
+	if !ok || key == "" {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key component invalid"))
+	}
+	keyType, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key type component invalid"))
+	}
+	duration := banmanager.ReasonDuration[banmanager.ReasonManual]
+	if len(arrayParams) > 2 {
+		durationParam, ok := arrayParams[2].(float64)
+		if !ok || durationParam < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Duration component invalid"))
+		}
+		duration = time.Duration(durationParam) * time.Second
+	}
+	message := ""
+	if len(arrayParams) > 3 {
+		if message, ok = arrayParams[3].(string); !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Message component invalid"))
+		}
+	}
+	ban, err := httpServer.config.BanManager.BanFor(keyType, key, banmanager.ReasonManual, message, duration)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.BanPeerError, err)
+	}
+	return ban, nil
+}
+
+/*
+handleUnbanPeer - lift the ban of a peer ID or mining key. Params: key, key type (peerid or miningkey)
+*/
+func (httpServer *HttpServer) handleUnbanPeer(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.BanManager == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnbanPeerError, errors.New("ban list is not available"))
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 2 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 element"))
+	}
+	key, ok := arrayParams[0].(string)
+	if !ok || key == "" {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key component invalid"))
+	}
+	keyType, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key type component invalid"))
+	}
+	if err := httpServer.config.BanManager.Unban(keyType, key); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnbanPeerError, err)
+	}
+	return true, nil
+}
+
 /*
 handleGetAllConnectedPeers - return all connnected peers which this node connected
 */
@@ -64,6 +154,9 @@ func (httpServer *HttpServer) handleGetNetWorkInfo(params interface{}, closeChan
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
 	}
+	if httpServer.config.Highway != nil {
+		result.NAT = httpServer.config.Highway.GetNATStatus()
+	}
 	return result, nil
 }
 
@@ -185,3 +185,33 @@ func (httpServer *HttpServer) handleGetMinerRewardFromMiningKey(params interface
 
 	return rewardAmountResult, nil
 }
+
+// handleGetConsensusJournal - RPC returns what this node observed in each timeslot of a chain:
+// expected proposer, received proposals and votes, own vote and when vote threshold was reached
+// params: [chainID, fromTimeslot, toTimeslot]
+func (httpServer *HttpServer) handleGetConsensusJournal(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 3 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 3 element"))
+	}
+	chainIDParam, ok := arrayParams[0].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Chain ID component invalid"))
+	}
+	fromTimeSlotParam, ok := arrayParams[1].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("From timeslot component invalid"))
+	}
+	toTimeSlotParam, ok := arrayParams[2].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("To timeslot component invalid"))
+	}
+	if fromTimeSlotParam > toTimeSlotParam {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("From timeslot must not be greater than to timeslot"))
+	}
+	result, err := httpServer.config.ConsensusEngine.GetConsensusJournal(int(chainIDParam), int64(fromTimeSlotParam), int64(toTimeSlotParam))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetConsensusJournalError, err)
+	}
+	return result, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
 	"strconv"
@@ -17,9 +18,11 @@ import (
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/pdeindexer"
 	"github.com/incognitochain/incognito-chain/rpcserver/bean"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/wallet"
 )
 
 type PDEWithdrawal struct {
@@ -279,14 +282,18 @@ func (httpServer *HttpServer) handleCreateRawTxWithPRVTradeReq(params interface{
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata is invalid"))
 	}
 	tradingFee := uint64(tradingFeeData)
-	meta, _ := metadata.NewPDETradeRequest(
+	deadline, err := getPDETradeDeadlineParam(data)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	meta := newPDETradeMeta(
+		deadline,
 		tokenIDToBuyStr,
 		tokenIDToSellStr,
 		sellAmount,
 		minAcceptableAmount,
 		tradingFee,
 		traderAddressStr,
-		metadata.PDETradeRequestMeta,
 	)
 
 	// create new param to build raw tx from param interface
@@ -374,14 +381,18 @@ func (httpServer *HttpServer) handleCreateRawTxWithPTokenTradeReq(params interfa
 	}
 	tradingFee := uint64(tradingFeeData)
 
-	meta, _ := metadata.NewPDETradeRequest(
+	deadline, err := getPDETradeDeadlineParam(tokenParamsRaw)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	meta := newPDETradeMeta(
+		deadline,
 		tokenIDToBuyStr,
 		tokenIDToSellStr,
 		sellAmount,
 		minAcceptableAmount,
 		tradingFee,
 		traderAddressStr,
-		metadata.PDETradeRequestMeta,
 	)
 
 	customTokenTx, rpcErr := httpServer.txService.BuildRawPrivacyCustomTokenTransaction(params, meta)
@@ -746,7 +757,11 @@ func parsePDETradeInst(inst []string, beaconHeight uint64) (*PDETrade, error) {
 	if err != nil {
 		return nil, err
 	}
-	if status == common.PDETradeRefundChainStatus {
+	if status == common.PDETradeRefundChainStatus || status == common.PDETradeExpiredRefundChainStatus {
+		refundStatus := "refunded"
+		if status == common.PDETradeExpiredRefundChainStatus {
+			refundStatus = "expired-refunded"
+		}
 		contentBytes, err := base64.StdEncoding.DecodeString(inst[3])
 		if err != nil {
 			return nil, err
@@ -768,7 +783,7 @@ func parsePDETradeInst(inst []string, beaconHeight uint64) (*PDETrade, error) {
 			Token2IDStr:         tokenIDStrs[1],
 			ShardID:             byte(shardID),
 			RequestedTxID:       pdeTradeReqAction.TxReqID,
-			Status:              "refunded",
+			Status:              refundStatus,
 			BeaconHeight:        beaconHeight,
 		}, nil
 	}
@@ -806,8 +821,13 @@ func parsePDERefundedTradeV2Inst(inst []string, beaconHeight uint64) (*PDERefund
 	}
 
 	refundStatus := "fee-refund"
-	if status == common.PDECrossPoolTradeSellingTokenRefundChainStatus {
+	switch status {
+	case common.PDECrossPoolTradeSellingTokenRefundChainStatus:
 		refundStatus = "selling-token-refund"
+	case common.PDECrossPoolTradeExpiredFeeRefundChainStatus:
+		refundStatus = "expired-fee-refund"
+	case common.PDECrossPoolTradeExpiredSellingTokenRefundChainStatus:
+		refundStatus = "expired-selling-token-refund"
 	}
 
 	var pdeRefundCrossPoolTrade metadata.PDERefundCrossPoolTrade
@@ -963,7 +983,7 @@ func (httpServer *HttpServer) handleExtractPDEInstsFromBeaconBlock(
 			}
 			pdeInfoFromBeaconBlock.PDEWithdrawals = append(pdeInfoFromBeaconBlock.PDEWithdrawals, pdeWithdrawal)
 
-		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta):
+		case strconv.Itoa(metadata.PDECrossPoolTradeRequestMeta), strconv.Itoa(metadata.PDERoutedTradeRequestMeta):
 			if inst[2] == common.PDECrossPoolTradeAcceptedChainStatus {
 				acceptedTradeV2, err := parsePDEAcceptedTradeV2Inst(inst, bcHeight)
 				if err != nil || acceptedTradeV2 == nil {
@@ -1031,6 +1051,141 @@ func convertPrice(
 	}
 }
 
+// getLatestPDEState - pde state of the beacon best state, its pool keys are at latestBeaconHeight
+func (httpServer *HttpServer) getLatestPDEState(latestBeaconHeight uint64) (*blockchain.CurrentPDEState, *rpcservice.RPCError) {
+	beaconFeatureStateRootHash, err := httpServer.config.BlockChain.GetBeaconFeatureRootHash(httpServer.config.BlockChain.GetBeaconBestState(), uint64(latestBeaconHeight))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEStateError, fmt.Errorf("Can't found ConsensusStateRootHash of beacon height %+v, error %+v", latestBeaconHeight, err))
+	}
+	beaconFeatureStateDB, err := statedb.NewWithPrefixTrie(beaconFeatureStateRootHash, statedb.NewDatabaseAccessWarper(httpServer.GetBeaconChainDatabase()))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEStateError, err)
+	}
+	pdeState, err := blockchain.InitCurrentPDEStateFromDB(beaconFeatureStateDB, latestBeaconHeight)
+	if err != nil || pdeState == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEStateError, err)
+	}
+	return pdeState, nil
+}
+
+func (httpServer *HttpServer) handleSimulatePDETrades(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	latestBeaconHeight := httpServer.config.BlockChain.GetBeaconBestState().BeaconHeight
+
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	tradeParams, ok := arrayParams[0].([]interface{})
+	if !ok || len(tradeParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Trades are invalid"))
+	}
+	trades := []blockchain.PDESimulatedTrade{}
+	for i, tradeParam := range tradeParams {
+		data, ok := tradeParam.(map[string]interface{})
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Trade %v is invalid", i))
+		}
+		trade := blockchain.PDESimulatedTrade{}
+		trade.TokenIDToBuyStr, ok = data["TokenIDToBuyStr"].(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("TokenIDToBuyStr of trade %v is invalid", i))
+		}
+		trade.TokenIDToSellStr, ok = data["TokenIDToSellStr"].(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("TokenIDToSellStr of trade %v is invalid", i))
+		}
+		var err error
+		trade.SellAmount, err = common.AssertAndConvertStrToNumber(data["SellAmount"])
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("SellAmount of trade %v is invalid", i))
+		}
+		if data["MinAcceptableAmount"] != nil {
+			trade.MinAcceptableAmount, err = common.AssertAndConvertStrToNumber(data["MinAcceptableAmount"])
+			if err != nil {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("MinAcceptableAmount of trade %v is invalid", i))
+			}
+		}
+		if data["TradingFee"] != nil {
+			trade.TradingFee, err = common.AssertAndConvertStrToNumber(data["TradingFee"])
+			if err != nil {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("TradingFee of trade %v is invalid", i))
+			}
+		}
+		if data["Path"] != nil {
+			pathItems, ok := data["Path"].([]interface{})
+			if !ok {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Path of trade %v is invalid", i))
+			}
+			for _, item := range pathItems {
+				tokenIDStr, ok := item.(string)
+				if !ok {
+					return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Path of trade %v is invalid", i))
+				}
+				trade.Path = append(trade.Path, tokenIDStr)
+			}
+		}
+		trades = append(trades, trade)
+	}
+	pdeState, rpcErr := httpServer.getLatestPDEState(latestBeaconHeight)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	simulation, err := httpServer.config.BlockChain.SimulatePDETrades(pdeState, latestBeaconHeight, trades)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEStateError, err)
+	}
+	return simulation, nil
+}
+
+func (httpServer *HttpServer) handleGetPDEBestRoute(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	latestBeaconHeight := httpServer.config.BlockChain.GetBeaconBestState().BeaconHeight
+
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	tokenIDToSellStr, ok := data["TokenIDToSellStr"].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("TokenIDToSellStr is invalid"))
+	}
+	tokenIDToBuyStr, ok := data["TokenIDToBuyStr"].(string)
+	if !ok || tokenIDToBuyStr == tokenIDToSellStr {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("TokenIDToBuyStr is invalid"))
+	}
+	sellAmount, err := common.AssertAndConvertStrToNumber(data["SellAmount"])
+	if err != nil || sellAmount == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("SellAmount is invalid"))
+	}
+	tradingFee := uint64(0)
+	if data["TradingFee"] != nil {
+		tradingFee, err = common.AssertAndConvertStrToNumber(data["TradingFee"])
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("TradingFee is invalid"))
+		}
+	}
+	maxHops := metadata.PDEMaxTradeHops
+	if data["MaxHops"] != nil {
+		hops, ok := data["MaxHops"].(float64)
+		if !ok || hops < 1 || int(hops) > metadata.PDEMaxTradeHops {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("MaxHops must be from 1 to %v", metadata.PDEMaxTradeHops))
+		}
+		maxHops = int(hops)
+	}
+	pdeState, rpcErr := httpServer.getLatestPDEState(latestBeaconHeight)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	route := blockchain.FindPDEBestRoute(pdeState, tokenIDToSellStr, tokenIDToBuyStr, sellAmount, tradingFee, maxHops)
+	if route == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEStateError, fmt.Errorf("No route from %v to %v", tokenIDToSellStr, tokenIDToBuyStr))
+	}
+	return route, nil
+}
+
 func (httpServer *HttpServer) handleConvertPDEPrices(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	latestBeaconHeight := httpServer.config.BlockChain.GetBeaconBestState().BeaconHeight
 
@@ -1258,6 +1413,129 @@ func (httpServer *HttpServer) handleCreateAndSendTxWithPTokenContributionV2(para
 	return sendResult, nil
 }
 
+// getPDETradeDeadlineParam - optional DeadlineBeaconHeight and DeadlineTimestamp of a trade request, nil if none is given
+func getPDETradeDeadlineParam(data map[string]interface{}) (*metadata.PDETradeDeadline, error) {
+	if data["DeadlineBeaconHeight"] == nil && data["DeadlineTimestamp"] == nil {
+		return nil, nil
+	}
+	deadline := &metadata.PDETradeDeadline{}
+	if data["DeadlineBeaconHeight"] != nil {
+		deadlineBeaconHeight, err := common.AssertAndConvertStrToNumber(data["DeadlineBeaconHeight"])
+		if err != nil {
+			return nil, errors.New("DeadlineBeaconHeight is invalid")
+		}
+		deadline.DeadlineBeaconHeight = deadlineBeaconHeight
+	}
+	if data["DeadlineTimestamp"] != nil {
+		deadlineTimestamp, err := common.AssertAndConvertStrToNumber(data["DeadlineTimestamp"])
+		if err != nil || deadlineTimestamp > math.MaxInt64 {
+			return nil, errors.New("DeadlineTimestamp is invalid")
+		}
+		deadline.DeadlineTimestamp = int64(deadlineTimestamp)
+	}
+	return deadline, nil
+}
+
+// newPDETradeMeta - trade with a deadline if one is given
+func newPDETradeMeta(
+	deadline *metadata.PDETradeDeadline,
+	tokenIDToBuyStr string,
+	tokenIDToSellStr string,
+	sellAmount uint64,
+	minAcceptableAmount uint64,
+	tradingFee uint64,
+	traderAddressStr string,
+) metadata.Metadata {
+	if deadline != nil {
+		meta, _ := metadata.NewPDETradeRequestV2(
+			tokenIDToBuyStr,
+			tokenIDToSellStr,
+			sellAmount,
+			minAcceptableAmount,
+			tradingFee,
+			traderAddressStr,
+			*deadline,
+			metadata.PDETradeRequestMetaV2,
+		)
+		return meta
+	}
+	meta, _ := metadata.NewPDETradeRequest(
+		tokenIDToBuyStr,
+		tokenIDToSellStr,
+		sellAmount,
+		minAcceptableAmount,
+		tradingFee,
+		traderAddressStr,
+		metadata.PDETradeRequestMeta,
+	)
+	return meta
+}
+
+// newPDECrossPoolTradeMeta - routed trade if a path is given, e.g. from getpdebestroute, cross pool trade through PRV otherwise.
+// Only cross pool trades can have a deadline
+func newPDECrossPoolTradeMeta(
+	pathParam interface{},
+	deadline *metadata.PDETradeDeadline,
+	tokenIDToBuyStr string,
+	tokenIDToSellStr string,
+	sellAmount uint64,
+	minAcceptableAmount uint64,
+	tradingFee uint64,
+	traderAddressStr string,
+) (metadata.Metadata, error) {
+	if pathParam != nil && deadline != nil {
+		return nil, errors.New("Deadline is not supported for trades with a path")
+	}
+	if deadline != nil {
+		return metadata.NewPDECrossPoolTradeRequestV2(
+			tokenIDToBuyStr,
+			tokenIDToSellStr,
+			sellAmount,
+			minAcceptableAmount,
+			tradingFee,
+			traderAddressStr,
+			*deadline,
+			metadata.PDECrossPoolTradeRequestMetaV2,
+		)
+	}
+	if pathParam == nil {
+		return metadata.NewPDECrossPoolTradeRequest(
+			tokenIDToBuyStr,
+			tokenIDToSellStr,
+			sellAmount,
+			minAcceptableAmount,
+			tradingFee,
+			traderAddressStr,
+			metadata.PDECrossPoolTradeRequestMeta,
+		)
+	}
+	pathItems, ok := pathParam.([]interface{})
+	if !ok {
+		return nil, errors.New("Path is invalid")
+	}
+	path := []string{}
+	for _, item := range pathItems {
+		tokenIDStr, ok := item.(string)
+		if !ok {
+			return nil, errors.New("Path is invalid")
+		}
+		path = append(path, tokenIDStr)
+	}
+	if err := metadata.ValidatePDETradePath(path, tokenIDToSellStr, tokenIDToBuyStr); err != nil {
+		return nil, err
+	}
+	return metadata.NewPDERoutedTradeRequest(
+		tokenIDToBuyStr,
+		tokenIDToSellStr,
+		path,
+		sellAmount,
+		minAcceptableAmount,
+		tradingFee,
+		traderAddressStr,
+		metadata.PDERoutedTradeRequestMeta,
+	)
+}
+
 func (httpServer *HttpServer) handleCreateRawTxWithPRVCrossPoolTradeReq(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
 
@@ -1290,15 +1568,23 @@ func (httpServer *HttpServer) handleCreateRawTxWithPRVCrossPoolTradeReq(params i
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
 	}
-	meta, _ := metadata.NewPDECrossPoolTradeRequest(
+	deadline, err := getPDETradeDeadlineParam(data)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	meta, err := newPDECrossPoolTradeMeta(
+		data["Path"],
+		deadline,
 		tokenIDToBuyStr,
 		tokenIDToSellStr,
 		sellAmount,
 		minAcceptableAmount,
 		tradingFee,
 		traderAddressStr,
-		metadata.PDECrossPoolTradeRequestMeta,
 	)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
 
 	// create new param to build raw tx from param interface
 	createRawTxParam, errNewParam := bean.NewCreateRawTxParamV2(params)
@@ -1382,15 +1668,23 @@ func (httpServer *HttpServer) handleCreateRawTxWithPTokenCrossPoolTradeReq(param
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
 	}
 
-	meta, _ := metadata.NewPDECrossPoolTradeRequest(
+	deadline, err := getPDETradeDeadlineParam(tokenParamsRaw)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	meta, err := newPDECrossPoolTradeMeta(
+		tokenParamsRaw["Path"],
+		deadline,
 		tokenIDToBuyStr,
 		tokenIDToSellStr,
 		sellAmount,
 		minAcceptableAmount,
 		tradingFee,
 		traderAddressStr,
-		metadata.PDECrossPoolTradeRequestMeta,
 	)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
 
 	customTokenTx, rpcErr := httpServer.txService.BuildRawPrivacyCustomTokenTransactionV2(params, meta)
 	if rpcErr != nil {
@@ -1584,3 +1878,63 @@ func (httpServer *HttpServer) handleCreateAndSendTxWithPDEFeeWithdrawalReq(param
 	result := jsonresult.NewCreateTransactionResult(nil, sendResult.(jsonresult.CreateTransactionResult).TxID, nil, sendResult.(jsonresult.CreateTransactionResult).ShardID)
 	return result, nil
 }
+
+func (httpServer *HttpServer) handleGetPDELiquidityPosition(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// pde status records are keyed by pair id or request tx, the history of an address can only come from the indexer
+	if httpServer.config.PDEIndexer == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, errPDEIndexerDisabled)
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	paymentAddressStr, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payment address is invalid"))
+	}
+	if _, err := wallet.Base58CheckDeserialize(paymentAddressStr); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Payment address is invalid: %v", err))
+	}
+	beaconBestState := httpServer.config.BlockChain.GetBeaconBestState()
+	pdeState, rpcErr := httpServer.getLatestPDEState(beaconBestState.BeaconHeight)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	result := jsonresult.PDELiquidityPosition{
+		BeaconHeight:    beaconBestState.BeaconHeight,
+		BeaconTimeStamp: beaconBestState.BestBlock.Header.Timestamp,
+		PaymentAddress:  paymentAddressStr,
+		Positions:       blockchain.GetPDELiquidityPositions(pdeState, beaconBestState.BeaconHeight, paymentAddressStr),
+		History:         []*jsonresult.PDELiquidityHistoryItem{},
+	}
+	events, err := httpServer.config.PDEIndexer.GetLiquidityEvents(paymentAddressStr)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+	}
+	for _, event := range events {
+		// requests without a tracked status are kept with status 0
+		item := &jsonresult.PDELiquidityHistoryItem{LiquidityEvent: event}
+		switch event.Type {
+		case pdeindexer.LiquidityEventContribution:
+			contributionStatus, err := httpServer.blockService.GetPDEContributionStatus(rawdbv2.PDEContributionStatusPrefix, []byte(event.PDEContributionPairID))
+			if err == nil && contributionStatus != nil {
+				item.Status = contributionStatus.Status
+				item.ContributionStatus = contributionStatus
+			}
+		case pdeindexer.LiquidityEventWithdrawal, pdeindexer.LiquidityEventFeeWithdrawal:
+			statusPrefix := rawdbv2.PDEWithdrawalStatusPrefix
+			if event.Type == pdeindexer.LiquidityEventFeeWithdrawal {
+				statusPrefix = rawdbv2.PDEFeeWithdrawalStatusPrefix
+			}
+			txReqID, err := common.Hash{}.NewHashFromStr(event.TxReqID)
+			if err != nil {
+				return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+			}
+			if status, err := httpServer.blockService.GetPDEStatus(statusPrefix, txReqID[:]); err == nil {
+				item.Status = status
+			}
+		}
+		result.History = append(result.History, item)
+	}
+	return result, nil
+}
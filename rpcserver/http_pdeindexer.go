@@ -0,0 +1,154 @@
+package rpcserver
+
+import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/pdeindexer"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+var errPDEIndexerDisabled = errors.New("PDE indexer is not enabled, start the node with --pdeindexer")
+
+// getPDEIndexerParams - pde indexer of the node and the payload of the request with its token pair
+func (httpServer *HttpServer) getPDEIndexerParams(params interface{}) (*pdeindexer.Indexer, map[string]interface{}, string, string, *rpcservice.RPCError) {
+	if httpServer.config.PDEIndexer == nil {
+		return nil, nil, "", "", rpcservice.NewRPCError(rpcservice.GetPDEIndexError, errPDEIndexerDisabled)
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, nil, "", "", rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, nil, "", "", rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	token1IDStr, ok := data["Token1IDStr"].(string)
+	if !ok {
+		return nil, nil, "", "", rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Token1IDStr is invalid"))
+	}
+	token2IDStr, ok := data["Token2IDStr"].(string)
+	if !ok {
+		return nil, nil, "", "", rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Token2IDStr is invalid"))
+	}
+	return httpServer.config.PDEIndexer, data, token1IDStr, token2IDStr, nil
+}
+
+// getOptionalNumberParam - non negative number in the payload, 0 if it is missing
+func getOptionalNumberParam(data map[string]interface{}, key string) (int64, *rpcservice.RPCError) {
+	if data[key] == nil {
+		return 0, nil
+	}
+	value, ok := data[key].(float64)
+	if !ok || value < 0 {
+		return 0, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New(key+" is invalid"))
+	}
+	return int64(value), nil
+}
+
+func (httpServer *HttpServer) handleGetPDECandles(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	indexer, data, token1IDStr, token2IDStr, rpcErr := httpServer.getPDEIndexerParams(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	interval, ok := data["Interval"].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Interval is invalid"))
+	}
+	from, rpcErr := getOptionalNumberParam(data, "From")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	to, rpcErr := getOptionalNumberParam(data, "To")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	limit, rpcErr := getOptionalNumberParam(data, "Limit")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	candles, err := indexer.GetCandles(token1IDStr, token2IDStr, interval, from, to, int(limit))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+	}
+	result := struct {
+		Interval string
+		From     int64
+		To       int64
+		Candles  []*pdeindexer.Candle
+	}{
+		interval,
+		from,
+		to,
+		candles,
+	}
+	return result, nil
+}
+
+func (httpServer *HttpServer) handleGetPDEPairStats(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	indexer, data, token1IDStr, token2IDStr, rpcErr := httpServer.getPDEIndexerParams(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	skip, rpcErr := getOptionalNumberParam(data, "Skip")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	limit, rpcErr := getOptionalNumberParam(data, "Limit")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	stats, err := indexer.GetPairStats(token1IDStr, token2IDStr)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+	}
+	snapshots, total, err := indexer.GetLiquidityHistory(token1IDStr, token2IDStr, int(skip), int(limit))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+	}
+	result := struct {
+		*pdeindexer.PairStats
+		Total            uint64
+		Skip             int64
+		Limit            int64
+		LiquidityHistory []*pdeindexer.LiquiditySnapshot
+	}{
+		stats,
+		total,
+		skip,
+		limit,
+		snapshots,
+	}
+	return result, nil
+}
+
+func (httpServer *HttpServer) handleGetPDETradeHistory(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	indexer, data, token1IDStr, token2IDStr, rpcErr := httpServer.getPDEIndexerParams(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	skip, rpcErr := getOptionalNumberParam(data, "Skip")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	limit, rpcErr := getOptionalNumberParam(data, "Limit")
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	trades, total, err := indexer.GetTradeHistory(token1IDStr, token2IDStr, int(skip), int(limit))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetPDEIndexError, err)
+	}
+	result := struct {
+		Total  uint64
+		Skip   int64
+		Limit  int64
+		Trades []*pdeindexer.Trade
+	}{
+		total,
+		skip,
+		limit,
+		trades,
+	}
+	return result, nil
+}
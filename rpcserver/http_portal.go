@@ -15,6 +15,12 @@ import (
 	"strings"
 )
 
+// isPortalToken - tokenID is a portal token enabled at the best beacon height
+func (httpServer *HttpServer) isPortalToken(tokenID string) bool {
+	bc := httpServer.config.BlockChain
+	return bc.IsPortalToken(bc.GetBeaconBestState().BeaconHeight, tokenID)
+}
+
 /*
 ====== Portal state
 */
@@ -103,7 +109,7 @@ func (httpServer *HttpServer) handleGetPortingRequestFees(params interface{}, cl
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata TokenID is invalid"))
 	}
-	if !metadata.IsPortalToken(tokenID) {
+	if !httpServer.isPortalToken(tokenID) {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata TokenID should be a portal token"))
 	}
 
@@ -168,7 +174,7 @@ func (httpServer *HttpServer) handleCreateRawTxWithPortingRequest(params interfa
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata PTokenId is invalid"))
 	}
 
-	if !metadata.IsPortalToken(pTokenId) {
+	if !httpServer.isPortalToken(pTokenId) {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata public token is not supported currently"))
 	}
 
@@ -887,6 +893,51 @@ func (httpServer *HttpServer) handleGetPortalFinalExchangeRates(params interface
 	return result, nil
 }
 
+func (httpServer *HttpServer) handleGetPortalExchangeRateHistory(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Param array must be at least 1"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("metadata param is invalid"))
+	}
+	beaconHeight, err := common.AssertAndConvertStrToNumber(data["BeaconHeight"])
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	// TokenID and FeederAddress are optional filters
+	tokenID := ""
+	if data["TokenID"] != nil {
+		tokenID, ok = data["TokenID"].(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("TokenID is invalid"))
+		}
+	}
+	feederAddress := ""
+	if data["FeederAddress"] != nil {
+		feederAddress, ok = data["FeederAddress"].(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("FeederAddress is invalid"))
+		}
+	}
+
+	featureStateRootHash, err := httpServer.config.BlockChain.GetBeaconFeatureRootHash(httpServer.config.BlockChain.GetBeaconBestState(), uint64(beaconHeight))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetExchangeRateHistoryError, fmt.Errorf("Can't found FeatureStateRootHash of beacon height %+v, error %+v", beaconHeight, err))
+	}
+	stateDB, err := statedb.NewWithPrefixTrie(featureStateRootHash, statedb.NewDatabaseAccessWarper(httpServer.config.BlockChain.GetBeaconChainDatabase()))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetExchangeRateHistoryError, err)
+	}
+
+	result, err := httpServer.portal.GetExchangeRateHistory(stateDB, uint64(beaconHeight), tokenID, feederAddress)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetExchangeRateHistoryError, err)
+	}
+	return result, nil
+}
+
 func (httpServer *HttpServer) handleConvertExchangeRates(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
 
@@ -1,6 +1,16 @@
 package rpcserver
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/rpcserver/bean"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 )
 
@@ -62,3 +72,82 @@ func (httpServer *HttpServer) handleGetProducersBlackListDetail(params interface
 
 	return nil, nil
 }
+
+// handleGetEquivocationEvidences - evidences of validators signing conflicting votes/proposals, observed by this node
+func (httpServer *HttpServer) handleGetEquivocationEvidences(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.config.ConsensusEngine.GetEquivocationEvidences(), nil
+}
+
+// handleGetEquivocationSlashes - equivocation slashes applied by beacon
+func (httpServer *HttpServer) handleGetEquivocationSlashes(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	slashStateDB := httpServer.config.BlockChain.GetBeaconBestState().GetBeaconSlashStateDB()
+	return statedb.GetAllEquivocationSlashes(slashStateDB), nil
+}
+
+// handleCreateRawTxWithEquivocationReport - RPC create tx reporting an equivocation evidence
+func (httpServer *HttpServer) handleCreateRawTxWithEquivocationReport(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	paramsArray := common.InterfaceSlice(params)
+	if paramsArray == nil || len(paramsArray) < 5 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 5 element"))
+	}
+	createRawTxParam, errNewParam := bean.NewCreateRawTxParam(params)
+	if errNewParam != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errNewParam)
+	}
+
+	data, ok := paramsArray[4].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Invalid metadata param %+v", paramsArray[4]))
+	}
+	evidenceData, ok := data["Evidence"]
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Evidence is missing"))
+	}
+	evidenceBytes, err := json.Marshal(evidenceData)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	var evidence metadata.EquivocationEvidence
+	if err := json.Unmarshal(evidenceBytes, &evidence); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	if err := evidence.ValidateSanity(); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+
+	meta, err := metadata.NewReportEquivocationMetadata(evidence, metadata.ReportEquivocationMeta)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	txID, txBytes, txShardID, err := httpServer.txService.CreateRawTransaction(createRawTxParam, meta)
+	if err.(*rpcservice.RPCError) != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.CreateTxDataError, err)
+	}
+
+	result := jsonresult.CreateTransactionResult{
+		TxID:            txID.String(),
+		Base58CheckData: base58.Base58Check{}.Encode(txBytes, common.ZeroByte),
+		ShardID:         txShardID,
+	}
+	return result, nil
+}
+
+// handleCreateAndSendTxWithEquivocationReport - RPC create and send tx reporting an equivocation evidence to network
+func (httpServer *HttpServer) handleCreateAndSendTxWithEquivocationReport(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	var err error
+	data, err := httpServer.handleCreateRawTxWithEquivocationReport(params, closeChan)
+	if err.(*rpcservice.RPCError) != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.CreateTxDataError, err)
+	}
+	tx := data.(jsonresult.CreateTransactionResult)
+	base58CheckData := tx.Base58CheckData
+
+	newParam := make([]interface{}, 0)
+	newParam = append(newParam, base58CheckData)
+	sendResult, err := httpServer.handleSendRawTransaction(newParam, closeChan)
+	if err.(*rpcservice.RPCError) != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.SendTxDataError, err)
+	}
+	result := jsonresult.NewCreateTransactionResult(nil, sendResult.(jsonresult.CreateTransactionResult).TxID, nil, tx.ShardID)
+	return result, nil
+}
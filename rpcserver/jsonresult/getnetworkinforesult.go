@@ -7,6 +7,7 @@ import (
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/connmanager"
+	"github.com/incognitochain/incognito-chain/peerv2"
 	"github.com/incognitochain/incognito-chain/wallet"
 )
 
@@ -23,6 +24,7 @@ type GetNetworkInfoResult struct {
 	Warnings        string                   `json:"Warnings"`
 	NodeTimeUnix    int64                    `json:"NodeTime"`
 	NodeTimeString  string                   `json:"NodeTimeString"`
+	NAT             *peerv2.NATStatus        `json:"NAT,omitempty"`
 }
 
 func NewGetNetworkInfoResult(protocolVerion string, connMgr connmanager.ConnManager, wallet *wallet.Wallet) (*GetNetworkInfoResult, error) {
@@ -12,3 +12,18 @@ type FinalExchangeRatesResult struct {
 type ExchangeRatesResult struct {
 	Rates map[string]uint64 `json:"Rates"`
 }
+
+type ExchangeRateFeederSubmissionResult struct {
+	FeederAddress string `json:"FeederAddress"`
+	Rate          uint64 `json:"Rate"`
+	BeaconHeight  uint64 `json:"BeaconHeight"`
+	TxReqID       string `json:"TxReqID"`
+}
+
+type ExchangeRateHistoryResult struct {
+	BeaconHeight      uint64                                          `json:"BeaconHeight"`
+	Feeders           []string                                        `json:"Feeders"`
+	Submissions       map[string][]ExchangeRateFeederSubmissionResult `json:"Submissions"`
+	LastQuorumHeights map[string]uint64                               `json:"LastQuorumHeights"`
+	IsStale           bool                                            `json:"IsStale"`
+}
@@ -1,6 +1,11 @@
 package jsonresult
 
-import "github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+import (
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/pdeindexer"
+)
 
 type CurrentPDEState struct {
 	WaitingPDEContributions map[string]*rawdbv2.PDEContribution `json:"WaitingPDEContributions"`
@@ -9,3 +14,17 @@ type CurrentPDEState struct {
 	PDETradingFees          map[string]uint64                   `json:"PDETradingFees"`
 	BeaconTimeStamp         int64                               `json:"BeaconTimeStamp"`
 }
+
+type PDELiquidityHistoryItem struct {
+	*pdeindexer.LiquidityEvent
+	Status             byte                            `json:"Status"` // 0 if the request is not tracked
+	ContributionStatus *metadata.PDEContributionStatus `json:"ContributionStatus,omitempty"`
+}
+
+type PDELiquidityPosition struct {
+	BeaconHeight    uint64                             `json:"BeaconHeight"`
+	BeaconTimeStamp int64                              `json:"BeaconTimeStamp"`
+	PaymentAddress  string                             `json:"PaymentAddress"`
+	Positions       []*blockchain.PDELiquidityPosition `json:"Positions"`
+	History         []*PDELiquidityHistoryItem         `json:"History"`
+}
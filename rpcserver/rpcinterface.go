@@ -22,6 +22,10 @@ var HttpHandler = map[string]httpHandler{
 	getAllConnectedPeers:     (*HttpServer).handleGetAllConnectedPeers,
 	getInOutMessages:         (*HttpServer).handleGetInOutMessages,
 	getInOutMessageCount:     (*HttpServer).handleGetInOutMessageCount,
+	getP2PStats:              (*HttpServer).handleGetP2PStats,
+	listBannedPeers:          (*HttpServer).handleListBannedPeers,
+	banPeer:                  (*HttpServer).handleBanPeer,
+	unbanPeer:                (*HttpServer).handleUnbanPeer,
 	getAllPeers:              (*HttpServer).handleGetAllPeers,
 	estimateFee:              (*HttpServer).handleEstimateFee,
 	estimateFeeV2:            (*HttpServer).handleEstimateFeeV2,
@@ -170,8 +174,14 @@ var HttpHandler = map[string]httpHandler{
 	getRoleByValidatorKey:       (*HttpServer).handleGetValidatorKeyRole,
 	getIncognitoPublicKeyRole:   (*HttpServer).handleGetIncognitoPublicKeyRole,
 	getMinerRewardFromMiningKey: (*HttpServer).handleGetMinerRewardFromMiningKey,
+	getConsensusJournal:         (*HttpServer).handleGetConsensusJournal,
 	getProducersBlackList:       (*HttpServer).handleGetProducersBlackList,
 	getProducersBlackListDetail: (*HttpServer).handleGetProducersBlackListDetail,
+	getEquivocationEvidences:    (*HttpServer).handleGetEquivocationEvidences,
+	getEquivocationSlashes:      (*HttpServer).handleGetEquivocationSlashes,
+
+	createRawTxWithEquivocationReport:     (*HttpServer).handleCreateRawTxWithEquivocationReport,
+	createAndSendTxWithEquivocationReport: (*HttpServer).handleCreateAndSendTxWithEquivocationReport,
 
 	// pde
 	getPDEState:                                (*HttpServer).handleGetPDEState,
@@ -193,6 +203,12 @@ var HttpHandler = map[string]httpHandler{
 	getPDEFeeWithdrawalStatus:                  (*HttpServer).handleGetPDEFeeWithdrawalStatus,
 	convertPDEPrices:                           (*HttpServer).handleConvertPDEPrices,
 	extractPDEInstsFromBeaconBlock:             (*HttpServer).handleExtractPDEInstsFromBeaconBlock,
+	getPDEBestRoute:                            (*HttpServer).handleGetPDEBestRoute,
+	simulatePDETrades:                          (*HttpServer).handleSimulatePDETrades,
+	getPDECandles:                              (*HttpServer).handleGetPDECandles,
+	getPDEPairStats:                            (*HttpServer).handleGetPDEPairStats,
+	getPDETradeHistory:                         (*HttpServer).handleGetPDETradeHistory,
+	getPDELiquidityPosition:                    (*HttpServer).handleGetPDELiquidityPosition,
 
 	getBurningAddress: (*HttpServer).handleGetBurningAddress,
 
@@ -204,6 +220,7 @@ var HttpHandler = map[string]httpHandler{
 	createAndSendTxWithReqPToken:                  (*HttpServer).handleCreateAndSendTxWithReqPToken,
 	createAndSendPortalExchangeRates:              (*HttpServer).handleCreateAndSendTxWithPortalExchangeRate,
 	getPortalFinalExchangeRates:                   (*HttpServer).handleGetPortalFinalExchangeRates,
+	getPortalExchangeRateHistory:                  (*HttpServer).handleGetPortalExchangeRateHistory,
 	getPortalPortingRequestByKey:                  (*HttpServer).handleGetPortingRequestStatusByTxID,
 	getPortalPortingRequestByPortingId:            (*HttpServer).handleGetPortingRequestStatusByPortingId,
 	convertExchangeRates:                          (*HttpServer).handleConvertExchangeRates,
@@ -216,6 +233,7 @@ var HttpHandler = map[string]httpHandler{
 	createAndSendCustodianWithdrawRequest:         (*HttpServer).handleCreateAndSendTxWithCustodianWithdrawRequest,
 	getCustodianWithdrawByTxId:                    (*HttpServer).handleGetCustodianWithdrawRequestStatusByTxId,
 	getCustodianLiquidationStatus:                 (*HttpServer).handleGetCustodianLiquidationStatus,
+	getCustodianHealth:                            (*HttpServer).handleGetCustodianHealth,
 	createAndSendTxWithReqWithdrawRewardPortal:    (*HttpServer).handleCreateAndSendTxWithReqWithdrawRewardPortal,
 	getLiquidationExchangeRatesPool:               (*HttpServer).handleGetLiquidationExchangeRatesPool,
 	createAndSendTxRedeemFromLiquidationPoolV3:    (*HttpServer).handleCreateAndSendTxRedeemFromLiquidationPoolV3,
@@ -246,13 +264,14 @@ var HttpHandler = map[string]httpHandler{
 	getPortalUnlockOverRateCollateralsStatus:      (*HttpServer).handleGetPortalReqUnlockOverRateCollateralStatus,
 
 	// relaying
-	createAndSendTxWithRelayingBNBHeader: (*HttpServer).handleCreateAndSendTxWithRelayingBNBHeader,
-	createAndSendTxWithRelayingBTCHeader: (*HttpServer).handleCreateAndSendTxWithRelayingBTCHeader,
-	getRelayingBNBHeaderState:            (*HttpServer).handleGetRelayingBNBHeaderState,
-	getRelayingBNBHeaderByBlockHeight:    (*HttpServer).handleGetRelayingBNBHeaderByBlockHeight,
-	getBTCRelayingBestState:              (*HttpServer).handleGetBTCRelayingBestState,
-	getBTCBlockByHash:                    (*HttpServer).handleGetBTCBlockByHash,
-	getLatestBNBHeaderBlockHeight:        (*HttpServer).handleGetLatestBNBHeaderBlockHeight,
+	createAndSendTxWithRelayingBNBHeader:  (*HttpServer).handleCreateAndSendTxWithRelayingBNBHeader,
+	createAndSendTxWithRelayingBTCHeader:  (*HttpServer).handleCreateAndSendTxWithRelayingBTCHeader,
+	createAndSendTxWithRelayingUTXOHeader: (*HttpServer).handleCreateAndSendTxWithRelayingUTXOHeader,
+	getRelayingBNBHeaderState:             (*HttpServer).handleGetRelayingBNBHeaderState,
+	getRelayingBNBHeaderByBlockHeight:     (*HttpServer).handleGetRelayingBNBHeaderByBlockHeight,
+	getBTCRelayingBestState:               (*HttpServer).handleGetBTCRelayingBestState,
+	getBTCBlockByHash:                     (*HttpServer).handleGetBTCBlockByHash,
+	getLatestBNBHeaderBlockHeight:         (*HttpServer).handleGetLatestBNBHeaderBlockHeight,
 
 	// incognnito mode for sc
 	getBurnProofForDepositToSC:                  (*HttpServer).handleGetBurnProofForDepositToSC,
@@ -315,4 +334,6 @@ var WsHandler = map[string]wsHandler{
 	subcribeBeaconBestState:                     (*WsServer).handleSubscribeBeaconBestState,
 	subcribeBeaconPoolBeststate:                 (*WsServer).handleSubscribeBeaconPoolBestState,
 	subcribeShardPoolBeststate:                  (*WsServer).handleSubscribeShardPoolBeststate,
+	subcribeConsensusJournal:                    (*WsServer).handleSubscribeConsensusJournal,
+	subcribeCustodianHealth:                     (*WsServer).handleSubscribeCustodianHealth,
 }
@@ -7,14 +7,20 @@ import (
 	"time"
 
 	"github.com/incognitochain/incognito-chain/addrmanager"
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/consensus"
 	"github.com/incognitochain/incognito-chain/connmanager"
+	"github.com/incognitochain/incognito-chain/consensus_v2/consensusjournal"
+	"github.com/incognitochain/incognito-chain/custodianmonitor"
 	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/memcache"
 	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/netsync"
+	"github.com/incognitochain/incognito-chain/pdeindexer"
+	"github.com/incognitochain/incognito-chain/peerv2"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 	"github.com/incognitochain/incognito-chain/syncker"
@@ -93,7 +99,16 @@ type RpcServerConfig struct {
 		GetAllMiningPublicKeys() []string
 		ExtractBridgeValidationData(block common.BlockInterface) ([][]byte, []int, error)
 		GetAllValidatorKeyState() map[string]consensus.MiningState
+		GetEquivocationEvidences() []metadata.EquivocationEvidence
+		GetConsensusJournal(chainID int, fromTimeSlot int64, toTimeSlot int64) ([]consensusjournal.TimeSlotRecord, error)
 	}
+	Highway interface {
+		GetP2PStats() peerv2.P2PStatsSnapshot
+		GetNATStatus() *peerv2.NATStatus
+	}
+	BanManager                  *banmanager.BanManager
+	PDEIndexer                  *pdeindexer.Indexer
+	CustodianMonitor            *custodianmonitor.Monitor
 	TxMemPool                   rpcservice.MempoolInterface
 	RPCMaxClients               int
 	RPCMaxWSClients             int
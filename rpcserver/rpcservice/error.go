@@ -101,6 +101,7 @@ const (
 	GetCustodianTopupWaitingPortingStatusError
 	GetAmountTopUpWaitingPortingError
 	GetCustodianDepositV3Error
+	GetExchangeRateHistoryError
 
 	// relaying
 	GetRelayingBNBHeaderByBlockHeightError
@@ -128,6 +129,21 @@ const (
 	RestoreCandidateShardWaitingForNextRandom
 
 	GetTotalStakerError
+
+	// consensus
+	GetConsensusJournalError
+
+	// p2p
+	GetP2PStatsError
+	ListBannedPeersError
+	BanPeerError
+	UnbanPeerError
+
+	// pde indexer
+	GetPDEIndexError
+
+	// custodian monitor
+	GetCustodianHealthError
 )
 
 // Standard JSON-RPC 2.0 errors.
@@ -231,6 +247,7 @@ var ErrCodeMessage = map[int]struct {
 	GetAmountTopUpWaitingPortingError:                  {-9017, "Get amount top up for waiting porting error"},
 	GetReqRedeemFromLiquidationPoolStatusError:         {-9018, "Get redeem request from liquidation pool status error"},
 	GetCustodianDepositV3Error:                         {-9019, "Get custodian deposit v3 status error"},
+	GetExchangeRateHistoryError:                        {-9020, "Get exchange rate history error"},
 
 	// relaying
 	GetRelayingBNBHeaderByBlockHeightError: {-10001, "Get relaying bnb header by block height error"},
@@ -256,6 +273,21 @@ var ErrCodeMessage = map[int]struct {
 	RestoreCandidateShardWaitingForNextRandom:     {-12008, "Restore candidate shard waiting for next random"},
 	GetAllBeaconViews:                             {-12009, "Get all beacon views"},
 	GetTotalStakerError:                           {-12010, "Get total staker return error"},
+
+	// consensus
+	GetConsensusJournalError: {-13000, "Get consensus journal error"},
+
+	// p2p
+	GetP2PStatsError:     {-14000, "Get p2p stats error"},
+	ListBannedPeersError: {-14001, "List banned peers error"},
+	BanPeerError:         {-14002, "Ban peer error"},
+	UnbanPeerError:       {-14003, "Unban peer error"},
+
+	// pde indexer
+	GetPDEIndexError: {-15000, "Get pde index error"},
+
+	// custodian monitor
+	GetCustodianHealthError: {-16000, "Get custodian health error"},
 }
 
 // RPCError represents an error that is used as a part of a JSON-RPC JsonResponse
@@ -101,6 +101,48 @@ func (s *PortalService) GetFinalExchangeRates(stateDB *statedb.StateDB, beaconHe
 	return result, nil
 }
 
+// GetExchangeRateHistory returns feeder submissions within the exchange rate window at beaconHeight,
+// filtered by tokenID and feederAddress when they are not empty
+func (s *PortalService) GetExchangeRateHistory(
+	stateDB *statedb.StateDB, beaconHeight uint64,
+	tokenID string, feederAddress string) (jsonresult.ExchangeRateHistoryResult, error) {
+	currentPortalState, err := blockchain.InitCurrentPortalStateFromDB(stateDB)
+	if err != nil {
+		return jsonresult.ExchangeRateHistoryResult{}, err
+	}
+
+	result := jsonresult.ExchangeRateHistoryResult{
+		BeaconHeight:      beaconHeight,
+		Feeders:           s.BlockChain.GetPortalFeederAddresses(beaconHeight),
+		Submissions:       map[string][]jsonresult.ExchangeRateFeederSubmissionResult{},
+		LastQuorumHeights: map[string]uint64{},
+		// whether liquidation by exchange rates is paused in the next beacon block
+		IsStale: blockchain.IsExchangeRateOracleStale(currentPortalState, beaconHeight, s.BlockChain.GetPortalParams(beaconHeight+1)),
+	}
+	oracle := currentPortalState.ExchangeRateOracle
+	if oracle == nil {
+		return result, nil
+	}
+	for pTokenID, submissions := range oracle.Submissions {
+		if tokenID != "" && pTokenID != tokenID {
+			continue
+		}
+		for _, submission := range submissions {
+			if feederAddress != "" && submission.FeederAddress != feederAddress {
+				continue
+			}
+			result.Submissions[pTokenID] = append(result.Submissions[pTokenID], jsonresult.ExchangeRateFeederSubmissionResult{
+				FeederAddress: submission.FeederAddress,
+				Rate:          submission.Rate,
+				BeaconHeight:  submission.BeaconHeight,
+				TxReqID:       submission.TxReqID,
+			})
+		}
+		result.LastQuorumHeights[pTokenID] = oracle.LastQuorumHeights[pTokenID]
+	}
+	return result, nil
+}
+
 func (s *PortalService) ConvertExchangeRates(
 	finalExchangeRates *statedb.FinalExchangeRatesState, portalParams blockchain.PortalParams,
 	amount uint64, tokenIDFrom string, tokenIDTo string) (uint64, error) {
@@ -0,0 +1,63 @@
+package rpcserver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/consensusjournal"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleSubscribeConsensusJournal - push the consensus journal record of a chain every time a timeslot ends
+// params: [chainID]
+func (wsServer *WsServer) handleSubscribeConsensusJournal(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Consensus Journal", params, subcription)
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Methods should only contain 1 params"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainIDParam, ok := arrayParams[0].(float64)
+	if !ok {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Chain ID component invalid"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainID := int(chainIDParam)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.ConsensusJournalTopic)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Consensus Journal")
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.ConsensusJournalTopic, subId)
+		close(cResult)
+	}()
+	for {
+		select {
+		case msg := <-subChan:
+			{
+				record, ok := msg.Value.(consensusjournal.TimeSlotRecord)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted consensusjournal.TimeSlotRecord, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if record.ChainID != chainID {
+					continue
+				}
+				cResult <- RpcSubResult{Result: record, Error: nil}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Consensus Journal"}}
+				return
+			}
+		}
+	}
+}
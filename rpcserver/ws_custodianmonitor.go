@@ -0,0 +1,62 @@
+package rpcserver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/custodianmonitor"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleSubscribeCustodianHealth - push every time a custodian crosses TP130 or TP120 for a portal token
+// params: [incognitoAddress], an empty address subscribes to all custodians
+func (wsServer *WsServer) handleSubscribeCustodianHealth(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Custodian Health", params, subcription)
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Methods should only contain 1 params"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	incAddress, ok := arrayParams[0].(string)
+	if !ok {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Custodian incognito address is invalid"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.CustodianHealthTopic)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Custodian Health")
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.CustodianHealthTopic, subId)
+		close(cResult)
+	}()
+	for {
+		select {
+		case msg := <-subChan:
+			{
+				alert, ok := msg.Value.(custodianmonitor.Alert)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted custodianmonitor.Alert, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if incAddress != "" && alert.IncognitoAddress != incAddress {
+					continue
+				}
+				cResult <- RpcSubResult{Result: alert, Error: nil}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Custodian Health"}}
+				return
+			}
+		}
+	}
+}
@@ -22,17 +22,22 @@ import (
 	bnbrelaying "github.com/incognitochain/incognito-chain/relaying/bnb"
 	"github.com/incognitochain/incognito-chain/syncker"
 
+	"github.com/incognitochain/incognito-chain/custodianmonitor"
+	"github.com/incognitochain/incognito-chain/pdeindexer"
 	"github.com/incognitochain/incognito-chain/peerv2"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/option"
 
 	"github.com/incognitochain/incognito-chain/addrmanager"
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/blockchain/btc"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/connmanager"
 	consensus "github.com/incognitochain/incognito-chain/consensus_v2"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/incognitokey"
@@ -48,6 +53,7 @@ import (
 	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/incognitochain/incognito-chain/wire"
 	libp2p "github.com/libp2p/go-libp2p-peer"
+	"github.com/multiformats/go-multiaddr"
 
 	p2ppubsub "github.com/incognitochain/go-libp2p-pubsub"
 
@@ -68,6 +74,7 @@ type Server struct {
 	memCache        *memcache.MemoryCache
 	rpcServer       *rpcserver.RpcServer
 	memPool         *mempool.TxPool
+	txQueue         *mempool.InboundTxQueue
 	tempMemPool     *mempool.TxPool
 	waitGroup       sync.WaitGroup
 	netSync         *netsync.NetSync
@@ -83,6 +90,9 @@ type Server struct {
 	// the mempool before they are mined into blocks.
 	feeEstimator map[byte]*mempool.FeeEstimator
 	highway      *peerv2.ConnManager
+	bans         *banmanager.BanManager
+	pdeIndexer   *pdeindexer.Indexer
+	cusMonitor   *custodianmonitor.Monitor
 
 	cQuit     chan struct{}
 	cNewPeers chan *peer.Peer
@@ -200,6 +210,7 @@ func (serverObj *Server) NewServer(
 	chainParams *blockchain.Params,
 	protocolVer string,
 	btcChain *btcrelaying.BlockChain,
+	utxoChains map[int]*btcrelaying.BlockChain,
 	bnbChainState *bnbrelaying.BNBChainState,
 	interrupt <-chan struct{},
 ) error {
@@ -273,29 +284,42 @@ func (serverObj *Server) NewServer(
 	Logger.log.Debug("Bootnode: ", cfg.DiscoverPeersAddress)
 
 	ip, port := peerv2.ParseListenner(cfg.Listener, "127.0.0.1", 9433)
-	host := peerv2.NewHost(version(), ip, port, cfg.Libp2pPrivateKey)
+	natMgr, err := peerv2.NewNATManager(cfg.NAT, port, cfg.ExternalAddress)
+	if err != nil {
+		Logger.log.Error(err)
+		return err
+	}
+	host := peerv2.NewHost(version(), ip, port, cfg.Libp2pPrivateKey, natMgr)
 
 	pubkey := serverObj.consensusEngine.GetMiningPublicKeys()
+	// bans of peers and validators, shared by p2p, syncker and consensus, kept after restart
+	serverObj.bans, err = banmanager.NewBanManager(filepath.Join(cfg.DataDir, banmanager.DataFile))
+	if err != nil {
+		Logger.log.Error(err)
+		return err
+	}
 	dispatcher := &peerv2.Dispatcher{
 		MessageListeners: &peerv2.MessageListeners{
-			OnBlockShard:     serverObj.OnBlockShard,
-			OnBlockBeacon:    serverObj.OnBlockBeacon,
-			OnCrossShard:     serverObj.OnCrossShard,
-			OnTx:             serverObj.OnTx,
-			OnTxPrivacyToken: serverObj.OnTxPrivacyToken,
-			OnVersion:        serverObj.OnVersion,
-			OnGetBlockBeacon: serverObj.OnGetBlockBeacon,
-			OnGetBlockShard:  serverObj.OnGetBlockShard,
-			OnGetCrossShard:  serverObj.OnGetCrossShard,
-			OnVerAck:         serverObj.OnVerAck,
-			OnGetAddr:        serverObj.OnGetAddr,
-			OnAddr:           serverObj.OnAddr,
+			OnBlockShard:        serverObj.OnBlockShard,
+			OnCompactBlockShard: serverObj.OnCompactBlockShard,
+			OnBlockBeacon:       serverObj.OnBlockBeacon,
+			OnCrossShard:        serverObj.OnCrossShard,
+			OnTx:                serverObj.OnTx,
+			OnTxPrivacyToken:    serverObj.OnTxPrivacyToken,
+			OnVersion:           serverObj.OnVersion,
+			OnGetBlockBeacon:    serverObj.OnGetBlockBeacon,
+			OnGetBlockShard:     serverObj.OnGetBlockShard,
+			OnGetCrossShard:     serverObj.OnGetCrossShard,
+			OnVerAck:            serverObj.OnVerAck,
+			OnGetAddr:           serverObj.OnGetAddr,
+			OnAddr:              serverObj.OnAddr,
 
 			//mubft
 			OnBFTMsg:    serverObj.OnBFTMsg,
 			OnPeerState: serverObj.OnPeerState,
 		},
-		BC: serverObj.blockChain,
+		BC:   serverObj.blockChain,
+		Bans: serverObj.bans,
 	}
 
 	monitor.SetGlobalParam("Bootnode", cfg.DiscoverPeersAddress)
@@ -310,9 +334,18 @@ func (serverObj *Server) NewServer(
 		"",
 		relayShards,
 	)
+	if err := serverObj.highway.SetCodecs(cfg.P2PCodec); err != nil {
+		return err
+	}
+	if err := serverObj.highway.SetDirectConfig(cfg.P2PMode, cfg.P2PStaticPeers, cfg.P2PMDNS); err != nil {
+		return err
+	}
+	// compact blocks are always rebuilt and served, published only if enabled by config
+	serverObj.highway.CompactBlocks = peerv2.NewCompactBlocks(serverObj.memPool, serverObj.syncker, serverObj.highway)
 
 	err = serverObj.blockChain.Init(&blockchain.Config{
 		BTCChain:      btcChain,
+		UTXOChains:    utxoChains,
 		BNBChainState: bnbChainState,
 		ChainParams:   serverObj.chainParams,
 		DataBase:      serverObj.dataBase,
@@ -411,6 +444,11 @@ func (serverObj *Server) NewServer(
 	//add tx pool
 	serverObj.blockChain.AddTxPool(serverObj.memPool)
 	serverObj.memPool.InitChannelMempool(cPendingTxs, cRemovedTxs)
+	serverObj.txQueue = mempool.NewInboundTxQueue(serverObj.memPool, mempool.InboundTxQueueConfig{
+		QueueSize: cfg.TxPoolInboundQueue,
+		PeerRate:  cfg.TxPoolPeerRate,
+		PeerBurst: cfg.TxPoolPeerBurst,
+	})
 	//==============Temp mem pool only used for validation
 	serverObj.tempMemPool = &mempool.TxPool{}
 	serverObj.tempMemPool.Init(&mempool.Config{
@@ -434,6 +472,7 @@ func (serverObj *Server) NewServer(
 		BlockChain: serverObj.blockChain,
 		ChainParam: chainParams,
 		TxMemPool:  serverObj.memPool,
+		TxQueue:    serverObj.txQueue,
 		Server:     serverObj,
 		Consensus:  serverObj.consensusEngine, // for onBFTMsg
 		// ShardToBeaconPool: serverObj.shardToBeaconPool,
@@ -479,8 +518,36 @@ func (serverObj *Server) NewServer(
 	})
 
 	serverObj.connManager = connManager
-	serverObj.consensusEngine.Init(&consensus.EngineConfig{Node: serverObj, Blockchain: serverObj.blockChain, PubSubManager: serverObj.pusubManager})
-	serverObj.syncker.Init(&syncker.SynckerManagerConfig{Network: serverObj.highway, Blockchain: serverObj.blockChain, Consensus: serverObj.consensusEngine})
+	if cfg.ExternalAddress == "" {
+		// report the address found by NAT traversal to bootnode
+		natMgr.AddOnExternalAddress(func(addr multiaddr.Multiaddr) {
+			address := ""
+			if addr != nil {
+				var err error
+				if address, err = peerv2.MultiaddrToHostPort(addr); err != nil {
+					Logger.log.Warn(err)
+				}
+			}
+			connManager.SetExternalAddress(address)
+		})
+	}
+	// load journal of signed votes/proposals, to prevent double signing after restart
+	signJournal, err := signjournal.NewJournal(filepath.Join(cfg.DataDir, signjournal.DataFile))
+	if err != nil {
+		Logger.log.Error(err)
+		return err
+	}
+	var remoteSigner *signer.RemoteConfig
+	if cfg.RemoteSigner != "" {
+		remoteSigner = &signer.RemoteConfig{
+			Address:  cfg.RemoteSigner,
+			CertFile: cfg.RemoteSignerCert,
+			KeyFile:  cfg.RemoteSignerKey,
+			CAFile:   cfg.RemoteSignerCA,
+		}
+	}
+	serverObj.consensusEngine.Init(&consensus.EngineConfig{Node: serverObj, Blockchain: serverObj.blockChain, PubSubManager: serverObj.pusubManager, SignJournal: signJournal, RemoteSigner: remoteSigner, BanManager: serverObj.bans})
+	serverObj.syncker.Init(&syncker.SynckerManagerConfig{Network: serverObj.highway, Blockchain: serverObj.blockChain, Consensus: serverObj.consensusEngine, Bans: serverObj.bans})
 
 	// Start up persistent peers.
 	permanentPeers := cfg.ConnectPeers
@@ -492,6 +559,21 @@ func (serverObj *Server) NewServer(
 		go serverObj.connManager.Connect(addr, "", "", nil)
 	}
 
+	// candles, trades and liquidity of PDE pairs, in their own database
+	if cfg.PDEIndexer {
+		pdeIndexDB, err := incdb.Open("leveldb", filepath.Join(cfg.DataDir, pdeindexer.DataDir))
+		if err != nil {
+			Logger.log.Error(err)
+			return err
+		}
+		serverObj.pdeIndexer = pdeindexer.NewIndexer(pdeIndexDB, serverObj.blockChain, serverObj.pusubManager)
+	}
+
+	// collateral ratios of portal custodians
+	if cfg.CustodianMonitor {
+		serverObj.cusMonitor = custodianmonitor.NewMonitor(serverObj.blockChain, serverObj.pusubManager)
+	}
+
 	if !cfg.DisableRPC {
 		// Setup listeners for the configured RPC listen addresses and
 		// TLS settings.
@@ -527,15 +609,19 @@ func (serverObj *Server) NewServer(
 			RPCLimitPass:                cfg.RPCLimitPass,
 			DisableAuth:                 cfg.RPCDisableAuth,
 			// NodeMode:                    cfg.NodeMode,
-			FeeEstimator:    serverObj.feeEstimator,
-			ProtocolVersion: serverObj.protocolVersion,
-			Database:        serverObj.dataBase,
-			MiningKeys:      cfg.MiningKeys,
-			NetSync:         serverObj.netSync,
-			PubSubManager:   pubsubManager,
-			ConsensusEngine: serverObj.consensusEngine,
-			MemCache:        serverObj.memCache,
-			Syncker:         serverObj.syncker,
+			FeeEstimator:     serverObj.feeEstimator,
+			ProtocolVersion:  serverObj.protocolVersion,
+			Database:         serverObj.dataBase,
+			MiningKeys:       cfg.MiningKeys,
+			NetSync:          serverObj.netSync,
+			PubSubManager:    pubsubManager,
+			ConsensusEngine:  serverObj.consensusEngine,
+			MemCache:         serverObj.memCache,
+			Syncker:          serverObj.syncker,
+			Highway:          serverObj.highway,
+			BanManager:       serverObj.bans,
+			PDEIndexer:       serverObj.pdeIndexer,
+			CustodianMonitor: serverObj.cusMonitor,
 		}
 		serverObj.rpcServer = &rpcserver.RpcServer{}
 		serverObj.rpcServer.Init(&rpcConfig)
@@ -606,6 +692,9 @@ func (serverObj *Server) Stop() error {
 	if errStopConnManager != nil {
 		Logger.log.Error(errStopConnManager)
 	}
+	if serverObj.highway != nil && serverObj.highway.LocalHost.NAT != nil {
+		serverObj.highway.LocalHost.NAT.Close()
+	}
 
 	// Shutdown the RPC server if it's not disabled.
 	if !cfg.DisableRPC && serverObj.rpcServer != nil {
@@ -630,6 +719,12 @@ func (serverObj *Server) Stop() error {
 	if err != nil {
 		Logger.log.Error(err)
 	}
+	if serverObj.pdeIndexer != nil {
+		serverObj.pdeIndexer.Stop()
+	}
+	if serverObj.cusMonitor != nil {
+		serverObj.cusMonitor.Stop()
+	}
 	// Signal the remaining goroutines to cQuit.
 	close(serverObj.cQuit)
 	return nil
@@ -728,6 +823,12 @@ func (serverObj Server) Start() {
 
 	//go serverObj.blockChain.Synker.Start()
 	go serverObj.syncker.Start()
+	if serverObj.pdeIndexer != nil {
+		serverObj.pdeIndexer.Start()
+	}
+	if serverObj.cusMonitor != nil {
+		serverObj.cusMonitor.Start()
+	}
 	go serverObj.blockgen.Start(serverObj.cQuit)
 
 	if serverObj.memPool != nil {
@@ -738,6 +839,7 @@ func (serverObj Server) Start() {
 		go serverObj.TransactionPoolBroadcastLoop()
 		go serverObj.memPool.Start(serverObj.cQuit)
 		go serverObj.memPool.MonitorPool()
+		go serverObj.txQueue.Start(serverObj.cQuit)
 	}
 	go serverObj.pusubManager.Start()
 
@@ -977,6 +1079,21 @@ func (serverObj *Server) OnBlockShard(p *peer.PeerConn,
 	go serverObj.syncker.ReceiveBlock(msg.Block, p.GetRemotePeerID().String())
 }
 
+// OnCompactBlockShard is invoked when a peer announces a shard block by header and short
+// transaction ids. The block is rebuilt from the pools and the transactions missing are requested
+// from origin, the announcing peer, or the block is fetched in full, then processed like OnBlockShard
+func (serverObj *Server) OnCompactBlockShard(p *peer.PeerConn, origin *peer.PeerConn,
+	msg *wire.MessageCompactBlockShard) {
+	go func() {
+		block, err := serverObj.highway.CompactBlocks.Rebuild(msg, p.GetRemotePeerID(), origin.GetRemotePeerID())
+		if err != nil {
+			Logger.log.Errorf("Cannot get shard block %v announced by compact block: %v", msg.BlockHash().String(), err)
+			return
+		}
+		serverObj.syncker.ReceiveBlock(block, p.GetRemotePeerID().String())
+	}()
+}
+
 func (serverObj *Server) OnBlockBeacon(p *peer.PeerConn,
 	msg *wire.MessageBlockBeacon) {
 
@@ -1033,7 +1150,7 @@ func (serverObj *Server) OnGetCrossShard(_ *peer.PeerConn, msg *wire.MessageGetC
 func (serverObj *Server) OnTx(peer *peer.PeerConn, msg *wire.MessageTx) {
 	Logger.log.Debug("Receive a new transaction START")
 	var txProcessed chan struct{}
-	serverObj.netSync.QueueTx(nil, msg, txProcessed)
+	serverObj.netSync.QueueTx(remotePeerID(peer), msg, txProcessed)
 	//<-txProcessed
 
 	Logger.log.Debug("Receive a new transaction END")
@@ -1042,12 +1159,20 @@ func (serverObj *Server) OnTx(peer *peer.PeerConn, msg *wire.MessageTx) {
 func (serverObj *Server) OnTxPrivacyToken(peer *peer.PeerConn, msg *wire.MessageTxPrivacyToken) {
 	Logger.log.Debug("Receive a new transaction(privacy token) START")
 	var txProcessed chan struct{}
-	serverObj.netSync.QueueTxPrivacyToken(nil, msg, txProcessed)
+	serverObj.netSync.QueueTxPrivacyToken(remotePeerID(peer), msg, txProcessed)
 	//<-txProcessed
 
 	Logger.log.Debug("Receive a new transaction(privacy token) END")
 }
 
+// remotePeerID - ID of the peer which sent a message, the highway for messages it relays
+func remotePeerID(peerConn *peer.PeerConn) string {
+	if peerConn == nil {
+		return ""
+	}
+	return peerConn.GetRemotePeerID().Pretty()
+}
+
 /*
 // OnVersion is invoked when a peer receives a version message
 // and is used to negotiate the protocol version details as well as kick start
@@ -1626,6 +1751,8 @@ func (serverObj *Server) PublishNodeState() error {
 		bBestState.BestBlockHash,
 		bBestState.Hash(),
 	}
+	// messages of both codecs are decoded whatever --p2pcodec is
+	msg.(*wire.MessagePeerState).MaxCodec = wire.CodecBinary
 
 	for chainID, validator := range chainValidator {
 		currentMiningKey := validator.MiningKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
@@ -1766,13 +1893,18 @@ func (serverObj *Server) PushBlockToAll(block common.BlockInterface, isBeacon bo
 		if !ok || shardBlock == nil {
 			return fmt.Errorf("Can not parse shard block or shard block is nil %v %v", ok, shardBlock == nil)
 		}
-		msgShard, err := wire.MakeEmptyMessage(wire.CmdBlockShard)
-		if err != nil {
-			Logger.log.Error(err)
-			return err
+		if cfg.P2PCompactBlocks && serverObj.highway.CompactBlocks != nil {
+			msgCompact := serverObj.highway.CompactBlocks.Announce(shardBlock)
+			serverObj.PushMessageToShard(msgCompact, shardBlock.Header.ShardID, map[libp2p.ID]bool{})
+		} else {
+			msgShard, err := wire.MakeEmptyMessage(wire.CmdBlockShard)
+			if err != nil {
+				Logger.log.Error(err)
+				return err
+			}
+			msgShard.(*wire.MessageBlockShard).Block = shardBlock
+			serverObj.PushMessageToShard(msgShard, shardBlock.Header.ShardID, map[libp2p.ID]bool{})
 		}
-		msgShard.(*wire.MessageBlockShard).Block = shardBlock
-		serverObj.PushMessageToShard(msgShard, shardBlock.Header.ShardID, map[libp2p.ID]bool{})
 
 		crossShardBlks := shardBlock.CreateAllCrossShardBlock(serverObj.blockChain.GetBeaconBestState().ActiveShards)
 		for shardID, crossShardBlk := range crossShardBlks {
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/jessevdk/go-flags"
+)
+
+var defaultDataDir = filepath.Join(common.AppDataDir("incognito", false), defaultDataDirname)
+
+// See loadConfig for details on the configuration load process.
+type config struct {
+	Listen     string `long:"listen" short:"l" description:"Listen address, unix:///path/to/socket or host:port (requires tls cert, key and ca)"`
+	DataDir    string `long:"datadir" short:"D" description:"Directory to store sign journal (slashing protection)"`
+	MiningKeys string `long:"miningkeys" description:"Mining keys (private seed) held by this signer, separated by comma"`
+	PrivateKey string `long:"privatekey" description:"Wallet privatekey to derive mining key from"`
+	TLSCert    string `long:"tlscert" description:"Server certificate for tcp listen address"`
+	TLSKey     string `long:"tlskey" description:"Server certificate key for tcp listen address"`
+	TLSCA      string `long:"tlsca" description:"CA certificate which signed the node client certificates"`
+}
+
+// newConfigParser returns a new command line flags parser.
+func newConfigParser(cfg *config, options flags.Options) *flags.Parser {
+	parser := flags.NewParser(cfg, options)
+	return parser
+}
+
+// loadConfig
+// - set default config
+// - read config from cmd line params
+// - return config object
+func loadConfig() (*config, error) {
+	// create config object from default values
+	cfg := config{
+		Listen:  defaultListen,
+		DataDir: defaultDataDir,
+	}
+
+	preParser := newConfigParser(&cfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, err
+		}
+	}
+	if cfg.MiningKeys == "" && cfg.PrivateKey == "" {
+		return nil, errors.New("miningkeys or privatekey is required")
+	}
+	cfg.DataDir = common.CleanAndExpandPath(cfg.DataDir, defaultDataDir)
+	return &cfg, nil
+}
@@ -0,0 +1,7 @@
+package main
+
+const (
+	version            = "1.0.0"
+	defaultListen      = "unix:///tmp/incognito-signer.sock"
+	defaultDataDirname = "signer"
+)
@@ -0,0 +1,110 @@
+//+build !test
+
+package main
+
+import (
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	consensus "github.com/incognitochain/incognito-chain/consensus_v2"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signer"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signjournal"
+	"google.golang.org/grpc/credentials"
+)
+
+const unixAddressPrefix = "unix://"
+
+// Signer is a standalone daemon which holds mining keys for an incognito node,
+// so that the keys are not stored on internet-facing nodes.
+// It enforces slashing protection with its own sign journal and log every request
+func main() {
+	// Show Version at startup.
+	log.Printf("Version %s\n", version)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Println("Parse config error", err.Error())
+		return
+	}
+
+	miningKeys, err := loadMiningKeys(cfg)
+	if err != nil {
+		log.Println("Load mining keys error", err.Error())
+		return
+	}
+
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		log.Println("Create data dir error", err.Error())
+		return
+	}
+	journal, err := signjournal.NewJournal(filepath.Join(cfg.DataDir, signjournal.DataFile))
+	if err != nil {
+		log.Println("Load sign journal error", err.Error())
+		return
+	}
+
+	var listener net.Listener
+	var creds credentials.TransportCredentials
+	if strings.HasPrefix(cfg.Listen, unixAddressPrefix) {
+		socketPath := strings.TrimPrefix(cfg.Listen, unixAddressPrefix)
+		os.Remove(socketPath)
+		listener, err = net.Listen("unix", socketPath)
+		if err == nil {
+			// only the user running signer (and node) can connect
+			err = os.Chmod(socketPath, 0600)
+		}
+	} else {
+		if cfg.TLSCert == "" || cfg.TLSKey == "" || cfg.TLSCA == "" {
+			log.Println("Listen on tcp requires tlscert, tlskey and tlsca")
+			return
+		}
+		tlsConfig, tlsErr := signer.LoadServerTLSConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA)
+		if tlsErr != nil {
+			log.Println("Load tls config error", tlsErr.Error())
+			return
+		}
+		creds = credentials.NewTLS(tlsConfig)
+		listener, err = net.Listen("tcp", cfg.Listen)
+	}
+	if err != nil {
+		log.Println("Listen error", err.Error())
+		return
+	}
+
+	server, err := signer.NewServer(miningKeys, journal, creds)
+	if err != nil {
+		log.Println("Init signer error", err.Error())
+		return
+	}
+	log.Printf("Start signer on %v with %v mining keys\n", cfg.Listen, len(miningKeys))
+	if err := server.Serve(listener); err != nil {
+		log.Println("Serve error", err.Error())
+	}
+}
+
+func loadMiningKeys(cfg *config) ([]*signatureschemes.MiningKey, error) {
+	seeds := []string{}
+	if cfg.PrivateKey != "" {
+		privateSeed, err := consensus.LoadUserKeyFromIncPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, privateSeed)
+	}
+	if cfg.MiningKeys != "" {
+		seeds = append(seeds, strings.Split(cfg.MiningKeys, ",")...)
+	}
+	miningKeys := []*signatureschemes.MiningKey{}
+	for _, seed := range seeds {
+		miningKey, err := consensus.GetMiningKeyFromPrivateSeed(seed)
+		if err != nil {
+			return nil, err
+		}
+		miningKeys = append(miningKeys, miningKey)
+	}
+	return miningKeys, nil
+}
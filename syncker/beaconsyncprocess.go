@@ -8,6 +8,7 @@ import (
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
@@ -34,9 +35,10 @@ type BeaconSyncProcess struct {
 	beaconPool          *BlkPool
 	actionCh            chan func()
 	lastCrossShardState map[byte]map[byte]uint64
+	bans                *banmanager.BanManager
 }
 
-func NewBeaconSyncProcess(network Network, bc *blockchain.BlockChain, chain BeaconChainInterface) *BeaconSyncProcess {
+func NewBeaconSyncProcess(network Network, bc *blockchain.BlockChain, chain BeaconChainInterface, bans *banmanager.BanManager) *BeaconSyncProcess {
 
 	var isOutdatedBlock = func(blk interface{}) bool {
 		if blk.(*blockchain.BeaconBlock).GetHeight() < chain.GetFinalViewHeight() {
@@ -55,6 +57,7 @@ func NewBeaconSyncProcess(network Network, bc *blockchain.BlockChain, chain Beac
 		beaconPeerStateCh:   make(chan *wire.MessagePeerState),
 		actionCh:            make(chan func()),
 		lastCrossShardState: make(map[byte]map[byte]uint64),
+		bans:                bans,
 	}
 	go s.syncBeacon()
 	go s.insertBeaconBlockFromPool()
@@ -68,6 +71,9 @@ func NewBeaconSyncProcess(network Network, bc *blockchain.BlockChain, chain Beac
 				f()
 			case beaconPeerState := <-s.beaconPeerStateCh:
 				Logger.Debugf("Got new peerstate, last height %v", beaconPeerState.Beacon.Height)
+				if s.bans != nil && s.bans.IsBanned(banmanager.KeyPeerID, beaconPeerState.SenderID) {
+					break
+				}
 				s.beaconPeerStates[beaconPeerState.SenderID] = BeaconPeerState{
 					Timestamp:      beaconPeerState.Timestamp,
 					BestViewHash:   beaconPeerState.Beacon.BlockHash.String(),
@@ -243,6 +249,7 @@ func (s *BeaconSyncProcess) insertBeaconBlockFromPool() {
 			//must validate this block when insert
 			if err := s.chain.InsertBlk(blk.(common.BlockInterface), true); err != nil {
 				Logger.Error("Insert beacon block from pool fail", blk.GetHeight(), blk.Hash(), err)
+				banInvalidBlockSender(s.bans, s.beaconPool.GetSender(*blk.Hash()), err)
 				continue
 			}
 			s.beaconPool.RemoveBlock(blk.Hash())
@@ -352,6 +359,7 @@ func (s *BeaconSyncProcess) streamFromPeer(peerID string, pState BeaconPeerState
 						if successBlk == 0 {
 							fmt.Println(err)
 						}
+						banInvalidBlockSender(s.bans, peerID, err)
 						return
 					} else {
 						insertBlkCnt += successBlk
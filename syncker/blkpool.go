@@ -10,6 +10,7 @@ type BlkPool struct {
 	action            chan func()
 	blkPoolByHash     map[string]common.BlockPoolInterface // hash -> block
 	blkPoolByPrevHash map[string][]string                  // prevhash -> []nexthash
+	blkSender         map[string]string                    // hash -> peer ID which sent the block
 }
 
 func NewBlkPool(name string, IsOutdatedBlk func(interface{}) bool) *BlkPool {
@@ -17,6 +18,7 @@ func NewBlkPool(name string, IsOutdatedBlk func(interface{}) bool) *BlkPool {
 	pool.action = make(chan func())
 	pool.blkPoolByHash = make(map[string]common.BlockPoolInterface)
 	pool.blkPoolByPrevHash = make(map[string][]string)
+	pool.blkSender = make(map[string]string)
 	go pool.Start()
 
 	//remove outdated block in pool, only trigger if pool has more than 1000 blocks
@@ -96,6 +98,11 @@ func (pool *BlkPool) GetBlockList() []common.BlockPoolInterface {
 }
 
 func (pool *BlkPool) AddBlock(blk common.BlockPoolInterface) {
+	pool.AddBlockFrom(blk, "")
+}
+
+// AddBlockFrom - add block sent by peerID, so the peer can be banned if the block is invalid
+func (pool *BlkPool) AddBlockFrom(blk common.BlockPoolInterface, peerID string) {
 	pool.action <- func() {
 		prevHash := blk.GetPrevHash().String()
 		hash := blk.Hash().String()
@@ -104,6 +111,9 @@ func (pool *BlkPool) AddBlock(blk common.BlockPoolInterface) {
 			return
 		}
 		pool.blkPoolByHash[hash] = blk
+		if peerID != "" {
+			pool.blkSender[hash] = peerID
+		}
 		//insert into prehash datastructure
 		if common.IndexOfStr(hash, pool.blkPoolByPrevHash[prevHash]) > -1 {
 			return
@@ -134,7 +144,17 @@ func (pool *BlkPool) HasHash(hash common.Hash) bool {
 func (pool *BlkPool) RemoveBlock(hash *common.Hash) {
 	pool.action <- func() {
 		delete(pool.blkPoolByHash, hash.String())
+		delete(pool.blkSender, hash.String())
+	}
+}
+
+// GetSender - peer ID which sent the block, empty if unknown
+func (pool *BlkPool) GetSender(hash common.Hash) string {
+	res := make(chan string)
+	pool.action <- func() {
+		res <- pool.blkSender[hash.String()]
 	}
+	return <-res
 }
 
 func (pool *BlkPool) RemovePrevHash(hash string) {
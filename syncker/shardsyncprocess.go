@@ -9,6 +9,7 @@ import (
 
 	lru "github.com/hashicorp/golang-lru"
 
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -36,9 +37,10 @@ type ShardSyncProcess struct {
 	shardPool             *BlkPool
 	actionCh              chan func()
 	lock                  *sync.RWMutex
+	bans                  *banmanager.BanManager
 }
 
-func NewShardSyncProcess(shardID int, network Network, bc *blockchain.BlockChain, beaconChain BeaconChainInterface, chain ShardChainInterface) *ShardSyncProcess {
+func NewShardSyncProcess(shardID int, network Network, bc *blockchain.BlockChain, beaconChain BeaconChainInterface, chain ShardChainInterface, bans *banmanager.BanManager) *ShardSyncProcess {
 	var isOutdatedBlock = func(blk interface{}) bool {
 		if blk.(*blockchain.ShardBlock).GetHeight() < chain.GetFinalViewHeight() {
 			return true
@@ -56,6 +58,7 @@ func NewShardSyncProcess(shardID int, network Network, bc *blockchain.BlockChain
 		shardPool:        NewBlkPool("ShardPool-"+string(shardID), isOutdatedBlock),
 		shardPeerState:   make(map[string]ShardPeerState),
 		shardPeerStateCh: make(chan *wire.MessagePeerState),
+		bans:             bans,
 
 		actionCh: make(chan func()),
 	}
@@ -77,6 +80,9 @@ func NewShardSyncProcess(shardID int, network Network, bc *blockchain.BlockChain
 			case f := <-s.actionCh:
 				f()
 			case shardPeerState := <-s.shardPeerStateCh:
+				if s.bans != nil && s.bans.IsBanned(banmanager.KeyPeerID, shardPeerState.SenderID) {
+					break
+				}
 				for sid, peerShardState := range shardPeerState.Shards {
 					if int(sid) == s.shardID {
 						s.shardPeerState[shardPeerState.SenderID] = ShardPeerState{
@@ -165,6 +171,7 @@ func (s *ShardSyncProcess) insertShardBlockFromPool() {
 			//must validate this block when insert
 			if err := s.Chain.InsertBlk(blk.(common.BlockInterface), true); err != nil {
 				Logger.Error("Insert shard block from pool fail", blk.GetHeight(), blk.Hash(), err)
+				banInvalidBlockSender(s.bans, s.shardPool.GetSender(*blk.Hash()), err)
 				continue
 			}
 			s.shardPool.RemoveBlock(blk.Hash())
@@ -279,6 +286,7 @@ func (s *ShardSyncProcess) streamFromPeer(peerID string, pState ShardPeerState)
 				for {
 					time1 := time.Now()
 					if successBlk, err := InsertBatchBlock(s.Chain, blockBuffer); err != nil {
+						banInvalidBlockSender(s.bans, peerID, err)
 						return
 					} else {
 						insertBlkCnt += successBlk
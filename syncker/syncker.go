@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/peerv2"
 
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
@@ -26,6 +27,7 @@ type SynckerManagerConfig struct {
 	Network    Network
 	Blockchain *blockchain.BlockChain
 	Consensus  peerv2.ConsensusData
+	Bans       *banmanager.BanManager // ban peers sending invalid blocks, nil to disable
 }
 
 type SynckerManager struct {
@@ -64,13 +66,13 @@ func (synckerManager *SynckerManager) Init(config *SynckerManagerConfig) {
 	}
 
 	//init beacon sync process
-	synckerManager.BeaconSyncProcess = NewBeaconSyncProcess(synckerManager.config.Network, synckerManager.config.Blockchain, synckerManager.config.Blockchain.BeaconChain)
+	synckerManager.BeaconSyncProcess = NewBeaconSyncProcess(synckerManager.config.Network, synckerManager.config.Blockchain, synckerManager.config.Blockchain.BeaconChain, synckerManager.config.Bans)
 	synckerManager.beaconPool = synckerManager.BeaconSyncProcess.beaconPool
 
 	//init shard sync process
 	for _, chain := range synckerManager.config.Blockchain.ShardChain {
 		sid := chain.GetShardID()
-		synckerManager.ShardSyncProcess[sid] = NewShardSyncProcess(sid, synckerManager.config.Network, synckerManager.config.Blockchain, synckerManager.config.Blockchain.BeaconChain, chain)
+		synckerManager.ShardSyncProcess[sid] = NewShardSyncProcess(sid, synckerManager.config.Network, synckerManager.config.Blockchain, synckerManager.config.Blockchain.BeaconChain, chain, synckerManager.config.Bans)
 		synckerManager.shardPool[sid] = synckerManager.ShardSyncProcess[sid].shardPool
 		synckerManager.CrossShardSyncProcess[sid] = synckerManager.ShardSyncProcess[sid].crossShardSyncProcess
 		synckerManager.crossShardPool[sid] = synckerManager.CrossShardSyncProcess[sid].crossShardPool
@@ -158,7 +160,7 @@ func (synckerManager *SynckerManager) ReceiveBlock(blk interface{}, peerID strin
 		//fmt.Printf("syncker: receive beacon block %d \n", beaconBlk.GetHeight())
 		//create fake s2b pool peerstate
 		if synckerManager.BeaconSyncProcess != nil {
-			synckerManager.beaconPool.AddBlock(beaconBlk)
+			synckerManager.beaconPool.AddBlockFrom(beaconBlk, peerID)
 			synckerManager.BeaconSyncProcess.beaconPeerStateCh <- &wire.MessagePeerState{
 				Beacon: wire.ChainState{
 					Timestamp: beaconBlk.Header.Timestamp,
@@ -175,7 +177,7 @@ func (synckerManager *SynckerManager) ReceiveBlock(blk interface{}, peerID strin
 		shardBlk := blk.(*blockchain.ShardBlock)
 		//fmt.Printf("syncker: receive shard block %d \n", shardBlk.GetHeight())
 		if synckerManager.shardPool[shardBlk.GetShardID()] != nil {
-			synckerManager.shardPool[shardBlk.GetShardID()].AddBlock(shardBlk)
+			synckerManager.shardPool[shardBlk.GetShardID()].AddBlockFrom(shardBlk, peerID)
 			if synckerManager.ShardSyncProcess[shardBlk.GetShardID()] != nil {
 				synckerManager.ShardSyncProcess[shardBlk.GetShardID()].shardPeerStateCh <- &wire.MessagePeerState{
 					Shards: map[byte]wire.ChainState{
@@ -324,6 +326,19 @@ func (synckerManager *SynckerManager) GetCrossShardBlocksForShardValidator(toSha
 	return crossShardPoolLists, nil
 }
 
+//Get Crossshard Block in pool by hash for rebuilding compact block, nil if not received
+func (synckerManager *SynckerManager) GetCrossShardBlockByHash(toShard byte, hash common.Hash) *blockchain.CrossShardBlock {
+	pool, ok := synckerManager.crossShardPool[int(toShard)]
+	if !ok {
+		return nil
+	}
+	blk, ok := pool.GetBlock(hash).(*blockchain.CrossShardBlock)
+	if !ok {
+		return nil
+	}
+	return blk
+}
+
 //Stream Missing CrossShard Block
 func (synckerManager *SynckerManager) StreamMissingCrossShardBlock(ctx context.Context, toShard byte, missingBlock map[byte][]uint64) {
 	for fromShard, missingHeight := range missingBlock {
@@ -417,7 +432,7 @@ func (synckerManager *SynckerManager) SyncMissingBeaconBlock(ctx context.Context
 			if blk.(*blockchain.BeaconBlock).GetHeight() <= synckerManager.config.Blockchain.BeaconChain.GetFinalViewHeight() {
 				return
 			}
-			synckerManager.beaconPool.AddBlock(blk.(common.BlockPoolInterface))
+			synckerManager.beaconPool.AddBlockFrom(blk.(common.BlockPoolInterface), peerID)
 			prevHash := blk.(*blockchain.BeaconBlock).GetPrevHash()
 			if v := synckerManager.config.Blockchain.BeaconChain.GetViewByHash(prevHash); v == nil {
 				requestHash = prevHash
@@ -442,7 +457,7 @@ func (synckerManager *SynckerManager) SyncMissingShardBlock(ctx context.Context,
 			if blk.(*blockchain.ShardBlock).GetHeight() <= synckerManager.config.Blockchain.ShardChain[sid].GetFinalViewHeight() {
 				return
 			}
-			synckerManager.shardPool[int(sid)].AddBlock(blk.(common.BlockPoolInterface))
+			synckerManager.shardPool[int(sid)].AddBlockFrom(blk.(common.BlockPoolInterface), peerID)
 			prevHash := blk.(*blockchain.ShardBlock).GetPrevHash()
 			if v := synckerManager.config.Blockchain.ShardChain[sid].GetViewByHash(prevHash); v == nil {
 				requestHash = prevHash
@@ -3,7 +3,9 @@ package syncker
 import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"reflect"
+	"strings"
 
+	"github.com/incognitochain/incognito-chain/banmanager"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 )
@@ -15,6 +17,20 @@ func isNil(v interface{}) bool {
 	return v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
 }
 
+// banInvalidBlockSender - ban the peer which sent a block failing validation (see blockchain.IsInvalidBlockError).
+// Nothing is done if the sender is unknown, e.g. blocks streamed from a peer chosen by highway
+func banInvalidBlockSender(bans *banmanager.BanManager, peerID string, err error) {
+	if bans == nil || peerID == "" || !blockchain.IsInvalidBlockError(err) {
+		return
+	}
+	reason := strings.TrimSpace(strings.SplitN(err.Error(), "\n", 2)[0])
+	if _, banErr := bans.Ban(banmanager.KeyPeerID, peerID, banmanager.ReasonInvalidBlock, reason); banErr != nil {
+		Logger.Errorf("Cannot ban peer %v which sent invalid block: %v", peerID, banErr)
+		return
+	}
+	Logger.Warnf("Ban peer %v which sent invalid block: %v", peerID, reason)
+}
+
 func InsertBatchBlock(chain Chain, blocks []common.BlockInterface) (int, error) {
 	sameCommitteeBlock := blocks
 
@@ -0,0 +1,76 @@
+package transaction
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+var _ = func() (_ struct{}) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	metadata.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	return
+}()
+
+// addMainnetTxSeeds - add transactions of shard blocks dumped from mainnet into blockchain/testdata/mainnet
+func addMainnetTxSeeds(f *testing.F, txType string) {
+	files, err := filepath.Glob(filepath.Join("..", "blockchain", "testdata", "mainnet", "shardblock-*.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		block := struct {
+			Body struct {
+				Transactions []json.RawMessage
+			}
+		}{}
+		if err := json.Unmarshal(data, &block); err != nil {
+			f.Fatal(err)
+		}
+		for _, tx := range block.Body.Transactions {
+			typ := struct{ Type string }{}
+			if err := json.Unmarshal(tx, &typ); err == nil && typ.Type == txType {
+				f.Add([]byte(tx))
+			}
+		}
+	}
+}
+
+func addJSONSeed(f *testing.F, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+}
+
+func FuzzTxUnmarshalJSON(f *testing.F) {
+	addMainnetTxSeeds(f, common.TxNormalType)
+	addMainnetTxSeeds(f, common.TxRewardType)
+	addJSONSeed(f, &Tx{Version: 1, Type: common.TxNormalType, Fee: 10, Info: []byte("info"), SigPubKey: common.HashB([]byte("pubkey"))})
+	addJSONSeed(f, &Tx{Version: 1, Type: common.TxRewardType, Metadata: &metadata.WithDrawRewardResponse{MetadataBase: metadata.MetadataBase{Type: metadata.WithDrawRewardResponseMeta}, TxRequest: &common.Hash{}}})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx := &Tx{}
+		json.Unmarshal(data, tx)
+	})
+}
+
+func FuzzTxCustomTokenPrivacyUnmarshalJSON(f *testing.F) {
+	addMainnetTxSeeds(f, common.TxCustomTokenPrivacyType)
+	addJSONSeed(f, &TxCustomTokenPrivacy{
+		Tx:                 Tx{Version: 1, Type: common.TxCustomTokenPrivacyType, Fee: 10},
+		TxPrivacyTokenData: TxPrivacyTokenData{PropertyName: "token", PropertySymbol: "TK", Amount: 100, TxNormal: Tx{Version: 1, Type: common.TxNormalType}},
+	})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx := &TxCustomTokenPrivacy{}
+		json.Unmarshal(data, tx)
+	})
+}
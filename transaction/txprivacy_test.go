@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/privacy"
 	"github.com/incognitochain/incognito-chain/wallet"
@@ -20,7 +21,7 @@ func TestUnmarshalJSON(t *testing.T) {
 	assert.Equal(t, nil, err)
 	paymentAddress := key.KeySet.PaymentAddress
 	responseMeta, err := metadata.NewWithDrawRewardResponse(&metadata.WithDrawRewardRequest{}, &common.Hash{})
-	tx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&paymentAddress, 10, &key.KeySet.PrivateKey, db, responseMeta, common.Hash{}, NormalCoinType, "PRV", 0))
+	tx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&paymentAddress, 10, &key.KeySet.PrivateKey, db, responseMeta, common.Hash{}, NormalCoinType, "PRV", 0, nil))
 	assert.Equal(t, nil, err)
 	assert.NotEqual(t, nil, tx)
 	assert.Equal(t, uint64(10), tx.(*Tx).Proof.GetOutputCoins()[0].CoinDetails.GetValue())
@@ -61,14 +62,15 @@ func TestInitTx(t *testing.T) {
 
 		// coin base tx to mint PRV
 		mintedAmount := 1000
-		coinBaseTx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&senderPaymentAddress, uint64(mintedAmount), &senderKey.KeySet.PrivateKey, db, nil, common.Hash{}, NormalCoinType, "PRV", 0))
+		coinBaseTx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&senderPaymentAddress, uint64(mintedAmount), &senderKey.KeySet.PrivateKey, db, nil, common.Hash{}, NormalCoinType, "PRV", 0, nil))
 
-		isValidSanity, err := coinBaseTx.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err := coinBaseTx.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, nil, err)
 		assert.Equal(t, true, isValidSanity)
 
 		// store output coin's coin commitments in coin base tx
-		db.StoreCommitments(
+		statedb.StoreCommitments(
+			db,
 			common.PRVCoinID,
 			senderPaymentAddress.Pk,
 			[][]byte{coinBaseTx.(*Tx).Proof.GetOutputCoins()[0].CoinDetails.GetCoinCommitment().ToBytesS()},
@@ -116,7 +118,7 @@ func TestInitTx(t *testing.T) {
 		fmt.Printf("actualSize: %v\n", actualSize)
 
 		senderPubKeyLastByte := tx1.GetSenderAddrLastByte()
-		assert.Equal(t, senderKey.KeySet.PaymentAddress.Pk[len(senderKey.KeySet.PaymentAddress.Pk)-1], senderPubKeyLastByte)
+		assert.Equal(t, shardID, senderPubKeyLastByte)
 
 		actualFee := tx1.GetTxFee()
 		assert.Equal(t, uint64(fee), actualFee)
@@ -147,11 +149,11 @@ func TestInitTx(t *testing.T) {
 		assert.Equal(t, 1, len(listInputSerialNumber))
 		assert.Equal(t, common.HashH(coinBaseOutput[0].CoinDetails.GetSerialNumber().ToBytesS()), listInputSerialNumber[0])
 
-		isValidSanity, err = tx1.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err = tx1.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, true, isValidSanity)
 		assert.Equal(t, nil, err)
 
-		isValid, err := tx1.ValidateTransaction(hasPrivacy, db, shardID, nil, false)
+		isValid, err := tx1.ValidateTransaction(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, shardID, nil)
 
 		fmt.Printf("Error: %v\n", err)
 		assert.Equal(t, true, isValid)
@@ -174,13 +176,13 @@ func TestInitTx(t *testing.T) {
 		//err = tx1.ValidateTxWithCurrentMempool(nil)
 		//	assert.Equal(t, nil, err)
 
-		err = tx1.ValidateDoubleSpendWithBlockchain(nil, shardID, db, nil)
+		err = tx1.ValidateDoubleSpendWithBlockchain(shardID, db, nil)
 		assert.Equal(t, nil, err)
 
-		err = tx1.ValidateTxWithBlockChain(nil, shardID, nil, nil, db)
+		err = tx1.ValidateTxWithBlockChain(nil, nil, nil, shardID, db)
 		assert.Equal(t, nil, err)
 
-		isValid, err = tx1.ValidateTxByItself(hasPrivacy, db, nil, shardID, nil, nil)
+		isValid, err = tx1.ValidateTxByItself(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, nil, shardID, nil, nil)
 		assert.Equal(t, nil, err)
 		assert.Equal(t, true, isValid)
 
@@ -272,14 +274,15 @@ func TestInitTxWithMultiScenario(t *testing.T) {
 
 		// create coin base tx to mint PRV
 		mintedAmount := 1000
-		coinBaseTx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&senderPaymentAddress, uint64(mintedAmount), &senderKey.KeySet.PrivateKey, db, nil, common.Hash{}, NormalCoinType, "PRV", 0))
+		coinBaseTx, err := BuildCoinBaseTxByCoinID(NewBuildCoinBaseTxByCoinIDParams(&senderPaymentAddress, uint64(mintedAmount), &senderKey.KeySet.PrivateKey, db, nil, common.Hash{}, NormalCoinType, "PRV", 0, nil))
 
-		isValidSanity, err := coinBaseTx.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err := coinBaseTx.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, nil, err)
 		assert.Equal(t, true, isValidSanity)
 
 		// store output coin's coin commitments in coin base tx
-		db.StoreCommitments(
+		statedb.StoreCommitments(
+			db,
 			common.PRVCoinID,
 			senderPaymentAddress.Pk,
 			[][]byte{coinBaseTx.(*Tx).Proof.GetOutputCoins()[0].CoinDetails.GetCoinCommitment().ToBytesS()},
@@ -310,28 +313,28 @@ func TestInitTxWithMultiScenario(t *testing.T) {
 		)
 		assert.Equal(t, nil, err)
 
-		isValidSanity, err = tx1.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err = tx1.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, true, isValidSanity)
 		assert.Equal(t, nil, err)
 		fmt.Println("Hello")
-		isValid, err := tx1.ValidateTransaction(hasPrivacy, db, shardID, nil, false)
+		isValid, err := tx1.ValidateTransaction(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, shardID, nil)
 		assert.Equal(t, true, isValid)
 		assert.Equal(t, nil, err)
 		fmt.Println("Hello")
-		err = tx1.ValidateDoubleSpendWithBlockchain(nil, shardID, db, nil)
+		err = tx1.ValidateDoubleSpendWithBlockchain(shardID, db, nil)
 		assert.Equal(t, nil, err)
 
-		err = tx1.ValidateTxWithBlockChain(nil, shardID, nil, nil, db)
+		err = tx1.ValidateTxWithBlockChain(nil, nil, nil, shardID, db)
 		assert.Equal(t, nil, err)
 
-		isValid, err = tx1.ValidateTxByItself(hasPrivacy, db, nil, shardID, nil, nil)
+		isValid, err = tx1.ValidateTxByItself(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, nil, shardID, nil, nil)
 		assert.Equal(t, nil, err)
 		assert.Equal(t, true, isValid)
 
 		// modify Sig
 		tx1.Sig[len(tx1.Sig)-1] = tx1.Sig[len(tx1.Sig)-1] ^ tx1.Sig[0]
 		tx1.Sig[len(tx1.Sig)-2] = tx1.Sig[len(tx1.Sig)-2] ^ tx1.Sig[1]
-		isValid, err = tx1.ValidateTransaction(hasPrivacy, db, shardID, nil, false)
+		isValid, err = tx1.ValidateTransaction(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, shardID, nil)
 		assert.Equal(t, false, isValid)
 		assert.NotEqual(t, nil, err)
 		tx1.Sig[len(tx1.Sig)-1] = tx1.Sig[len(tx1.Sig)-1] ^ tx1.Sig[0]
@@ -341,7 +344,7 @@ func TestInitTxWithMultiScenario(t *testing.T) {
 		tx1.SigPubKey[len(tx1.SigPubKey)-1] = tx1.SigPubKey[len(tx1.SigPubKey)-1] ^ tx1.SigPubKey[0]
 		tx1.SigPubKey[len(tx1.SigPubKey)-2] = tx1.SigPubKey[len(tx1.SigPubKey)-2] ^ tx1.SigPubKey[1]
 
-		isValid, err = tx1.ValidateTransaction(hasPrivacy, db, shardID, nil, false)
+		isValid, err = tx1.ValidateTransaction(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, shardID, nil)
 		assert.Equal(t, false, isValid)
 		assert.NotEqual(t, nil, err)
 
@@ -366,7 +369,7 @@ func TestInitTxWithMultiScenario(t *testing.T) {
 		tx1.Proof.SetBytes(originProof)
 
 		// back to correct case
-		isValid, err = tx1.ValidateTxByItself(hasPrivacy, db, nil, shardID, nil, nil)
+		isValid, err = tx1.ValidateTxByItself(map[string]bool{"hasPrivacy": hasPrivacy}, db, nil, nil, shardID, nil, nil)
 		assert.Equal(t, nil, err)
 		assert.Equal(t, true, isValid)
 	}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/privacy"
 	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/stretchr/testify/assert"
@@ -60,7 +61,7 @@ func TestInitTxPrivacyToken(t *testing.T) {
 
 		paramToCreateTx := NewTxPrivacyTokenInitParams(&senderKey.KeySet.PrivateKey,
 			paymentInfoPRV, inputCoinsPRV, 0, tokenParam, db, nil,
-			hasPrivacyForPRV, hasPrivacyForToken, shardID, []byte{})
+			hasPrivacyForPRV, hasPrivacyForToken, shardID, []byte{}, db)
 
 		// init tx
 		tx := new(TxCustomTokenPrivacy)
@@ -102,14 +103,14 @@ func TestInitTxPrivacyToken(t *testing.T) {
 		//err = tx.ValidateTxWithCurrentMempool(nil)
 		//assert.Equal(t, nil, err)
 
-		err = tx.ValidateTxWithBlockChain(nil, shardID, nil, nil, db)
+		err = tx.ValidateTxWithBlockChain(nil, nil, nil, shardID, db)
 		assert.Equal(t, nil, err)
 
-		isValidSanity, err := tx.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err := tx.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, true, isValidSanity)
 		assert.Equal(t, nil, err)
 
-		isValidTxItself, err := tx.ValidateTxByItself(hasPrivacyForPRV, db, nil, shardID, nil, nil)
+		isValidTxItself, err := tx.ValidateTxByItself(map[string]bool{"hasPrivacy": hasPrivacyForPRV}, db, nil, nil, shardID, nil, nil)
 		assert.Equal(t, true, isValidTxItself)
 		assert.Equal(t, nil, err)
 
@@ -158,8 +159,8 @@ func TestInitTxPrivacyToken(t *testing.T) {
 			outputCoins[0].CoinDetails.GetSNDerivator())
 		outputCoins[0].CoinDetails.SetSerialNumber(serialNumber)
 
-		db.StorePrivacyToken(*tx.GetTokenID(), tx.Hash()[:])
-		db.StoreCommitments(*tx.GetTokenID(), senderKey.KeySet.PaymentAddress.Pk[:], [][]byte{outputCoins[0].CoinDetails.GetCoinCommitment().ToBytesS()}, shardID)
+		statedb.StorePrivacyTokenTx(db, *tx.GetTokenID(), *tx.Hash())
+		statedb.StoreCommitments(db, *tx.GetTokenID(), senderKey.KeySet.PaymentAddress.Pk[:], [][]byte{outputCoins[0].CoinDetails.GetCoinCommitment().ToBytesS()}, shardID)
 
 		//listTokens, err := db.ListPrivacyToken()
 		//assert.Equal(t, nil, err)
@@ -184,7 +185,7 @@ func TestInitTxPrivacyToken(t *testing.T) {
 
 		paramToCreateTx2 := NewTxPrivacyTokenInitParams(&senderKey.KeySet.PrivateKey,
 			paymentInfoPRV, inputCoinsPRV, 0, tokenParam2, db, nil,
-			hasPrivacyForPRV, true, shardID, []byte{})
+			hasPrivacyForPRV, true, shardID, []byte{}, db)
 
 		// init tx
 		tx2 := new(TxCustomTokenPrivacy)
@@ -193,14 +194,14 @@ func TestInitTxPrivacyToken(t *testing.T) {
 
 		assert.Equal(t, len(msgCipherText.Bytes()), len(tx2.TxPrivacyTokenData.TxNormal.Proof.GetOutputCoins()[0].CoinDetails.GetInfo()))
 
-		err = tx2.ValidateTxWithBlockChain(nil, shardID, nil, nil, db)
+		err = tx2.ValidateTxWithBlockChain(nil, nil, nil, shardID, db)
 		assert.Equal(t, nil, err)
 
-		isValidSanity, err = tx2.ValidateSanityData(nil, nil, nil)
+		isValidSanity, err = tx2.ValidateSanityData(newChainRetriever(), nil, nil, 0)
 		assert.Equal(t, true, isValidSanity)
 		assert.Equal(t, nil, err)
 
-		isValidTxItself, err = tx2.ValidateTxByItself(hasPrivacyForPRV, db, nil, shardID, nil, nil)
+		isValidTxItself, err = tx2.ValidateTxByItself(map[string]bool{"hasPrivacy": hasPrivacyForPRV}, db, nil, nil, shardID, nil, nil)
 		assert.Equal(t, true, isValidTxItself)
 		assert.Equal(t, nil, err)
 
@@ -3,6 +3,7 @@ package transaction
 import (
 	"github.com/incognitochain/incognito-chain/privacy"
 	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -32,10 +33,14 @@ func TestTxTokenPrivacyData(t *testing.T) {
 }
 
 func TestCreateCustomTokenPrivacyReceiverArray(t *testing.T) {
+	masterKey, _ := wallet.NewMasterKey([]byte{1, 2, 3})
+	receiver1, _ := masterKey.NewChildKey(uint32(1))
+	receiver2, _ := masterKey.NewChildKey(uint32(2))
 	data := make(map[string]interface{})
-	data["1Uv3BkYiWy9Mjt1yBa4dXBYKo3az22TeCVEpeXN93ieJ8qhrTDuUZBzsPZWjjP2AeRQnjw1y18iFPHTRuAqqufwVC1vNUAWs4wHFbbWC2"] = 10.0
-	data["1Uv2oZbQ2Zs1qGtNBfWmLUm862wJyxw8Br7r1CysKmC4MMyv9ySR7urcaEjABH9NK2zfei8s8iGepP7ZAmyh4ew9JT9mQy7RpGF8W9w6C"] = 20.0
-	result, voutsAmount, _ := CreateCustomTokenPrivacyReceiverArray(data)
+	data[receiver1.Base58CheckSerialize(wallet.PaymentAddressType)] = 10.0
+	data[receiver2.Base58CheckSerialize(wallet.PaymentAddressType)] = 20.0
+	result, voutsAmount, err := CreateCustomTokenPrivacyReceiverArray(data)
+	assert.Equal(t, nil, err)
 	assert.Equal(t, uint64(30), uint64(voutsAmount))
 	assert.Equal(t, 2, len(result))
 }
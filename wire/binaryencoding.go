@@ -0,0 +1,165 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// binaryWriter - append fields of a message in binary codec: integers are varint,
+// byte slices and strings are prefixed by their length
+type binaryWriter struct {
+	buf bytes.Buffer
+	tmp [binary.MaxVarintLen64]byte
+}
+
+func (w *binaryWriter) writeByte(b byte) {
+	w.buf.WriteByte(b)
+}
+
+func (w *binaryWriter) writeUvarint(v uint64) {
+	n := binary.PutUvarint(w.tmp[:], v)
+	w.buf.Write(w.tmp[:n])
+}
+
+func (w *binaryWriter) writeVarint(v int64) {
+	n := binary.PutVarint(w.tmp[:], v)
+	w.buf.Write(w.tmp[:n])
+}
+
+func (w *binaryWriter) writeBytes(b []byte) {
+	w.writeUvarint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *binaryWriter) writeString(s string) {
+	w.writeUvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *binaryWriter) writeHash(h common.Hash) {
+	w.buf.Write(h[:])
+}
+
+func (w *binaryWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// binaryReader - read fields written by binaryWriter. The first error is kept,
+// following reads return zero values, so the caller only checks err once at the end
+type binaryReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func newBinaryReader(data []byte) *binaryReader {
+	return &binaryReader{data: data}
+}
+
+func (r *binaryReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *binaryReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.data) {
+		r.fail(errors.New("unexpected end of binary message"))
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *binaryReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(errors.New("invalid uvarint in binary message"))
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *binaryReader) readVarint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(errors.New("invalid varint in binary message"))
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+// readLength - read a length prefix and check that the remaining data is long enough
+func (r *binaryReader) readLength() int {
+	l := r.readUvarint()
+	if r.err != nil {
+		return 0
+	}
+	if l > uint64(len(r.data)-r.pos) {
+		r.fail(fmt.Errorf("length %v exceeds remaining %v bytes of binary message", l, len(r.data)-r.pos))
+		return 0
+	}
+	return int(l)
+}
+
+func (r *binaryReader) readBytes() []byte {
+	l := r.readLength()
+	if r.err != nil {
+		return nil
+	}
+	b := make([]byte, l)
+	copy(b, r.data[r.pos:r.pos+l])
+	r.pos += l
+	return b
+}
+
+func (r *binaryReader) readString() string {
+	l := r.readLength()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.data[r.pos : r.pos+l])
+	r.pos += l
+	return s
+}
+
+func (r *binaryReader) readHash() common.Hash {
+	h := common.Hash{}
+	if r.err != nil {
+		return h
+	}
+	if len(r.data)-r.pos < common.HashSize {
+		r.fail(errors.New("unexpected end of binary message"))
+		return h
+	}
+	copy(h[:], r.data[r.pos:r.pos+common.HashSize])
+	r.pos += common.HashSize
+	return h
+}
+
+// finish - return the first error, or an error if there is unread data
+func (r *binaryReader) finish() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.pos != len(r.data) {
+		return fmt.Errorf("%v trailing bytes in binary message", len(r.data)-r.pos)
+	}
+	return nil
+}
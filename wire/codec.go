@@ -0,0 +1,227 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec of a message on the wire
+const (
+	// CodecJSON - legacy format: json body + 24 bytes header, gzip, hex string
+	CodecJSON = 1
+	// CodecBinary - binary header + binary body (see BinaryMessage), zstd compressed if large
+	CodecBinary = 2
+)
+
+const (
+	// BinaryMagic - first byte of a binary encoded message, never a character of the hex string of the legacy format
+	BinaryMagic = byte(0x00)
+
+	// magic (1) + codec version (1) + cmd type (12) + flags (1)
+	BinaryHeaderSize = 2 + MessageCmdTypeSize + 1
+
+	binaryFlagCompressed = byte(1)
+
+	// body smaller than this is not worth compressing (votes, peer state)
+	binaryCompressThreshold = 256
+
+	// largest payload of all messages, bound memory used to decompress a message
+	maxDecodedSize = MaxBFTPayload
+)
+
+var (
+	binaryCompresser   *zstd.Encoder
+	binaryDecompresser *zstd.Decoder
+)
+
+func init() {
+	binaryCompresser, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	binaryDecompresser, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecodedSize))
+}
+
+// EncodeJSONMessage - encode msg with CodecJSON
+func EncodeJSONMessage(msg Message) (string, error) {
+	messageBytes, err := msg.JsonSerialize()
+	if err != nil {
+		return "", err
+	}
+
+	// Add 24 bytes headerBytes into messageHex
+	headerBytes := make([]byte, MessageHeaderSize)
+	// add command type of message
+	cmdType, err := GetCmdType(reflect.TypeOf(msg))
+	if err != nil {
+		return "", err
+	}
+	copy(headerBytes[:], []byte(cmdType))
+	// add forward type of message at 13st byte
+	forwardType := byte('s')
+	forwardValue := byte(0)
+	copy(headerBytes[MessageCmdTypeSize:], []byte{forwardType})
+	copy(headerBytes[MessageCmdTypeSize+1:], []byte{forwardValue})
+	messageBytes = append(messageBytes, headerBytes...)
+
+	// zip data before send
+	messageBytes, err = common.GZipFromBytes(messageBytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(messageBytes), nil
+}
+
+// DecodeJSONMessage - decode a hex string encoded by EncodeJSONMessage
+func DecodeJSONMessage(msgStr string) (Message, error) {
+	jsonDecodeBytesRaw, err := hex.DecodeString(msgStr)
+	if err != nil {
+		return nil, err
+	}
+	// unzip data before process
+	jsonDecodeBytes, err := common.GZipToBytesWithLimit(jsonDecodeBytesRaw, maxDecodedSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(jsonDecodeBytes) < MessageHeaderSize {
+		return nil, errors.New("message is shorter than header")
+	}
+
+	// Parse Message body
+	messageBody := jsonDecodeBytes[:len(jsonDecodeBytes)-MessageHeaderSize]
+	messageHeader := jsonDecodeBytes[len(jsonDecodeBytes)-MessageHeaderSize:]
+
+	// get cmd type in header message
+	commandInHeader := bytes.Trim(messageHeader[:MessageCmdTypeSize], "\x00")
+	commandType := string(messageHeader[:len(commandInHeader)])
+	// convert to particular message from message cmd type
+	message, err := MakeEmptyMessage(commandType)
+	if err != nil {
+		return nil, err
+	}
+	if len(jsonDecodeBytes) > message.MaxPayloadLength(Version) {
+		return nil, fmt.Errorf("Message size too lagre %v, it must be less than %v", len(jsonDecodeBytes), message.MaxPayloadLength(Version))
+	}
+	if err := json.Unmarshal(messageBody, &message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// BinaryMessage - message which supports CodecBinary
+type BinaryMessage interface {
+	Message
+	BinarySerialize() ([]byte, error)
+	BinaryDeserialize(data []byte) error
+}
+
+// IsBinaryMessage - check if data is encoded by CodecBinary, otherwise it is a legacy hex string
+func IsBinaryMessage(data []byte) bool {
+	return len(data) > 0 && data[0] == BinaryMagic
+}
+
+// EncodeBinaryMessage - encode msg with CodecBinary
+func EncodeBinaryMessage(msg Message) ([]byte, error) {
+	binaryMsg, ok := msg.(BinaryMessage)
+	if !ok {
+		return nil, fmt.Errorf("message %v does not support binary codec", msg.MessageType())
+	}
+	cmdType := msg.MessageType()
+	if len(cmdType) > MessageCmdTypeSize {
+		return nil, fmt.Errorf("command type %v is too long", cmdType)
+	}
+	body, err := binaryMsg.BinarySerialize()
+	if err != nil {
+		return nil, err
+	}
+	flags := byte(0)
+	if len(body) >= binaryCompressThreshold {
+		body = binaryCompresser.EncodeAll(body, make([]byte, 0, len(body)/2))
+		flags |= binaryFlagCompressed
+	}
+	res := make([]byte, BinaryHeaderSize, BinaryHeaderSize+len(body))
+	res[0] = BinaryMagic
+	res[1] = CodecBinary
+	copy(res[2:], cmdType)
+	res[2+MessageCmdTypeSize] = flags
+	return append(res, body...), nil
+}
+
+// DecodeBinaryMessage - decode data encoded by EncodeBinaryMessage
+func DecodeBinaryMessage(data []byte) (Message, error) {
+	if len(data) < BinaryHeaderSize || data[0] != BinaryMagic {
+		return nil, errors.New("invalid binary message header")
+	}
+	if data[1] != CodecBinary {
+		return nil, fmt.Errorf("unsupported codec version %v", data[1])
+	}
+	cmdType := strings.TrimRight(string(data[2:2+MessageCmdTypeSize]), "\x00")
+	flags := data[2+MessageCmdTypeSize]
+	msg, err := MakeEmptyMessage(cmdType)
+	if err != nil {
+		return nil, err
+	}
+	binaryMsg, ok := msg.(BinaryMessage)
+	if !ok {
+		return nil, fmt.Errorf("message %v does not support binary codec", cmdType)
+	}
+	body := data[BinaryHeaderSize:]
+	if flags&binaryFlagCompressed != 0 {
+		body, err = binaryDecompresser.DecodeAll(body, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(body) > msg.MaxPayloadLength(Version) {
+		return nil, fmt.Errorf("message size too large %v, it must be less than %v", len(body), msg.MaxPayloadLength(Version))
+	}
+	if err := binaryMsg.BinaryDeserialize(body); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ParseCodecs - parse codec config: "json", "binary", or a list of "cmd:codec" (e.g. "bft:binary,peerstate:binary").
+// Return the default codec and the codec of each command which overrides the default
+func ParseCodecs(s string) (int, map[string]int, error) {
+	defaultCodec := CodecJSON
+	codecs := map[string]int{}
+	parseCodec := func(name string) (int, error) {
+		switch strings.TrimSpace(name) {
+		case "json":
+			return CodecJSON, nil
+		case "binary":
+			return CodecBinary, nil
+		}
+		return 0, fmt.Errorf("unknown codec %v", name)
+	}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) == 1 {
+			codec, err := parseCodec(parts[0])
+			if err != nil {
+				return 0, nil, err
+			}
+			defaultCodec = codec
+			continue
+		}
+		if len(parts) != 2 {
+			return 0, nil, fmt.Errorf("invalid codec config %v", item)
+		}
+		codec, err := parseCodec(parts[1])
+		if err != nil {
+			return 0, nil, err
+		}
+		codecs[strings.TrimSpace(parts[0])] = codec
+	}
+	return defaultCodec, codecs, nil
+}
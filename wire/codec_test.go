@@ -0,0 +1,284 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+func newTestTx(i int) *transaction.Tx {
+	return &transaction.Tx{
+		Version:   1,
+		Type:      common.TxNormalType,
+		LockTime:  1600000000 + int64(i),
+		Fee:       uint64(100 + i),
+		Info:      []byte(fmt.Sprintf("tx %v", i)),
+		SigPubKey: common.HashB([]byte(fmt.Sprintf("pubkey %v", i))),
+		Sig:       append(common.HashB([]byte(fmt.Sprintf("sig r %v", i))), common.HashB([]byte(fmt.Sprintf("sig s %v", i)))...),
+	}
+}
+
+func newTestShardBlock(numInsts int, numTxs int) *blockchain.ShardBlock {
+	insts := [][]string{}
+	for i := 0; i < numInsts; i++ {
+		insts = append(insts, []string{"113", fmt.Sprintf("%v", i%8), "accepted", common.HashH([]byte(fmt.Sprintf("inst %v", i))).String()})
+	}
+	txs := []metadata.Transaction{}
+	for i := 0; i < numTxs; i++ {
+		txs = append(txs, newTestTx(i))
+	}
+	return &blockchain.ShardBlock{
+		ValidationData: `{"ProducerBLSSig":"c2lnbmF0dXJl","ProducerBriSig":null,"ValidatiorsIdx":[0,1,2],"AggSig":"YWdnc2ln","BridgeSig":["","",""]}`,
+		Header: blockchain.ShardHeader{
+			Producer:              "producer",
+			ShardID:               1,
+			Version:               2,
+			PreviousBlockHash:     common.HashH([]byte("prev")),
+			Height:                100,
+			Round:                 1,
+			Epoch:                 2,
+			BeaconHeight:          50,
+			BeaconHash:            common.HashH([]byte("beacon")),
+			ConsensusType:         common.BlsConsensus,
+			TotalTxsFee:           map[common.Hash]uint64{},
+			Timestamp:             1600000000,
+			TxRoot:                common.HashH([]byte("txroot")),
+			CommitteeRoot:         common.HashH([]byte("committeeroot")),
+			InstructionMerkleRoot: common.HashH([]byte("instructionroot")),
+			Proposer:              "proposer",
+			ProposeTime:           1600000000,
+		},
+		Body: blockchain.ShardBody{
+			Instructions:      insts,
+			CrossTransactions: map[byte][]blockchain.CrossTransaction{},
+			Transactions:      txs,
+		},
+	}
+}
+
+func newTestBFTMessage(block *blockchain.ShardBlock) *MessageBFT {
+	blockBytes, _ := json.Marshal(block)
+	content, _ := json.Marshal(struct {
+		PeerID   string
+		Block    json.RawMessage
+		TimeSlot uint64
+	}{"QmPeer", blockBytes, 160000000})
+	return &MessageBFT{
+		PeerID:    "QmPeer",
+		Type:      "propose",
+		Content:   content,
+		ChainKey:  "shard-1",
+		Timestamp: 1600000000123,
+		TimeSlot:  160000000,
+	}
+}
+
+func newTestPeerState() *MessagePeerState {
+	msg := &MessagePeerState{
+		Beacon:                ChainState{Timestamp: 1600000000, Height: 50, BlockHash: common.HashH([]byte("beacon")), BestStateHash: common.HashH([]byte("beacon state"))},
+		Shards:                map[byte]ChainState{},
+		CrossShardPool:        map[byte]map[byte][]uint64{},
+		Timestamp:             1600000001,
+		SenderID:              "QmPeer",
+		SenderMiningPublicKey: "miningkey",
+		MaxCodec:              CodecBinary,
+	}
+	for i := byte(0); i < 8; i++ {
+		msg.Shards[i] = ChainState{Timestamp: 1600000000 + int64(i), Height: 100 + uint64(i), BlockHash: common.HashH([]byte{i}), BestStateHash: common.HashH([]byte{i, i})}
+		msg.CrossShardPool[i] = map[byte][]uint64{(i + 1) % 8: {1, 2, 3}}
+	}
+	return msg
+}
+
+func testMessages() []Message {
+	return []Message{
+		newTestBFTMessage(newTestShardBlock(10, 2)),
+		&MessageBFT{Type: "vote", ChainKey: "beacon", Content: []byte(`{"BlockHash":"abc"}`), TimeSlot: 1},
+		&MessageBlockShard{Block: newTestShardBlock(10, 2)},
+		NewMessageCompactBlockShard(newTestShardBlock(10, 2)),
+		&MessageBlockBeacon{Block: &blockchain.BeaconBlock{ValidationData: "val", Header: blockchain.BeaconHeader{Height: 10, Epoch: 1}, Body: blockchain.BeaconBody{Instructions: [][]string{{"stake", "a"}}}}},
+		&MessageCrossShard{Block: &blockchain.CrossShardBlock{ValidationData: "val", Header: blockchain.ShardHeader{Height: 10, ShardID: 2}, ToShardID: 3, MerklePathShard: []common.Hash{common.HashH([]byte("merkle"))}}},
+		&MessageTx{Transaction: newTestTx(1)},
+		newTestPeerState(),
+	}
+}
+
+func TestBinaryCodec_RoundTrip(t *testing.T) {
+	for _, msg := range testMessages() {
+		t.Run(msg.MessageType(), func(t *testing.T) {
+			data, err := EncodeBinaryMessage(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsBinaryMessage(data) {
+				t.Fatal("encoded message is not detected as binary")
+			}
+			decoded, err := DecodeBinaryMessage(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := msg.JsonSerialize()
+			got, _ := decoded.JsonSerialize()
+			if !bytes.Equal(want, got) {
+				t.Errorf("decoded message differs\nwant %s\ngot  %s", want, got)
+			}
+
+			// legacy codec is still decoded
+			legacy, err := EncodeJSONMessage(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if IsBinaryMessage([]byte(legacy)) {
+				t.Fatal("legacy message is detected as binary")
+			}
+			decoded, err = DecodeJSONMessage(legacy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ = decoded.JsonSerialize()
+			if !bytes.Equal(want, got) {
+				t.Errorf("legacy decoded message differs\nwant %s\ngot  %s", want, got)
+			}
+		})
+	}
+}
+
+func TestBinaryCodec_Invalid(t *testing.T) {
+	// small enough to not be compressed
+	data, err := EncodeBinaryMessage(&MessageBFT{Type: "vote", ChainKey: "beacon", Content: []byte(`{"BlockHash":"abc"}`), TimeSlot: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownVersion := append([]byte{}, data...)
+	unknownVersion[1] = 99
+	unknownCmd := append([]byte{}, data...)
+	copy(unknownCmd[2:2+MessageCmdTypeSize], "unknowncmd\x00\x00")
+	notBinaryCmd := append([]byte{}, data...)
+	copy(notBinaryCmd[2:2+MessageCmdTypeSize], CmdGetAddr+"\x00\x00\x00\x00\x00")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"header only", data[:BinaryHeaderSize-1]},
+		{"truncated body", data[:len(data)-1]},
+		{"trailing bytes", append(append([]byte{}, data...), 0)},
+		{"unknown codec version", unknownVersion},
+		{"unknown command", unknownCmd},
+		{"command without binary codec", notBinaryCmd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeBinaryMessage(tt.data); err == nil {
+				t.Error("expect error")
+			}
+		})
+	}
+}
+
+func TestParseCodecs(t *testing.T) {
+	tests := []struct {
+		config      string
+		wantDefault int
+		wantCodecs  map[string]int
+		wantErr     bool
+	}{
+		{"", CodecJSON, map[string]int{}, false},
+		{"json", CodecJSON, map[string]int{}, false},
+		{"binary", CodecBinary, map[string]int{}, false},
+		{"bft:binary, peerstate:binary", CodecJSON, map[string]int{CmdBFT: CodecBinary, CmdPeerState: CodecBinary}, false},
+		{"binary,tx:json", CodecBinary, map[string]int{CmdTx: CodecJSON}, false},
+		{"protobuf", 0, nil, true},
+		{"bft:binary:json", 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.config, func(t *testing.T) {
+			gotDefault, gotCodecs, err := ParseCodecs(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCodecs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if gotDefault != tt.wantDefault || fmt.Sprint(gotCodecs) != fmt.Sprint(tt.wantCodecs) {
+				t.Errorf("ParseCodecs() = %v %v, want %v %v", gotDefault, gotCodecs, tt.wantDefault, tt.wantCodecs)
+			}
+		})
+	}
+}
+
+func benchmarkMessages() map[string]Message {
+	block := newTestShardBlock(200, 50)
+	return map[string]Message{
+		"bft_propose":         newTestBFTMessage(block),
+		"bft_vote":            &MessageBFT{PeerID: "QmPeer", Type: "vote", ChainKey: "shard-1", Content: bytes.Repeat([]byte("v"), 400), Timestamp: 1600000000123, TimeSlot: 160000000},
+		"block_shard":         &MessageBlockShard{Block: block},
+		"compact_block_shard": NewMessageCompactBlockShard(block),
+		"tx":                  &MessageTx{Transaction: newTestTx(1)},
+		"peer_state":          newTestPeerState(),
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for name, msg := range benchmarkMessages() {
+		b.Run(name+"/json", func(b *testing.B) {
+			b.ReportAllocs()
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := EncodeJSONMessage(msg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/msg")
+		})
+		b.Run(name+"/binary", func(b *testing.B) {
+			b.ReportAllocs()
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := EncodeBinaryMessage(msg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/msg")
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for name, msg := range benchmarkMessages() {
+		legacy, err := EncodeJSONMessage(msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		binaryData, err := EncodeBinaryMessage(msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name+"/json", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeJSONMessage(legacy); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(name+"/binary", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeBinaryMessage(binaryData); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
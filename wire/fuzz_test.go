@@ -0,0 +1,116 @@
+package wire
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fuzzCmdTypes - every command handled by MakeEmptyMessage
+var fuzzCmdTypes = []string{
+	CmdGetBlockBeacon, CmdGetBlockShard, CmdGetCrossShard, CmdBlockShard, CmdCompactBlockShard, CmdBlockBeacon, CmdCrossShard,
+	CmdTx, CmdPrivacyCustomToken, CmdVersion, CmdVerack, CmdGetAddr, CmdAddr, CmdPing,
+	CmdBFT, CmdPeerState, CmdMsgCheck, CmdMsgCheckResp,
+}
+
+// mainnetBlockSeeds - blocks dumped from mainnet into blockchain/testdata/mainnet, see README there
+func mainnetBlockSeeds(f *testing.F, pattern string) [][]byte {
+	files, err := filepath.Glob(filepath.Join("..", "blockchain", "testdata", "mainnet", pattern))
+	if err != nil {
+		f.Fatal(err)
+	}
+	res := [][]byte{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		res = append(res, data)
+	}
+	return res
+}
+
+func fuzzSeedMessages(f *testing.F) []Message {
+	msgs := testMessages()
+	for _, data := range mainnetBlockSeeds(f, "shardblock-*.json") {
+		msg := &MessageBlockShard{}
+		if err := json.Unmarshal([]byte(`{"Block":`+string(data)+`}`), msg); err != nil {
+			f.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+	for _, data := range mainnetBlockSeeds(f, "beaconblock-*.json") {
+		msg := &MessageBlockBeacon{}
+		if err := json.Unmarshal([]byte(`{"Block":`+string(data)+`}`), msg); err != nil {
+			f.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// Body of each message type given to its JSON deserializer
+func FuzzMessageJsonDeserialize(f *testing.F) {
+	for _, msg := range fuzzSeedMessages(f) {
+		body, err := msg.JsonSerialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(msg.MessageType(), body)
+	}
+	for _, cmd := range fuzzCmdTypes {
+		f.Add(cmd, []byte("{}"))
+	}
+	f.Fuzz(func(t *testing.T, cmd string, body []byte) {
+		msg, err := MakeEmptyMessage(cmd)
+		if err != nil {
+			return
+		}
+		if len(body) > msg.MaxPayloadLength(Version) {
+			return
+		}
+		if err := msg.JsonDeserialize(string(body)); err != nil {
+			return
+		}
+		msg.Hash()
+	})
+}
+
+// A message received from network in legacy hex-gzip-json format
+func FuzzDecodeJSONMessage(f *testing.F) {
+	for _, msg := range fuzzSeedMessages(f) {
+		data, err := EncodeJSONMessage(msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data string) {
+		msg, err := DecodeJSONMessage(data)
+		if err != nil {
+			return
+		}
+		msg.Hash()
+	})
+}
+
+// A message received from network in binary codec
+func FuzzDecodeBinaryMessage(f *testing.F) {
+	for _, msg := range fuzzSeedMessages(f) {
+		data, err := EncodeBinaryMessage(msg)
+		if err != nil {
+			continue
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := DecodeBinaryMessage(data)
+		if err != nil {
+			return
+		}
+		if _, err := EncodeBinaryMessage(msg); err != nil {
+			t.Fatalf("decoded %v message cannot be encoded: %v", msg.MessageType(), err)
+		}
+	})
+}
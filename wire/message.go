@@ -18,6 +18,7 @@ const (
 	CmdGetCrossShard      = "getcrossshd"
 	CmdBlockShard         = "blockshard"
 	CmdBlockBeacon        = "blockbeacon"
+	CmdCompactBlockShard  = "cmpblkshard"
 	CmdCrossShard         = "crossshard"
 	CmdTx                 = "tx"
 	CmdPrivacyCustomToken = "txprivacytok"
@@ -61,6 +62,9 @@ func MakeEmptyMessage(messageType string) (Message, error) {
 	case CmdBlockShard:
 		msg = &MessageBlockShard{}
 		break
+	case CmdCompactBlockShard:
+		msg = &MessageCompactBlockShard{}
+		break
 	case CmdGetCrossShard:
 		msg = &MessageGetCrossShard{}
 		break
@@ -167,6 +171,8 @@ func GetCmdType(msgType reflect.Type) (string, error) {
 		return CmdBlockBeacon, nil
 	case reflect.TypeOf(&MessageBlockShard{}):
 		return CmdBlockShard, nil
+	case reflect.TypeOf(&MessageCompactBlockShard{}):
+		return CmdCompactBlockShard, nil
 	case reflect.TypeOf(&MessageGetCrossShard{}):
 		return CmdGetCrossShard, nil
 	case reflect.TypeOf(&MessageCrossShard{}):
@@ -58,3 +58,25 @@ func (msg *MessageBFT) SignMsg(keySet *incognitokey.KeySet) error {
 func (msg *MessageBFT) VerifyMsgSanity() error {
 	return nil
 }
+
+func (msg *MessageBFT) BinarySerialize() ([]byte, error) {
+	w := &binaryWriter{}
+	w.writeString(msg.PeerID)
+	w.writeString(msg.Type)
+	w.writeBytes(msg.Content)
+	w.writeString(msg.ChainKey)
+	w.writeVarint(msg.Timestamp)
+	w.writeVarint(msg.TimeSlot)
+	return w.bytes(), nil
+}
+
+func (msg *MessageBFT) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	msg.PeerID = r.readString()
+	msg.Type = r.readString()
+	msg.Content = r.readBytes()
+	msg.ChainKey = r.readString()
+	msg.Timestamp = r.readVarint()
+	msg.TimeSlot = r.readVarint()
+	return r.finish()
+}
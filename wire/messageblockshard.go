@@ -50,3 +50,24 @@ func (msg *MessageBlockShard) SignMsg(_ *incognitokey.KeySet) error {
 func (msg *MessageBlockShard) VerifyMsgSanity() error {
 	return nil
 }
+
+// BinarySerialize - block keeps its json encoding inside the binary message, its transactions are polymorphic
+func (msg *MessageBlockShard) BinarySerialize() ([]byte, error) {
+	blockBytes, err := json.Marshal(msg.Block)
+	if err != nil {
+		return nil, err
+	}
+	w := &binaryWriter{}
+	w.writeBytes(blockBytes)
+	return w.bytes(), nil
+}
+
+func (msg *MessageBlockShard) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	blockBytes := r.readBytes()
+	if err := r.finish(); err != nil {
+		return err
+	}
+	msg.Block = new(blockchain.ShardBlock)
+	return json.Unmarshal(blockBytes, msg.Block)
+}
@@ -0,0 +1,182 @@
+package wire
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/libp2p/go-libp2p-peer"
+)
+
+// ShortTxIDSize - bytes of a tx hash kept in a short id
+const ShortTxIDSize = 6
+
+// MessageCompactBlockShard - shard block announced by its header and the short ids of its transactions,
+// published on the topics of CmdBlockShard.
+// Receivers rebuild the block with transactions of their pool and cross transactions of their cross shard pool,
+// and request the missing ones from the peer which sent the announcement
+type MessageCompactBlockShard struct {
+	ValidationData string
+	Header         blockchain.ShardHeader
+	Instructions   [][]string
+	// short id of every transaction of the block, in order, see ShortTxID
+	ShortTxIDs []uint64
+	// transactions minted by the producer, they are never in the pool of receivers
+	Prefilled        blockchain.ShardBody
+	PrefilledIndexes []int
+	// hashes of the cross shard blocks the cross transactions come from, by source shard
+	CrossShardBlocks map[byte][]common.Hash
+}
+
+// ShortTxID - first ShortTxIDSize bytes of the hash of txHash salted with blockHash,
+// so short ids of colliding transactions cannot be prepared before the block exists
+func ShortTxID(blockHash common.Hash, txHash common.Hash) uint64 {
+	h := common.HashH(append(blockHash[:], txHash[:]...))
+	id := make([]byte, 8)
+	copy(id[8-ShortTxIDSize:], h[:ShortTxIDSize])
+	return binary.BigEndian.Uint64(id)
+}
+
+func isPrefilledTx(tx metadata.Transaction) bool {
+	proof := tx.GetProof()
+	return proof == nil || len(proof.GetInputCoins()) == 0
+}
+
+// NewMessageCompactBlockShard - compact announcement of block
+func NewMessageCompactBlockShard(block *blockchain.ShardBlock) *MessageCompactBlockShard {
+	blockHash := block.Header.Hash()
+	msg := &MessageCompactBlockShard{
+		ValidationData:   block.ValidationData,
+		Header:           block.Header,
+		Instructions:     block.Body.Instructions,
+		ShortTxIDs:       make([]uint64, len(block.Body.Transactions)),
+		Prefilled:        blockchain.ShardBody{Transactions: []metadata.Transaction{}},
+		PrefilledIndexes: []int{},
+		CrossShardBlocks: make(map[byte][]common.Hash),
+	}
+	for i, tx := range block.Body.Transactions {
+		msg.ShortTxIDs[i] = ShortTxID(blockHash, *tx.Hash())
+		if isPrefilledTx(tx) {
+			msg.Prefilled.Transactions = append(msg.Prefilled.Transactions, tx)
+			msg.PrefilledIndexes = append(msg.PrefilledIndexes, i)
+		}
+	}
+	for shardID, crossTxs := range block.Body.CrossTransactions {
+		for _, crossTx := range crossTxs {
+			msg.CrossShardBlocks[shardID] = append(msg.CrossShardBlocks[shardID], crossTx.BlockHash)
+		}
+	}
+	return msg
+}
+
+// BlockHash - hash of the announced block
+func (msg *MessageCompactBlockShard) BlockHash() common.Hash {
+	return msg.Header.Hash()
+}
+
+func (msg *MessageCompactBlockShard) Hash() string {
+	rawBytes, err := msg.JsonSerialize()
+	if err != nil {
+		return ""
+	}
+	return common.HashH(rawBytes).String()
+}
+
+func (msg *MessageCompactBlockShard) MessageType() string {
+	return CmdCompactBlockShard
+}
+
+func (msg *MessageCompactBlockShard) MaxPayloadLength(pver int) int {
+	return MaxBlockPayload
+}
+
+func (msg *MessageCompactBlockShard) JsonSerialize() ([]byte, error) {
+	jsonBytes, err := json.Marshal(msg)
+	return jsonBytes, err
+}
+
+func (msg *MessageCompactBlockShard) JsonDeserialize(jsonStr string) error {
+	err := json.Unmarshal([]byte(jsonStr), msg)
+	return err
+}
+
+func (msg *MessageCompactBlockShard) SetSenderID(senderID peer.ID) error {
+	return nil
+}
+
+func (msg *MessageCompactBlockShard) SignMsg(_ *incognitokey.KeySet) error {
+	return nil
+}
+
+func (msg *MessageCompactBlockShard) VerifyMsgSanity() error {
+	if len(msg.ShortTxIDs) > blockchain.MaxShardBlockTxs {
+		return fmt.Errorf("compact block has %v short tx ids, exceeds limit %v", len(msg.ShortTxIDs), blockchain.MaxShardBlockTxs)
+	}
+	if len(msg.PrefilledIndexes) != len(msg.Prefilled.Transactions) {
+		return fmt.Errorf("compact block has %v prefilled indexes for %v prefilled txs", len(msg.PrefilledIndexes), len(msg.Prefilled.Transactions))
+	}
+	last := -1
+	for _, i := range msg.PrefilledIndexes {
+		if i <= last || i >= len(msg.ShortTxIDs) {
+			return fmt.Errorf("invalid prefilled index %v of compact block with %v txs", i, len(msg.ShortTxIDs))
+		}
+		last = i
+	}
+	for shardID, hashes := range msg.CrossShardBlocks {
+		if len(hashes) > blockchain.MaxCrossTransactionsOfShard {
+			return fmt.Errorf("compact block has %v cross shard blocks from shard %v, exceeds limit %v", len(hashes), shardID, blockchain.MaxCrossTransactionsOfShard)
+		}
+	}
+	return nil
+}
+
+// BinarySerialize - short ids are written as fixed size integers, the rest keeps its json encoding
+// like MessageBlockShard because prefilled transactions are polymorphic
+func (msg *MessageCompactBlockShard) BinarySerialize() ([]byte, error) {
+	rest := *msg
+	rest.ShortTxIDs = nil
+	restBytes, err := json.Marshal(&rest)
+	if err != nil {
+		return nil, err
+	}
+	w := &binaryWriter{}
+	w.writeBytes(restBytes)
+	w.writeUvarint(uint64(len(msg.ShortTxIDs)))
+	id := make([]byte, 8)
+	for _, shortID := range msg.ShortTxIDs {
+		binary.BigEndian.PutUint64(id, shortID)
+		w.buf.Write(id[8-ShortTxIDSize:])
+	}
+	return w.bytes(), nil
+}
+
+func (msg *MessageCompactBlockShard) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	restBytes := r.readBytes()
+	n := r.readUvarint()
+	if r.err == nil && n > uint64(len(r.data)-r.pos)/ShortTxIDSize {
+		r.fail(fmt.Errorf("%v short tx ids exceed remaining %v bytes of binary message", n, len(r.data)-r.pos))
+	}
+	if r.err != nil {
+		return r.err
+	}
+	shortIDs := make([]uint64, n)
+	id := make([]byte, 8)
+	for i := range shortIDs {
+		copy(id[8-ShortTxIDSize:], r.data[r.pos:r.pos+ShortTxIDSize])
+		shortIDs[i] = binary.BigEndian.Uint64(id)
+		r.pos += ShortTxIDSize
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(restBytes, msg); err != nil {
+		return err
+	}
+	msg.ShortTxIDs = shortIDs
+	return nil
+}
@@ -54,3 +54,24 @@ func (msg *MessageCrossShard) SignMsg(_ *incognitokey.KeySet) error {
 func (msg *MessageCrossShard) VerifyMsgSanity() error {
 	return nil
 }
+
+// BinarySerialize - block keeps its json encoding inside the binary message, its transactions are polymorphic
+func (msg *MessageCrossShard) BinarySerialize() ([]byte, error) {
+	blockBytes, err := json.Marshal(msg.Block)
+	if err != nil {
+		return nil, err
+	}
+	w := &binaryWriter{}
+	w.writeBytes(blockBytes)
+	return w.bytes(), nil
+}
+
+func (msg *MessageCrossShard) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	blockBytes := r.readBytes()
+	if err := r.finish(); err != nil {
+		return err
+	}
+	msg.Block = new(blockchain.CrossShardBlock)
+	return json.Unmarshal(blockBytes, msg.Block)
+}
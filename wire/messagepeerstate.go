@@ -2,6 +2,7 @@ package wire
 
 import (
 	"encoding/json"
+	"sort"
 
 	peer "github.com/libp2p/go-libp2p-peer"
 
@@ -27,6 +28,8 @@ type MessagePeerState struct {
 	Timestamp             int64
 	SenderID              string
 	SenderMiningPublicKey string
+	// MaxCodec - highest codec the sender decodes, 0 (missing in json of old nodes) means CodecJSON only
+	MaxCodec int
 }
 
 func (msg *MessagePeerState) Hash() string {
@@ -67,3 +70,105 @@ func (msg *MessagePeerState) SignMsg(_ *incognitokey.KeySet) error {
 func (msg *MessagePeerState) VerifyMsgSanity() error {
 	return nil
 }
+
+func (state ChainState) writeBinary(w *binaryWriter) {
+	w.writeVarint(state.Timestamp)
+	w.writeUvarint(state.Height)
+	w.writeHash(state.BlockHash)
+	w.writeHash(state.BestStateHash)
+}
+
+func (state *ChainState) readBinary(r *binaryReader) {
+	state.Timestamp = r.readVarint()
+	state.Height = r.readUvarint()
+	state.BlockHash = r.readHash()
+	state.BestStateHash = r.readHash()
+}
+
+// BinarySerialize - map entries are written in order of their keys, so the encoding is deterministic
+func (msg *MessagePeerState) BinarySerialize() ([]byte, error) {
+	w := &binaryWriter{}
+	msg.Beacon.writeBinary(w)
+	shardIDs := sortedShardIDs(len(msg.Shards), func(f func(byte)) {
+		for shardID := range msg.Shards {
+			f(shardID)
+		}
+	})
+	w.writeUvarint(uint64(len(shardIDs)))
+	for _, shardID := range shardIDs {
+		w.writeByte(shardID)
+		msg.Shards[shardID].writeBinary(w)
+	}
+	toShardIDs := sortedShardIDs(len(msg.CrossShardPool), func(f func(byte)) {
+		for shardID := range msg.CrossShardPool {
+			f(shardID)
+		}
+	})
+	w.writeUvarint(uint64(len(toShardIDs)))
+	for _, toShardID := range toShardIDs {
+		w.writeByte(toShardID)
+		pool := msg.CrossShardPool[toShardID]
+		fromShardIDs := sortedShardIDs(len(pool), func(f func(byte)) {
+			for shardID := range pool {
+				f(shardID)
+			}
+		})
+		w.writeUvarint(uint64(len(fromShardIDs)))
+		for _, fromShardID := range fromShardIDs {
+			w.writeByte(fromShardID)
+			w.writeUvarint(uint64(len(pool[fromShardID])))
+			for _, height := range pool[fromShardID] {
+				w.writeUvarint(height)
+			}
+		}
+	}
+	w.writeVarint(msg.Timestamp)
+	w.writeString(msg.SenderID)
+	w.writeString(msg.SenderMiningPublicKey)
+	w.writeUvarint(uint64(msg.MaxCodec))
+	return w.bytes(), nil
+}
+
+func (msg *MessagePeerState) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	msg.Beacon.readBinary(r)
+	msg.Shards = make(map[byte]ChainState)
+	numShards := r.readLength()
+	for i := 0; i < numShards && r.err == nil; i++ {
+		shardID := r.readByte()
+		state := ChainState{}
+		state.readBinary(r)
+		msg.Shards[shardID] = state
+	}
+	msg.CrossShardPool = make(map[byte]map[byte][]uint64)
+	numToShards := r.readLength()
+	for i := 0; i < numToShards && r.err == nil; i++ {
+		toShardID := r.readByte()
+		pool := make(map[byte][]uint64)
+		numFromShards := r.readLength()
+		for j := 0; j < numFromShards && r.err == nil; j++ {
+			fromShardID := r.readByte()
+			numHeights := r.readLength()
+			heights := make([]uint64, 0, numHeights)
+			for k := 0; k < numHeights && r.err == nil; k++ {
+				heights = append(heights, r.readUvarint())
+			}
+			pool[fromShardID] = heights
+		}
+		msg.CrossShardPool[toShardID] = pool
+	}
+	msg.Timestamp = r.readVarint()
+	msg.SenderID = r.readString()
+	msg.SenderMiningPublicKey = r.readString()
+	msg.MaxCodec = int(r.readUvarint())
+	return r.finish()
+}
+
+func sortedShardIDs(n int, iterate func(f func(byte))) []byte {
+	shardIDs := make([]byte, 0, n)
+	iterate(func(shardID byte) {
+		shardIDs = append(shardIDs, shardID)
+	})
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+	return shardIDs
+}
@@ -3,6 +3,7 @@ package wire
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/common"
@@ -56,3 +57,27 @@ func (msg *MessageTx) SignMsg(_ *incognitokey.KeySet) error {
 func (msg *MessageTx) VerifyMsgSanity() error {
 	return nil
 }
+
+// BinarySerialize - transaction keeps its json encoding inside the binary message
+func (msg *MessageTx) BinarySerialize() ([]byte, error) {
+	txBytes, err := json.Marshal(msg.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	w := &binaryWriter{}
+	w.writeBytes(txBytes)
+	return w.bytes(), nil
+}
+
+// BinaryDeserialize - msg.Transaction must be set to an empty transaction of the expected type (see MakeEmptyMessage)
+func (msg *MessageTx) BinaryDeserialize(data []byte) error {
+	r := newBinaryReader(data)
+	txBytes := r.readBytes()
+	if err := r.finish(); err != nil {
+		return err
+	}
+	if msg.Transaction == nil {
+		return errors.New("transaction type of message is not set")
+	}
+	return json.Unmarshal(txBytes, msg.Transaction)
+}